@@ -1,12 +1,29 @@
 package api
 
 import (
+	"errors"
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/health"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/metrics"
+	"github.com/imjenal/transaction-service/internal/ratelimit"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/risk"
+	"github.com/imjenal/transaction-service/internal/tracing"
 	"github.com/imjenal/transaction-service/pkg/validator"
+	"log/slog"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/api/openapi"
 	"github.com/imjenal/transaction-service/api/v1/accounts"
+	"github.com/imjenal/transaction-service/api/v1/analytics"
+	"github.com/imjenal/transaction-service/api/v1/disputes"
+	"github.com/imjenal/transaction-service/api/v1/schedules"
 	"github.com/imjenal/transaction-service/api/v1/transactions"
+	"github.com/imjenal/transaction-service/api/v1/users"
 	"github.com/imjenal/transaction-service/internal/app"
 	"github.com/imjenal/transaction-service/internal/db"
 	"github.com/imjenal/transaction-service/internal/db/models"
@@ -14,39 +31,112 @@ import (
 	"github.com/imjenal/transaction-service/pkg/http/response"
 )
 
+var errNotReady = errors.New("NOT_READY")
+
 type Params struct {
-	DB        *db.DB
-	Reader    *request.Reader
-	Writer    *response.JSONWriter
-	Validator *validator.Validator
+	DB         *db.DB
+	Reader     *request.Reader
+	Writer     *response.JSONWriter
+	Validator  *validator.Validator
+	RiskEngine *risk.Engine
+	// RewardsEngine accrues the rewards of the transactions
+	RewardsEngine *rewards.Engine
+	Accounts      *config.Accounts
+	Transactions  *config.Transactions
+	// Clock tells the current time to the handlers
+	Clock clock.Clock
+	// Authenticator authenticates the callers of the /v1/ routes
+	Authenticator *auth.Authenticator
+	// RateLimiter limits the requests to the /v1/ routes
+	RateLimiter *ratelimit.Limiter
+	// Logger writes the access logs, the handlers log with the logger of the request in the context
+	Logger *slog.Logger
+	// MaxBodyBytes is the size limit of the request bodies, of the routes that don't set their own
+	MaxBodyBytes int64
+	// Health has the checks of the dependencies of the service, the service is ready when they all pass
+	Health *health.Registry
 }
 
 func Routes(r *mux.Router, params *Params) {
 	querier := models.New(params.DB.Conn)
 
+	// Every request is counted and timed. The metrics middleware is the outermost one, so that the handlers write
+	// to the writer of the logging middleware, which adds the request ID to the errors
+	r.Use(metrics.Middleware)
+
+	// Every request gets a span, in the trace of the caller's traceparent. It runs before the logging middleware,
+	// which logs the trace ID
+	r.Use(tracing.Middleware)
+
+	// Every request gets an ID and a logger with the ID, and is logged once it is served
+	r.Use(logging.NewMiddleware(params.Logger))
+
+	// A handler that panics responds with the default error, the panic is logged with the ID of the request
+	r.Use(logging.NewRecoveryMiddleware(params.Writer))
+
+	// The request bodies are limited, the routes that take larger or smaller bodies set their own limit. The limit
+	// applies before the middlewares of the sub-routers, which may read the body, e.g. the rate limiter
+	r.Use(request.LimitBody(params.MaxBodyBytes))
+
 	// Add the API health check route at the top level
 	r.HandleFunc("/health", healthCheck(params.Writer))
 
+	// The liveness probe only tells that the process serves requests, the readiness probe checks the dependencies
+	r.HandleFunc("/health/live", healthCheck(params.Writer)).Methods(http.MethodGet)
+	r.HandleFunc("/health/ready", readinessCheck(params.Writer, params.Health)).Methods(http.MethodGet)
+
+	// The OpenAPI spec and the Swagger UI page are public, at /openapi.json and /docs/
+	openapi.Routes(r)
+
 	// Create a /v1/ sub-router for the API
 	v1Router := r.PathPrefix("/v1/").Subrouter()
 
+	// The requests are limited by IP before the authentication, so that the requests with bad credentials are counted
+	v1Router.Use(ratelimit.NewIPMiddleware(params.RateLimiter, params.Writer))
+
+	// Every /v1/ route requires an API key or a bearer token, the identity of the caller is added to the context
+	v1Router.Use(auth.NewMiddleware(params.Authenticator, params.Writer))
+
+	// The requests are limited by API key or account after the authentication
+	v1Router.Use(ratelimit.NewMiddleware(params.RateLimiter, params.Writer))
+
 	pathValidatorMiddleware := validator.NewPathValidator(params.Validator, params.Writer, map[string]string{
 		"transactionID": "uuid4",
 		"accountID":     "uuid4",
+		"userID":        "uuid4",
+		"scheduleID":    "uuid4",
+		"disputeID":     "uuid4",
 	})
 	v1Router.Use(pathValidatorMiddleware)
 
 	// All repositories are initialized here
-	accountsRepo := accounts.NewRepository(querier)
-	transactionsRepo := transactions.NewRepository(querier)
+	accountsRepo := accounts.NewRepository(querier, params.DB.Conn, params.Accounts.DocumentUniqueness)
+	transactionsRepo := transactions.NewRepository(querier, params.DB.Conn)
+	usersRepo := users.NewRepository(querier)
+	schedulesRepo := schedules.NewRepository(querier, params.DB.Conn)
+	disputesRepo := disputes.NewRepository(querier, params.DB.Conn)
+	analyticsRepo := analytics.NewRepository(querier)
+
+	// The policy authorizes the callers, the users only access their own resources
+	policy := auth.NewPolicy(querier, params.Writer)
 
 	// All handlers are initialized here
-	accountsHandler := accounts.NewHandler(params.Reader, params.Writer, accountsRepo)
-	transactionsHandler := transactions.NewHandler(params.Reader, params.Writer, transactionsRepo)
+	accountsHandler := accounts.NewHandler(params.Reader, params.Writer, accountsRepo, policy)
+	transactionsHandler := transactions.NewHandler(params.Reader, params.Writer, transactionsRepo, params.RiskEngine, params.RewardsEngine, params.Clock, params.Transactions, policy)
+	usersHandler := users.NewHandler(params.Reader, params.Writer, usersRepo)
+	schedulesHandler := schedules.NewHandler(params.Reader, params.Writer, schedulesRepo, params.Clock)
+	disputesHandler := disputes.NewHandler(params.Reader, params.Writer, disputesRepo, params.RiskEngine, params.RewardsEngine, params.Clock, params.Transactions)
+	analyticsHandler := analytics.NewHandler(params.Reader, params.Writer, analyticsRepo)
 
 	// All routes are added here
-	accounts.Routes(v1Router.PathPrefix("/accounts").Subrouter(), accountsHandler)
-	transactions.Routes(v1Router.PathPrefix("/transactions").Subrouter(), transactionsHandler)
+	accounts.Routes(v1Router.PathPrefix("/accounts").Subrouter(), accountsHandler, policy)
+	transactions.Routes(v1Router.PathPrefix("/transactions").Subrouter(), transactionsHandler, policy)
+	users.Routes(v1Router.PathPrefix("/users").Subrouter(), usersHandler, policy)
+	schedules.Routes(v1Router.PathPrefix("/accounts/{accountID}/scheduled-transactions").Subrouter(), schedulesHandler, policy)
+	disputes.Routes(v1Router.PathPrefix("/disputes").Subrouter(), disputesHandler, policy)
+	disputes.TransactionRoutes(v1Router.PathPrefix("/transactions/{transactionID}/disputes").Subrouter(), disputesHandler, policy)
+	analytics.AccountRoutes(v1Router.PathPrefix("/accounts/{accountID}/analytics").Subrouter(), analyticsHandler, policy)
+	analytics.Routes(v1Router.PathPrefix("/admin/analytics").Subrouter(), analyticsHandler, policy)
 
 }
 
@@ -56,3 +146,19 @@ func healthCheck(jsonWriter *response.JSONWriter) http.HandlerFunc {
 		jsonWriter.Ok(w, map[string]any{"version": app.Version()})
 	}
 }
+
+// readinessCheck returns a handler that runs the checks of the dependencies. It responds with the status of every
+// component, with a 200 when they are all up and a 503 otherwise
+func readinessCheck(jsonWriter *response.JSONWriter, checks *health.Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		report := checks.Check(r.Context())
+		if report.Status == health.StatusUp {
+			jsonWriter.Ok(w, report)
+			return
+		}
+
+		logging.FromContext(r.Context()).Warn("readinessCheck: the service is not ready", "components", report.Components)
+		jsonWriter.ServiceUnavailable(w, response.NewError(response.NotReady, errNotReady.Error(),
+			"Check the components that are down", report))
+	}
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/api/openapi"
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/internal/health"
+	"github.com/imjenal/transaction-service/internal/ratelimit"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// undocumentedRoutes are the routes of the documentation itself
+var undocumentedRoutes = map[string]bool{
+	"GET /api/openapi.json": true,
+	"GET /api/docs":         true,
+	"GET /api/docs/":        true,
+	"GET /api/docs/{file}":  true,
+}
+
+// TestRoutes_AreDocumented fails when a route is added to or removed from Routes without regenerating the spec
+func TestRoutes_AreDocumented(t *testing.T) {
+	writer := response.NewJSONWriter()
+	validatr := validator.New()
+
+	r := mux.NewRouter()
+	Routes(r.PathPrefix("/api/").Subrouter(), &Params{
+		DB:           &db.DB{},
+		Reader:       request.NewReader(writer, validatr),
+		Writer:       writer,
+		Validator:    validatr,
+		Accounts:     &config.Accounts{DocumentUniqueness: config.DocumentUniquenessGlobal},
+		Transactions: &config.Transactions{},
+		Clock:        clock.System{},
+		Logger:       slog.Default(),
+	})
+
+	var routes []string
+	err := r.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		template, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+
+		// Sub-routers have no handler, and routes without methods, such as /health, answer GET
+		if route.GetHandler() == nil {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = []string{http.MethodGet}
+		}
+
+		for _, method := range methods {
+			if key := method + " " + template; !undocumentedRoutes[key] {
+				routes = append(routes, key)
+			}
+		}
+
+		return nil
+	})
+	assert.Nil(t, err)
+
+	doc, err := openapi3.NewLoader().LoadFromData(openapi.Spec())
+	assert.Nil(t, err)
+
+	var documented []string
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			documented = append(documented, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	sort.Strings(routes)
+	sort.Strings(documented)
+	assert.Equal(t, documented, routes, "the routes differ from the spec, update openapi.Operations and run go generate ./api/openapi")
+	assert.Contains(t, routes, http.MethodPost+" /api/v1/transactions")
+}
+
+// TestRoutes_LimitsBadCredentials checks that the requests with bad credentials are counted by IP before they are
+// rejected, so that the API keys can't be guessed at full speed
+func TestRoutes_LimitsBadCredentials(t *testing.T) {
+	// Prepare mock responses
+	ctrl := gomock.NewController(t)
+	querier := mock.NewMockQuerier(ctrl)
+	querier.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows).Times(2)
+
+	rulesFile := filepath.Join(t.TempDir(), "rate_limits.yaml")
+	assert.Nil(t, os.WriteFile(rulesFile, []byte("rules: [{name: per-ip, key_by: ip, requests: 2, period: 1m}]"), 0o600))
+
+	limiter, err := ratelimit.NewLimiter(rulesFile, ratelimit.NewMemoryStore(clock.System{}))
+	assert.Nil(t, err)
+
+	writer := response.NewJSONWriter()
+	validatr := validator.New()
+
+	r := mux.NewRouter()
+	Routes(r.PathPrefix("/api/").Subrouter(), &Params{
+		DB:            &db.DB{},
+		Reader:        request.NewReader(writer, validatr),
+		Writer:        writer,
+		Validator:     validatr,
+		Accounts:      &config.Accounts{DocumentUniqueness: config.DocumentUniquenessGlobal},
+		Transactions:  &config.Transactions{},
+		Clock:         clock.System{},
+		Authenticator: auth.NewAuthenticator(querier, nil, nil, clock.System{}),
+		RateLimiter:   limiter,
+		Logger:        slog.Default(),
+	})
+
+	// Call the handler
+	codes := make([]int, 0, 3)
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+		req.Header.Set("X-API-Key", "guessed-key")
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, req)
+		codes = append(codes, rr.Code)
+	}
+
+	// Check the results
+	assert.Equal(t, []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests}, codes)
+}
+
+func TestReadinessCheck(t *testing.T) {
+	t.Run("ready", func(t *testing.T) {
+		// Prepare mock responses
+		checks := health.NewRegistry(time.Second)
+		checks.Register(health.NewChecker("database", func(context.Context) error { return nil }))
+
+		// Call the handler
+		rr := httptest.NewRecorder()
+		readinessCheck(response.NewJSONWriter(), checks)(rr, httptest.NewRequest(http.MethodGet, "/api/health/ready", nil))
+
+		// Check the results
+		assert.Equal(t, http.StatusOK, rr.Code)
+		assert.JSONEq(t, `{"data":{"status":"UP","components":{"database":{"status":"UP","latency_ms":0}}},"error":null}`,
+			zeroLatency(t, rr.Body.Bytes()))
+	})
+
+	t.Run("not ready", func(t *testing.T) {
+		// Prepare mock responses
+		checks := health.NewRegistry(time.Second)
+		checks.Register(
+			health.NewChecker("database", func(context.Context) error { return errors.New("connection refused") }),
+			health.NewChecker("scheduler", func(context.Context) error { return nil }),
+		)
+
+		// Call the handler
+		rr := httptest.NewRecorder()
+		readinessCheck(response.NewJSONWriter(), checks)(rr, httptest.NewRequest(http.MethodGet, "/api/health/ready", nil))
+
+		// Check the results
+		assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+
+		var body struct {
+			Error struct {
+				Code    response.ErrorCode `json:"code"`
+				Message string             `json:"message"`
+				Data    health.Report      `json:"data"`
+			} `json:"error"`
+		}
+		assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &body))
+		assert.Equal(t, response.NotReady, body.Error.Code)
+		assert.Equal(t, "NOT_READY", body.Error.Message)
+		assert.Equal(t, health.StatusDown, body.Error.Data.Status)
+		assert.Equal(t, health.StatusDown, body.Error.Data.Components["database"].Status)
+		assert.Equal(t, "connection refused", body.Error.Data.Components["database"].Error)
+		assert.Equal(t, health.StatusUp, body.Error.Data.Components["scheduler"].Status)
+	})
+}
+
+// zeroLatency sets the latencies of the readiness report to 0, so that the body can be compared
+func zeroLatency(t *testing.T, body []byte) string {
+	var decoded struct {
+		Data  health.Report `json:"data"`
+		Error any           `json:"error"`
+	}
+	assert.Nil(t, json.Unmarshal(body, &decoded))
+
+	for _, component := range decoded.Data.Components {
+		component.LatencyMs = 0
+	}
+
+	encoded, err := json.Marshal(decoded)
+	assert.Nil(t, err)
+
+	return string(encoded)
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	pismov1 "github.com/imjenal/transaction-service/api/proto/pismo/v1"
+	"github.com/imjenal/transaction-service/api/v1/accounts"
+	"github.com/imjenal/transaction-service/api/v1/transactions"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"google.golang.org/grpc"
+)
+
+// RegisterGRPC registers the services of the gRPC API. They use the same repositories, domain logic and policy as
+// the routes, the callers are authenticated by the interceptors of the server
+func RegisterGRPC(s grpc.ServiceRegistrar, params *Params) {
+	querier := models.New(params.DB.Conn)
+
+	accountsRepo := accounts.NewRepository(querier, params.DB.Conn, params.Accounts.DocumentUniqueness)
+	transactionsRepo := transactions.NewRepository(querier, params.DB.Conn)
+	transactionsService := transactions.NewService(transactionsRepo, params.RiskEngine, params.RewardsEngine, params.Clock, params.Transactions)
+
+	policy := auth.NewPolicy(querier, params.Writer)
+
+	pismov1.RegisterAccountServiceServer(s, accounts.NewGRPCServer(params.Reader, accountsRepo, policy))
+	pismov1.RegisterTransactionServiceServer(s, transactions.NewGRPCServer(params.Reader, transactionsRepo, transactionsService, policy))
+}
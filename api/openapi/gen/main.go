@@ -0,0 +1,29 @@
+// Command gen writes the OpenAPI 3 document of the API, generated from the operations of the openapi package
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/imjenal/transaction-service/api/openapi"
+)
+
+func main() {
+	out := flag.String("o", "openapi.json", "the file the spec is written to")
+	flag.Parse()
+
+	doc, err := openapi.Generate()
+	if err != nil {
+		log.Fatalf("failed to generate the spec: %v", err)
+	}
+
+	b, err := openapi.Marshal(doc)
+	if err != nil {
+		log.Fatalf("failed to encode the spec: %v", err)
+	}
+
+	if err = os.WriteFile(*out, b, 0o644); err != nil {
+		log.Fatalf("failed to write the spec: %v", err)
+	}
+}
@@ -0,0 +1,410 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3gen"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+const (
+	apiKeyScheme = "apiKey"
+	bearerScheme = "bearer"
+)
+
+// pathParam is a param of a route template, e.g. {accountID}
+var pathParam = regexp.MustCompile(`\{(\w+)}`)
+
+// The patterns of the validate tags that aren't formats of the spec
+var tagPatterns = map[string]string{
+	"e164":             `^\+[1-9][0-9]{1,14}$`,
+	"numeric":          `^[0-9]+$`,
+	"iso3166_1_alpha2": `^[A-Z]{2}$`,
+}
+
+// Generate builds the spec of the operations. The schemas are generated from the structs of the operations, with the
+// constraints of their `validate` tags
+func Generate() (*openapi3.T, error) {
+	doc := &openapi3.T{
+		OpenAPI: "3.0.3",
+		Info: &openapi3.Info{
+			Title:       "Pismo transaction service",
+			Description: "Users, accounts and their transactions. Every response is a JSON object with the data or the error.",
+			Version:     "1.0.0",
+		},
+		Paths: openapi3.NewPaths(),
+		Components: &openapi3.Components{
+			Schemas:   openapi3.Schemas{},
+			Responses: openapi3.ResponseBodies{},
+			SecuritySchemes: openapi3.SecuritySchemes{
+				apiKeyScheme: &openapi3.SecuritySchemeRef{Value: openapi3.NewSecurityScheme().
+					WithType("apiKey").WithIn("header").WithName("X-API-Key")},
+				bearerScheme: &openapi3.SecuritySchemeRef{Value: openapi3.NewJWTSecurityScheme()},
+			},
+		},
+	}
+
+	apiError, err := schemaFor(&response.APIError{})
+	if err != nil {
+		return nil, fmt.Errorf("openapi.Generate: failed to generate the error schema: %w", err)
+	}
+
+	doc.Components.Schemas["APIError"] = apiError
+	doc.Components.Schemas["ErrorResponse"] = openapi3.NewSchemaRef("", openapi3.NewObjectSchema().
+		WithProperty("data", nullSchema()).
+		WithPropertyRef("error", openapi3.NewSchemaRef("#/components/schemas/APIError", nil)))
+
+	for _, status := range []int{
+		http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound,
+		http.StatusConflict, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity, http.StatusTooManyRequests,
+		http.StatusInternalServerError, http.StatusServiceUnavailable,
+	} {
+		doc.Components.Responses[responseName(status)] = &openapi3.ResponseRef{Value: openapi3.NewResponse().
+			WithDescription(http.StatusText(status)).
+			WithJSONSchemaRef(openapi3.NewSchemaRef("#/components/schemas/ErrorResponse", nil))}
+	}
+
+	for _, op := range Operations {
+		operation, err := newOperation(op)
+		if err != nil {
+			return nil, fmt.Errorf("openapi.Generate: %s %s: %w", op.Method, op.Path, err)
+		}
+
+		doc.AddOperation(op.Path, op.Method, operation)
+	}
+
+	return doc, nil
+}
+
+// Marshal encodes the spec as indented JSON, as it is committed
+func Marshal(doc *openapi3.T) ([]byte, error) {
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("openapi.Marshal: %w", err)
+	}
+
+	return append(b, '\n'), nil
+}
+
+// newOperation documents the params, the body, the responses and the security of an operation
+func newOperation(op Operation) (*openapi3.Operation, error) {
+	operation := openapi3.NewOperation()
+	operation.OperationID = op.ID
+	operation.Summary = op.Summary
+	operation.Tags = []string{op.Tag}
+
+	// The routes with a scope are behind the authentication, the others are public
+	operation.Security = &openapi3.SecurityRequirements{}
+	if op.Scope != "" {
+		operation.Description = fmt.Sprintf("Requires the `%s` scope.", op.Scope)
+		operation.Security.With(openapi3.NewSecurityRequirement().Authenticate(apiKeyScheme)).
+			With(openapi3.NewSecurityRequirement().Authenticate(bearerScheme))
+	}
+
+	for _, m := range pathParam.FindAllStringSubmatch(op.Path, -1) {
+		operation.AddParameter(openapi3.NewPathParameter(m[1]).
+			WithSchema(openapi3.NewStringSchema().WithFormat("uuid")))
+	}
+
+	if op.Query != nil {
+		params, err := queryParams(op.Query)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, param := range params {
+			operation.AddParameter(param)
+		}
+	}
+
+	if op.Request != nil {
+		schema, err := schemaFor(op.Request)
+		if err != nil {
+			return nil, err
+		}
+
+		operation.RequestBody = &openapi3.RequestBodyRef{Value: openapi3.NewRequestBody().
+			WithRequired(true).
+			WithJSONSchemaRef(schema)}
+	}
+
+	data, err := schemaFor(op.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	operation.Responses = openapi3.NewResponses(openapi3.WithStatus(http.StatusOK, &openapi3.ResponseRef{Value: openapi3.NewResponse().
+		WithDescription(http.StatusText(http.StatusOK)).
+		WithJSONSchema(openapi3.NewObjectSchema().
+			WithPropertyRef("data", data).
+			WithProperty("error", nullSchema()))}))
+
+	for _, status := range errorStatuses(op) {
+		operation.Responses.Set(strconv.Itoa(status), &openapi3.ResponseRef{Ref: "#/components/responses/" + responseName(status)})
+	}
+
+	return operation, nil
+}
+
+// errorStatuses are the statuses of the errors the operation can respond with
+func errorStatuses(op Operation) []int {
+	statuses := map[int]bool{http.StatusInternalServerError: true}
+	for _, status := range op.Errors {
+		statuses[status] = true
+	}
+
+	if op.Scope != "" {
+		statuses[http.StatusUnauthorized] = true
+		statuses[http.StatusForbidden] = true
+		statuses[http.StatusTooManyRequests] = true
+	}
+
+	hasPathParams := pathParam.MatchString(op.Path)
+	if hasPathParams {
+		statuses[http.StatusNotFound] = true
+	}
+
+	if hasPathParams || op.Query != nil || op.Request != nil {
+		statuses[http.StatusBadRequest] = true
+	}
+
+	// The bodies are limited by request.LimitBody
+	if op.Request != nil {
+		statuses[http.StatusRequestEntityTooLarge] = true
+	}
+
+	sorted := make([]int, 0, len(statuses))
+	for status := range statuses {
+		sorted = append(sorted, status)
+	}
+	sort.Ints(sorted)
+
+	return sorted
+}
+
+// responseName names the shared response of an error status, e.g. TooManyRequests
+func responseName(status int) string {
+	return strings.ReplaceAll(http.StatusText(status), " ", "")
+}
+
+// nullSchema is the schema of the data of the errors and of the error of the successful responses, they are null
+func nullSchema() *openapi3.Schema {
+	schema := openapi3.NewSchema()
+	schema.Nullable = true
+	schema.Description = "Always null"
+
+	return schema
+}
+
+// schemaFor generates the schema of the struct of value, the pointers are nullable
+func schemaFor(value any) (*openapi3.SchemaRef, error) {
+	return openapi3gen.NewSchemaRefForValue(value, nil,
+		openapi3gen.UseAllExportedFields(),
+		openapi3gen.SchemaCustomizer(applyStructTags))
+}
+
+// queryParams documents the fields of the query struct, they are named by their `schema` tags
+func queryParams(query any) ([]*openapi3.Parameter, error) {
+	t := reflect.TypeOf(query)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	params := make([]*openapi3.Parameter, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("schema"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		schema, err := openapi3gen.NewSchemaRefForValue(reflect.New(field.Type).Elem().Interface(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		param := openapi3.NewQueryParameter(name).WithSchema(schema.Value)
+		param.Required = applyValidateTag(schema.Value, field.Tag.Get("validate"))
+		params = append(params, param)
+	}
+
+	return params, nil
+}
+
+// applyStructTags adds the constraints of the `validate` tags of the fields of a struct to the schemas of its
+// properties. The struct is handled as a whole, so that the constraints before a dive apply to the field and the
+// constraints after it to the items
+func applyStructTags(_ string, t reflect.Type, _ reflect.StructTag, schema *openapi3.Schema) error {
+	if t.Kind() != reflect.Struct || schema.Properties == nil {
+		return nil
+	}
+
+	for _, field := range fields(t) {
+		property := schema.Properties[jsonName(field)]
+		if property == nil || property.Value == nil {
+			continue
+		}
+
+		if applyValidateTag(property.Value, field.Tag.Get("validate")) {
+			schema.Required = append(schema.Required, jsonName(field))
+		}
+	}
+
+	return nil
+}
+
+// fields are the fields of the struct, with the fields of the embedded structs
+func fields(t reflect.Type) []reflect.StructField {
+	var all []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		if _, tagged := field.Tag.Lookup("json"); field.Anonymous && !tagged {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				all = append(all, fields(embedded)...)
+				continue
+			}
+		}
+
+		all = append(all, field)
+	}
+
+	return all
+}
+
+// jsonName is the name of the field in the JSON documents
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" {
+		return field.Name
+	}
+
+	return name
+}
+
+// applyValidateTag adds the constraints of a `validate` tag to the schema of the field and tells if the field is
+// required. The rules after a dive apply to the items of the arrays and to the values of the maps, the rules of the
+// keys of the maps aren't documented. The custom rules, e.g. document or name, are left out
+func applyValidateTag(schema *openapi3.Schema, tag string) bool {
+	required := false
+	target := schema
+	inKeys := false
+
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch {
+		case name == "keys":
+			inKeys = true
+			continue
+		case name == "endkeys":
+			inKeys = false
+			continue
+		case inKeys:
+			continue
+		}
+
+		switch name {
+		case "required":
+			// The required items of an array can't be null
+			if target == schema {
+				required = true
+			} else {
+				target.Nullable = false
+			}
+		case "dive":
+			switch {
+			case target.Items != nil && target.Items.Value != nil:
+				target = target.Items.Value
+			case target.AdditionalProperties.Schema != nil && target.AdditionalProperties.Schema.Value != nil:
+				target = target.AdditionalProperties.Schema.Value
+			}
+		case "oneof":
+			for _, value := range strings.Fields(param) {
+				target.Enum = append(target.Enum, value)
+			}
+		case "uuid", "uuid4":
+			target.Format = "uuid"
+		case "email":
+			target.Format = "email"
+		case "datetime":
+			if param == "2006-01-02" {
+				target.Format = "date"
+			}
+		case "min", "max", "len", "gt", "gte", "lt", "lte":
+			applyBound(target, name, param)
+		default:
+			if pattern, ok := tagPatterns[name]; ok {
+				target.Pattern = pattern
+			}
+		}
+	}
+
+	return required
+}
+
+// applyBound adds a bound to the length of the strings, to the size of the arrays and of the maps or to the numbers
+func applyBound(schema *openapi3.Schema, rule, param string) {
+	value, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return
+	}
+
+	switch {
+	case schema.Type.Is(openapi3.TypeString), schema.Type.Is(openapi3.TypeArray), schema.Type.Is(openapi3.TypeObject):
+		size := uint64(value)
+		lower, upper := &schema.MinLength, &schema.MaxLength
+		if schema.Type.Is(openapi3.TypeArray) {
+			lower, upper = &schema.MinItems, &schema.MaxItems
+		}
+
+		if schema.Type.Is(openapi3.TypeObject) {
+			lower, upper = &schema.MinProps, &schema.MaxProps
+		}
+
+		switch rule {
+		case "min", "gte":
+			*lower = size
+		case "gt":
+			*lower = size + 1
+		case "max", "lte":
+			*upper = &size
+		case "lt":
+			size--
+			*upper = &size
+		case "len":
+			*lower = size
+			*upper = &size
+		}
+	case schema.Type.Is(openapi3.TypeNumber), schema.Type.Is(openapi3.TypeInteger):
+		switch rule {
+		case "min", "gte":
+			schema.Min = &value
+		case "gt":
+			schema.Min = &value
+			schema.ExclusiveMin = true
+		case "max", "lte":
+			schema.Max = &value
+		case "lt":
+			schema.Max = &value
+			schema.ExclusiveMax = true
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	_ "embed"
+	"net/http"
+	"path"
+
+	"github.com/gorilla/mux"
+	swaggerFiles "github.com/swaggo/files/v2"
+)
+
+// The spec is generated from the operations and committed, a test fails when it is out of date
+//go:generate go run ./gen -o openapi.json
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed swagger.html
+var swaggerIndex []byte
+
+// Spec returns the OpenAPI 3 document of the API, as JSON
+func Spec() []byte {
+	return spec
+}
+
+// Routes adds the routes of the spec and of the Swagger UI page
+func Routes(r *mux.Router) {
+	r.HandleFunc("/openapi.json", serveSpec).Methods(http.MethodGet)
+	r.HandleFunc("/docs", redirectToDocs).Methods(http.MethodGet)
+	r.HandleFunc("/docs/", serveSwaggerIndex).Methods(http.MethodGet)
+	r.HandleFunc("/docs/{file}", serveSwaggerFile).Methods(http.MethodGet)
+}
+
+func serveSpec(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(spec)
+}
+
+// redirectToDocs adds the trailing slash, so that the page loads the files of the Swagger UI next to it
+func redirectToDocs(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, path.Base(r.URL.Path)+"/", http.StatusMovedPermanently)
+}
+
+func serveSwaggerIndex(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(swaggerIndex)
+}
+
+// serveSwaggerFile serves the files of the Swagger UI distribution, e.g. swagger-ui-bundle.js
+func serveSwaggerFile(w http.ResponseWriter, r *http.Request) {
+	http.ServeFileFS(w, r, swaggerFiles.FS, mux.Vars(r)["file"])
+}
@@ -0,0 +1,168 @@
+package openapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSpec_IsUpToDate(t *testing.T) {
+	doc, err := Generate()
+	assert.Nil(t, err)
+
+	data, err := Marshal(doc)
+	assert.Nil(t, err)
+
+	// A change of the operations or of the structs they document must come with a new spec
+	assert.Equal(t, string(data), string(Spec()), "openapi.json is out of date, run go generate ./api/openapi")
+}
+
+func TestSpec_IsValid(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData(Spec())
+	assert.Nil(t, err)
+
+	assert.Nil(t, doc.Validate(context.Background()))
+}
+
+func TestSpec_Operations(t *testing.T) {
+	doc, err := openapi3.NewLoader().LoadFromData(Spec())
+	assert.Nil(t, err)
+
+	// The health check is public
+	health := doc.Paths.Find("/api/health").Get
+	assert.NotNil(t, health)
+	assert.NotNil(t, health.Security)
+	assert.Empty(t, *health.Security)
+
+	create := doc.Paths.Find("/api/v1/transactions").Post
+	assert.NotNil(t, create)
+	assert.Equal(t, "createTransaction", create.OperationID)
+	assert.Len(t, *create.Security, 2)
+	assert.True(t, create.RequestBody.Value.Required)
+	assert.NotNil(t, create.Responses.Status(http.StatusOK))
+	assert.NotNil(t, create.Responses.Status(http.StatusUnprocessableEntity))
+	assert.Nil(t, create.Responses.Default())
+
+	// The validate tags are documented
+	body := create.RequestBody.Value.Content.Get("application/json").Schema.Value
+	assert.Contains(t, body.Required, "account_id")
+	assert.Equal(t, "uuid", body.Properties["account_id"].Value.Format)
+}
+
+func TestApplyValidateTag(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   *openapi3.Schema
+		tag      string
+		required bool
+		check    func(t *testing.T, schema *openapi3.Schema)
+	}{
+		{
+			name:     "required string with a length",
+			schema:   openapi3.NewStringSchema(),
+			tag:      "required,min=2,max=10",
+			required: true,
+			check: func(t *testing.T, schema *openapi3.Schema) {
+				assert.Equal(t, uint64(2), schema.MinLength)
+				assert.Equal(t, uint64(10), *schema.MaxLength)
+			},
+		},
+		{
+			name:   "optional enum",
+			schema: openapi3.NewStringSchema(),
+			tag:    "omitempty,oneof=DAILY WEEKLY",
+			check: func(t *testing.T, schema *openapi3.Schema) {
+				assert.Equal(t, []any{"DAILY", "WEEKLY"}, schema.Enum)
+			},
+		},
+		{
+			name:   "number bounds",
+			schema: openapi3.NewFloat64Schema(),
+			tag:    "gt=0,lte=100",
+			check: func(t *testing.T, schema *openapi3.Schema) {
+				assert.Equal(t, float64(0), *schema.Min)
+				assert.True(t, schema.ExclusiveMin)
+				assert.Equal(t, float64(100), *schema.Max)
+				assert.False(t, schema.ExclusiveMax)
+			},
+		},
+		{
+			name:     "dive into the items",
+			schema:   openapi3.NewArraySchema().WithItems(openapi3.NewStringSchema().WithNullable()),
+			tag:      "required,max=5,dive,required,uuid",
+			required: true,
+			check: func(t *testing.T, schema *openapi3.Schema) {
+				assert.Equal(t, uint64(5), *schema.MaxItems)
+				assert.False(t, schema.Items.Value.Nullable)
+				assert.Equal(t, "uuid", schema.Items.Value.Format)
+			},
+		},
+		{
+			name:   "the keys of the maps are skipped",
+			schema: openapi3.NewObjectSchema().WithAdditionalProperties(openapi3.NewInt64Schema()),
+			tag:    "dive,keys,oneof=A B,endkeys,min=1",
+			check: func(t *testing.T, schema *openapi3.Schema) {
+				values := schema.AdditionalProperties.Schema.Value
+				assert.Empty(t, values.Enum)
+				assert.Equal(t, float64(1), *values.Min)
+			},
+		},
+		{
+			name:   "pattern",
+			schema: openapi3.NewStringSchema(),
+			tag:    "omitempty,e164",
+			check: func(t *testing.T, schema *openapi3.Schema) {
+				assert.Equal(t, tagPatterns["e164"], schema.Pattern)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.required, applyValidateTag(tt.schema, tt.tag))
+			tt.check(t, tt.schema)
+		})
+	}
+}
+
+func TestRoutes(t *testing.T) {
+	r := mux.NewRouter()
+	Routes(r.PathPrefix("/api/").Subrouter())
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+		location    string
+	}{
+		{name: "spec", path: "/api/openapi.json", status: http.StatusOK, contentType: "application/json"},
+		{name: "swagger ui", path: "/api/docs/", status: http.StatusOK, contentType: "text/html; charset=utf-8"},
+		{name: "swagger ui file", path: "/api/docs/swagger-ui-bundle.js", status: http.StatusOK, contentType: "text/javascript; charset=utf-8"},
+		{name: "missing file", path: "/api/docs/missing.js", status: http.StatusNotFound},
+		{name: "trailing slash", path: "/api/docs", status: http.StatusMovedPermanently, location: "/api/docs/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Call the handler
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			// Check the results
+			assert.Equal(t, tt.status, w.Code)
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, w.Header().Get("Content-Type"))
+			}
+
+			if tt.location != "" {
+				assert.Equal(t, tt.location, w.Header().Get("Location"))
+			}
+		})
+	}
+}
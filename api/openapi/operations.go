@@ -0,0 +1,262 @@
+package openapi
+
+import (
+	"net/http"
+
+	"github.com/imjenal/transaction-service/api/v1/accounts"
+	"github.com/imjenal/transaction-service/api/v1/analytics"
+	"github.com/imjenal/transaction-service/api/v1/disputes"
+	"github.com/imjenal/transaction-service/api/v1/schedules"
+	"github.com/imjenal/transaction-service/api/v1/transactions"
+	"github.com/imjenal/transaction-service/api/v1/users"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/health"
+)
+
+// Operation documents a route of api.Routes. The schemas of the query, of the body and of the response are generated
+// from the structs the handler reads and writes
+type Operation struct {
+	Method string
+	// Path is the route template, e.g. /api/v1/accounts/{accountID}. The path params are the IDs the path
+	// validator checks, they are all UUIDs
+	Path    string
+	ID      string
+	Tag     string
+	Summary string
+	// Scope is the scope the API keys and the tokens need, the users only access their own resources.
+	// The routes without a scope don't require a caller
+	Scope auth.Scope
+	// Query is the struct of the query params, read from their `schema` tags
+	Query any
+	// Request is the struct of the JSON body
+	Request any
+	// Response is the data of the response
+	Response any
+	// Errors are the statuses of the errors specific to the operation, e.g. 409 or 422. The statuses of the
+	// authentication, of the validation and of the unexpected errors are added to every operation that can return them
+	Errors []int
+}
+
+// healthResponseData is the data of the health check response
+type healthResponseData struct {
+	Version string `json:"version"`
+}
+
+// Operations are all the operations of the API, in the order of the spec
+var Operations = []Operation{
+	{
+		Method: http.MethodGet, Path: "/api/health", ID: "healthCheck", Tag: "health",
+		Summary:  "Check that the service is up and get its version",
+		Response: &healthResponseData{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/health/live", ID: "healthLive", Tag: "health",
+		Summary:  "Check that the service is up, the liveness probe",
+		Response: &healthResponseData{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/health/ready", ID: "healthReady", Tag: "health",
+		Summary:  "Check that the dependencies of the service are up, the readiness probe",
+		Response: &health.Report{},
+		Errors:   []int{http.StatusServiceUnavailable},
+	},
+
+	// Users
+	{
+		Method: http.MethodPost, Path: "/api/v1/users", ID: "createUser", Tag: "users",
+		Summary: "Create a user", Scope: auth.ScopeUsersWrite,
+		Request: &users.CreateUserRequestData{}, Response: &models.User{},
+		Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/users", ID: "listUsers", Tag: "users",
+		Summary: "List the users, by pages", Scope: auth.ScopeUsersRead,
+		Query: &users.ListUsersQueryParams{}, Response: &users.ListUsersResponseData{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/users/{userID}", ID: "getUser", Tag: "users",
+		Summary: "Fetch a user", Scope: auth.ScopeUsersRead,
+		Response: &models.User{},
+	},
+	{
+		Method: http.MethodPatch, Path: "/api/v1/users/{userID}", ID: "updateUser", Tag: "users",
+		Summary: "Update the fields of a user that are in the request", Scope: auth.ScopeUsersWrite,
+		Request: &users.UpdateUserRequestData{}, Response: &models.User{},
+		Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/users/{userID}/accounts", ID: "getUserAccounts", Tag: "users",
+		Summary: "Fetch the accounts of a user", Scope: auth.ScopeAccountsRead,
+		Response: &users.UserAccountsResponseData{},
+	},
+
+	// Accounts
+	{
+		Method: http.MethodPost, Path: "/api/v1/accounts", ID: "createAccount", Tag: "accounts",
+		Summary: "Create an account for a user", Scope: auth.ScopeAccountsWrite,
+		Request: &accounts.CreateAccountRequestData{}, Response: &models.Account{},
+		Errors: []int{http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/accounts/{accountID}", ID: "getAccount", Tag: "accounts",
+		Summary: "Fetch an account", Scope: auth.ScopeAccountsRead,
+		Response: &models.Account{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/accounts/{accountID}/limits", ID: "getAccountLimits", Tag: "accounts",
+		Summary: "Fetch the spending limits of an account with their usage", Scope: auth.ScopeAccountsRead,
+		Response: &accounts.AccountLimitsResponseData{},
+	},
+	{
+		Method: http.MethodPut, Path: "/api/v1/accounts/{accountID}/limits", ID: "setAccountLimits", Tag: "accounts",
+		Summary: "Replace the spending limits of an account", Scope: auth.ScopeAccountsWrite,
+		Request: &accounts.SetAccountLimitsRequestData{}, Response: &accounts.AccountLimitsResponseData{},
+		Errors: []int{http.StatusUnprocessableEntity},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/accounts/{accountID}/summary", ID: "getAccountSummary", Tag: "accounts",
+		Summary: "Fetch the balance, the debt, the holds and the billing cycle of an account", Scope: auth.ScopeAccountsRead,
+		Response: &accounts.AccountSummaryResponseData{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/accounts/{accountID}/rewards", ID: "getAccountRewards", Tag: "accounts",
+		Summary: "Fetch the cashback and the points of an account", Scope: auth.ScopeAccountsRead,
+		Response: &accounts.AccountRewardsResponseData{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/accounts/{accountID}/block", ID: "blockAccount", Tag: "accounts",
+		Summary: "Block an account, it can still receive credits", Scope: auth.ScopeAdmin,
+		Request: &accounts.UpdateAccountStatusRequestData{}, Response: &models.Account{},
+		Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/accounts/{accountID}/unblock", ID: "unblockAccount", Tag: "accounts",
+		Summary: "Unblock an account", Scope: auth.ScopeAdmin,
+		Request: &accounts.UpdateAccountStatusRequestData{}, Response: &models.Account{},
+		Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/accounts/{accountID}/suspend", ID: "suspendAccount", Tag: "accounts",
+		Summary: "Suspend an account, it can still receive credits", Scope: auth.ScopeAdmin,
+		Request: &accounts.UpdateAccountStatusRequestData{}, Response: &models.Account{},
+		Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/accounts/{accountID}/resume", ID: "resumeAccount", Tag: "accounts",
+		Summary: "Resume a suspended account", Scope: auth.ScopeAdmin,
+		Request: &accounts.UpdateAccountStatusRequestData{}, Response: &models.Account{},
+		Errors: []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/accounts/{accountID}/close", ID: "closeAccount", Tag: "accounts",
+		Summary: "Close an account, it can't transact anymore", Scope: auth.ScopeAdmin,
+		Request: &accounts.UpdateAccountStatusRequestData{}, Response: &models.Account{},
+		Errors: []int{http.StatusConflict},
+	},
+
+	// Transactions
+	{
+		Method: http.MethodPost, Path: "/api/v1/transactions", ID: "createTransaction", Tag: "transactions",
+		Summary: "Create a transaction on an account", Scope: auth.ScopeTransactionsWrite,
+		Request: &transactions.CreateTransactionRequestData{}, Response: &models.CreateTransactionRow{},
+		Errors: []int{http.StatusNotFound, http.StatusUnprocessableEntity},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/transactions", ID: "listTransactions", Tag: "transactions",
+		Summary: "List the transactions, by pages", Scope: auth.ScopeTransactionsRead,
+		Query: &transactions.ListTransactionsQueryParams{}, Response: &transactions.ListTransactionsResponseData{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/transactions/quote", ID: "quoteTransaction", Tag: "transactions",
+		Summary: "Tell what creating a transaction would do, without creating it", Scope: auth.ScopeTransactionsRead,
+		Request: &transactions.CreateTransactionRequestData{}, Response: &transactions.QuoteResponseData{},
+		Errors: []int{http.StatusNotFound, http.StatusUnprocessableEntity},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/transactions/{transactionID}", ID: "getTransaction", Tag: "transactions",
+		Summary: "Fetch a transaction", Scope: auth.ScopeTransactionsRead,
+		Response: &models.GetTransactionDetailsByTransactionIdRow{},
+	},
+	{
+		Method: http.MethodPatch, Path: "/api/v1/transactions/{transactionID}", ID: "updateTransaction", Tag: "transactions",
+		Summary: "Update the tags and the notes of a transaction", Scope: auth.ScopeTransactionsWrite,
+		Request: &transactions.UpdateTransactionRequestData{}, Response: &models.UpdateTransactionAnnotationsRow{},
+	},
+
+	// Scheduled transactions
+	{
+		Method: http.MethodPost, Path: "/api/v1/accounts/{accountID}/scheduled-transactions", ID: "createScheduledTransaction", Tag: "scheduled-transactions",
+		Summary: "Schedule a recurring transaction on an account", Scope: auth.ScopeTransactionsWrite,
+		Request: &schedules.CreateScheduledTransactionRequestData{}, Response: &schedules.ScheduledTransactionResponseData{},
+		Errors: []int{http.StatusUnprocessableEntity},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/accounts/{accountID}/scheduled-transactions", ID: "listScheduledTransactions", Tag: "scheduled-transactions",
+		Summary: "List the scheduled transactions of an account with their upcoming runs", Scope: auth.ScopeTransactionsRead,
+		Query: &schedules.ListScheduledTransactionsQueryParams{}, Response: &schedules.ScheduledTransactionsResponseData{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/accounts/{accountID}/scheduled-transactions/{scheduleID}/pause", ID: "pauseScheduledTransaction", Tag: "scheduled-transactions",
+		Summary: "Pause a scheduled transaction", Scope: auth.ScopeTransactionsWrite,
+		Response: &schedules.ScheduledTransactionResponseData{},
+		Errors:   []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/accounts/{accountID}/scheduled-transactions/{scheduleID}/resume", ID: "resumeScheduledTransaction", Tag: "scheduled-transactions",
+		Summary: "Resume a paused scheduled transaction", Scope: auth.ScopeTransactionsWrite,
+		Response: &schedules.ScheduledTransactionResponseData{},
+		Errors:   []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/accounts/{accountID}/scheduled-transactions/{scheduleID}/cancel", ID: "cancelScheduledTransaction", Tag: "scheduled-transactions",
+		Summary: "Cancel a scheduled transaction", Scope: auth.ScopeTransactionsWrite,
+		Response: &schedules.ScheduledTransactionResponseData{},
+		Errors:   []int{http.StatusConflict},
+	},
+
+	// Disputes
+	{
+		Method: http.MethodPost, Path: "/api/v1/transactions/{transactionID}/disputes", ID: "createDispute", Tag: "disputes",
+		Summary: "Open a dispute on a transaction", Scope: auth.ScopeTransactionsWrite,
+		Request: &disputes.CreateDisputeRequestData{}, Response: &disputes.DisputeResponseData{},
+		Errors: []int{http.StatusConflict, http.StatusUnprocessableEntity},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/disputes/{disputeID}", ID: "getDispute", Tag: "disputes",
+		Summary: "Fetch a dispute with its timeline", Scope: auth.ScopeTransactionsRead,
+		Response: &disputes.DisputeResponseData{},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/disputes/{disputeID}/review", ID: "reviewDispute", Tag: "disputes",
+		Summary: "Move an opened dispute under review", Scope: auth.ScopeAdmin,
+		Response: &disputes.DisputeResponseData{},
+		Errors:   []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/disputes/{disputeID}/win", ID: "winDispute", Tag: "disputes",
+		Summary: "Resolve a dispute in favour of the cardholder", Scope: auth.ScopeAdmin,
+		Response: &disputes.DisputeResponseData{},
+		Errors:   []int{http.StatusConflict},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/disputes/{disputeID}/lose", ID: "loseDispute", Tag: "disputes",
+		Summary: "Resolve a dispute in favour of the merchant", Scope: auth.ScopeAdmin,
+		Response: &disputes.DisputeResponseData{},
+		Errors:   []int{http.StatusConflict},
+	},
+
+	// Analytics
+	{
+		Method: http.MethodGet, Path: "/api/v1/accounts/{accountID}/analytics", ID: "getAccountAnalytics", Tag: "analytics",
+		Summary: "Fetch the spend of an account, grouped by operation type, month or category", Scope: auth.ScopeAccountsRead,
+		Query: &analytics.SpendAnalyticsQueryParams{}, Response: &analytics.SpendAnalyticsResponseData{},
+		Errors: []int{http.StatusUnprocessableEntity},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/admin/analytics", ID: "getPortfolioAnalytics", Tag: "analytics",
+		Summary: "Fetch the spend of all the accounts, grouped by operation type, month or category", Scope: auth.ScopeAdmin,
+		Query: &analytics.SpendAnalyticsQueryParams{}, Response: &analytics.SpendAnalyticsResponseData{},
+		Errors: []int{http.StatusUnprocessableEntity},
+	},
+}
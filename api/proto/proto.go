@@ -0,0 +1,5 @@
+// Package proto has the protobuf definitions of the gRPC API, the Go code is generated in pismo/v1
+package proto
+
+//go:generate buf lint
+//go:generate buf generate
@@ -2,14 +2,17 @@ package accounts
 
 import (
 	"context"
+	"errors"
+	"github.com/imjenal/transaction-service/internal/auth"
 	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
 	"github.com/imjenal/transaction-service/pkg/http/response"
-	"log"
 	"net/http"
 )
 
 type CreateAccountRequestData struct {
-	DocumentNumber string  `json:"document_number" validate:"required"`
+	// DocumentNumber is a CPF or a CNPJ, the punctuation is stripped before it is stored
+	DocumentNumber string  `json:"document_number" validate:"required,document"`
 	CurrentBalance float64 `json:"current_balance" validate:"required,gt=0"`
 	UserId         string  `json:"user_id"  validate:"required,uuid"`
 }
@@ -24,6 +27,11 @@ func (h *Handler) createAccount() http.HandlerFunc {
 			return
 		}
 
+		// The users can only open accounts for themselves
+		if !h.policy.AuthorizeUser(w, r, auth.ScopeAccountsWrite, requestBody.UserId) {
+			return
+		}
+
 		// Validate that the user exists in the database
 		if !h.validateUserExists(ctx, w, requestBody.UserId) {
 			return
@@ -42,8 +50,17 @@ func (h *Handler) createAndRespondAccount(ctx context.Context, w http.ResponseWr
 		UserID:         requestBody.UserId,
 	})
 
+	if errors.Is(err, errAccountAlreadyExists) {
+		logging.FromContext(ctx).Info("createAndRespondAccount: document number is already used by another account", "user_id", requestBody.UserId, "document_number", requestBody.DocumentNumber)
+		h.writer.Conflict(w, &response.APIError{
+			Code:    response.ErrAccountAlreadyExists,
+			Message: errAccountAlreadyExists.Error(),
+		})
+		return
+	}
+
 	if err != nil {
-		log.Printf("createAndRespondAccount: failed to create an account: %v", err)
+		logging.FromContext(ctx).Error("createAndRespondAccount: failed to create an account", "user_id", requestBody.UserId, "error", err)
 		h.writer.Internal(w, &response.APIError{
 			Code:    response.DefaultErrorCode,
 			Message: "Failed to create account.",
@@ -58,7 +75,7 @@ func (h *Handler) createAndRespondAccount(ctx context.Context, w http.ResponseWr
 func (h *Handler) validateUserExists(ctx context.Context, w http.ResponseWriter, userID string) bool {
 	userExists, err := h.repository.userExists(ctx, userID)
 	if err != nil {
-		log.Printf("validateUserExists: failed to check user existence: %v", err)
+		logging.FromContext(ctx).Error("validateUserExists: failed to check user existence", "user_id", userID, "error", err)
 		h.writer.Internal(w, &response.APIError{
 			Code:    response.DefaultErrorCode,
 			Message: "Failed to validate user ID.",
@@ -67,7 +84,7 @@ func (h *Handler) validateUserExists(ctx context.Context, w http.ResponseWriter,
 	}
 
 	if !userExists {
-		log.Printf("validateUserExists: user %s does not exist", userID)
+		logging.FromContext(ctx).Info("validateUserExists: user does not exist", "user_id", userID)
 		h.writer.NotFound(w, &response.APIError{
 			Code:    response.ErrUserNotFound,
 			Message: errUserNotFound.Error(),
@@ -4,24 +4,38 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"github.com/imjenal/transaction-service/internal/auth"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/config"
 	"github.com/imjenal/transaction-service/internal/db/models"
 	"github.com/imjenal/transaction-service/internal/db/models/mock"
 	"github.com/imjenal/transaction-service/pkg/http/request"
 	"github.com/imjenal/transaction-service/pkg/http/response"
 	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgconn"
 	"github.com/stretchr/testify/assert"
 )
 
 const (
 	dummyUserId    = "88e0e837-e7f2-47b1-a08c-3af267c03088"
 	dummyAccountId = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+	// dummyDocumentNumber is a valid CPF
+	dummyDocumentNumber = "529.982.247-25"
 )
 
+// asAdmin authenticates the request as an admin, the authorization of the users is tested separately
+func asAdmin(req *http.Request) *http.Request {
+	return withIdentity(req, &auth.Identity{Subject: "admin", Method: auth.MethodAPIKey, Scopes: []auth.Scope{auth.ScopeAdmin}})
+}
+
+func withIdentity(req *http.Request, identity *auth.Identity) *http.Request {
+	return req.WithContext(auth.NewContext(req.Context(), identity))
+}
+
 func TestCreateAccountHandler_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -30,20 +44,31 @@ func TestCreateAccountHandler_Success(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock responses
 	mockRepo.EXPECT().UserExists(gomock.Any(), dummyUserId).Return(true, nil)
-	mockRepo.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(&models.Account{Uuid: dummyAccountId}, nil)
+	mockRepo.EXPECT().LockDocumentNumber(gomock.Any(), "52998224725").Return(nil)
+	mockRepo.EXPECT().DocumentNumberExists(gomock.Any(), models.DocumentNumberExistsParams{DocumentNumber: "52998224725"}).Return(false, nil)
+	mockRepo.EXPECT().CreateAccount(gomock.Any(), models.CreateAccountParams{
+		DocumentNumber: "52998224725",
+		CurrentBalance: 1000.0,
+		UserID:         dummyUserId,
+	}).Return(&models.Account{Uuid: dummyAccountId}, nil)
+	mockRepo.EXPECT().CreateAccountDocument(gomock.Any(), models.CreateAccountDocumentParams{
+		AccountID:      dummyAccountId,
+		DocumentNumber: "52998224725",
+		ScopeKey:       "",
+	}).Return(nil)
 
 	// Prepare the request
 	requestBody, _ := json.Marshal(CreateAccountRequestData{
-		DocumentNumber: "1234567890",
+		DocumentNumber: dummyDocumentNumber,
 		CurrentBalance: 1000.0,
 		UserId:         dummyUserId,
 	})
 
-	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody)))
 	rr := httptest.NewRecorder()
 
 	// Call the handler
@@ -62,7 +87,7 @@ func TestCreateAccountHandler_ValidationError(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare the invalid request
 	requestBody, _ := json.Marshal(CreateAccountRequestData{
@@ -71,7 +96,7 @@ func TestCreateAccountHandler_ValidationError(t *testing.T) {
 		UserId:         "invalid-user-id",
 	})
 
-	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody)))
 	rr := httptest.NewRecorder()
 
 	// Call the handler
@@ -89,19 +114,19 @@ func TestCreateAccountHandler_UserNotFound(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock response
 	mockRepo.EXPECT().UserExists(gomock.Any(), dummyUserId).Return(false, nil)
 
 	// Prepare the request
 	requestBody, _ := json.Marshal(CreateAccountRequestData{
-		DocumentNumber: "1234567890",
+		DocumentNumber: dummyDocumentNumber,
 		CurrentBalance: 1000.0,
 		UserId:         dummyUserId,
 	})
 
-	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody)))
 	rr := httptest.NewRecorder()
 
 	// Call the handler
@@ -119,20 +144,22 @@ func TestCreateAccountHandler_DBError(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
 
 	// Mock database error
 	mockRepo.EXPECT().UserExists(gomock.Any(), dummyUserId).Return(true, nil)
+	mockRepo.EXPECT().LockDocumentNumber(gomock.Any(), "52998224725").Return(nil)
+	mockRepo.EXPECT().DocumentNumberExists(gomock.Any(), models.DocumentNumberExistsParams{DocumentNumber: "52998224725"}).Return(false, nil)
 	mockRepo.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
 
 	// Prepare the request
 	requestBody, _ := json.Marshal(CreateAccountRequestData{
-		DocumentNumber: "1234567890",
+		DocumentNumber: dummyDocumentNumber,
 		CurrentBalance: 1000.0,
 		UserId:         dummyUserId,
 	})
 
-	req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody))
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody)))
 	rr := httptest.NewRecorder()
 
 	// Call the handler
@@ -142,3 +169,122 @@ func TestCreateAccountHandler_DBError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	assert.Contains(t, rr.Body.String(), "Failed to create account.")
 }
+
+func TestCreateAccountHandler_DuplicateDocument(t *testing.T) {
+	tests := []struct {
+		scope    config.DocumentUniqueness
+		scopeKey string
+	}{
+		{config.DocumentUniquenessGlobal, ""},
+		{config.DocumentUniquenessUser, dummyUserId},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.scope), func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, NewRepository(mockRepo, nil, tt.scope), auth.NewPolicy(mockRepo, writer))
+
+			// Prepare mock responses
+			mockRepo.EXPECT().UserExists(gomock.Any(), dummyUserId).Return(true, nil)
+			mockRepo.EXPECT().LockDocumentNumber(gomock.Any(), "52998224725").Return(nil)
+			mockRepo.EXPECT().DocumentNumberExists(gomock.Any(), models.DocumentNumberExistsParams{
+				DocumentNumber: "52998224725",
+				UserID:         tt.scopeKey,
+			}).Return(false, nil)
+			mockRepo.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(&models.Account{Uuid: dummyAccountId}, nil)
+			mockRepo.EXPECT().CreateAccountDocument(gomock.Any(), models.CreateAccountDocumentParams{
+				AccountID:      dummyAccountId,
+				DocumentNumber: "52998224725",
+				ScopeKey:       tt.scopeKey,
+			}).Return(&pgconn.PgError{Code: "23505"})
+
+			// Prepare the request
+			requestBody, _ := json.Marshal(CreateAccountRequestData{
+				DocumentNumber: dummyDocumentNumber,
+				CurrentBalance: 1000.0,
+				UserId:         dummyUserId,
+			})
+
+			req := asAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody)))
+			rr := httptest.NewRecorder()
+
+			// Call the handler
+			handler.createAccount()(rr, req)
+
+			// Check the results
+			assert.Equal(t, http.StatusConflict, rr.Code)
+			assert.Contains(t, rr.Body.String(), errAccountAlreadyExists.Error())
+		})
+	}
+}
+
+// TestCreateAccountHandler_DuplicateBackfilledDocument covers the documents backfilled with the user as scope key,
+// which the unique index doesn't match in the GLOBAL scope
+func TestCreateAccountHandler_DuplicateBackfilledDocument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, NewRepository(mockRepo, nil, config.DocumentUniquenessGlobal), auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, the account is never created
+	mockRepo.EXPECT().UserExists(gomock.Any(), dummyUserId).Return(true, nil)
+	mockRepo.EXPECT().LockDocumentNumber(gomock.Any(), "52998224725").Return(nil)
+	mockRepo.EXPECT().DocumentNumberExists(gomock.Any(), models.DocumentNumberExistsParams{DocumentNumber: "52998224725"}).Return(true, nil)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateAccountRequestData{
+		DocumentNumber: dummyDocumentNumber,
+		CurrentBalance: 1000.0,
+		UserId:         dummyUserId,
+	})
+
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody)))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createAccount()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), errAccountAlreadyExists.Error())
+}
+
+func TestCreateAccountHandler_ForAnotherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare the request, a user can't open an account for another user
+	requestBody, _ := json.Marshal(CreateAccountRequestData{
+		DocumentNumber: dummyDocumentNumber,
+		CurrentBalance: 1000.0,
+		UserId:         dummyUserId,
+	})
+
+	const otherUserID = "0b8b6c54-4c3e-4a8e-9a3f-6a4b1d3e2f10"
+	req := withIdentity(httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(requestBody)),
+		&auth.Identity{Subject: otherUserID, Method: auth.MethodJWT, UserID: otherUserID})
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createAccount()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":1012`)
+}
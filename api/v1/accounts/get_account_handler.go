@@ -3,10 +3,10 @@ package accounts
 import (
 	"context"
 	"errors"
-	"log"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/logging"
 	"github.com/imjenal/transaction-service/pkg/http/response"
 )
 
@@ -24,7 +24,7 @@ func (h *Handler) getAccountDetails() http.HandlerFunc {
 func (h *Handler) fetchAndRespondAccountDetails(ctx context.Context, w http.ResponseWriter, accountID string) {
 	accountDetails, err := h.repository.getAccountDetails(ctx, accountID)
 	if errors.Is(err, errAccountNotFound) {
-		log.Printf("fetchAndRespondAccountDetails: account %s not found", accountID)
+		logging.FromContext(ctx).Info("fetchAndRespondAccountDetails: account not found", "account_id", accountID)
 		h.writer.NotFound(w, &response.APIError{
 			Code:    response.ErrAccountNotFound,
 			Message: errAccountNotFound.Error(),
@@ -33,7 +33,7 @@ func (h *Handler) fetchAndRespondAccountDetails(ctx context.Context, w http.Resp
 	}
 
 	if err != nil {
-		log.Printf("fetchAndRespondAccountDetails: failed to fetch account details: %v", err)
+		logging.FromContext(ctx).Error("fetchAndRespondAccountDetails: failed to fetch account details", "account_id", accountID, "error", err)
 		h.writer.Internal(w, &response.APIError{
 			Code:    response.DefaultErrorCode,
 			Message: "Failed to fetch account details.",
@@ -2,6 +2,7 @@ package accounts
 
 import (
 	"errors"
+	"github.com/imjenal/transaction-service/internal/auth"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -28,7 +29,7 @@ func TestGetAccountHandler_Success(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock response
 	mockRepo.EXPECT().GetAccountDetailsByUUID(gomock.Any(), dummyAccountID).Return(&models.Account{Uuid: dummyAccountID}, nil)
@@ -56,7 +57,7 @@ func TestGetAccountHandler_AccountNotFound(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock response
 	mockRepo.EXPECT().GetAccountDetailsByUUID(gomock.Any(), dummyAccountID).Return(nil, errAccountNotFound)
@@ -83,7 +84,7 @@ func TestGetAccountHandler_DBError(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock response for database error
 	mockRepo.EXPECT().GetAccountDetailsByUUID(gomock.Any(), dummyAccountID).Return(nil, errors.New("database error"))
@@ -102,3 +103,55 @@ func TestGetAccountHandler_DBError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	assert.Contains(t, rr.Body.String(), "Failed to fetch account details.")
 }
+
+func TestGetAccountHandler_Authorization(t *testing.T) {
+	const otherUserID = "0b8b6c54-4c3e-4a8e-9a3f-6a4b1d3e2f10"
+
+	tests := map[string]struct {
+		identity *auth.Identity
+		owner    string
+		status   int
+	}{
+		"the owner":                    {&auth.Identity{Subject: dummyUserId, Method: auth.MethodJWT, UserID: dummyUserId}, dummyUserId, http.StatusOK},
+		"another user":                 {&auth.Identity{Subject: otherUserID, Method: auth.MethodJWT, UserID: otherUserID}, dummyUserId, http.StatusForbidden},
+		"a partner with accounts:read": {&auth.Identity{Subject: "partner", Method: auth.MethodAPIKey, Scopes: []auth.Scope{auth.ScopeAccountsRead}}, "", http.StatusOK},
+		"a partner without the scope":  {&auth.Identity{Subject: "partner", Method: auth.MethodAPIKey, Scopes: []auth.Scope{auth.ScopeTransactionsWrite}}, "", http.StatusForbidden},
+		"an unauthenticated request":   {nil, "", http.StatusForbidden},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			reader := request.NewReader(writer, validator.New())
+			policy := auth.NewPolicy(mockRepo, writer)
+
+			router := mux.NewRouter()
+			Routes(router.PathPrefix("/accounts").Subrouter(), NewHandler(reader, writer, &Repository{querier: mockRepo}, policy), policy)
+
+			// Prepare mock responses, the owner is only fetched for the users
+			if tt.owner != "" {
+				mockRepo.EXPECT().GetAccountOwner(gomock.Any(), dummyAccountID).Return(tt.owner, nil)
+			}
+
+			if tt.status == http.StatusOK {
+				mockRepo.EXPECT().GetAccountDetailsByUUID(gomock.Any(), dummyAccountID).Return(&models.Account{Uuid: dummyAccountID}, nil)
+			}
+
+			// Prepare the request
+			req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID, nil)
+			if tt.identity != nil {
+				req = withIdentity(req, tt.identity)
+			}
+			rr := httptest.NewRecorder()
+
+			// Call the handler
+			router.ServeHTTP(rr, req)
+
+			// Check the results
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+}
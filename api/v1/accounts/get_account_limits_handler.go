@@ -0,0 +1,104 @@
+package accounts
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/limits"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type (
+	// AccountLimit is a spending limit of the account along with its usage in the current period
+	AccountLimit struct {
+		OperationTypeId int64              `json:"operation_type_id"`
+		Period          models.LimitPeriod `json:"period"`
+		MaxAmount       float64            `json:"max_amount"`
+		UsedAmount      float64            `json:"used_amount"`
+		RemainingAmount float64            `json:"remaining_amount"`
+		PeriodStart     time.Time          `json:"period_start"`
+		ResetsAt        time.Time          `json:"resets_at"`
+	}
+
+	AccountLimitsResponseData struct {
+		AccountId string          `json:"account_id"`
+		Limits    []*AccountLimit `json:"limits"`
+	}
+)
+
+// getAccountLimits handles fetching the spending limits of an account and their usage
+func (h *Handler) getAccountLimits() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		accountID := mux.Vars(r)["accountID"]
+
+		if !h.validateAccountExists(ctx, w, accountID) {
+			return
+		}
+
+		h.fetchAndRespondAccountLimits(ctx, w, accountID)
+	}
+}
+
+// fetchAndRespondAccountLimits fetches the limits with their usage in the current period and responds to the client
+func (h *Handler) fetchAndRespondAccountLimits(ctx context.Context, w http.ResponseWriter, accountID string) {
+	now := time.Now()
+
+	accountLimits, err := h.repository.getLimitsWithUsage(ctx, accountID, now)
+	if err != nil {
+		logging.FromContext(ctx).Error("fetchAndRespondAccountLimits: failed to fetch account limits", "account_id", accountID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to fetch account limits.",
+		})
+		return
+	}
+
+	data := &AccountLimitsResponseData{
+		AccountId: accountID,
+		Limits:    make([]*AccountLimit, 0, len(accountLimits)),
+	}
+
+	for _, limit := range accountLimits {
+		data.Limits = append(data.Limits, &AccountLimit{
+			OperationTypeId: limit.OperationTypeID,
+			Period:          limit.Period,
+			MaxAmount:       limit.MaxAmount,
+			UsedAmount:      limit.UsedAmount,
+			RemainingAmount: math.Max(limit.MaxAmount-limit.UsedAmount, 0),
+			PeriodStart:     limits.PeriodStart(limit.Period, now),
+			ResetsAt:        limits.PeriodEnd(limit.Period, now),
+		})
+	}
+
+	h.writer.Ok(w, data)
+}
+
+// validateAccountExists checks if the account exists in the database
+func (h *Handler) validateAccountExists(ctx context.Context, w http.ResponseWriter, accountID string) bool {
+	accountExists, err := h.repository.accountExists(ctx, accountID)
+	if err != nil {
+		logging.FromContext(ctx).Error("validateAccountExists: failed to check account existence", "account_id", accountID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to validate account ID.",
+		})
+		return false
+	}
+
+	if !accountExists {
+		logging.FromContext(ctx).Info("validateAccountExists: account does not exist", "account_id", accountID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrAccountNotFound,
+			Message: errAccountNotFound.Error(),
+		})
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,116 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAccountLimitsHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(true, nil)
+	mockRepo.EXPECT().GetAccountLimitsWithUsage(gomock.Any(), gomock.Any()).Return([]*models.GetAccountLimitsWithUsageRow{{
+		OperationTypeID: 3,
+		Period:          models.LimitPeriodDAILY,
+		MaxAmount:       500,
+		UsedAmount:      120,
+	}}, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/limits", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.getAccountLimits()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data *AccountLimitsResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Len(t, res.Data.Limits, 1)
+	assert.Equal(t, float64(380), res.Data.Limits[0].RemainingAmount)
+	assert.True(t, res.Data.Limits[0].ResetsAt.After(res.Data.Limits[0].PeriodStart))
+}
+
+func TestGetAccountLimitsHandler_AccountNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock response
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(false, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/limits", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.getAccountLimits()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetAccountLimitsHandler_DBError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses for database error
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(true, nil)
+	mockRepo.EXPECT().GetAccountLimitsWithUsage(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/limits", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.getAccountLimits()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), "Failed to fetch account limits.")
+}
@@ -0,0 +1,70 @@
+package accounts
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type (
+	// CashbackBalance is the cashback accrued by the account. The pending cashback is credited on the next payout
+	CashbackBalance struct {
+		Accrued float64 `json:"accrued"`
+		PaidOut float64 `json:"paid_out"`
+		Pending float64 `json:"pending"`
+	}
+
+	// PointsBalance is the points accrued by the account, net of the reversed transactions
+	PointsBalance struct {
+		Balance float64 `json:"balance"`
+	}
+
+	AccountRewardsResponseData struct {
+		AccountId string          `json:"account_id"`
+		Cashback  CashbackBalance `json:"cashback"`
+		Points    PointsBalance   `json:"points"`
+	}
+)
+
+// getAccountRewards handles fetching the cashback and points balances of an account
+func (h *Handler) getAccountRewards() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		accountID := mux.Vars(r)["accountID"]
+
+		if !h.validateAccountExists(ctx, w, accountID) {
+			return
+		}
+
+		balances, err := h.repository.getRewardsBalance(ctx, accountID)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("getAccountRewards: failed to fetch rewards balance", "account_id", accountID, "error", err)
+			h.writer.Internal(w, &response.APIError{
+				Code:    response.DefaultErrorCode,
+				Message: "Failed to fetch rewards balance.",
+			})
+			return
+		}
+
+		data := &AccountRewardsResponseData{AccountId: accountID}
+
+		for _, balance := range balances {
+			switch balance.Kind {
+			case models.RewardKindCASHBACK:
+				data.Cashback = CashbackBalance{
+					Accrued: balance.Accrued,
+					PaidOut: balance.PaidOut,
+					Pending: rewards.Round(balance.Kind, balance.Accrued-balance.PaidOut),
+				}
+			case models.RewardKindPOINTS:
+				data.Points = PointsBalance{Balance: balance.Accrued}
+			}
+		}
+
+		h.writer.Ok(w, data)
+	}
+}
@@ -0,0 +1,83 @@
+package accounts
+
+import (
+	"encoding/json"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAccountRewardsHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(true, nil)
+	mockRepo.EXPECT().GetRewardsBalance(gomock.Any(), dummyAccountID).Return([]*models.GetRewardsBalanceRow{
+		{Kind: models.RewardKindCASHBACK, Accrued: 12.40, PaidOut: 10.10},
+		{Kind: models.RewardKindPOINTS, Accrued: 350},
+	}, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/rewards", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.getAccountRewards()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data *AccountRewardsResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Equal(t, CashbackBalance{Accrued: 12.40, PaidOut: 10.10, Pending: 2.30}, res.Data.Cashback)
+	assert.Equal(t, float64(350), res.Data.Points.Balance)
+}
+
+func TestGetAccountRewardsHandler_AccountNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock response
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(false, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/rewards", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.getAccountRewards()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,152 @@
+package accounts
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/limits"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type (
+	// DebtByOperationType is the debt of the account left to pay for an operation type
+	DebtByOperationType struct {
+		OperationTypeId int64                  `json:"operation_type_id"`
+		Description     models.TransactionType `json:"description"`
+		Count           int64                  `json:"count"`
+		Amount          float64                `json:"amount"`
+	}
+
+	OutstandingDebt struct {
+		Total           float64                `json:"total"`
+		ByOperationType []*DebtByOperationType `json:"by_operation_type"`
+	}
+
+	// PendingHold is an amount committed but not posted yet
+	PendingHold struct {
+		Count  int64   `json:"count"`
+		Amount float64 `json:"amount"`
+	}
+
+	PendingHolds struct {
+		// Disputes are the disputed amounts in progress without a provisional credit
+		Disputes PendingHold `json:"disputes"`
+		// Scheduled are the next runs of the active schedules due in the billing cycle, debits are negative
+		Scheduled PendingHold `json:"scheduled"`
+	}
+
+	LastTransaction struct {
+		Uuid            string    `json:"uuid"`
+		OperationTypeId int64     `json:"operation_type_id"`
+		Amount          float64   `json:"amount"`
+		Balance         float64   `json:"balance"`
+		EventDate       time.Time `json:"event_date"`
+	}
+
+	// BillingCycle has the counts and totals of the transactions of the current billing cycle, the calendar month in UTC
+	BillingCycle struct {
+		Start   time.Time `json:"start"`
+		End     time.Time `json:"end"`
+		Count   int64     `json:"count"`
+		Debits  float64   `json:"debits"`
+		Credits float64   `json:"credits"`
+	}
+
+	AccountSummaryResponseData struct {
+		AccountId       string               `json:"account_id"`
+		Status          models.AccountStatus `json:"status"`
+		OutstandingDebt OutstandingDebt      `json:"outstanding_debt"`
+		// UnusedCredit is the credit left after paying the debts, it pays the next debts
+		UnusedCredit    float64          `json:"unused_credit"`
+		AvailableLimits []*AccountLimit  `json:"available_limits"`
+		PendingHolds    PendingHolds     `json:"pending_holds"`
+		LastTransaction *LastTransaction `json:"last_transaction"`
+		BillingCycle    BillingCycle     `json:"billing_cycle"`
+	}
+)
+
+// getAccountSummary handles fetching the summary of an account for a dashboard
+func (h *Handler) getAccountSummary() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID := mux.Vars(r)["accountID"]
+		now := time.Now()
+
+		summary, err := h.repository.getSummary(r.Context(), accountID, now)
+		if errors.Is(err, errAccountNotFound) {
+			logging.FromContext(r.Context()).Info("getAccountSummary: account does not exist", "account_id", accountID)
+			h.writer.NotFound(w, &response.APIError{
+				Code:    response.ErrAccountNotFound,
+				Message: errAccountNotFound.Error(),
+			})
+			return
+		}
+
+		var data *AccountSummaryResponseData
+		if err == nil {
+			data, err = newAccountSummary(summary, now)
+		}
+
+		if err != nil {
+			logging.FromContext(r.Context()).Error("getAccountSummary: failed to fetch account summary", "account_id", accountID, "error", err)
+			h.writer.Internal(w, &response.APIError{
+				Code:    response.DefaultErrorCode,
+				Message: "Failed to fetch account summary.",
+			})
+			return
+		}
+
+		h.writer.Ok(w, data)
+	}
+}
+
+// newAccountSummary builds the response from the summary row, the lists of the row are aggregated as JSON
+func newAccountSummary(summary *models.GetAccountSummaryRow, now time.Time) (*AccountSummaryResponseData, error) {
+	data := &AccountSummaryResponseData{
+		AccountId:    summary.Uuid,
+		Status:       summary.Status,
+		UnusedCredit: summary.UnusedCredit,
+		PendingHolds: PendingHolds{
+			Disputes:  PendingHold{Count: summary.DisputedCount, Amount: summary.DisputedAmount},
+			Scheduled: PendingHold{Count: summary.ScheduledCount, Amount: summary.ScheduledAmount},
+		},
+		BillingCycle: BillingCycle{
+			Start:   limits.PeriodStart(models.LimitPeriodMONTHLY, now),
+			End:     limits.PeriodEnd(models.LimitPeriodMONTHLY, now),
+			Count:   summary.CycleCount,
+			Debits:  summary.CycleDebits,
+			Credits: summary.CycleCredits,
+		},
+	}
+
+	if err := json.Unmarshal(summary.OutstandingDebt, &data.OutstandingDebt.ByOperationType); err != nil {
+		return nil, fmt.Errorf("newAccountSummary: failed to decode outstanding debt: %w", err)
+	}
+
+	for _, debt := range data.OutstandingDebt.ByOperationType {
+		data.OutstandingDebt.Total += debt.Amount
+	}
+
+	if err := json.Unmarshal(summary.AvailableLimits, &data.AvailableLimits); err != nil {
+		return nil, fmt.Errorf("newAccountSummary: failed to decode available limits: %w", err)
+	}
+
+	for _, limit := range data.AvailableLimits {
+		limit.PeriodStart = limits.PeriodStart(limit.Period, now)
+		limit.ResetsAt = limits.PeriodEnd(limit.Period, now)
+	}
+
+	// The last transaction is null when the account has no transactions
+	if len(summary.LastTransaction) > 0 {
+		if err := json.Unmarshal(summary.LastTransaction, &data.LastTransaction); err != nil {
+			return nil, fmt.Errorf("newAccountSummary: failed to decode last transaction: %w", err)
+		}
+	}
+
+	return data, nil
+}
@@ -0,0 +1,147 @@
+package accounts
+
+import (
+	"encoding/json"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAccountSummaryHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, the lists are aggregated as JSON by the query
+	mockRepo.EXPECT().GetAccountSummary(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.GetAccountSummaryParams) (*models.GetAccountSummaryRow, error) {
+			assert.Equal(t, dummyAccountID, arg.AccountID)
+			assert.True(t, arg.CycleEnd.After(arg.MonthlyStart))
+			return &models.GetAccountSummaryRow{
+				Uuid:   dummyAccountID,
+				Status: models.AccountStatusACTIVE,
+				OutstandingDebt: json.RawMessage(`[
+					{"operation_type_id": 1, "description": "Normal Purchase", "count": 2, "amount": 80.5},
+					{"operation_type_id": 3, "description": "Withdrawal", "count": 1, "amount": 20}
+				]`),
+				UnusedCredit:    0,
+				AvailableLimits: json.RawMessage(`[{"operation_type_id": 1, "period": "DAILY", "max_amount": 500, "used_amount": 120, "remaining_amount": 380}]`),
+				DisputedCount:   1,
+				DisputedAmount:  40,
+				ScheduledCount:  1,
+				ScheduledAmount: -50,
+				LastTransaction: json.RawMessage(`{"uuid": "98a0f8e7-6e28-4d4f-872b-4d28b3d5ee66", "operation_type_id": 1, "amount": -30.5, "balance": -30.5, "event_date": "2024-07-17T15:04:05.123456+00:00"}`),
+				CycleCount:      3,
+				CycleDebits:     100.5,
+				CycleCredits:    0,
+			}, nil
+		})
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/summary", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.getAccountSummary()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data *AccountSummaryResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Equal(t, 100.5, res.Data.OutstandingDebt.Total)
+	assert.Len(t, res.Data.OutstandingDebt.ByOperationType, 2)
+	assert.Equal(t, float64(380), res.Data.AvailableLimits[0].RemainingAmount)
+	assert.True(t, res.Data.AvailableLimits[0].ResetsAt.After(res.Data.AvailableLimits[0].PeriodStart))
+	assert.Equal(t, PendingHold{Count: 1, Amount: -50}, res.Data.PendingHolds.Scheduled)
+	assert.Equal(t, -30.5, res.Data.LastTransaction.Amount)
+	assert.Equal(t, int64(3), res.Data.BillingCycle.Count)
+}
+
+func TestGetAccountSummaryHandler_WithoutTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, the last transaction of an account without transactions is null
+	mockRepo.EXPECT().GetAccountSummary(gomock.Any(), gomock.Any()).Return(&models.GetAccountSummaryRow{
+		Uuid:            dummyAccountID,
+		Status:          models.AccountStatusACTIVE,
+		OutstandingDebt: json.RawMessage(`[]`),
+		AvailableLimits: json.RawMessage(`[]`),
+		LastTransaction: json.RawMessage(`null`),
+	}, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/summary", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.getAccountSummary()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data *AccountSummaryResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Nil(t, res.Data.LastTransaction)
+	assert.Empty(t, res.Data.OutstandingDebt.ByOperationType)
+}
+
+func TestGetAccountSummaryHandler_AccountNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock response
+	mockRepo.EXPECT().GetAccountSummary(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/summary", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.getAccountSummary()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
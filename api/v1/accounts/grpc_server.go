@@ -0,0 +1,138 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+
+	pismov1 "github.com/imjenal/transaction-service/api/proto/pismo/v1"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/rpc"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// GRPCServer serves the AccountService of the gRPC API, with the same repository and checks as the handlers
+type GRPCServer struct {
+	pismov1.UnimplementedAccountServiceServer
+	// reader validates the requests with the tags of the request data of the handlers
+	reader     *request.Reader
+	repository *Repository
+	policy     *auth.Policy
+}
+
+func NewGRPCServer(reader *request.Reader, repository *Repository, policy *auth.Policy) *GRPCServer {
+	return &GRPCServer{
+		reader:     reader,
+		repository: repository,
+		policy:     policy,
+	}
+}
+
+// getAccountRequestData is the account ID of GetAccount, validated like the {accountID} path variable
+type getAccountRequestData struct {
+	AccountId string `json:"account_id" validate:"required,uuid4"`
+}
+
+// CreateAccount creates an account for the user of the request
+func (s *GRPCServer) CreateAccount(ctx context.Context, req *pismov1.CreateAccountRequest) (*pismov1.CreateAccountResponse, error) {
+	requestBody := &CreateAccountRequestData{
+		DocumentNumber: req.GetDocumentNumber(),
+		CurrentBalance: req.GetCurrentBalance(),
+		UserId:         req.GetUserId(),
+	}
+	if apiErr := s.reader.Validate(ctx, requestBody); apiErr != nil {
+		return nil, rpc.InvalidArgument(ctx, apiErr)
+	}
+
+	// The users can only open accounts for themselves
+	if err := s.policy.CheckUser(ctx, auth.ScopeAccountsWrite, requestBody.UserId); err != nil {
+		return nil, rpc.AuthorizationError(ctx, auth.ScopeAccountsWrite, err)
+	}
+
+	userExists, err := s.repository.userExists(ctx, requestBody.UserId)
+	if err != nil {
+		logging.FromContext(ctx).Error("CreateAccount: failed to check user existence", "user_id", requestBody.UserId, "error", err)
+		return nil, rpc.Internal(ctx, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to validate user ID.",
+		})
+	}
+
+	if !userExists {
+		logging.FromContext(ctx).Info("CreateAccount: user does not exist", "user_id", requestBody.UserId)
+		return nil, rpc.NotFound(ctx, &response.APIError{
+			Code:    response.ErrUserNotFound,
+			Message: errUserNotFound.Error(),
+		})
+	}
+
+	account, err := s.repository.createAccount(ctx, models.CreateAccountParams{
+		DocumentNumber: requestBody.DocumentNumber,
+		CurrentBalance: requestBody.CurrentBalance,
+		UserID:         requestBody.UserId,
+	})
+
+	if errors.Is(err, errAccountAlreadyExists) {
+		logging.FromContext(ctx).Info("CreateAccount: document number is already used by another account", "user_id", requestBody.UserId, "document_number", requestBody.DocumentNumber)
+		return nil, rpc.AlreadyExists(ctx, &response.APIError{
+			Code:    response.ErrAccountAlreadyExists,
+			Message: errAccountAlreadyExists.Error(),
+		})
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("CreateAccount: failed to create an account", "user_id", requestBody.UserId, "error", err)
+		return nil, rpc.Internal(ctx, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to create account.",
+		})
+	}
+
+	return &pismov1.CreateAccountResponse{Account: toProtoAccount(account)}, nil
+}
+
+// GetAccount returns the details of the account of the request
+func (s *GRPCServer) GetAccount(ctx context.Context, req *pismov1.GetAccountRequest) (*pismov1.GetAccountResponse, error) {
+	requestData := &getAccountRequestData{AccountId: req.GetAccountId()}
+	if apiErr := s.reader.Validate(ctx, requestData); apiErr != nil {
+		return nil, rpc.InvalidArgument(ctx, apiErr)
+	}
+
+	if err := s.policy.CheckAccount(ctx, auth.ScopeAccountsRead, requestData.AccountId); err != nil {
+		return nil, rpc.AuthorizationError(ctx, auth.ScopeAccountsRead, err, "account_id", requestData.AccountId)
+	}
+
+	account, err := s.repository.getAccountDetails(ctx, requestData.AccountId)
+	if errors.Is(err, errAccountNotFound) {
+		logging.FromContext(ctx).Info("GetAccount: account not found", "account_id", requestData.AccountId)
+		return nil, rpc.NotFound(ctx, &response.APIError{
+			Code:    response.ErrAccountNotFound,
+			Message: errAccountNotFound.Error(),
+		})
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("GetAccount: failed to fetch account details", "account_id", requestData.AccountId, "error", err)
+		return nil, rpc.Internal(ctx, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to fetch account details.",
+		})
+	}
+
+	return &pismov1.GetAccountResponse{Account: toProtoAccount(account)}, nil
+}
+
+func toProtoAccount(account *models.Account) *pismov1.Account {
+	return &pismov1.Account{
+		Id:             account.Uuid,
+		DocumentNumber: account.DocumentNumber,
+		CurrentBalance: account.CurrentBalance,
+		UserId:         account.UserID,
+		Status:         string(account.Status),
+		CreatedAt:      timestamppb.New(account.CreatedAt),
+		UpdatedAt:      timestamppb.New(account.UpdatedAt),
+	}
+}
@@ -0,0 +1,162 @@
+package accounts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	pismov1 "github.com/imjenal/transaction-service/api/proto/pismo/v1"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/internal/rpc"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestGRPCServer(mockRepo *mock.MockQuerier) *GRPCServer {
+	writer := response.NewJSONWriter()
+
+	return NewGRPCServer(request.NewReader(writer, validator.New()), &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+}
+
+// adminContext authenticates the call as an admin, like asAdmin does for the requests
+func adminContext() context.Context {
+	return auth.NewContext(context.Background(), &auth.Identity{Subject: "admin", Method: auth.MethodAPIKey, Scopes: []auth.Scope{auth.ScopeAdmin}})
+}
+
+func TestGRPCServer_CreateAccount_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	server := newTestGRPCServer(mockRepo)
+
+	// Prepare mock responses, the document number is normalized by the validation
+	mockRepo.EXPECT().UserExists(gomock.Any(), dummyUserId).Return(true, nil)
+	mockRepo.EXPECT().LockDocumentNumber(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().DocumentNumberExists(gomock.Any(), gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().CreateAccount(gomock.Any(), models.CreateAccountParams{
+		DocumentNumber: "52998224725",
+		CurrentBalance: 1000.0,
+		UserID:         dummyUserId,
+	}).Return(&models.Account{Uuid: dummyAccountId, DocumentNumber: "52998224725", Status: models.AccountStatusACTIVE}, nil)
+	mockRepo.EXPECT().CreateAccountDocument(gomock.Any(), gomock.Any()).Return(nil)
+
+	// Call the server
+	res, err := server.CreateAccount(adminContext(), &pismov1.CreateAccountRequest{
+		DocumentNumber: dummyDocumentNumber,
+		CurrentBalance: 1000.0,
+		UserId:         dummyUserId,
+	})
+
+	// Check the results
+	assert.Nil(t, err)
+	assert.Equal(t, dummyAccountId, res.GetAccount().GetId())
+	assert.Equal(t, "ACTIVE", res.GetAccount().GetStatus())
+}
+
+func TestGRPCServer_CreateAccount_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestGRPCServer(mock.NewMockQuerier(ctrl))
+
+	// Call the server
+	_, err := server.CreateAccount(adminContext(), &pismov1.CreateAccountRequest{
+		DocumentNumber: "Doc131",
+		CurrentBalance: 100.0,
+		UserId:         "invalid-user-id",
+	})
+
+	// Check the results
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	details := rpc.DetailsFromError(err)
+	assert.Equal(t, int32(response.ValidationFailed), details.GetCode())
+	assert.ElementsMatch(t, []any{"document_number", "user_id"}, details.GetData().AsInterface())
+}
+
+func TestGRPCServer_CreateAccount_AlreadyExists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	server := newTestGRPCServer(mockRepo)
+
+	// Prepare mock responses, the document number is used by another account
+	mockRepo.EXPECT().UserExists(gomock.Any(), dummyUserId).Return(true, nil)
+	mockRepo.EXPECT().LockDocumentNumber(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().DocumentNumberExists(gomock.Any(), gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().CreateAccount(gomock.Any(), gomock.Any()).Return(&models.Account{Uuid: dummyAccountId}, nil)
+	mockRepo.EXPECT().CreateAccountDocument(gomock.Any(), gomock.Any()).Return(&pgconn.PgError{Code: "23505"})
+
+	// Call the server
+	_, err := server.CreateAccount(adminContext(), &pismov1.CreateAccountRequest{
+		DocumentNumber: dummyDocumentNumber,
+		CurrentBalance: 1000.0,
+		UserId:         dummyUserId,
+	})
+
+	// Check the results
+	assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	assert.Equal(t, int32(response.ErrAccountAlreadyExists), rpc.DetailsFromError(err).GetCode())
+}
+
+func TestGRPCServer_CreateAccount_ForAnotherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestGRPCServer(mock.NewMockQuerier(ctrl))
+
+	// The users can only open accounts for themselves
+	ctx := auth.NewContext(context.Background(), &auth.Identity{Subject: "user", Method: auth.MethodJWT, UserID: "another-user"})
+
+	// Call the server
+	_, err := server.CreateAccount(ctx, &pismov1.CreateAccountRequest{
+		DocumentNumber: dummyDocumentNumber,
+		CurrentBalance: 1000.0,
+		UserId:         dummyUserId,
+	})
+
+	// Check the results
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, int32(response.Forbidden), rpc.DetailsFromError(err).GetCode())
+}
+
+func TestGRPCServer_GetAccount_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	server := newTestGRPCServer(mockRepo)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetAccountDetailsByUUID(gomock.Any(), dummyAccountId).Return(nil, pgx.ErrNoRows)
+
+	// Call the server
+	_, err := server.GetAccount(adminContext(), &pismov1.GetAccountRequest{AccountId: dummyAccountId})
+
+	// Check the results
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, int32(response.ErrAccountNotFound), rpc.DetailsFromError(err).GetCode())
+}
+
+func TestGRPCServer_GetAccount_InvalidID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestGRPCServer(mock.NewMockQuerier(ctrl))
+
+	// Call the server
+	_, err := server.GetAccount(adminContext(), &pismov1.GetAccountRequest{AccountId: "not-a-uuid"})
+
+	// Check the results
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, []any{"account_id"}, rpc.DetailsFromError(err).GetData().AsInterface())
+}
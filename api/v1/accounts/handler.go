@@ -1,6 +1,7 @@
 package accounts
 
 import (
+	"github.com/imjenal/transaction-service/internal/auth"
 	"github.com/imjenal/transaction-service/pkg/http/request"
 	"github.com/imjenal/transaction-service/pkg/http/response"
 )
@@ -9,12 +10,15 @@ type Handler struct {
 	reader     *request.Reader
 	writer     *response.JSONWriter
 	repository *Repository
+	// policy authorizes the callers on the accounts of the request bodies
+	policy *auth.Policy
 }
 
-func NewHandler(reader *request.Reader, writer *response.JSONWriter, repository *Repository) *Handler {
+func NewHandler(reader *request.Reader, writer *response.JSONWriter, repository *Repository, policy *auth.Policy) *Handler {
 	return &Handler{
 		reader:     reader,
 		writer:     writer,
 		repository: repository,
+		policy:     policy,
 	}
 }
@@ -2,28 +2,56 @@ package accounts
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
+
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/db"
 	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/lifecycle"
+	"github.com/imjenal/transaction-service/internal/limits"
+	"github.com/imjenal/transaction-service/internal/tracing"
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 )
 
 type Repository struct {
 	querier models.Querier
+	// conn is used to start DB transactions. It is nil in unit tests, where the queries run on the querier directly
+	conn db.TxBeginner
+	// documentUniqueness is the scope in which the document number of an account must be unique
+	documentUniqueness config.DocumentUniqueness
+}
+
+func NewRepository(querier models.Querier, conn db.TxBeginner, documentUniqueness config.DocumentUniqueness) *Repository {
+	return &Repository{querier: querier, conn: conn, documentUniqueness: documentUniqueness}
 }
 
-func NewRepository(querier models.Querier) *Repository {
-	return &Repository{querier: querier}
+// withTx runs fn in a DB transaction. All the queries of the repository passed to fn run in the DB transaction
+func (r *Repository) withTx(ctx context.Context, fn func(repo *Repository) error) error {
+	if r.conn == nil {
+		return fn(r)
+	}
+
+	return db.RunInTx(ctx, r.conn, func(tx pgx.Tx) error {
+		return fn(&Repository{querier: models.New(tx), documentUniqueness: r.documentUniqueness})
+	})
 }
 
 var (
-	errAccountNotFound      = errors.New("ACCOUNT_NOT_FOUND")
-	errAccountAlreadyExists = errors.New("ACCOUNT_ALREADY_EXISTS")
-	errUserNotFound         = errors.New("USER_NOT_FOUND")
+	errAccountNotFound       = errors.New("ACCOUNT_NOT_FOUND")
+	errAccountAlreadyExists  = errors.New("ACCOUNT_ALREADY_EXISTS")
+	errUserNotFound          = errors.New("USER_NOT_FOUND")
+	errOperationTypeNotFound = errors.New("OPERATION_TYPE_NOT_FOUND")
 )
 
 func (r *Repository) getAccountDetails(ctx context.Context, uuid string) (*models.Account, error) {
+	ctx, span := tracing.Start(ctx, "accounts.Repository.getAccountDetails")
+	defer span.End()
+
 	accountDetails, err := r.querier.GetAccountDetailsByUUID(ctx, uuid)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -37,29 +65,232 @@ func (r *Repository) getAccountDetails(ctx context.Context, uuid string) (*model
 	return accountDetails, nil
 }
 
+// createAccount creates the account and reserves its document number, a document number that is already used
+// in the uniqueness scope returns errAccountAlreadyExists
 func (r *Repository) createAccount(ctx context.Context, arg models.CreateAccountParams) (*models.Account, error) {
+	ctx, span := tracing.Start(ctx, "accounts.Repository.createAccount")
+	defer span.End()
 
-	accountDetails, err := r.querier.CreateAccount(ctx, arg)
+	var accountDetails *models.Account
 
-	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, nil
-	}
+	err := r.withTx(ctx, func(repo *Repository) error {
+		// The unique index on the account documents alone misses the documents backfilled with another scope key,
+		// so the document is looked up under the lock before it is reserved
+		if err := repo.querier.LockDocumentNumber(ctx, arg.DocumentNumber); err != nil {
+			return err
+		}
+
+		exists, err := repo.querier.DocumentNumberExists(ctx, models.DocumentNumberExistsParams{
+			DocumentNumber: arg.DocumentNumber,
+			UserID:         r.documentScopeKey(arg.UserID),
+		})
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			return errAccountAlreadyExists
+		}
+
+		accountDetails, err = repo.querier.CreateAccount(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return repo.querier.CreateAccountDocument(ctx, models.CreateAccountDocumentParams{
+			AccountID:      accountDetails.Uuid,
+			DocumentNumber: arg.DocumentNumber,
+			ScopeKey:       r.documentScopeKey(arg.UserID),
+		})
+	})
 
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is a unique violation
 		return nil, errAccountAlreadyExists
 	}
 
+	if errors.Is(err, errAccountAlreadyExists) {
+		return nil, errAccountAlreadyExists
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("repo.createAccount: error: %w", err)
 	}
 	return accountDetails, nil
 }
 
+// documentScopeKey returns the key in which the document number must be unique, see the account_documents table
+func (r *Repository) documentScopeKey(userID string) string {
+	if r.documentUniqueness == config.DocumentUniquenessUser {
+		return userID
+	}
+
+	return ""
+}
+
 func (r *Repository) userExists(ctx context.Context, userID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "accounts.Repository.userExists")
+	defer span.End()
+
 	exists, err := r.querier.UserExists(ctx, userID)
 	if err != nil {
 		return false, fmt.Errorf("repo.createAccount: error checking user existence: %w", err)
 	}
 	return exists, nil
 }
+
+func (r *Repository) accountExists(ctx context.Context, accountID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "accounts.Repository.accountExists")
+	defer span.End()
+
+	exists, err := r.querier.AccountExists(ctx, accountID)
+	if err != nil {
+		return false, fmt.Errorf("repo.accountExists: error checking account existence: %w", err)
+	}
+	return exists, nil
+}
+
+// replaceLimits replaces all the spending limits of the account with the given limits
+func (r *Repository) replaceLimits(ctx context.Context, accountID string, accountLimits []models.CreateAccountLimitParams) error {
+	ctx, span := tracing.Start(ctx, "accounts.Repository.replaceLimits")
+	defer span.End()
+
+	return r.withTx(ctx, func(repo *Repository) error {
+		if err := repo.querier.DeleteAccountLimits(ctx, accountID); err != nil {
+			return fmt.Errorf("repo.replaceLimits: error deleting limits: %w", err)
+		}
+
+		for _, limit := range accountLimits {
+			_, err := repo.querier.CreateAccountLimit(ctx, limit)
+
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is a foreign key violation
+				return errOperationTypeNotFound
+			}
+
+			if err != nil {
+				return fmt.Errorf("repo.replaceLimits: error creating limit: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// getLimitsWithUsage fetches the spending limits of the account along with their usage in the current period
+func (r *Repository) getLimitsWithUsage(ctx context.Context, accountID string, at time.Time) ([]*models.GetAccountLimitsWithUsageRow, error) {
+	ctx, span := tracing.Start(ctx, "accounts.Repository.getLimitsWithUsage")
+	defer span.End()
+
+	accountLimits, err := r.querier.GetAccountLimitsWithUsage(ctx, models.GetAccountLimitsWithUsageParams{
+		AccountID:    accountID,
+		DailyStart:   limits.PeriodStart(models.LimitPeriodDAILY, at),
+		WeeklyStart:  limits.PeriodStart(models.LimitPeriodWEEKLY, at),
+		MonthlyStart: limits.PeriodStart(models.LimitPeriodMONTHLY, at),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo.getLimitsWithUsage: error: %w", err)
+	}
+
+	return accountLimits, nil
+}
+
+// getSummary fetches the summary of the account in a single query. The billing cycle is the calendar month containing at
+func (r *Repository) getSummary(ctx context.Context, accountID string, at time.Time) (*models.GetAccountSummaryRow, error) {
+	ctx, span := tracing.Start(ctx, "accounts.Repository.getSummary")
+	defer span.End()
+
+	summary, err := r.querier.GetAccountSummary(ctx, models.GetAccountSummaryParams{
+		AccountID:    accountID,
+		DailyStart:   limits.PeriodStart(models.LimitPeriodDAILY, at),
+		WeeklyStart:  limits.PeriodStart(models.LimitPeriodWEEKLY, at),
+		MonthlyStart: limits.PeriodStart(models.LimitPeriodMONTHLY, at),
+		CycleEnd:     limits.PeriodEnd(models.LimitPeriodMONTHLY, at),
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errAccountNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("repo.getSummary: error: %w", err)
+	}
+
+	return summary, nil
+}
+
+// getRewardsBalance fetches the rewards accrued and paid out to the account, by kind of reward
+func (r *Repository) getRewardsBalance(ctx context.Context, accountID string) ([]*models.GetRewardsBalanceRow, error) {
+	ctx, span := tracing.Start(ctx, "accounts.Repository.getRewardsBalance")
+	defer span.End()
+
+	balances, err := r.querier.GetRewardsBalance(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("repo.getRewardsBalance: error: %w", err)
+	}
+
+	return balances, nil
+}
+
+// changeStatus moves the account to the given status and records the transition in the account history. When from
+// is set, the account must be in one of these statuses too. The account row is locked so that concurrent transitions
+// are applied one after the other
+func (r *Repository) changeStatus(ctx context.Context, accountID string, from []models.AccountStatus, to models.AccountStatus, reasonCode, note string) (*models.Account, error) {
+	ctx, span := tracing.Start(ctx, "accounts.Repository.changeStatus")
+	defer span.End()
+
+	var account *models.Account
+
+	err := r.withTx(ctx, func(repo *Repository) error {
+		current, err := repo.querier.GetAccountStatusForUpdate(ctx, accountID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errAccountNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("repo.changeStatus: error fetching status: %w", err)
+		}
+
+		if !lifecycle.CanTransition(current, to) || (len(from) > 0 && !slices.Contains(from, current)) {
+			return &statusTransitionError{from: current, to: to}
+		}
+
+		account, err = repo.querier.UpdateAccountStatus(ctx, models.UpdateAccountStatusParams{
+			Uuid:   accountID,
+			Status: to,
+		})
+		if err != nil {
+			return fmt.Errorf("repo.changeStatus: error updating status: %w", err)
+		}
+
+		_, err = repo.querier.CreateAccountStatusHistory(ctx, models.CreateAccountStatusHistoryParams{
+			AccountID:  accountID,
+			FromStatus: current,
+			ToStatus:   to,
+			ReasonCode: reasonCode,
+			Note:       sql.NullString{String: note, Valid: note != ""},
+		})
+		if err != nil {
+			return fmt.Errorf("repo.changeStatus: error recording status history: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// statusTransitionError is returned when the account state machine doesn't allow the transition
+type statusTransitionError struct {
+	from, to models.AccountStatus
+}
+
+func (e *statusTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s to %s", lifecycle.ErrInvalidTransition, e.from, e.to)
+}
+
+func (e *statusTransitionError) Unwrap() error {
+	return lifecycle.ErrInvalidTransition
+}
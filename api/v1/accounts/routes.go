@@ -4,9 +4,21 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/auth"
 )
 
-func Routes(r *mux.Router, h *Handler) {
-	r.HandleFunc("/{accountID}", h.getAccountDetails()).Methods(http.MethodGet)
+func Routes(r *mux.Router, h *Handler, p *auth.Policy) {
+	r.HandleFunc("/{accountID}", p.Account(auth.ScopeAccountsRead, h.getAccountDetails())).Methods(http.MethodGet)
 	r.HandleFunc("", h.createAccount()).Methods(http.MethodPost)
+	r.HandleFunc("/{accountID}/limits", p.Account(auth.ScopeAccountsRead, h.getAccountLimits())).Methods(http.MethodGet)
+	r.HandleFunc("/{accountID}/limits", p.Account(auth.ScopeAccountsWrite, h.setAccountLimits())).Methods(http.MethodPut)
+	r.HandleFunc("/{accountID}/summary", p.Account(auth.ScopeAccountsRead, h.getAccountSummary())).Methods(http.MethodGet)
+	r.HandleFunc("/{accountID}/rewards", p.Account(auth.ScopeAccountsRead, h.getAccountRewards())).Methods(http.MethodGet)
+
+	// The status transitions are the back office's, a user must not unblock an account blocked for fraud
+	r.HandleFunc("/{accountID}/block", p.Require(auth.ScopeAdmin, h.blockAccount())).Methods(http.MethodPost)
+	r.HandleFunc("/{accountID}/unblock", p.Require(auth.ScopeAdmin, h.unblockAccount())).Methods(http.MethodPost)
+	r.HandleFunc("/{accountID}/suspend", p.Require(auth.ScopeAdmin, h.suspendAccount())).Methods(http.MethodPost)
+	r.HandleFunc("/{accountID}/resume", p.Require(auth.ScopeAdmin, h.resumeAccount())).Methods(http.MethodPost)
+	r.HandleFunc("/{accountID}/close", p.Require(auth.ScopeAdmin, h.closeAccount())).Methods(http.MethodPost)
 }
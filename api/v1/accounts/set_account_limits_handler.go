@@ -0,0 +1,109 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type (
+	SetAccountLimitsRequestData struct {
+		// Limits replaces all the limits of the account. An empty list removes all the limits
+		Limits []*AccountLimitRequestData `json:"limits" validate:"required,max=50,dive,required"`
+	}
+
+	AccountLimitRequestData struct {
+		OperationTypeId int64   `json:"operation_type_id" validate:"required"`
+		Period          string  `json:"period" validate:"required,oneof=DAILY WEEKLY MONTHLY"`
+		MaxAmount       float64 `json:"max_amount" validate:"required,gt=0"`
+	}
+)
+
+// setAccountLimits handles replacing the spending limits of an account
+func (h *Handler) setAccountLimits() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		accountID := mux.Vars(r)["accountID"]
+
+		requestBody := &SetAccountLimitsRequestData{}
+		if ok := h.reader.ReadJSONAndValidate(w, r, requestBody); !ok {
+			return
+		}
+
+		if duplicate := findDuplicateLimit(requestBody.Limits); duplicate != "" {
+			h.writer.UnprocessableEntity(w, response.NewError(
+				response.ValidationFailed,
+				"Invalid data received for request",
+				"Send only one limit per operation type and period",
+				map[string]string{"duplicate": duplicate},
+			))
+			return
+		}
+
+		if !h.validateAccountExists(ctx, w, accountID) {
+			return
+		}
+
+		if !h.replaceAccountLimits(ctx, w, accountID, requestBody) {
+			return
+		}
+
+		h.fetchAndRespondAccountLimits(ctx, w, accountID)
+	}
+}
+
+// replaceAccountLimits replaces the limits of the account in the database
+func (h *Handler) replaceAccountLimits(ctx context.Context, w http.ResponseWriter, accountID string, requestBody *SetAccountLimitsRequestData) bool {
+	accountLimits := make([]models.CreateAccountLimitParams, 0, len(requestBody.Limits))
+	for _, limit := range requestBody.Limits {
+		accountLimits = append(accountLimits, models.CreateAccountLimitParams{
+			AccountID:       accountID,
+			OperationTypeID: limit.OperationTypeId,
+			Period:          models.LimitPeriod(limit.Period),
+			MaxAmount:       limit.MaxAmount,
+		})
+	}
+
+	err := h.repository.replaceLimits(ctx, accountID, accountLimits)
+	if errors.Is(err, errOperationTypeNotFound) {
+		logging.FromContext(ctx).Info("replaceAccountLimits: unknown operation type in limits", "account_id", accountID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrOperationTypeNotFound,
+			Message: errOperationTypeNotFound.Error(),
+		})
+		return false
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("replaceAccountLimits: failed to replace account limits", "account_id", accountID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to update account limits.",
+		})
+		return false
+	}
+
+	return true
+}
+
+// findDuplicateLimit returns the first operation type and period that has more than one limit
+func findDuplicateLimit(accountLimits []*AccountLimitRequestData) string {
+	seen := make(map[string]bool, len(accountLimits))
+
+	for _, limit := range accountLimits {
+		key := fmt.Sprintf("%d:%s", limit.OperationTypeId, limit.Period)
+		if seen[key] {
+			return key
+		}
+
+		seen[key] = true
+	}
+
+	return ""
+}
@@ -0,0 +1,132 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAccountLimitsHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, the existing limits are replaced
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(true, nil)
+	mockRepo.EXPECT().DeleteAccountLimits(gomock.Any(), dummyAccountID).Return(nil)
+	mockRepo.EXPECT().CreateAccountLimit(gomock.Any(), models.CreateAccountLimitParams{
+		AccountID:       dummyAccountID,
+		OperationTypeID: 3,
+		Period:          models.LimitPeriodDAILY,
+		MaxAmount:       500,
+	}).Return(&models.AccountLimit{}, nil)
+	mockRepo.EXPECT().GetAccountLimitsWithUsage(gomock.Any(), gomock.Any()).Return([]*models.GetAccountLimitsWithUsageRow{{
+		OperationTypeID: 3,
+		Period:          models.LimitPeriodDAILY,
+		MaxAmount:       500,
+	}}, nil)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(SetAccountLimitsRequestData{
+		Limits: []*AccountLimitRequestData{{OperationTypeId: 3, Period: "DAILY", MaxAmount: 500}},
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/accounts/"+dummyAccountID+"/limits", bytes.NewReader(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.setAccountLimits()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"remaining_amount":500`)
+}
+
+func TestSetAccountLimitsHandler_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	tests := map[string][]*AccountLimitRequestData{
+		"invalid period":     {{OperationTypeId: 3, Period: "YEARLY", MaxAmount: 500}},
+		"invalid max amount": {{OperationTypeId: 3, Period: "DAILY", MaxAmount: -1}},
+		"duplicate limits": {
+			{OperationTypeId: 3, Period: "DAILY", MaxAmount: 500},
+			{OperationTypeId: 3, Period: "DAILY", MaxAmount: 100},
+		},
+	}
+
+	for name, accountLimits := range tests {
+		t.Run(name, func(t *testing.T) {
+			requestBody, _ := json.Marshal(SetAccountLimitsRequestData{Limits: accountLimits})
+
+			req := httptest.NewRequest(http.MethodPut, "/accounts/"+dummyAccountID+"/limits", bytes.NewReader(requestBody))
+			rr := httptest.NewRecorder()
+			req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+			handler.setAccountLimits()(rr, req)
+
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		})
+	}
+}
+
+func TestSetAccountLimitsHandler_OperationTypeNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, the operation type foreign key is violated
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(true, nil)
+	mockRepo.EXPECT().DeleteAccountLimits(gomock.Any(), dummyAccountID).Return(nil)
+	mockRepo.EXPECT().CreateAccountLimit(gomock.Any(), gomock.Any()).Return(nil, &pgconn.PgError{Code: "23503"})
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(SetAccountLimitsRequestData{
+		Limits: []*AccountLimitRequestData{{OperationTypeId: 99, Period: "MONTHLY", MaxAmount: 5000}},
+	})
+
+	req := httptest.NewRequest(http.MethodPut, "/accounts/"+dummyAccountID+"/limits", bytes.NewReader(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.setAccountLimits()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "OPERATION_TYPE_NOT_FOUND")
+}
@@ -0,0 +1,97 @@
+package accounts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/lifecycle"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type UpdateAccountStatusRequestData struct {
+	ReasonCode string `json:"reason_code" validate:"required,oneof=CUSTOMER_REQUEST FRAUD_SUSPECTED FRAUD_CONFIRMED LOST_OR_STOLEN COMPLIANCE_REVIEW ISSUE_RESOLVED OTHER"`
+	Note       string `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
+
+// blockAccount handles blocking an account, a blocked account can't be debited but can still receive credits
+func (h *Handler) blockAccount() http.HandlerFunc {
+	return h.updateAccountStatus(models.AccountStatusBLOCKED)
+}
+
+// unblockAccount handles moving a blocked or suspended account back to active
+func (h *Handler) unblockAccount() http.HandlerFunc {
+	return h.updateAccountStatus(models.AccountStatusACTIVE)
+}
+
+// suspendAccount handles suspending an active account, e.g. during a compliance review. Like a blocked account,
+// a suspended account can't be debited but can still receive credits
+func (h *Handler) suspendAccount() http.HandlerFunc {
+	return h.updateAccountStatus(models.AccountStatusSUSPENDED)
+}
+
+// resumeAccount handles moving a suspended account back to active, a blocked account must be unblocked instead
+func (h *Handler) resumeAccount() http.HandlerFunc {
+	return h.updateAccountStatus(models.AccountStatusACTIVE, models.AccountStatusSUSPENDED)
+}
+
+// closeAccount handles closing an account. Closing is final, a closed account can't transact anymore
+func (h *Handler) closeAccount() http.HandlerFunc {
+	return h.updateAccountStatus(models.AccountStatusCLOSED)
+}
+
+// updateAccountStatus handles moving an account to the given status. When from is set, only the accounts in one of
+// these statuses are moved
+func (h *Handler) updateAccountStatus(status models.AccountStatus, from ...models.AccountStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID := mux.Vars(r)["accountID"]
+
+		requestBody := &UpdateAccountStatusRequestData{}
+		if ok := h.reader.ReadJSONAndValidate(w, r, requestBody); !ok {
+			return
+		}
+
+		h.changeAndRespondAccountStatus(r.Context(), w, accountID, from, status, requestBody)
+	}
+}
+
+// changeAndRespondAccountStatus changes the status of the account and responds with the updated account
+func (h *Handler) changeAndRespondAccountStatus(ctx context.Context, w http.ResponseWriter, accountID string, from []models.AccountStatus, status models.AccountStatus, requestBody *UpdateAccountStatusRequestData) {
+	accountDetails, err := h.repository.changeStatus(ctx, accountID, from, status, requestBody.ReasonCode, requestBody.Note)
+	if errors.Is(err, errAccountNotFound) {
+		logging.FromContext(ctx).Info("changeAndRespondAccountStatus: account not found", "account_id", accountID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrAccountNotFound,
+			Message: errAccountNotFound.Error(),
+		})
+		return
+	}
+
+	var transitionErr *statusTransitionError
+	if errors.As(err, &transitionErr) {
+		logging.FromContext(ctx).Info("changeAndRespondAccountStatus: invalid status transition", "account_id", accountID, "error", err)
+		h.writer.Conflict(w, &response.APIError{
+			Code:    response.ErrInvalidAccountStatusTransition,
+			Message: lifecycle.ErrInvalidTransition.Error(),
+			Data: map[string]models.AccountStatus{
+				"current_status":   transitionErr.from,
+				"requested_status": transitionErr.to,
+			},
+		})
+		return
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("changeAndRespondAccountStatus: failed to change account status", "account_id", accountID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to update account status.",
+		})
+		return
+	}
+
+	h.writer.Ok(w, accountDetails)
+}
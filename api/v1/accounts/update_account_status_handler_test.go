@@ -0,0 +1,259 @@
+package accounts
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockAccountHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountID).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().UpdateAccountStatus(gomock.Any(), models.UpdateAccountStatusParams{
+		Uuid:   dummyAccountID,
+		Status: models.AccountStatusBLOCKED,
+	}).Return(&models.Account{Uuid: dummyAccountID, Status: models.AccountStatusBLOCKED}, nil)
+	mockRepo.EXPECT().CreateAccountStatusHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateAccountStatusHistoryParams) (*models.AccountStatusHistory, error) {
+			assert.Equal(t, models.AccountStatusACTIVE, arg.FromStatus)
+			assert.Equal(t, models.AccountStatusBLOCKED, arg.ToStatus)
+			assert.Equal(t, "FRAUD_SUSPECTED", arg.ReasonCode)
+			assert.Equal(t, "card reported by the customer", arg.Note.String)
+			return &models.AccountStatusHistory{}, nil
+		})
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(UpdateAccountStatusRequestData{
+		ReasonCode: "FRAUD_SUSPECTED",
+		Note:       "card reported by the customer",
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/block", bytes.NewReader(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.blockAccount()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), string(models.AccountStatusBLOCKED))
+}
+
+func TestCloseAccountHandler_InvalidTransition(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, a closed account can't be unblocked
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountID).Return(models.AccountStatusCLOSED, nil)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(UpdateAccountStatusRequestData{ReasonCode: "ISSUE_RESOLVED"})
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/unblock", bytes.NewReader(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.unblockAccount()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "INVALID_ACCOUNT_STATUS_TRANSITION")
+}
+
+func TestSuspendAndResumeAccountHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	reader := request.NewReader(writer, validator.New())
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		from    models.AccountStatus
+		to      models.AccountStatus
+	}{
+		{"suspend an active account", handler.suspendAccount(), models.AccountStatusACTIVE, models.AccountStatusSUSPENDED},
+		{"resume a suspended account", handler.resumeAccount(), models.AccountStatusSUSPENDED, models.AccountStatusACTIVE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Prepare mock responses
+			mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountID).Return(tt.from, nil)
+			mockRepo.EXPECT().UpdateAccountStatus(gomock.Any(), models.UpdateAccountStatusParams{
+				Uuid:   dummyAccountID,
+				Status: tt.to,
+			}).Return(&models.Account{Uuid: dummyAccountID, Status: tt.to}, nil)
+			mockRepo.EXPECT().CreateAccountStatusHistory(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ any, arg models.CreateAccountStatusHistoryParams) (*models.AccountStatusHistory, error) {
+					assert.Equal(t, tt.from, arg.FromStatus)
+					assert.Equal(t, tt.to, arg.ToStatus)
+					return &models.AccountStatusHistory{}, nil
+				})
+
+			// Prepare the request
+			requestBody, _ := json.Marshal(UpdateAccountStatusRequestData{ReasonCode: "COMPLIANCE_REVIEW"})
+
+			req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID, bytes.NewReader(requestBody))
+			req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+			rr := httptest.NewRecorder()
+
+			// Call the handler
+			tt.handler(rr, req)
+
+			// Check the results
+			assert.Equal(t, http.StatusOK, rr.Code)
+			assert.Contains(t, rr.Body.String(), string(tt.to))
+		})
+	}
+}
+
+func TestResumeAccountHandler_BlockedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	reader := request.NewReader(writer, validator.New())
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, a blocked account must be unblocked, it isn't resumed
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountID).Return(models.AccountStatusBLOCKED, nil)
+	mockRepo.EXPECT().UpdateAccountStatus(gomock.Any(), gomock.Any()).Times(0)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(UpdateAccountStatusRequestData{ReasonCode: "ISSUE_RESOLVED"})
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/resume", bytes.NewReader(requestBody))
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.resumeAccount()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"current_status":"BLOCKED"`)
+}
+
+func TestCloseAccountHandler_AccountNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock response
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountID).Return(models.AccountStatus(""), pgx.ErrNoRows)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(UpdateAccountStatusRequestData{ReasonCode: "CUSTOMER_REQUEST"})
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/close", bytes.NewReader(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.closeAccount()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestBlockAccountHandler_InvalidReasonCode(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(UpdateAccountStatusRequestData{ReasonCode: "BECAUSE"})
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/block", bytes.NewReader(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.blockAccount()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestUnblockAccount_UserForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	reader := request.NewReader(writer, validator.New())
+	policy := auth.NewPolicy(mockRepo, writer)
+
+	r := mux.NewRouter()
+	Routes(r.PathPrefix("/accounts").Subrouter(), NewHandler(reader, writer, &Repository{querier: mockRepo}, policy), policy)
+
+	// Prepare mock responses, the account isn't read nor updated
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), gomock.Any()).Times(0)
+	mockRepo.EXPECT().UpdateAccountStatus(gomock.Any(), gomock.Any()).Times(0)
+
+	requestBody, _ := json.Marshal(UpdateAccountStatusRequestData{ReasonCode: "CUSTOMER_REQUEST"})
+
+	// The user owns the account and its credentials have no scopes, so they have every user scope on it
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/unblock", bytes.NewReader(requestBody))
+	req = withIdentity(req, &auth.Identity{Subject: dummyUserId, Method: auth.MethodJWT, UserID: dummyUserId})
+	rr := httptest.NewRecorder()
+
+	// Call the server
+	r.ServeHTTP(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -0,0 +1,163 @@
+package analytics
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// defaultRangeMonths is the number of calendar months of the range when from is not set, the current one included
+	defaultRangeMonths = 12
+)
+
+type (
+	SpendAnalyticsQueryParams struct {
+		GroupBy string `schema:"group_by" validate:"required,oneof=operation_type month category"`
+		// From is the first day of the range, it defaults to the first day of the 12th month before to
+		From string `schema:"from" validate:"omitempty,datetime=2006-01-02"`
+		// To is the last day of the range, it defaults to today in UTC
+		To string `schema:"to" validate:"omitempty,datetime=2006-01-02"`
+	}
+
+	SpendGroup struct {
+		// Key is the operation type description, the month as YYYY-MM or the category of the group
+		Key     string  `json:"key"`
+		Count   int64   `json:"count"`
+		Total   float64 `json:"total"`
+		Average float64 `json:"average"`
+		// Accounts is the number of accounts that spent in the group
+		Accounts int64 `json:"accounts"`
+	}
+
+	SpendAnalyticsResponseData struct {
+		// AccountId is not set for the portfolio analytics
+		AccountId string        `json:"account_id,omitempty"`
+		GroupBy   string        `json:"group_by"`
+		From      string        `json:"from"`
+		To        string        `json:"to"`
+		Count     int64         `json:"count"`
+		Total     float64       `json:"total"`
+		Average   float64       `json:"average"`
+		Groups    []*SpendGroup `json:"groups"`
+	}
+)
+
+// getAccountAnalytics handles aggregating the spend of an account
+func (h *Handler) getAccountAnalytics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.respondSpendAnalytics(w, r, mux.Vars(r)["accountID"])
+	}
+}
+
+// getPortfolioAnalytics handles aggregating the spend of all the accounts
+func (h *Handler) getPortfolioAnalytics() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h.respondSpendAnalytics(w, r, "")
+	}
+}
+
+// respondSpendAnalytics reads the date range and the grouping, aggregates the spend and responds to the client.
+// The spend of all the accounts is aggregated when the account is empty
+func (h *Handler) respondSpendAnalytics(w http.ResponseWriter, r *http.Request, accountID string) {
+	queryParams := &SpendAnalyticsQueryParams{}
+	if ok := h.reader.ReadQueryParamsAndValidate(w, r, queryParams); !ok {
+		return
+	}
+
+	from, to := dateRange(queryParams, time.Now())
+	if from.After(to) {
+		h.writer.UnprocessableEntity(w, &response.APIError{
+			Code:    response.ErrInvalidDateRange,
+			Message: "INVALID_DATE_RANGE",
+			Data:    "from must not be after to",
+		})
+		return
+	}
+
+	rows, err := h.repository.getSpend(r.Context(), accountID, queryParams.GroupBy, from, to)
+	if errors.Is(err, errAccountNotFound) {
+		logging.FromContext(r.Context()).Info("respondSpendAnalytics: account does not exist", "account_id", accountID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrAccountNotFound,
+			Message: errAccountNotFound.Error(),
+		})
+		return
+	}
+
+	if err != nil {
+		logging.FromContext(r.Context()).Error("respondSpendAnalytics: failed to fetch spend analytics", "account_id", accountID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to fetch spend analytics.",
+		})
+		return
+	}
+
+	data := newSpendAnalytics(rows)
+	data.AccountId = accountID
+	data.GroupBy = queryParams.GroupBy
+	data.From = from.Format(dateLayout)
+	data.To = to.Format(dateLayout)
+
+	h.writer.Ok(w, data)
+}
+
+// dateRange returns the days of the range of the query, the dates were validated by the reader
+func dateRange(queryParams *SpendAnalyticsQueryParams, now time.Time) (time.Time, time.Time) {
+	now = now.UTC()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if queryParams.To != "" {
+		to, _ = time.Parse(dateLayout, queryParams.To)
+	}
+
+	from := time.Date(to.Year(), to.Month()-(defaultRangeMonths-1), 1, 0, 0, 0, 0, time.UTC)
+	if queryParams.From != "" {
+		from, _ = time.Parse(dateLayout, queryParams.From)
+	}
+
+	return from, to
+}
+
+// newSpendAnalytics builds the groups and the totals of the spend from the aggregated rollups
+func newSpendAnalytics(rows []*models.GetSpendAnalyticsRow) *SpendAnalyticsResponseData {
+	data := &SpendAnalyticsResponseData{Groups: make([]*SpendGroup, 0, len(rows))}
+
+	for _, row := range rows {
+		data.Groups = append(data.Groups, &SpendGroup{
+			Key:      row.GroupKey,
+			Count:    row.TxnCount,
+			Total:    roundAmount(row.TotalAmount),
+			Average:  average(row.TotalAmount, row.TxnCount),
+			Accounts: row.AccountCount,
+		})
+
+		data.Count += row.TxnCount
+		data.Total += row.TotalAmount
+	}
+
+	data.Average = average(data.Total, data.Count)
+	data.Total = roundAmount(data.Total)
+
+	return data
+}
+
+func average(total float64, count int64) float64 {
+	if count == 0 {
+		return 0
+	}
+
+	return roundAmount(total / float64(count))
+}
+
+// roundAmount rounds the amount to cents
+func roundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,166 @@
+package analytics
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+const dummyAccountID = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+
+func newTestHandler(t *testing.T) (*Handler, *mock.MockQuerier) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	reader := request.NewReader(writer, validator.New())
+
+	return NewHandler(reader, writer, NewRepository(mockRepo)), mockRepo
+}
+
+func TestGetAccountAnalyticsHandler_Success(t *testing.T) {
+	handler, mockRepo := newTestHandler(t)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(true, nil)
+	mockRepo.EXPECT().GetSpendAnalytics(gomock.Any(), models.GetSpendAnalyticsParams{
+		GroupBy:   "category",
+		AccountID: sql.NullString{String: dummyAccountID, Valid: true},
+		FromDay:   time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC),
+		ToDay:     time.Date(2024, time.July, 31, 0, 0, 0, 0, time.UTC),
+	}).Return([]*models.GetSpendAnalyticsRow{
+		{GroupKey: "GROCERIES", TxnCount: 3, TotalAmount: 100, AccountCount: 1},
+		{GroupKey: "RESTAURANTS", TxnCount: 1, TotalAmount: 45.5, AccountCount: 1},
+	}, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/analytics?group_by=category&from=2024-06-01&to=2024-07-31", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.getAccountAnalytics()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data *SpendAnalyticsResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Equal(t, &SpendAnalyticsResponseData{
+		AccountId: dummyAccountID,
+		GroupBy:   "category",
+		From:      "2024-06-01",
+		To:        "2024-07-31",
+		Count:     4,
+		Total:     145.5,
+		Average:   36.38,
+		Groups: []*SpendGroup{
+			{Key: "GROCERIES", Count: 3, Total: 100, Average: 33.33, Accounts: 1},
+			{Key: "RESTAURANTS", Count: 1, Total: 45.5, Average: 45.5, Accounts: 1},
+		},
+	}, res.Data)
+}
+
+func TestGetAccountAnalyticsHandler_AccountNotFound(t *testing.T) {
+	handler, mockRepo := newTestHandler(t)
+
+	// Prepare mock response
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(false, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/analytics?group_by=month", nil)
+	rr := httptest.NewRecorder()
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.getAccountAnalytics()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
+
+func TestGetPortfolioAnalyticsHandler_Success(t *testing.T) {
+	handler, mockRepo := newTestHandler(t)
+
+	// Prepare mock response, the portfolio analytics aggregate all the accounts
+	mockRepo.EXPECT().GetSpendAnalytics(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.GetSpendAnalyticsParams) ([]*models.GetSpendAnalyticsRow, error) {
+			assert.False(t, arg.AccountID.Valid)
+			assert.Equal(t, "operation_type", arg.GroupBy)
+			return []*models.GetSpendAnalyticsRow{
+				{GroupKey: "NORMAL_PURCHASE", TxnCount: 10, TotalAmount: 250, AccountCount: 4},
+			}, nil
+		})
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/admin/analytics?group_by=operation_type&from=2024-07-01&to=2024-07-31", nil)
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.getPortfolioAnalytics()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data *SpendAnalyticsResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Empty(t, res.Data.AccountId)
+	assert.Equal(t, []*SpendGroup{{Key: "NORMAL_PURCHASE", Count: 10, Total: 250, Average: 25, Accounts: 4}}, res.Data.Groups)
+}
+
+func TestGetPortfolioAnalyticsHandler_InvalidQuery(t *testing.T) {
+	tests := map[string]struct {
+		query  string
+		status int
+	}{
+		"missing group_by":  {"from=2024-07-01", http.StatusUnprocessableEntity},
+		"unknown group_by":  {"group_by=merchant", http.StatusUnprocessableEntity},
+		"invalid date":      {"group_by=month&from=01/07/2024", http.StatusUnprocessableEntity},
+		"from after to":     {"group_by=month&from=2024-08-01&to=2024-07-31", http.StatusUnprocessableEntity},
+		"unknown parameter": {"group_by=month&account=1", http.StatusBadRequest},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			handler, _ := newTestHandler(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/analytics?"+tt.query, nil)
+			rr := httptest.NewRecorder()
+
+			handler.getPortfolioAnalytics()(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+}
+
+func TestDateRange(t *testing.T) {
+	now := time.Date(2024, time.July, 17, 23, 30, 0, 0, time.FixedZone("UTC-3", -3*60*60))
+
+	// Defaults to the 12 calendar months up to today in UTC
+	from, to := dateRange(&SpendAnalyticsQueryParams{}, now)
+	assert.Equal(t, time.Date(2023, time.August, 1, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2024, time.July, 18, 0, 0, 0, 0, time.UTC), to)
+
+	from, _ = dateRange(&SpendAnalyticsQueryParams{To: "2024-02-10"}, now)
+	assert.Equal(t, time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC), from)
+}
@@ -0,0 +1,52 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/tracing"
+)
+
+type Repository struct {
+	querier models.Querier
+}
+
+func NewRepository(querier models.Querier) *Repository {
+	return &Repository{querier: querier}
+}
+
+var errAccountNotFound = errors.New("ACCOUNT_NOT_FOUND")
+
+// getSpend aggregates the spend rollups between the from and to days, both included.
+// The spend of all the accounts is aggregated when the account is empty
+func (r *Repository) getSpend(ctx context.Context, accountID, groupBy string, from, to time.Time) ([]*models.GetSpendAnalyticsRow, error) {
+	ctx, span := tracing.Start(ctx, "analytics.Repository.getSpend")
+	defer span.End()
+
+	if accountID != "" {
+		exists, err := r.querier.AccountExists(ctx, accountID)
+		if err != nil {
+			return nil, fmt.Errorf("repo.getSpend: error checking account existence: %w", err)
+		}
+
+		if !exists {
+			return nil, errAccountNotFound
+		}
+	}
+
+	rows, err := r.querier.GetSpendAnalytics(ctx, models.GetSpendAnalyticsParams{
+		GroupBy:   groupBy,
+		AccountID: sql.NullString{String: accountID, Valid: accountID != ""},
+		FromDay:   from,
+		ToDay:     to,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo.getSpend: error: %w", err)
+	}
+
+	return rows, nil
+}
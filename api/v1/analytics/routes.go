@@ -0,0 +1,18 @@
+package analytics
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/auth"
+)
+
+// Routes adds the routes of the portfolio analytics, across all the accounts
+func Routes(r *mux.Router, h *Handler, p *auth.Policy) {
+	r.HandleFunc("", p.Require(auth.ScopeAdmin, h.getPortfolioAnalytics())).Methods(http.MethodGet)
+}
+
+// AccountRoutes adds the routes of the analytics of an account
+func AccountRoutes(r *mux.Router, h *Handler, p *auth.Policy) {
+	r.HandleFunc("", p.Account(auth.ScopeAccountsRead, h.getAccountAnalytics())).Methods(http.MethodGet)
+}
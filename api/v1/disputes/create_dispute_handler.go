@@ -0,0 +1,100 @@
+package disputes
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type CreateDisputeRequestData struct {
+	// Amount is the disputed amount, it defaults to the amount of the transaction that isn't reversed yet
+	Amount     float64 `json:"amount,omitempty" validate:"omitempty,gt=0"`
+	ReasonCode string  `json:"reason_code" validate:"required,oneof=FRAUD NOT_RECEIVED NOT_AS_DESCRIBED DUPLICATE INCORRECT_AMOUNT CANCELLED OTHER"`
+	Note       string  `json:"note,omitempty" validate:"max=500"`
+	// ProvisionalCredit credits the disputed amount back to the account while the dispute is open
+	ProvisionalCredit bool `json:"provisional_credit,omitempty"`
+}
+
+// createDispute handles opening a dispute on a transaction
+func (h *Handler) createDispute() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transactionID := mux.Vars(r)["transactionID"]
+
+		requestBody := &CreateDisputeRequestData{}
+		if ok := h.reader.ReadJSONAndValidate(w, r, requestBody); !ok {
+			return
+		}
+
+		h.openAndRespondDispute(r.Context(), w, transactionID, requestBody)
+	}
+}
+
+// openAndRespondDispute opens the dispute and responds with the dispute and its timeline
+func (h *Handler) openAndRespondDispute(ctx context.Context, w http.ResponseWriter, transactionID string, requestBody *CreateDisputeRequestData) {
+	dispute, err := h.repository.openDispute(ctx, openDisputeParams{
+		TransactionID:     transactionID,
+		Amount:            requestBody.Amount,
+		ReasonCode:        requestBody.ReasonCode,
+		Note:              requestBody.Note,
+		ProvisionalCredit: requestBody.ProvisionalCredit,
+	}, h.reverse)
+	if err != nil {
+		logging.FromContext(ctx).Error("openAndRespondDispute: failed to open dispute", "transaction_id", transactionID, "error", err)
+		h.writeDisputeError(w, err, "Failed to open dispute.")
+		return
+	}
+
+	h.fetchAndRespondDispute(ctx, w, dispute.Uuid)
+}
+
+// writeDisputeError responds with the error of a dispute operation, message is used for unexpected errors
+func (h *Handler) writeDisputeError(w http.ResponseWriter, err error, message string) {
+	switch {
+	case errors.Is(err, errTransactionNotFound):
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrTransactionNotFound,
+			Message: errTransactionNotFound.Error(),
+		})
+
+	case errors.Is(err, errDisputeNotFound):
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrDisputeNotFound,
+			Message: errDisputeNotFound.Error(),
+		})
+
+	case errors.Is(err, errTransactionNotDisputable):
+		h.writer.UnprocessableEntity(w, &response.APIError{
+			Code:    response.ErrTransactionNotDisputable,
+			Message: errTransactionNotDisputable.Error(),
+		})
+
+	case errors.Is(err, errDisputeAmountExceeded):
+		h.writer.UnprocessableEntity(w, &response.APIError{
+			Code:    response.ErrDisputeAmountExceeded,
+			Message: errDisputeAmountExceeded.Error(),
+		})
+
+	case errors.Is(err, errDisputeAlreadyOpen):
+		h.writer.Conflict(w, &response.APIError{
+			Code:    response.ErrDisputeAlreadyOpen,
+			Message: errDisputeAlreadyOpen.Error(),
+		})
+
+	case errors.Is(err, errInvalidDisputeStatusTransition):
+		h.writer.Conflict(w, &response.APIError{
+			Code:    response.ErrInvalidDisputeStatusTransition,
+			Message: errInvalidDisputeStatusTransition.Error(),
+			Data:    err.Error(),
+		})
+
+	default:
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: message,
+		})
+	}
+}
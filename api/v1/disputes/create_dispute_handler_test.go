@@ -0,0 +1,210 @@
+package disputes
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/risk"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dummyAccountID     = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+	dummyTransactionID = "98a0f8e7-6e28-4d4f-872b-4d28b3d5ee66"
+	dummyDisputeID     = "5b1e0f0a-8c4d-4f3e-9b6a-7d2c1e0f9a44"
+	dummyCreditID      = "d4c3b2a1-0f9e-4d8c-b7a6-958473625140"
+)
+
+var dummyNow = time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+
+func newTestHandler(t *testing.T, mockRepo *mock.MockQuerier) *Handler {
+	t.Helper()
+
+	writer := response.NewJSONWriter()
+	reader := request.NewReader(writer, validator.New())
+
+	engine, err := risk.NewEngine("", risk.NewStore(mockRepo), clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	rewardsEngine, err := rewards.NewEngine("")
+	assert.Nil(t, err)
+
+	return NewHandler(reader, writer, NewRepository(mockRepo, nil), engine, rewardsEngine, clock.Fixed(dummyNow), &config.Transactions{})
+}
+
+func newDisputeRequest(t *testing.T, requestBody *CreateDisputeRequestData) *http.Request {
+	t.Helper()
+
+	body, _ := json.Marshal(requestBody)
+	req := httptest.NewRequest(http.MethodPost, "/transactions/"+dummyTransactionID+"/disputes", bytes.NewReader(body))
+
+	return mux.SetURLVars(req, map[string]string{"transactionID": dummyTransactionID})
+}
+
+func TestCreateDisputeHandler_WithProvisionalCredit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	handler := newTestHandler(t, mockRepo)
+
+	// Prepare mock responses, the transaction is fetched by the dispute and by the reversal
+	debit := &models.GetTransactionForUpdateRow{
+		Uuid:            dummyTransactionID,
+		AccountID:       dummyAccountID,
+		Amount:          -100,
+		OperationTypeID: 1,
+		Balance:         -100,
+	}
+	dispute := &models.Dispute{Uuid: dummyDisputeID, TransactionID: dummyTransactionID, AccountID: dummyAccountID, Amount: 100, Status: models.DisputeStatusOPENED}
+
+	mockRepo.EXPECT().GetTransactionForUpdate(gomock.Any(), dummyTransactionID).Return(debit, nil).Times(2)
+	mockRepo.EXPECT().GetReversedAmount(gomock.Any(), dummyTransactionID).Return(float64(0), nil).Times(2)
+	mockRepo.EXPECT().CreateDispute(gomock.Any(), models.CreateDisputeParams{
+		TransactionID: dummyTransactionID,
+		AccountID:     dummyAccountID,
+		Amount:        100,
+		ReasonCode:    "NOT_RECEIVED",
+	}).Return(dispute, nil)
+	mockRepo.EXPECT().CreateDisputeEvent(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateDisputeEventParams) (*models.DisputeEvent, error) {
+			assert.Equal(t, models.DisputeEventTypeOPENED, arg.Type)
+			assert.Equal(t, "item never arrived", arg.Note.String)
+			return &models.DisputeEvent{}, nil
+		})
+	mockRepo.EXPECT().UpdateTransactionBalances(gomock.Any(), models.UpdateTransactionBalancesParams{Uuid: dummyTransactionID, Balance: 0}).Return(nil)
+	mockRepo.EXPECT().CreateReversalTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateReversalTransactionParams) (*models.CreateReversalTransactionRow, error) {
+			// The provisional credit reverses the disputed debit
+			assert.Equal(t, float64(100), arg.Amount)
+			assert.Equal(t, dummyTransactionID, arg.ReversalOf.String)
+			return &models.CreateReversalTransactionRow{Uuid: dummyCreditID, Amount: arg.Amount}, nil
+		})
+	mockRepo.EXPECT().GetRewardAccrualTotalsByTransactionID(gomock.Any(), dummyTransactionID).Return(nil, nil)
+	mockRepo.EXPECT().SetDisputeProvisionalCredit(gomock.Any(), models.SetDisputeProvisionalCreditParams{
+		Uuid:                           dummyDisputeID,
+		ProvisionalCreditTransactionID: sql.NullString{String: dummyCreditID, Valid: true},
+	}).Return(dispute, nil)
+	mockRepo.EXPECT().CreateDisputeEvent(gomock.Any(), models.CreateDisputeEventParams{
+		DisputeID:     dummyDisputeID,
+		Type:          models.DisputeEventTypePROVISIONALCREDITPOSTED,
+		TransactionID: sql.NullString{String: dummyCreditID, Valid: true},
+	}).Return(&models.DisputeEvent{}, nil)
+	mockRepo.EXPECT().GetDispute(gomock.Any(), dummyDisputeID).Return(dispute, nil)
+	mockRepo.EXPECT().GetDisputeEvents(gomock.Any(), dummyDisputeID).Return([]*models.DisputeEvent{
+		{Type: models.DisputeEventTypeOPENED},
+		{Type: models.DisputeEventTypePROVISIONALCREDITPOSTED},
+	}, nil)
+
+	// Prepare the request, the amount defaults to the whole transaction
+	req := newDisputeRequest(t, &CreateDisputeRequestData{
+		ReasonCode:        "NOT_RECEIVED",
+		Note:              "item never arrived",
+		ProvisionalCredit: true,
+	})
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createDispute()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), string(models.DisputeEventTypePROVISIONALCREDITPOSTED))
+}
+
+func TestCreateDisputeHandler_Rejected(t *testing.T) {
+	tests := []struct {
+		name     string
+		txn      *models.GetTransactionForUpdateRow
+		reversed float64
+		amount   float64
+		code     int
+		errCode  string
+	}{
+		{
+			name:    "credits can't be disputed",
+			txn:     &models.GetTransactionForUpdateRow{Uuid: dummyTransactionID, Amount: 100},
+			code:    http.StatusUnprocessableEntity,
+			errCode: "TRANSACTION_NOT_DISPUTABLE",
+		},
+		{
+			name:    "reversals can't be disputed",
+			txn:     &models.GetTransactionForUpdateRow{Uuid: dummyTransactionID, Amount: -100, ReversalOf: sql.NullString{String: dummyCreditID, Valid: true}},
+			code:    http.StatusUnprocessableEntity,
+			errCode: "TRANSACTION_NOT_DISPUTABLE",
+		},
+		{
+			name:     "the amount can't exceed what is left to reverse",
+			txn:      &models.GetTransactionForUpdateRow{Uuid: dummyTransactionID, Amount: -100},
+			reversed: 60,
+			amount:   50,
+			code:     http.StatusUnprocessableEntity,
+			errCode:  "DISPUTE_AMOUNT_EXCEEDED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			handler := newTestHandler(t, mockRepo)
+
+			// Prepare mock responses
+			mockRepo.EXPECT().GetTransactionForUpdate(gomock.Any(), dummyTransactionID).Return(tt.txn, nil)
+			mockRepo.EXPECT().GetReversedAmount(gomock.Any(), dummyTransactionID).Return(tt.reversed, nil).AnyTimes()
+
+			// Prepare the request
+			req := newDisputeRequest(t, &CreateDisputeRequestData{Amount: tt.amount, ReasonCode: "FRAUD"})
+			rr := httptest.NewRecorder()
+
+			// Call the handler
+			handler.createDispute()(rr, req)
+
+			// Check the results
+			assert.Equal(t, tt.code, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.errCode)
+		})
+	}
+}
+
+func TestCreateDisputeHandler_AlreadyOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	handler := newTestHandler(t, mockRepo)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetTransactionForUpdate(gomock.Any(), dummyTransactionID).Return(&models.GetTransactionForUpdateRow{Uuid: dummyTransactionID, Amount: -100}, nil)
+	mockRepo.EXPECT().GetReversedAmount(gomock.Any(), dummyTransactionID).Return(float64(0), nil)
+	mockRepo.EXPECT().CreateDispute(gomock.Any(), gomock.Any()).Return(nil, &pgconn.PgError{Code: "23505"})
+
+	// Prepare the request
+	req := newDisputeRequest(t, &CreateDisputeRequestData{Amount: 40, ReasonCode: "DUPLICATE"})
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createDispute()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusConflict, rr.Code)
+	assert.Contains(t, rr.Body.String(), "DISPUTE_ALREADY_OPEN")
+}
@@ -0,0 +1,37 @@
+package disputes
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+)
+
+type DisputeResponseData struct {
+	*models.Dispute
+	// Events is the timeline of the dispute, from the oldest to the newest event
+	Events []*models.DisputeEvent `json:"events"`
+}
+
+// getDispute handles fetching a dispute with its timeline
+func (h *Handler) getDispute() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		disputeID := mux.Vars(r)["disputeID"]
+
+		h.fetchAndRespondDispute(r.Context(), w, disputeID)
+	}
+}
+
+// fetchAndRespondDispute fetches the dispute and its timeline and responds to the client
+func (h *Handler) fetchAndRespondDispute(ctx context.Context, w http.ResponseWriter, disputeID string) {
+	dispute, events, err := h.repository.getDispute(ctx, disputeID)
+	if err != nil {
+		logging.FromContext(ctx).Error("fetchAndRespondDispute: failed to fetch dispute", "dispute_id", disputeID, "error", err)
+		h.writeDisputeError(w, err, "Failed to fetch dispute.")
+		return
+	}
+
+	h.writer.Ok(w, &DisputeResponseData{Dispute: dispute, Events: events})
+}
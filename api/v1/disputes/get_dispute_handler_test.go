@@ -0,0 +1,71 @@
+package disputes
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDisputeHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	handler := newTestHandler(t, mockRepo)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetDispute(gomock.Any(), dummyDisputeID).Return(&models.Dispute{Uuid: dummyDisputeID, Status: models.DisputeStatusUNDERREVIEW}, nil)
+	mockRepo.EXPECT().GetDisputeEvents(gomock.Any(), dummyDisputeID).Return([]*models.DisputeEvent{
+		{SerialID: 1, Type: models.DisputeEventTypeOPENED},
+		{SerialID: 2, Type: models.DisputeEventTypeUNDERREVIEW},
+	}, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/disputes/"+dummyDisputeID, nil)
+	req = mux.SetURLVars(req, map[string]string{"disputeID": dummyDisputeID})
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.getDispute()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data DisputeResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Equal(t, models.DisputeStatusUNDERREVIEW, res.Data.Status)
+	assert.Len(t, res.Data.Events, 2)
+}
+
+func TestGetDisputeHandler_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	handler := newTestHandler(t, mockRepo)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetDispute(gomock.Any(), dummyDisputeID).Return(nil, pgx.ErrNoRows)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/disputes/"+dummyDisputeID, nil)
+	req = mux.SetURLVars(req, map[string]string{"disputeID": dummyDisputeID})
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.getDispute()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "DISPUTE_NOT_FOUND")
+}
@@ -0,0 +1,41 @@
+package disputes
+
+import (
+	"context"
+
+	"github.com/imjenal/transaction-service/api/v1/transactions"
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/risk"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type Handler struct {
+	reader             *request.Reader
+	writer             *response.JSONWriter
+	repository         *Repository
+	riskEngine         *risk.Engine
+	rewardsEngine      *rewards.Engine
+	clock              clock.Clock
+	transactionsConfig *config.Transactions
+}
+
+func NewHandler(reader *request.Reader, writer *response.JSONWriter, repository *Repository, riskEngine *risk.Engine, rewardsEngine *rewards.Engine, clock clock.Clock, transactionsConfig *config.Transactions) *Handler {
+	return &Handler{
+		reader:             reader,
+		writer:             writer,
+		repository:         repository,
+		riskEngine:         riskEngine,
+		rewardsEngine:      rewardsEngine,
+		clock:              clock,
+		transactionsConfig: transactionsConfig,
+	}
+}
+
+// reverse posts the reversal through the transactions service, in the DB transaction of the given repository
+func (h *Handler) reverse(ctx context.Context, txns *transactions.Repository, transactionID string, amount float64) (*models.CreateReversalTransactionRow, error) {
+	return transactions.NewService(txns, h.riskEngine, h.rewardsEngine, h.clock, h.transactionsConfig).Reverse(ctx, transactionID, amount)
+}
@@ -0,0 +1,299 @@
+package disputes
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/imjenal/transaction-service/api/v1/transactions"
+	"github.com/imjenal/transaction-service/internal/db"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/tracing"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type Repository struct {
+	querier models.Querier
+	// conn is used to start DB transactions. It is nil in unit tests, where the queries run on the querier directly
+	conn db.TxBeginner
+}
+
+func NewRepository(querier models.Querier, conn db.TxBeginner) *Repository {
+	return &Repository{querier: querier, conn: conn}
+}
+
+// withTx runs fn in a DB transaction. All the queries of the repositories passed to fn run in the DB transaction
+func (r *Repository) withTx(ctx context.Context, fn func(repo *Repository, txns *transactions.Repository) error) error {
+	if r.conn == nil {
+		return fn(r, transactions.NewRepository(r.querier, nil))
+	}
+
+	return db.RunInTx(ctx, r.conn, func(tx pgx.Tx) error {
+		querier := models.New(tx)
+		return fn(&Repository{querier: querier}, transactions.NewRepository(querier, tx))
+	})
+}
+
+var (
+	errTransactionNotFound            = errors.New("TRANSACTION_NOT_FOUND")
+	errTransactionNotDisputable       = errors.New("TRANSACTION_NOT_DISPUTABLE")
+	errDisputeAmountExceeded          = errors.New("DISPUTE_AMOUNT_EXCEEDED")
+	errDisputeAlreadyOpen             = errors.New("DISPUTE_ALREADY_OPEN")
+	errDisputeNotFound                = errors.New("DISPUTE_NOT_FOUND")
+	errInvalidDisputeStatusTransition = errors.New("INVALID_DISPUTE_STATUS_TRANSITION")
+)
+
+// reverseFn posts a reversal of the transaction with the transactions repository
+type reverseFn func(ctx context.Context, txns *transactions.Repository, transactionID string, amount float64) (*models.CreateReversalTransactionRow, error)
+
+// transitions is the dispute state machine. WON and LOST are terminal statuses.
+var transitions = map[models.DisputeStatus][]models.DisputeStatus{
+	models.DisputeStatusOPENED:      {models.DisputeStatusUNDERREVIEW},
+	models.DisputeStatusUNDERREVIEW: {models.DisputeStatusWON, models.DisputeStatusLOST},
+}
+
+func canTransition(from, to models.DisputeStatus) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+type openDisputeParams struct {
+	TransactionID string
+	// Amount is the disputed amount, the whole amount left to reverse is disputed when it is zero
+	Amount            float64
+	ReasonCode        string
+	Note              string
+	ProvisionalCredit bool
+}
+
+// openDispute opens a dispute on a debit and posts the provisional credit when it is requested.
+// The disputed transaction is locked, so the disputed amount is checked against its reversals without races
+func (r *Repository) openDispute(ctx context.Context, arg openDisputeParams, reverse reverseFn) (*models.Dispute, error) {
+	ctx, span := tracing.Start(ctx, "disputes.Repository.openDispute")
+	defer span.End()
+
+	var dispute *models.Dispute
+
+	err := r.withTx(ctx, func(repo *Repository, txns *transactions.Repository) error {
+		txn, err := repo.querier.GetTransactionForUpdate(ctx, arg.TransactionID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errTransactionNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("repo.openDispute: error fetching transaction: %w", err)
+		}
+
+		// Only debits can be disputed, a reversal is never disputed itself
+		if txn.Amount >= 0 || txn.ReversalOf.Valid {
+			return errTransactionNotDisputable
+		}
+
+		reversed, err := repo.querier.GetReversedAmount(ctx, txn.Uuid)
+		if err != nil {
+			return fmt.Errorf("repo.openDispute: error fetching reversed amount: %w", err)
+		}
+
+		disputable := math.Abs(txn.Amount) - reversed
+		if arg.Amount == 0 {
+			arg.Amount = disputable
+		}
+
+		if arg.Amount <= 0 || arg.Amount > disputable {
+			return errDisputeAmountExceeded
+		}
+
+		dispute, err = repo.querier.CreateDispute(ctx, models.CreateDisputeParams{
+			TransactionID: txn.Uuid,
+			AccountID:     txn.AccountID,
+			Amount:        arg.Amount,
+			ReasonCode:    arg.ReasonCode,
+		})
+
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is a unique violation
+			return errDisputeAlreadyOpen
+		}
+
+		if err != nil {
+			return fmt.Errorf("repo.openDispute: error creating dispute: %w", err)
+		}
+
+		if err = repo.addEvent(ctx, dispute.Uuid, models.DisputeEventTypeOPENED, "", arg.Note); err != nil {
+			return err
+		}
+
+		if !arg.ProvisionalCredit {
+			return nil
+		}
+
+		credit, err := reverse(ctx, txns, txn.Uuid, dispute.Amount)
+		if err != nil {
+			return fmt.Errorf("repo.openDispute: error posting provisional credit: %w", err)
+		}
+
+		dispute, err = repo.querier.SetDisputeProvisionalCredit(ctx, models.SetDisputeProvisionalCreditParams{
+			Uuid:                           dispute.Uuid,
+			ProvisionalCreditTransactionID: sql.NullString{String: credit.Uuid, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("repo.openDispute: error saving provisional credit: %w", err)
+		}
+
+		return repo.addEvent(ctx, dispute.Uuid, models.DisputeEventTypePROVISIONALCREDITPOSTED, credit.Uuid, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+// changeStatus locks the dispute and moves it to the new status.
+// Winning a dispute makes the credit final: the provisional credit is kept, or the disputed amount is reversed now.
+// Losing a dispute claws back the provisional credit by reversing it.
+func (r *Repository) changeStatus(ctx context.Context, disputeID string, to models.DisputeStatus, reverse reverseFn) (*models.Dispute, error) {
+	ctx, span := tracing.Start(ctx, "disputes.Repository.changeStatus")
+	defer span.End()
+
+	var dispute *models.Dispute
+
+	err := r.withTx(ctx, func(repo *Repository, txns *transactions.Repository) error {
+		current, err := repo.querier.GetDisputeForUpdate(ctx, disputeID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errDisputeNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("repo.changeStatus: error fetching dispute: %w", err)
+		}
+
+		if !canTransition(current.Status, to) {
+			return fmt.Errorf("%w: from %s to %s", errInvalidDisputeStatusTransition, current.Status, to)
+		}
+
+		switch to {
+		case models.DisputeStatusWON:
+			dispute, err = repo.win(ctx, txns, current, reverse)
+		case models.DisputeStatusLOST:
+			dispute, err = repo.lose(ctx, txns, current, reverse)
+		default:
+			dispute, err = repo.querier.UpdateDisputeStatus(ctx, models.UpdateDisputeStatusParams{Uuid: current.Uuid, Status: to})
+		}
+		if err != nil {
+			return fmt.Errorf("repo.changeStatus: error updating dispute: %w", err)
+		}
+
+		return repo.addEvent(ctx, dispute.Uuid, models.DisputeEventType(to), dispute.ResolutionTransactionID.String, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return dispute, nil
+}
+
+func (r *Repository) win(ctx context.Context, txns *transactions.Repository, dispute *models.Dispute, reverse reverseFn) (*models.Dispute, error) {
+	ctx, span := tracing.Start(ctx, "disputes.Repository.win")
+	defer span.End()
+
+	credit := dispute.ProvisionalCreditTransactionID
+	eventType := models.DisputeEventTypePROVISIONALCREDITFINALIZED
+
+	if !credit.Valid {
+		reversal, err := reverse(ctx, txns, dispute.TransactionID, dispute.Amount)
+		if transactions.IsRejected(err) {
+			return nil, fmt.Errorf("%w: %v", errDisputeAmountExceeded, err)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		credit = sql.NullString{String: reversal.Uuid, Valid: true}
+		eventType = models.DisputeEventTypeCREDITPOSTED
+	}
+
+	if err := r.addEvent(ctx, dispute.Uuid, eventType, credit.String, ""); err != nil {
+		return nil, err
+	}
+
+	return r.querier.ResolveDispute(ctx, models.ResolveDisputeParams{
+		Uuid:                    dispute.Uuid,
+		Status:                  models.DisputeStatusWON,
+		ResolutionTransactionID: credit,
+	})
+}
+
+func (r *Repository) lose(ctx context.Context, txns *transactions.Repository, dispute *models.Dispute, reverse reverseFn) (*models.Dispute, error) {
+	ctx, span := tracing.Start(ctx, "disputes.Repository.lose")
+	defer span.End()
+
+	clawback := sql.NullString{}
+
+	if dispute.ProvisionalCreditTransactionID.Valid {
+		reversal, err := reverse(ctx, txns, dispute.ProvisionalCreditTransactionID.String, dispute.Amount)
+		if err != nil {
+			return nil, err
+		}
+
+		clawback = sql.NullString{String: reversal.Uuid, Valid: true}
+
+		if err = r.addEvent(ctx, dispute.Uuid, models.DisputeEventTypePROVISIONALCREDITREVERSED, reversal.Uuid, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.querier.ResolveDispute(ctx, models.ResolveDisputeParams{
+		Uuid:                    dispute.Uuid,
+		Status:                  models.DisputeStatusLOST,
+		ResolutionTransactionID: clawback,
+	})
+}
+
+// addEvent adds the event to the timeline of the dispute, the transaction and the note are optional
+func (r *Repository) addEvent(ctx context.Context, disputeID string, eventType models.DisputeEventType, transactionID, note string) error {
+	ctx, span := tracing.Start(ctx, "disputes.Repository.addEvent")
+	defer span.End()
+
+	_, err := r.querier.CreateDisputeEvent(ctx, models.CreateDisputeEventParams{
+		DisputeID:     disputeID,
+		Type:          eventType,
+		TransactionID: sql.NullString{String: transactionID, Valid: transactionID != ""},
+		Note:          sql.NullString{String: note, Valid: note != ""},
+	})
+	if err != nil {
+		return fmt.Errorf("repo.addEvent: error adding %s event: %w", eventType, err)
+	}
+
+	return nil
+}
+
+func (r *Repository) getDispute(ctx context.Context, disputeID string) (*models.Dispute, []*models.DisputeEvent, error) {
+	ctx, span := tracing.Start(ctx, "disputes.Repository.getDispute")
+	defer span.End()
+
+	dispute, err := r.querier.GetDispute(ctx, disputeID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, errDisputeNotFound
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("repo.getDispute: error fetching dispute: %w", err)
+	}
+
+	events, err := r.querier.GetDisputeEvents(ctx, disputeID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("repo.getDispute: error fetching events: %w", err)
+	}
+
+	return dispute, events, nil
+}
@@ -0,0 +1,21 @@
+package disputes
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/auth"
+)
+
+// Routes adds the routes of the disputes. The disputes are resolved by the back office, which needs the admin scope
+func Routes(r *mux.Router, h *Handler, p *auth.Policy) {
+	r.HandleFunc("/{disputeID}", p.Dispute(auth.ScopeTransactionsRead, h.getDispute())).Methods(http.MethodGet)
+	r.HandleFunc("/{disputeID}/review", p.Require(auth.ScopeAdmin, h.reviewDispute())).Methods(http.MethodPost)
+	r.HandleFunc("/{disputeID}/win", p.Require(auth.ScopeAdmin, h.winDispute())).Methods(http.MethodPost)
+	r.HandleFunc("/{disputeID}/lose", p.Require(auth.ScopeAdmin, h.loseDispute())).Methods(http.MethodPost)
+}
+
+// TransactionRoutes adds the routes of the disputes of a transaction
+func TransactionRoutes(r *mux.Router, h *Handler, p *auth.Policy) {
+	r.HandleFunc("", p.Transaction(auth.ScopeTransactionsWrite, h.createDispute())).Methods(http.MethodPost)
+}
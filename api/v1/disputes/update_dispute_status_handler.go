@@ -0,0 +1,40 @@
+package disputes
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+)
+
+// reviewDispute handles moving an opened dispute under review
+func (h *Handler) reviewDispute() http.HandlerFunc {
+	return h.updateDisputeStatus(models.DisputeStatusUNDERREVIEW)
+}
+
+// winDispute handles resolving a dispute in favour of the cardholder, the credit of the disputed amount becomes final
+func (h *Handler) winDispute() http.HandlerFunc {
+	return h.updateDisputeStatus(models.DisputeStatusWON)
+}
+
+// loseDispute handles resolving a dispute in favour of the merchant, the provisional credit is clawed back
+func (h *Handler) loseDispute() http.HandlerFunc {
+	return h.updateDisputeStatus(models.DisputeStatusLOST)
+}
+
+// updateDisputeStatus handles moving the dispute to the given status
+func (h *Handler) updateDisputeStatus(to models.DisputeStatus) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		disputeID := mux.Vars(r)["disputeID"]
+
+		dispute, err := h.repository.changeStatus(r.Context(), disputeID, to, h.reverse)
+		if err != nil {
+			logging.FromContext(r.Context()).Error("updateDisputeStatus: failed to move dispute", "dispute_id", disputeID, "status", to, "error", err)
+			h.writeDisputeError(w, err, "Failed to update dispute.")
+			return
+		}
+
+		h.fetchAndRespondDispute(r.Context(), w, dispute.Uuid)
+	}
+}
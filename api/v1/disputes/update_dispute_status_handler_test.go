@@ -0,0 +1,226 @@
+package disputes
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+const dummyReversalID = "7e6d5c4b-3a29-4180-9f8e-7d6c5b4a3921"
+
+func newTestDispute(status models.DisputeStatus, provisionalCreditID string) *models.Dispute {
+	return &models.Dispute{
+		Uuid:                           dummyDisputeID,
+		TransactionID:                  dummyTransactionID,
+		AccountID:                      dummyAccountID,
+		Amount:                         40,
+		Status:                         status,
+		ProvisionalCreditTransactionID: sql.NullString{String: provisionalCreditID, Valid: provisionalCreditID != ""},
+	}
+}
+
+func newStatusRequest() *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/disputes/"+dummyDisputeID, nil)
+	return mux.SetURLVars(req, map[string]string{"disputeID": dummyDisputeID})
+}
+
+// expectEvent expects the event to be added to the timeline of the dispute
+func expectEvent(mockRepo *mock.MockQuerier, eventType models.DisputeEventType, transactionID string) {
+	mockRepo.EXPECT().CreateDisputeEvent(gomock.Any(), models.CreateDisputeEventParams{
+		DisputeID:     dummyDisputeID,
+		Type:          eventType,
+		TransactionID: sql.NullString{String: transactionID, Valid: transactionID != ""},
+	}).Return(&models.DisputeEvent{}, nil)
+}
+
+// expectResponse expects the dispute to be fetched for the response
+func expectResponse(mockRepo *mock.MockQuerier, dispute *models.Dispute) {
+	mockRepo.EXPECT().GetDispute(gomock.Any(), dummyDisputeID).Return(dispute, nil)
+	mockRepo.EXPECT().GetDisputeEvents(gomock.Any(), dummyDisputeID).Return(nil, nil)
+}
+
+func TestReviewDisputeHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	handler := newTestHandler(t, mockRepo)
+
+	// Prepare mock responses
+	reviewed := newTestDispute(models.DisputeStatusUNDERREVIEW, "")
+	mockRepo.EXPECT().GetDisputeForUpdate(gomock.Any(), dummyDisputeID).Return(newTestDispute(models.DisputeStatusOPENED, ""), nil)
+	mockRepo.EXPECT().UpdateDisputeStatus(gomock.Any(), models.UpdateDisputeStatusParams{
+		Uuid:   dummyDisputeID,
+		Status: models.DisputeStatusUNDERREVIEW,
+	}).Return(reviewed, nil)
+	expectEvent(mockRepo, models.DisputeEventTypeUNDERREVIEW, "")
+	expectResponse(mockRepo, reviewed)
+
+	// Call the handler
+	rr := httptest.NewRecorder()
+	handler.reviewDispute()(rr, newStatusRequest())
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestWinDisputeHandler_PostsTheCredit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	handler := newTestHandler(t, mockRepo)
+
+	// Prepare mock responses, there is no provisional credit so the disputed amount is reversed now
+	won := newTestDispute(models.DisputeStatusWON, "")
+	won.ResolutionTransactionID = sql.NullString{String: dummyReversalID, Valid: true}
+
+	mockRepo.EXPECT().GetDisputeForUpdate(gomock.Any(), dummyDisputeID).Return(newTestDispute(models.DisputeStatusUNDERREVIEW, ""), nil)
+	mockRepo.EXPECT().GetTransactionForUpdate(gomock.Any(), dummyTransactionID).Return(&models.GetTransactionForUpdateRow{
+		Uuid:      dummyTransactionID,
+		AccountID: dummyAccountID,
+		Amount:    -100,
+		Balance:   0,
+	}, nil)
+	mockRepo.EXPECT().GetReversedAmount(gomock.Any(), dummyTransactionID).Return(float64(0), nil)
+	mockRepo.EXPECT().GetNegativeBalanceTransactionsByAccountID(gomock.Any(), dummyAccountID).Return(nil, nil)
+	mockRepo.EXPECT().CreateReversalTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateReversalTransactionParams) (*models.CreateReversalTransactionRow, error) {
+			// The debit was already paid, the whole credit is left as balance
+			assert.Equal(t, float64(40), arg.Amount)
+			assert.Equal(t, float64(40), arg.Balance)
+			return &models.CreateReversalTransactionRow{Uuid: dummyReversalID}, nil
+		})
+	mockRepo.EXPECT().GetRewardAccrualTotalsByTransactionID(gomock.Any(), dummyTransactionID).Return(nil, nil)
+	expectEvent(mockRepo, models.DisputeEventTypeCREDITPOSTED, dummyReversalID)
+	mockRepo.EXPECT().ResolveDispute(gomock.Any(), models.ResolveDisputeParams{
+		Uuid:                    dummyDisputeID,
+		Status:                  models.DisputeStatusWON,
+		ResolutionTransactionID: sql.NullString{String: dummyReversalID, Valid: true},
+	}).Return(won, nil)
+	expectEvent(mockRepo, models.DisputeEventTypeWON, dummyReversalID)
+	expectResponse(mockRepo, won)
+
+	// Call the handler
+	rr := httptest.NewRecorder()
+	handler.winDispute()(rr, newStatusRequest())
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestWinDisputeHandler_FinalizesTheProvisionalCredit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	handler := newTestHandler(t, mockRepo)
+
+	// Prepare mock responses, no transaction is posted
+	won := newTestDispute(models.DisputeStatusWON, dummyCreditID)
+	won.ResolutionTransactionID = sql.NullString{String: dummyCreditID, Valid: true}
+
+	mockRepo.EXPECT().GetDisputeForUpdate(gomock.Any(), dummyDisputeID).Return(newTestDispute(models.DisputeStatusUNDERREVIEW, dummyCreditID), nil)
+	expectEvent(mockRepo, models.DisputeEventTypePROVISIONALCREDITFINALIZED, dummyCreditID)
+	mockRepo.EXPECT().ResolveDispute(gomock.Any(), models.ResolveDisputeParams{
+		Uuid:                    dummyDisputeID,
+		Status:                  models.DisputeStatusWON,
+		ResolutionTransactionID: sql.NullString{String: dummyCreditID, Valid: true},
+	}).Return(won, nil)
+	expectEvent(mockRepo, models.DisputeEventTypeWON, dummyCreditID)
+	expectResponse(mockRepo, won)
+
+	// Call the handler
+	rr := httptest.NewRecorder()
+	handler.winDispute()(rr, newStatusRequest())
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestLoseDisputeHandler_ClawsBackTheProvisionalCredit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	handler := newTestHandler(t, mockRepo)
+
+	// Prepare mock responses
+	lost := newTestDispute(models.DisputeStatusLOST, dummyCreditID)
+	lost.ResolutionTransactionID = sql.NullString{String: dummyReversalID, Valid: true}
+
+	mockRepo.EXPECT().GetDisputeForUpdate(gomock.Any(), dummyDisputeID).Return(newTestDispute(models.DisputeStatusUNDERREVIEW, dummyCreditID), nil)
+	mockRepo.EXPECT().GetTransactionForUpdate(gomock.Any(), dummyCreditID).Return(&models.GetTransactionForUpdateRow{
+		Uuid:       dummyCreditID,
+		AccountID:  dummyAccountID,
+		Amount:     40,
+		Balance:    15,
+		ReversalOf: sql.NullString{String: dummyTransactionID, Valid: true},
+	}, nil)
+	mockRepo.EXPECT().GetReversedAmount(gomock.Any(), dummyCreditID).Return(float64(0), nil)
+	mockRepo.EXPECT().UpdateTransactionBalances(gomock.Any(), models.UpdateTransactionBalancesParams{Uuid: dummyCreditID, Balance: 0}).Return(nil)
+	mockRepo.EXPECT().CreateReversalTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateReversalTransactionParams) (*models.CreateReversalTransactionRow, error) {
+			// The unused part of the credit is taken back, the rest is owed again
+			assert.Equal(t, float64(-40), arg.Amount)
+			assert.Equal(t, float64(-25), arg.Balance)
+			assert.Equal(t, dummyCreditID, arg.ReversalOf.String)
+			return &models.CreateReversalTransactionRow{Uuid: dummyReversalID}, nil
+		})
+	mockRepo.EXPECT().GetRewardAccrualTotalsByTransactionID(gomock.Any(), dummyCreditID).Return(nil, nil)
+	expectEvent(mockRepo, models.DisputeEventTypePROVISIONALCREDITREVERSED, dummyReversalID)
+	mockRepo.EXPECT().ResolveDispute(gomock.Any(), models.ResolveDisputeParams{
+		Uuid:                    dummyDisputeID,
+		Status:                  models.DisputeStatusLOST,
+		ResolutionTransactionID: sql.NullString{String: dummyReversalID, Valid: true},
+	}).Return(lost, nil)
+	expectEvent(mockRepo, models.DisputeEventTypeLOST, dummyReversalID)
+	expectResponse(mockRepo, lost)
+
+	// Call the handler
+	rr := httptest.NewRecorder()
+	handler.loseDispute()(rr, newStatusRequest())
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestUpdateDisputeStatusHandler_InvalidTransition(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler func(h *Handler) http.HandlerFunc
+		status  models.DisputeStatus
+	}{
+		{"an opened dispute must be reviewed first", (*Handler).winDispute, models.DisputeStatusOPENED},
+		{"a won dispute is final", (*Handler).loseDispute, models.DisputeStatusWON},
+		{"a lost dispute can't be reviewed again", (*Handler).reviewDispute, models.DisputeStatusLOST},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			handler := newTestHandler(t, mockRepo)
+
+			// Prepare mock responses
+			mockRepo.EXPECT().GetDisputeForUpdate(gomock.Any(), dummyDisputeID).Return(newTestDispute(tt.status, ""), nil)
+
+			// Call the handler
+			rr := httptest.NewRecorder()
+			tt.handler(handler)(rr, newStatusRequest())
+
+			// Check the results
+			assert.Equal(t, http.StatusConflict, rr.Code)
+			assert.Contains(t, rr.Body.String(), "INVALID_DISPUTE_STATUS_TRANSITION")
+		})
+	}
+}
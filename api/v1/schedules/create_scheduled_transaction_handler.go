@@ -0,0 +1,142 @@
+package schedules
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/schedule"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+// defaultUpcomingRuns is the number of upcoming runs in the responses
+const defaultUpcomingRuns = 5
+
+type CreateScheduledTransactionRequestData struct {
+	OperationTypeId int64   `json:"operation_type_id" validate:"required"`
+	Amount          float64 `json:"amount" validate:"required,gt=0"`
+	// RecurrenceType is CRON for a 5 fields cron expression, e.g. "0 9 1 * *", or RRULE for an RFC 5545 rule,
+	// e.g. "FREQ=MONTHLY;BYMONTHDAY=1". Occurrences are in UTC
+	RecurrenceType models.RecurrenceType `json:"recurrence_type" validate:"required,oneof=CRON RRULE"`
+	Recurrence     string                `json:"recurrence" validate:"required,max=500"`
+	// StartsAt defaults to now
+	StartsAt *time.Time `json:"starts_at,omitempty"`
+	// EndsAt is optional, the schedule never ends without it
+	EndsAt *time.Time `json:"ends_at,omitempty"`
+}
+
+type ScheduledTransactionResponseData struct {
+	*models.ScheduledTransaction
+	UpcomingRuns []time.Time `json:"upcoming_runs"`
+}
+
+// createScheduledTransaction handles creating a scheduled transaction on an account
+func (h *Handler) createScheduledTransaction() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID := mux.Vars(r)["accountID"]
+
+		requestBody := &CreateScheduledTransactionRequestData{}
+		if ok := h.reader.ReadJSONAndValidate(w, r, requestBody); !ok {
+			return
+		}
+
+		if requestBody.StartsAt == nil {
+			now := h.clock.Now()
+			requestBody.StartsAt = &now
+		}
+
+		firstRun, ok := h.validateRecurrence(r.Context(), w, requestBody)
+		if !ok {
+			return
+		}
+
+		h.createAndRespondScheduledTransaction(r.Context(), w, accountID, requestBody, firstRun)
+	}
+}
+
+// validateRecurrence parses the recurrence and finds its first occurrence, the schedule must have at least one occurrence
+func (h *Handler) validateRecurrence(ctx context.Context, w http.ResponseWriter, requestBody *CreateScheduledTransactionRequestData) (time.Time, bool) {
+	if requestBody.EndsAt != nil && !requestBody.EndsAt.After(*requestBody.StartsAt) {
+		h.writer.UnprocessableEntity(w, &response.APIError{
+			Code:    response.ErrInvalidRecurrence,
+			Message: schedule.ErrInvalidRecurrence.Error(),
+			Data:    "ends_at must be after starts_at",
+		})
+		return time.Time{}, false
+	}
+
+	recurrence, err := schedule.Parse(requestBody.RecurrenceType, requestBody.Recurrence, *requestBody.StartsAt)
+	if err != nil {
+		logging.FromContext(ctx).Info("validateRecurrence: invalid recurrence", "error", err)
+		h.writer.UnprocessableEntity(w, &response.APIError{
+			Code:    response.ErrInvalidRecurrence,
+			Message: schedule.ErrInvalidRecurrence.Error(),
+			Data:    errors.Unwrap(err).Error(),
+		})
+		return time.Time{}, false
+	}
+
+	firstRun := schedule.First(recurrence, *requestBody.StartsAt, requestBody.EndsAt)
+	if firstRun.IsZero() {
+		h.writer.UnprocessableEntity(w, &response.APIError{
+			Code:    response.ErrInvalidRecurrence,
+			Message: schedule.ErrInvalidRecurrence.Error(),
+			Data:    "the recurrence has no occurrence between starts_at and ends_at",
+		})
+		return time.Time{}, false
+	}
+
+	return firstRun, true
+}
+
+// createAndRespondScheduledTransaction creates the scheduled transaction and responds with its upcoming runs
+func (h *Handler) createAndRespondScheduledTransaction(ctx context.Context, w http.ResponseWriter, accountID string, requestBody *CreateScheduledTransactionRequestData, firstRun time.Time) {
+	endsAt := sql.NullTime{}
+	if requestBody.EndsAt != nil {
+		endsAt = sql.NullTime{Time: *requestBody.EndsAt, Valid: true}
+	}
+
+	scheduledTxn, err := h.repository.createScheduledTransaction(ctx, models.CreateScheduledTransactionParams{
+		AccountID:       accountID,
+		OperationTypeID: requestBody.OperationTypeId,
+		Amount:          requestBody.Amount,
+		RecurrenceType:  requestBody.RecurrenceType,
+		Recurrence:      requestBody.Recurrence,
+		StartsAt:        *requestBody.StartsAt,
+		EndsAt:          endsAt,
+		NextRunAt:       sql.NullTime{Time: firstRun, Valid: true},
+	})
+	if errors.Is(err, errAccountNotFound) {
+		logging.FromContext(ctx).Info("createAndRespondScheduledTransaction: account not found", "account_id", accountID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrAccountNotFound,
+			Message: errAccountNotFound.Error(),
+		})
+		return
+	}
+
+	if errors.Is(err, errOperationTypeNotFound) {
+		logging.FromContext(ctx).Info("createAndRespondScheduledTransaction: operation type not found", "operation_type_id", requestBody.OperationTypeId)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrOperationTypeNotFound,
+			Message: errOperationTypeNotFound.Error(),
+		})
+		return
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("createAndRespondScheduledTransaction: failed to create scheduled transaction", "account_id", accountID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to create scheduled transaction.",
+		})
+		return
+	}
+
+	h.writer.Ok(w, newScheduledTransactionResponseData(ctx, scheduledTxn, defaultUpcomingRuns))
+}
@@ -0,0 +1,177 @@
+package schedules
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dummyAccountID  = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+	dummyScheduleID = "2f0e8e44-3d57-4a4e-8d0e-4c1f4a6f3a11"
+)
+
+var dummyNow = time.Date(2024, time.July, 15, 12, 0, 0, 0, time.UTC)
+
+func TestCreateScheduledTransactionHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, NewRepository(mockRepo, nil), clock.Fixed(dummyNow))
+
+	// Prepare mock responses
+	firstRun := time.Date(2024, time.August, 1, 9, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().CreateScheduledTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateScheduledTransactionParams) (*models.ScheduledTransaction, error) {
+			// The schedule starts now and the first run is the next occurrence
+			assert.Equal(t, dummyNow, arg.StartsAt)
+			assert.Equal(t, firstRun, arg.NextRunAt.Time)
+			assert.False(t, arg.EndsAt.Valid)
+			return &models.ScheduledTransaction{
+				Uuid:            dummyScheduleID,
+				AccountID:       arg.AccountID,
+				OperationTypeID: arg.OperationTypeID,
+				Amount:          arg.Amount,
+				RecurrenceType:  arg.RecurrenceType,
+				Recurrence:      arg.Recurrence,
+				StartsAt:        arg.StartsAt,
+				NextRunAt:       arg.NextRunAt,
+				Status:          models.ScheduleStatusACTIVE,
+			}, nil
+		})
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateScheduledTransactionRequestData{
+		OperationTypeId: 1,
+		Amount:          50,
+		RecurrenceType:  models.RecurrenceTypeCRON,
+		Recurrence:      "0 9 1 * *",
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/scheduled-transactions", bytes.NewReader(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.createScheduledTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data ScheduledTransactionResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Len(t, res.Data.UpcomingRuns, defaultUpcomingRuns)
+	assert.Equal(t, firstRun, res.Data.UpcomingRuns[0])
+	assert.Equal(t, time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC), res.Data.UpcomingRuns[1])
+}
+
+func TestCreateScheduledTransactionHandler_InvalidRecurrence(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestBody CreateScheduledTransactionRequestData
+	}{
+		{
+			name:        "invalid cron expression",
+			requestBody: CreateScheduledTransactionRequestData{OperationTypeId: 1, Amount: 50, RecurrenceType: models.RecurrenceTypeCRON, Recurrence: "every monday"},
+		},
+		{
+			name:        "invalid rrule",
+			requestBody: CreateScheduledTransactionRequestData{OperationTypeId: 1, Amount: 50, RecurrenceType: models.RecurrenceTypeRRULE, Recurrence: "FREQ=SOMETIMES"},
+		},
+		{
+			name: "no occurrence before the end",
+			requestBody: CreateScheduledTransactionRequestData{
+				OperationTypeId: 1,
+				Amount:          50,
+				RecurrenceType:  models.RecurrenceTypeCRON,
+				Recurrence:      "0 9 1 * *",
+				EndsAt:          &[]time.Time{dummyNow.Add(24 * time.Hour)}[0],
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, NewRepository(mockRepo, nil), clock.Fixed(dummyNow))
+
+			// Prepare the request
+			requestBody, _ := json.Marshal(tt.requestBody)
+
+			req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/scheduled-transactions", bytes.NewReader(requestBody))
+			rr := httptest.NewRecorder()
+			req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+			// Call the handler
+			handler.createScheduledTransaction()(rr, req)
+
+			// Check the results
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			assert.Contains(t, rr.Body.String(), "INVALID_RECURRENCE")
+		})
+	}
+}
+
+func TestCreateScheduledTransactionHandler_AccountNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, NewRepository(mockRepo, nil), clock.Fixed(dummyNow))
+
+	// Prepare mock responses
+	mockRepo.EXPECT().CreateScheduledTransaction(gomock.Any(), gomock.Any()).Return(nil, &pgconn.PgError{
+		Code:           "23503",
+		ConstraintName: "scheduled_transactions_account_id_fkey",
+	})
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateScheduledTransactionRequestData{
+		OperationTypeId: 1,
+		Amount:          50,
+		RecurrenceType:  models.RecurrenceTypeRRULE,
+		Recurrence:      "FREQ=WEEKLY;BYDAY=MO",
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/scheduled-transactions", bytes.NewReader(requestBody))
+	rr := httptest.NewRecorder()
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.createScheduledTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "ACCOUNT_NOT_FOUND")
+}
@@ -0,0 +1,23 @@
+package schedules
+
+import (
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type Handler struct {
+	reader     *request.Reader
+	writer     *response.JSONWriter
+	repository *Repository
+	clock      clock.Clock
+}
+
+func NewHandler(reader *request.Reader, writer *response.JSONWriter, repository *Repository, clock clock.Clock) *Handler {
+	return &Handler{
+		reader:     reader,
+		writer:     writer,
+		repository: repository,
+		clock:      clock,
+	}
+}
@@ -0,0 +1,109 @@
+package schedules
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/schedule"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type ListScheduledTransactionsQueryParams struct {
+	// Upcoming is the number of upcoming runs of each schedule
+	Upcoming int `schema:"upcoming" validate:"omitempty,min=1,max=50"`
+}
+
+type ScheduledTransactionsResponseData struct {
+	ScheduledTransactions []*ScheduledTransactionResponseData `json:"scheduled_transactions"`
+}
+
+// listScheduledTransactions handles listing the scheduled transactions of an account with their upcoming runs
+func (h *Handler) listScheduledTransactions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID := mux.Vars(r)["accountID"]
+
+		queryParams := &ListScheduledTransactionsQueryParams{}
+		if ok := h.reader.ReadQueryParamsAndValidate(w, r, queryParams); !ok {
+			return
+		}
+
+		if queryParams.Upcoming == 0 {
+			queryParams.Upcoming = defaultUpcomingRuns
+		}
+
+		h.fetchAndRespondScheduledTransactions(r.Context(), w, accountID, queryParams.Upcoming)
+	}
+}
+
+// fetchAndRespondScheduledTransactions fetches the scheduled transactions of the account and responds to the client
+func (h *Handler) fetchAndRespondScheduledTransactions(ctx context.Context, w http.ResponseWriter, accountID string, upcoming int) {
+	accountExists, err := h.repository.accountExists(ctx, accountID)
+	if err != nil {
+		logging.FromContext(ctx).Error("fetchAndRespondScheduledTransactions: failed to check account existence", "account_id", accountID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to validate account ID.",
+		})
+		return
+	}
+
+	if !accountExists {
+		logging.FromContext(ctx).Info("fetchAndRespondScheduledTransactions: account does not exist", "account_id", accountID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrAccountNotFound,
+			Message: errAccountNotFound.Error(),
+		})
+		return
+	}
+
+	scheduledTxns, err := h.repository.getScheduledTransactions(ctx, accountID)
+	if err != nil {
+		logging.FromContext(ctx).Error("fetchAndRespondScheduledTransactions: failed to fetch scheduled transactions", "account_id", accountID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to fetch scheduled transactions.",
+		})
+		return
+	}
+
+	res := &ScheduledTransactionsResponseData{
+		ScheduledTransactions: make([]*ScheduledTransactionResponseData, 0, len(scheduledTxns)),
+	}
+	for _, scheduledTxn := range scheduledTxns {
+		res.ScheduledTransactions = append(res.ScheduledTransactions, newScheduledTransactionResponseData(ctx, scheduledTxn, upcoming))
+	}
+
+	h.writer.Ok(w, res)
+}
+
+// newScheduledTransactionResponseData adds the upcoming runs to the schedule.
+// Paused schedules show the runs they would have once resumed, finished schedules have no upcoming runs
+func newScheduledTransactionResponseData(ctx context.Context, scheduledTxn *models.ScheduledTransaction, upcoming int) *ScheduledTransactionResponseData {
+	res := &ScheduledTransactionResponseData{
+		ScheduledTransaction: scheduledTxn,
+		UpcomingRuns:         []time.Time{},
+	}
+
+	if !scheduledTxn.NextRunAt.Valid {
+		return res
+	}
+
+	if scheduledTxn.Status != models.ScheduleStatusACTIVE && scheduledTxn.Status != models.ScheduleStatusPAUSED {
+		return res
+	}
+
+	recurrence, err := schedule.Parse(scheduledTxn.RecurrenceType, scheduledTxn.Recurrence, scheduledTxn.StartsAt)
+	if err != nil {
+		// The recurrence is validated when the schedule is created, it can only fail if the row was edited by hand
+		logging.FromContext(ctx).Error("newScheduledTransactionResponseData: invalid recurrence", "schedule_id", scheduledTxn.Uuid, "error", err)
+		return res
+	}
+
+	res.UpcomingRuns = schedule.Upcoming(recurrence, scheduledTxn.NextRunAt.Time, schedule.EndsAt(scheduledTxn), upcoming)
+
+	return res
+}
@@ -0,0 +1,103 @@
+package schedules
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListScheduledTransactionsHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, NewRepository(mockRepo, nil), clock.Fixed(dummyNow))
+
+	// Prepare mock responses
+	startsAt := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(true, nil)
+	mockRepo.EXPECT().GetScheduledTransactionsByAccountID(gomock.Any(), dummyAccountID).Return([]*models.ScheduledTransaction{
+		{
+			Uuid:           dummyScheduleID,
+			RecurrenceType: models.RecurrenceTypeRRULE,
+			Recurrence:     "FREQ=WEEKLY;BYDAY=MO;COUNT=3",
+			StartsAt:       startsAt,
+			NextRunAt:      sql.NullTime{Time: time.Date(2024, time.July, 8, 0, 0, 0, 0, time.UTC), Valid: true},
+			Status:         models.ScheduleStatusACTIVE,
+		},
+		{
+			Uuid:           "c3f1f1de-4b5e-4a55-9d11-0c8f5b1e2b22",
+			RecurrenceType: models.RecurrenceTypeCRON,
+			Recurrence:     "0 9 1 * *",
+			StartsAt:       startsAt,
+			Status:         models.ScheduleStatusCANCELLED,
+		},
+	}, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/scheduled-transactions?upcoming=10", nil)
+	rr := httptest.NewRecorder()
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.listScheduledTransactions()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data ScheduledTransactionsResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Len(t, res.Data.ScheduledTransactions, 2)
+
+	// The rule has 3 occurrences, the first one is already posted
+	assert.Equal(t, []time.Time{
+		time.Date(2024, time.July, 8, 0, 0, 0, 0, time.UTC),
+		time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC),
+	}, res.Data.ScheduledTransactions[0].UpcomingRuns)
+
+	// Cancelled schedules have no upcoming runs
+	assert.Empty(t, res.Data.ScheduledTransactions[1].UpcomingRuns)
+}
+
+func TestListScheduledTransactionsHandler_AccountNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, NewRepository(mockRepo, nil), clock.Fixed(dummyNow))
+
+	// Prepare mock responses
+	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountID).Return(false, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID+"/scheduled-transactions", nil)
+	rr := httptest.NewRecorder()
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+
+	// Call the handler
+	handler.listScheduledTransactions()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
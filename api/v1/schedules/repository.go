@@ -0,0 +1,128 @@
+package schedules
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/imjenal/transaction-service/internal/db"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/tracing"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type Repository struct {
+	querier models.Querier
+	// conn is used to start DB transactions. It is nil in unit tests, where the queries run on the querier directly
+	conn db.TxBeginner
+}
+
+func NewRepository(querier models.Querier, conn db.TxBeginner) *Repository {
+	return &Repository{querier: querier, conn: conn}
+}
+
+// withTx runs fn in a DB transaction. All the queries of the repository passed to fn run in the DB transaction
+func (r *Repository) withTx(ctx context.Context, fn func(repo *Repository) error) error {
+	if r.conn == nil {
+		return fn(r)
+	}
+
+	return db.RunInTx(ctx, r.conn, func(tx pgx.Tx) error {
+		return fn(&Repository{querier: models.New(tx)})
+	})
+}
+
+var (
+	errAccountNotFound                 = errors.New("ACCOUNT_NOT_FOUND")
+	errOperationTypeNotFound           = errors.New("OPERATION_TYPE_NOT_FOUND")
+	errScheduleNotFound                = errors.New("SCHEDULED_TRANSACTION_NOT_FOUND")
+	errInvalidScheduleStatusTransition = errors.New("INVALID_SCHEDULED_TRANSACTION_STATUS_TRANSITION")
+)
+
+func (r *Repository) accountExists(ctx context.Context, accountID string) (bool, error) {
+	ctx, span := tracing.Start(ctx, "schedules.Repository.accountExists")
+	defer span.End()
+
+	exists, err := r.querier.AccountExists(ctx, accountID)
+	if err != nil {
+		return false, fmt.Errorf("repo.accountExists: error checking account existence: %w", err)
+	}
+	return exists, nil
+}
+
+func (r *Repository) createScheduledTransaction(ctx context.Context, arg models.CreateScheduledTransactionParams) (*models.ScheduledTransaction, error) {
+	ctx, span := tracing.Start(ctx, "schedules.Repository.createScheduledTransaction")
+	defer span.End()
+
+	schedule, err := r.querier.CreateScheduledTransaction(ctx, arg)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23503" { // 23503 is a foreign key violation
+		if pgErr.ConstraintName == "scheduled_transactions_account_id_fkey" {
+			return nil, errAccountNotFound
+		}
+		return nil, errOperationTypeNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("repo.createScheduledTransaction: error: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (r *Repository) getScheduledTransactions(ctx context.Context, accountID string) ([]*models.ScheduledTransaction, error) {
+	ctx, span := tracing.Start(ctx, "schedules.Repository.getScheduledTransactions")
+	defer span.End()
+
+	schedules, err := r.querier.GetScheduledTransactionsByAccountID(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("repo.getScheduledTransactions: error: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// transitionFn returns the new status and next run of a schedule, it returns errInvalidScheduleStatusTransition
+// when the schedule can't move to the new status
+type transitionFn func(schedule *models.ScheduledTransaction) (models.UpdateScheduledTransactionStatusParams, error)
+
+// changeStatus locks the schedule and applies the transition, so that it doesn't race with the scheduler
+func (r *Repository) changeStatus(ctx context.Context, accountID, scheduleID string, transition transitionFn) (*models.ScheduledTransaction, error) {
+	ctx, span := tracing.Start(ctx, "schedules.Repository.changeStatus")
+	defer span.End()
+
+	var updated *models.ScheduledTransaction
+
+	err := r.withTx(ctx, func(repo *Repository) error {
+		schedule, err := repo.querier.GetScheduledTransactionForUpdate(ctx, models.GetScheduledTransactionForUpdateParams{
+			Uuid:      scheduleID,
+			AccountID: accountID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return errScheduleNotFound
+		}
+
+		if err != nil {
+			return fmt.Errorf("repo.changeStatus: error fetching schedule: %w", err)
+		}
+
+		arg, err := transition(schedule)
+		if err != nil {
+			return err
+		}
+
+		updated, err = repo.querier.UpdateScheduledTransactionStatus(ctx, arg)
+		if err != nil {
+			return fmt.Errorf("repo.changeStatus: error updating schedule: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
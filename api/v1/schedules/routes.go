@@ -0,0 +1,17 @@
+package schedules
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/auth"
+)
+
+// Routes adds the routes of the scheduled transactions, r is the /accounts/{accountID}/scheduled-transactions router
+func Routes(r *mux.Router, h *Handler, p *auth.Policy) {
+	r.HandleFunc("", p.Account(auth.ScopeTransactionsWrite, h.createScheduledTransaction())).Methods(http.MethodPost)
+	r.HandleFunc("", p.Account(auth.ScopeTransactionsRead, h.listScheduledTransactions())).Methods(http.MethodGet)
+	r.HandleFunc("/{scheduleID}/pause", p.Account(auth.ScopeTransactionsWrite, h.pauseScheduledTransaction())).Methods(http.MethodPost)
+	r.HandleFunc("/{scheduleID}/resume", p.Account(auth.ScopeTransactionsWrite, h.resumeScheduledTransaction())).Methods(http.MethodPost)
+	r.HandleFunc("/{scheduleID}/cancel", p.Account(auth.ScopeTransactionsWrite, h.cancelScheduledTransaction())).Methods(http.MethodPost)
+}
@@ -0,0 +1,118 @@
+package schedules
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/schedule"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+// pauseScheduledTransaction handles pausing a schedule, no occurrence is posted while it is paused
+func (h *Handler) pauseScheduledTransaction() http.HandlerFunc {
+	return h.updateScheduledTransactionStatus(func(scheduledTxn *models.ScheduledTransaction) (models.UpdateScheduledTransactionStatusParams, error) {
+		if scheduledTxn.Status != models.ScheduleStatusACTIVE {
+			return models.UpdateScheduledTransactionStatusParams{}, errInvalidScheduleStatusTransition
+		}
+
+		return models.UpdateScheduledTransactionStatusParams{
+			Uuid:      scheduledTxn.Uuid,
+			Status:    models.ScheduleStatusPAUSED,
+			NextRunAt: scheduledTxn.NextRunAt,
+		}, nil
+	})
+}
+
+// resumeScheduledTransaction handles resuming a paused schedule.
+// The occurrences missed while the schedule was paused are skipped, the schedule resumes at its next occurrence from now
+func (h *Handler) resumeScheduledTransaction() http.HandlerFunc {
+	return h.updateScheduledTransactionStatus(func(scheduledTxn *models.ScheduledTransaction) (models.UpdateScheduledTransactionStatusParams, error) {
+		if scheduledTxn.Status != models.ScheduleStatusPAUSED {
+			return models.UpdateScheduledTransactionStatusParams{}, errInvalidScheduleStatusTransition
+		}
+
+		recurrence, err := schedule.Parse(scheduledTxn.RecurrenceType, scheduledTxn.Recurrence, scheduledTxn.StartsAt)
+		if err != nil {
+			return models.UpdateScheduledTransactionStatusParams{}, err
+		}
+
+		from := h.clock.Now()
+		if scheduledTxn.StartsAt.After(from) {
+			from = scheduledTxn.StartsAt
+		}
+
+		status := models.ScheduleStatusACTIVE
+		next := schedule.First(recurrence, from, schedule.EndsAt(scheduledTxn))
+		if next.IsZero() {
+			status = models.ScheduleStatusCOMPLETED
+		}
+
+		return models.UpdateScheduledTransactionStatusParams{
+			Uuid:      scheduledTxn.Uuid,
+			Status:    status,
+			NextRunAt: sql.NullTime{Time: next, Valid: !next.IsZero()},
+		}, nil
+	})
+}
+
+// cancelScheduledTransaction handles cancelling a schedule. Cancelling is final, the schedule can't be resumed
+func (h *Handler) cancelScheduledTransaction() http.HandlerFunc {
+	return h.updateScheduledTransactionStatus(func(scheduledTxn *models.ScheduledTransaction) (models.UpdateScheduledTransactionStatusParams, error) {
+		if scheduledTxn.Status != models.ScheduleStatusACTIVE && scheduledTxn.Status != models.ScheduleStatusPAUSED {
+			return models.UpdateScheduledTransactionStatusParams{}, errInvalidScheduleStatusTransition
+		}
+
+		return models.UpdateScheduledTransactionStatusParams{
+			Uuid:   scheduledTxn.Uuid,
+			Status: models.ScheduleStatusCANCELLED,
+		}, nil
+	})
+}
+
+// updateScheduledTransactionStatus handles applying the transition to the schedule
+func (h *Handler) updateScheduledTransactionStatus(transition transitionFn) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		accountID := mux.Vars(r)["accountID"]
+		scheduleID := mux.Vars(r)["scheduleID"]
+
+		h.changeAndRespondScheduledTransactionStatus(r.Context(), w, accountID, scheduleID, transition)
+	}
+}
+
+// changeAndRespondScheduledTransactionStatus changes the status of the schedule and responds with the updated schedule
+func (h *Handler) changeAndRespondScheduledTransactionStatus(ctx context.Context, w http.ResponseWriter, accountID, scheduleID string, transition transitionFn) {
+	scheduledTxn, err := h.repository.changeStatus(ctx, accountID, scheduleID, transition)
+	if errors.Is(err, errScheduleNotFound) {
+		logging.FromContext(ctx).Info("changeAndRespondScheduledTransactionStatus: schedule not found", "schedule_id", scheduleID, "account_id", accountID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrScheduledTransactionNotFound,
+			Message: errScheduleNotFound.Error(),
+		})
+		return
+	}
+
+	if errors.Is(err, errInvalidScheduleStatusTransition) {
+		logging.FromContext(ctx).Info("changeAndRespondScheduledTransactionStatus: invalid status transition", "schedule_id", scheduleID, "error", err)
+		h.writer.Conflict(w, &response.APIError{
+			Code:    response.ErrInvalidScheduledTransactionStatusTransition,
+			Message: errInvalidScheduleStatusTransition.Error(),
+		})
+		return
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("changeAndRespondScheduledTransactionStatus: failed to change schedule status", "schedule_id", scheduleID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to update scheduled transaction.",
+		})
+		return
+	}
+
+	h.writer.Ok(w, newScheduledTransactionResponseData(ctx, scheduledTxn, defaultUpcomingRuns))
+}
@@ -0,0 +1,148 @@
+package schedules
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestScheduledTransaction(status models.ScheduleStatus) *models.ScheduledTransaction {
+	return &models.ScheduledTransaction{
+		Uuid:           dummyScheduleID,
+		AccountID:      dummyAccountID,
+		RecurrenceType: models.RecurrenceTypeCRON,
+		Recurrence:     "0 9 1 * *",
+		StartsAt:       time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC),
+		NextRunAt:      sql.NullTime{Time: time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC), Valid: true},
+		Status:         status,
+	}
+}
+
+func TestUpdateScheduledTransactionStatusHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  func(h *Handler) http.HandlerFunc
+		status   models.ScheduleStatus
+		expected *models.UpdateScheduledTransactionStatusParams
+		code     int
+	}{
+		{
+			name:    "pause an active schedule",
+			handler: (*Handler).pauseScheduledTransaction,
+			status:  models.ScheduleStatusACTIVE,
+			expected: &models.UpdateScheduledTransactionStatusParams{
+				Uuid:      dummyScheduleID,
+				Status:    models.ScheduleStatusPAUSED,
+				NextRunAt: sql.NullTime{Time: time.Date(2024, time.March, 1, 9, 0, 0, 0, time.UTC), Valid: true},
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:    "resume skips the occurrences missed while paused",
+			handler: (*Handler).resumeScheduledTransaction,
+			status:  models.ScheduleStatusPAUSED,
+			expected: &models.UpdateScheduledTransactionStatusParams{
+				Uuid:      dummyScheduleID,
+				Status:    models.ScheduleStatusACTIVE,
+				NextRunAt: sql.NullTime{Time: time.Date(2024, time.August, 1, 9, 0, 0, 0, time.UTC), Valid: true},
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:    "cancel a paused schedule",
+			handler: (*Handler).cancelScheduledTransaction,
+			status:  models.ScheduleStatusPAUSED,
+			expected: &models.UpdateScheduledTransactionStatusParams{
+				Uuid:   dummyScheduleID,
+				Status: models.ScheduleStatusCANCELLED,
+			},
+			code: http.StatusOK,
+		},
+		{
+			name:    "resume an active schedule",
+			handler: (*Handler).resumeScheduledTransaction,
+			status:  models.ScheduleStatusACTIVE,
+			code:    http.StatusConflict,
+		},
+		{
+			name:    "cancel a completed schedule",
+			handler: (*Handler).cancelScheduledTransaction,
+			status:  models.ScheduleStatusCOMPLETED,
+			code:    http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, NewRepository(mockRepo, nil), clock.Fixed(dummyNow))
+
+			// Prepare mock responses
+			mockRepo.EXPECT().GetScheduledTransactionForUpdate(gomock.Any(), models.GetScheduledTransactionForUpdateParams{
+				Uuid:      dummyScheduleID,
+				AccountID: dummyAccountID,
+			}).Return(newTestScheduledTransaction(tt.status), nil)
+
+			if tt.expected != nil {
+				mockRepo.EXPECT().UpdateScheduledTransactionStatus(gomock.Any(), *tt.expected).
+					Return(newTestScheduledTransaction(tt.expected.Status), nil)
+			}
+
+			// Prepare the request
+			req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/scheduled-transactions/"+dummyScheduleID, nil)
+			rr := httptest.NewRecorder()
+			req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID, "scheduleID": dummyScheduleID})
+
+			// Call the handler
+			tt.handler(handler)(rr, req)
+
+			// Check the results
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestUpdateScheduledTransactionStatusHandler_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, NewRepository(mockRepo, nil), clock.Fixed(dummyNow))
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetScheduledTransactionForUpdate(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodPost, "/accounts/"+dummyAccountID+"/scheduled-transactions/"+dummyScheduleID+"/pause", nil)
+	rr := httptest.NewRecorder()
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID, "scheduleID": dummyScheduleID})
+
+	// Call the handler
+	handler.pauseScheduledTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+	assert.Contains(t, rr.Body.String(), "SCHEDULED_TRANSACTION_NOT_FOUND")
+}
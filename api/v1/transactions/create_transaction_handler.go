@@ -2,18 +2,32 @@ package transactions
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
-	"github.com/imjenal/transaction-service/internal/db/models"
-	"github.com/imjenal/transaction-service/pkg/http/response"
-	"log"
-	"math"
 	"net/http"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/limits"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
 )
 
 type CreateTransactionRequestData struct {
 	AccountId       string  `json:"account_id" validate:"required,uuid"`
 	OperationTypeId int64   `json:"operation_type_id" validate:"required"`
 	Amount          float64 `json:"amount"  validate:"required,gt=0"`
+	MerchantId      string  `json:"merchant_id,omitempty" validate:"omitempty,max=255"`
+	MerchantCountry string  `json:"merchant_country,omitempty" validate:"omitempty,iso3166_1_alpha2"`
+	// Mcc is the merchant category code (ISO 18245), it is used by the reward rules
+	Mcc string `json:"mcc,omitempty" validate:"omitempty,len=4,numeric"`
+	// EventDate is when the transaction happened, it defaults to now. It can be in the past for offline transactions
+	// or delayed clearing, within the limits set in the config
+	EventDate *time.Time `json:"event_date,omitempty"`
+	// Metadata is set by the integrator, e.g. their order ID, and can't be updated. The values are strings so that
+	// they can be used as filters of the list of transactions, the keys can't contain a colon for the same reason
+	Metadata map[string]string `json:"metadata,omitempty" validate:"omitempty,max=20,jsonsize=4096,dive,keys,min=1,max=40,excludes=:,endkeys,max=500"`
 }
 
 // createTransaction handles creating a transaction
@@ -25,167 +39,109 @@ func (h *Handler) createTransaction() http.HandlerFunc {
 			return
 		}
 
-		ctx := r.Context()
-
-		if !h.validateAccount(ctx, w, requestBody.AccountId) {
+		if !h.policy.AuthorizeAccount(w, r, auth.ScopeTransactionsWrite, requestBody.AccountId) {
 			return
 		}
 
-		amountBehavior, err := h.validateAndFetchOperationType(ctx, w, requestBody.OperationTypeId)
+		txnDetails, err := h.service.Create(r.Context(), requestBody)
 		if err != nil {
+			h.writeCreateTransactionError(r.Context(), w, requestBody, err)
 			return
 		}
 
-		requestBody.Amount = adjustAmountBasedOnOperationTypeAmountBehavior(amountBehavior, requestBody.Amount)
-
-		if amountBehavior == models.AmountBehaviorPOSITIVE {
-			h.dischargeAndCreateTransaction(ctx, w, requestBody)
-		} else {
-			h.createAndRespondTransaction(ctx, w, requestBody)
-		}
+		h.writer.Ok(w, txnDetails)
 	}
 }
 
-func (h *Handler) dischargeAndCreateTransaction(ctx context.Context, w http.ResponseWriter, requestBody *CreateTransactionRequestData) {
-	transactions, err := h.repository.getNegativeBalanceTransactionsByAccountID(ctx, requestBody.AccountId)
-	if err != nil {
-		log.Printf("dischargeAndCreateTransaction: failed to fetch txns: %v", err)
-		h.writer.Internal(w, &response.APIError{
-			Code:    response.DefaultErrorCode,
-			Message: "Failed to fetch transactions for discharge",
-		})
+// writeCreateTransactionError responds with the error that failed the creation of the transaction
+func (h *Handler) writeCreateTransactionError(ctx context.Context, w http.ResponseWriter, requestBody *CreateTransactionRequestData, err error) {
+	if apiErr := violationError(err); apiErr != nil {
+		logging.FromContext(ctx).Info("writeCreateTransactionError: transaction rejected", "account_id", requestBody.AccountId, "error", err)
+		h.writer.UnprocessableEntity(w, apiErr)
 		return
 	}
 
-	dischargedTransactions, remainingBalance := h.performDischarge(transactions, requestBody.Amount)
+	switch {
+	case errors.Is(err, errAccountNotFound):
+		logging.FromContext(ctx).Info("writeCreateTransactionError: account does not exist", "account_id", requestBody.AccountId)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrAccountNotFound,
+			Message: errAccountNotFound.Error(),
+		})
 
-	if err := h.repository.updateTransactionBalances(ctx, dischargedTransactions); err != nil {
-		log.Printf("dischargeAndCreateTransaction: failed to update transaction balances: %v", err)
-		h.writer.Internal(w, &response.APIError{
-			Code:    response.DefaultErrorCode,
-			Message: "Failed to fetch update transaction balances",
+	case errors.Is(err, errOperationTypeNotFound):
+		logging.FromContext(ctx).Info("writeCreateTransactionError: operation type does not exist", "operation_type_id", requestBody.OperationTypeId)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrOperationTypeNotFound,
+			Message: errOperationTypeNotFound.Error(),
 		})
-		return
-	}
 
-	//requestBody.Amount = remainingBalance
-	newTxn, err := h.repository.createTransaction(ctx, models.CreateTransactionParams{
-		AccountID:       requestBody.AccountId,
-		OperationTypeID: requestBody.OperationTypeId,
-		Amount:          requestBody.Amount,
-		Balance:         remainingBalance,
-	})
-	if err != nil {
-		log.Printf("dischargeAndCreateTransaction: failed to create transaction: %v", err)
+	default:
+		logging.FromContext(ctx).Error("writeCreateTransactionError: failed to create transaction", "account_id", requestBody.AccountId, "error", err)
 		h.writer.Internal(w, &response.APIError{
 			Code:    response.DefaultErrorCode,
 			Message: "Failed to create transaction.",
 		})
-		return
 	}
-
-	h.writer.Ok(w, newTxn)
-
 }
 
-func (h *Handler) performDischarge(transactions []*models.GetNegativeBalanceTransactionsByAccountIDRow, amount float64) ([]*models.GetNegativeBalanceTransactionsByAccountIDRow, float64) {
-	for i := range transactions {
-		if amount <= 0 {
-			break
+// violationError converts the error of a rule the transaction breaks to the API error, it returns nil for other errors
+func violationError(err error) *response.APIError {
+	var (
+		inactiveErr  *AccountInactiveError
+		declinedErr  *DeclinedError
+		eventDateErr *EventDateError
+		limitErr     *limits.ExceededError
+	)
+
+	switch {
+	case errors.As(err, &eventDateErr):
+		return &response.APIError{
+			Code:    response.ErrInvalidEventDate,
+			Message: errInvalidEventDate.Error(),
+			Data:    eventDateErr,
 		}
-		if transactions[i].Balance < 0 { //only discharge txns with negative balances
-			dischargeAmount := min(-transactions[i].Balance, amount)
-			transactions[i].Balance += dischargeAmount
-			amount -= dischargeAmount
+
+	case errors.As(err, &inactiveErr):
+		return &response.APIError{
+			Code:    response.ErrAccountInactive,
+			Message: errAccountInactive.Error(),
+			Data:    inactiveErr,
 		}
-	}
-	return transactions, amount
-}
 
-func min(a, b float64) float64 {
-	if a < b {
-		return a
-	}
-	return b
-}
+	case errors.As(err, &declinedErr):
+		return &response.APIError{
+			Code:    response.ErrTransactionDeclined,
+			Message: errTransactionDeclined.Error(),
+			Data: map[string]any{
+				"reason_code": declinedErr.Decision.ReasonCode,
+			},
+		}
 
-// validateAccount checks if the account exists
-func (h *Handler) validateAccount(ctx context.Context, w http.ResponseWriter, accountID string) bool {
-	accountExists, err := h.repository.accountExists(ctx, accountID)
-	if err != nil {
-		log.Printf("validateAccount: failed to check account existence: %v", err)
-		h.writer.Internal(w, &response.APIError{
-			Code:    response.DefaultErrorCode,
-			Message: "Failed to validate account ID.",
-		})
-		return false
+	case errors.As(err, &limitErr):
+		return &response.APIError{
+			Code:    response.ErrSpendingLimitExceeded,
+			Message: errSpendingLimitExceeded.Error(),
+			Data:    limitErr,
+		}
 	}
 
-	if !accountExists {
-		log.Printf("validateAccount: account %s does not exist", accountID)
-		h.writer.NotFound(w, &response.APIError{
-			Code:    response.ErrAccountNotFound,
-			Message: errAccountNotFound.Error(),
-		})
-		return false
-	}
-	return true
+	return nil
 }
 
-// validateAndFetchOperationType checks if the operation type exists and retrieves its amount behavior
-func (h *Handler) validateAndFetchOperationType(ctx context.Context, w http.ResponseWriter, operationTypeID int64) (models.AmountBehavior, error) {
-	amountBehavior, err := h.repository.getAmountBehavior(ctx, operationTypeID)
-	if errors.Is(err, errOperationTypeNotFound) {
-		log.Printf("validateAndFetchOperationType: operation type %d does not exist", operationTypeID)
-		h.writer.NotFound(w, &response.APIError{
-			Code:    response.ErrOperationTypeNotFound,
-			Message: errOperationTypeNotFound.Error(),
-		})
-		return "", err
-	}
-
-	if err != nil {
-		log.Printf("validateAndFetchOperationType: failed to get amount behavior: %v", err)
-		h.writer.Internal(w, &response.APIError{
-			Code:    response.DefaultErrorCode,
-			Message: "Failed to fetch operation type information.",
-		})
-		return "", err
-	}
-
-	return amountBehavior, nil
+// nullString converts an optional request field to a nullable DB column
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
 }
 
-// createAndRespondTransaction creates the transaction and responds to the client
-func (h *Handler) createAndRespondTransaction(ctx context.Context, w http.ResponseWriter, requestBody *CreateTransactionRequestData) {
-	txnDetails, err := h.repository.createTransaction(ctx, models.CreateTransactionParams{
-		AccountID:       requestBody.AccountId,
-		OperationTypeID: requestBody.OperationTypeId,
-		Amount:          requestBody.Amount,
-		Balance:         requestBody.Amount,
-	})
-	if err != nil {
-		log.Printf("createTransaction: failed to create transaction: %v", err)
-		h.writer.Internal(w, &response.APIError{
-			Code:    response.DefaultErrorCode,
-			Message: "Failed to create transaction.",
-		})
-		return
+// metadataJSON encodes the metadata of the request, a transaction without metadata has an empty object
+func metadataJSON(metadata map[string]string) json.RawMessage {
+	if len(metadata) == 0 {
+		return json.RawMessage("{}")
 	}
 
-	h.writer.Ok(w, txnDetails)
-}
+	// A map of strings can always be encoded
+	encoded, _ := json.Marshal(metadata)
 
-// Adjust the amount based on the amount behavior
-func adjustAmountBasedOnOperationTypeAmountBehavior(amountBehavior models.AmountBehavior, amount float64) float64 {
-	switch amountBehavior {
-	case models.AmountBehaviorNEGATIVE:
-		return -math.Abs(amount) // Store as negative
-	case models.AmountBehaviorPOSITIVE:
-		return math.Abs(amount) // Store as positive
-	default:
-		// In case of an unexpected value, return the absolute value by default
-		log.Printf("Unknown amount behavior: %v, defaulting to positive amount.", amountBehavior)
-		return math.Abs(amount)
-	}
+	return encoded
 }
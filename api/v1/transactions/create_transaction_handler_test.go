@@ -4,16 +4,27 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"github.com/imjenal/transaction-service/internal/auth"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/clock"
 	"github.com/imjenal/transaction-service/internal/db/models"
 	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/risk"
 	"github.com/imjenal/transaction-service/pkg/http/request"
 	"github.com/imjenal/transaction-service/pkg/http/response"
 	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,6 +34,49 @@ const (
 	dummyTransactionID = "98a0f8e7-6e28-4d4f-872b-4d28b3d5ee66"
 )
 
+var (
+	dummyNow   = time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+	testConfig = &config.Transactions{
+		MaxEventDateBackdate: 72 * time.Hour,
+		MaxEventDateForward:  time.Hour,
+	}
+)
+
+// asAdmin authenticates the request as an admin, the authorization of the users is tested separately
+func asAdmin(req *http.Request) *http.Request {
+	return withIdentity(req, &auth.Identity{Subject: "admin", Method: auth.MethodAPIKey, Scopes: []auth.Scope{auth.ScopeAdmin}})
+}
+
+func withIdentity(req *http.Request, identity *auth.Identity) *http.Request {
+	return req.WithContext(auth.NewContext(req.Context(), identity))
+}
+
+// newTestRiskEngine returns a risk engine without any rules, it approves every transaction
+func newTestRiskEngine(t *testing.T, querier models.Querier) *risk.Engine {
+	t.Helper()
+
+	engine, err := risk.NewEngine("", risk.NewStore(querier), clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	return engine
+}
+
+// newTestRewardsEngine returns a rewards engine with the rules in the given YAML, it accrues nothing when rules is empty
+func newTestRewardsEngine(t *testing.T, rules string) *rewards.Engine {
+	t.Helper()
+
+	path := ""
+	if rules != "" {
+		path = filepath.Join(t.TempDir(), "rewards.yaml")
+		assert.Nil(t, os.WriteFile(path, []byte(rules), 0o600))
+	}
+
+	engine, err := rewards.NewEngine(path)
+	assert.Nil(t, err)
+
+	return engine
+}
+
 func TestCreateTransactionHandler_Success(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -31,21 +85,29 @@ func TestCreateTransactionHandler_Success(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock responses
-	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountId).Return(true, nil)
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
 	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehaviorNEGATIVE, nil)
-	mockRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Return(&models.Transaction{Uuid: dummyTransactionID}, nil)
+	mockRepo.EXPECT().CreateRiskDecision(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateTransactionParams) (*models.CreateTransactionRow, error) {
+			assert.JSONEq(t, `{"order_id": "ord_123"}`, string(arg.Metadata))
+			return &models.CreateTransactionRow{Uuid: dummyTransactionID, Metadata: arg.Metadata}, nil
+		})
 
 	// Prepare the request
 	requestBody, _ := json.Marshal(CreateTransactionRequestData{
 		AccountId:       dummyAccountId,
 		OperationTypeId: dummyOperationType,
 		Amount:          100.0,
+		Metadata:        map[string]string{"order_id": "ord_123"},
 	})
 
-	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody))
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
 	rr := httptest.NewRecorder()
 
 	// Call the handler
@@ -54,6 +116,7 @@ func TestCreateTransactionHandler_Success(t *testing.T) {
 	// Check the results
 	assert.Equal(t, http.StatusOK, rr.Code)
 	assert.Contains(t, rr.Body.String(), dummyTransactionID)
+	assert.Contains(t, rr.Body.String(), `"metadata":{"order_id":"ord_123"}`)
 }
 
 func TestCreateTransactionHandler_ValidationError(t *testing.T) {
@@ -64,7 +127,7 @@ func TestCreateTransactionHandler_ValidationError(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare the invalid request
 	requestBody, _ := json.Marshal(CreateTransactionRequestData{
@@ -73,7 +136,7 @@ func TestCreateTransactionHandler_ValidationError(t *testing.T) {
 		Amount:          -100.0, // Invalid amount (negative value)
 	})
 
-	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody))
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
 	rr := httptest.NewRecorder()
 
 	// Call the handler
@@ -83,6 +146,61 @@ func TestCreateTransactionHandler_ValidationError(t *testing.T) {
 	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
 }
 
+func TestCreateTransactionHandler_InvalidMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+	}{
+		{"too many keys", func() map[string]string {
+			metadata := map[string]string{}
+			for i := 0; i < 21; i++ {
+				metadata[fmt.Sprintf("key_%d", i)] = "value"
+			}
+			return metadata
+		}()},
+		{"key too long", map[string]string{strings.Repeat("k", 41): "value"}},
+		{"key with a colon", map[string]string{"order:id": "value"}},
+		{"value too long", map[string]string{"order_id": strings.Repeat("v", 501)}},
+		{"too large", func() map[string]string {
+			metadata := map[string]string{}
+			for i := 0; i < 10; i++ {
+				metadata[fmt.Sprintf("key_%d", i)] = strings.Repeat("v", 450)
+			}
+			return metadata
+		}()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+			// Prepare the request
+			requestBody, _ := json.Marshal(CreateTransactionRequestData{
+				AccountId:       dummyAccountId,
+				OperationTypeId: dummyOperationType,
+				Amount:          100.0,
+				Metadata:        tt.metadata,
+			})
+
+			req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
+			rr := httptest.NewRecorder()
+
+			// Call the handler
+			handler.createTransaction()(rr, req)
+
+			// Check the results
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		})
+	}
+}
+
 func TestCreateTransactionHandler_AccountNotFound(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -91,10 +209,10 @@ func TestCreateTransactionHandler_AccountNotFound(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock response
-	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountId).Return(false, nil)
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatus(""), pgx.ErrNoRows)
 
 	// Prepare the request
 	requestBody, _ := json.Marshal(CreateTransactionRequestData{
@@ -103,7 +221,7 @@ func TestCreateTransactionHandler_AccountNotFound(t *testing.T) {
 		Amount:          100.0,
 	})
 
-	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody))
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
 	rr := httptest.NewRecorder()
 
 	// Call the handler
@@ -121,10 +239,10 @@ func TestCreateTransactionHandler_OperationTypeNotFound(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock responses
-	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountId).Return(true, nil)
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
 	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehavior(""), errOperationTypeNotFound)
 
 	// Prepare the request
@@ -134,7 +252,7 @@ func TestCreateTransactionHandler_OperationTypeNotFound(t *testing.T) {
 		Amount:          100.0,
 	})
 
-	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody))
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
 	rr := httptest.NewRecorder()
 
 	// Call the handler
@@ -152,11 +270,14 @@ func TestCreateTransactionHandler_DBError(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
 
 	// Mock database error during account validation
-	mockRepo.EXPECT().AccountExists(gomock.Any(), dummyAccountId).Return(true, nil)
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
 	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehaviorNEGATIVE, nil)
+	mockRepo.EXPECT().CreateRiskDecision(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return(nil, nil)
 	mockRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
 
 	// Prepare the request
@@ -166,7 +287,7 @@ func TestCreateTransactionHandler_DBError(t *testing.T) {
 		Amount:          100.0,
 	})
 
-	req := httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody))
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
 	rr := httptest.NewRecorder()
 
 	// Call the handler
@@ -176,3 +297,345 @@ func TestCreateTransactionHandler_DBError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 	assert.Contains(t, rr.Body.String(), "Failed to create transaction.")
 }
+
+func TestCreateTransactionHandler_SpendingLimitExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, the counter can't be incremented as the limit would be breached
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehaviorNEGATIVE, nil)
+	mockRepo.EXPECT().CreateRiskDecision(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return([]*models.AccountLimit{{
+		AccountID:       dummyAccountId,
+		OperationTypeID: dummyOperationType,
+		Period:          models.LimitPeriodDAILY,
+		MaxAmount:       500,
+	}}, nil)
+	mockRepo.EXPECT().IncrementAccountLimitUsage(gomock.Any(), gomock.Any()).Return(float64(0), pgx.ErrNoRows)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateTransactionRequestData{
+		AccountId:       dummyAccountId,
+		OperationTypeId: dummyOperationType,
+		Amount:          100.0,
+	})
+
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), "SPENDING_LIMIT_EXCEEDED")
+	assert.Contains(t, rr.Body.String(), "DAILY")
+}
+
+func TestCreateTransactionHandler_DeclinedByRiskRules(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rulesFile := filepath.Join(t.TempDir(), "rules.yaml")
+	assert.Nil(t, os.WriteFile(rulesFile, []byte("blocklist:\n  countries: [KP]\n"), 0o600))
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	engine, err := risk.NewEngine(rulesFile, risk.NewStore(mockRepo), clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, engine, newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, the transaction must never be created
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehaviorNEGATIVE, nil)
+	mockRepo.EXPECT().CreateRiskDecision(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateRiskDecisionParams) error {
+			assert.Equal(t, models.RiskOutcomeDECLINED, arg.Outcome)
+			assert.Equal(t, string(risk.ReasonCountryBlocked), arg.ReasonCode.String)
+			return nil
+		})
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateTransactionRequestData{
+		AccountId:       dummyAccountId,
+		OperationTypeId: dummyOperationType,
+		Amount:          100.0,
+		MerchantCountry: "KP",
+	})
+
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), string(risk.ReasonCountryBlocked))
+}
+
+func TestCreateTransactionHandler_AccountInactive(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         models.AccountStatus
+		amountBehavior models.AmountBehavior
+	}{
+		{"debit on a blocked account", models.AccountStatusBLOCKED, models.AmountBehaviorNEGATIVE},
+		{"debit on a suspended account", models.AccountStatusSUSPENDED, models.AmountBehaviorNEGATIVE},
+		{"credit on a closed account", models.AccountStatusCLOSED, models.AmountBehaviorPOSITIVE},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+			// Prepare mock responses
+			mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(tt.status, nil)
+			mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(tt.amountBehavior, nil)
+
+			// Prepare the request
+			requestBody, _ := json.Marshal(CreateTransactionRequestData{
+				AccountId:       dummyAccountId,
+				OperationTypeId: dummyOperationType,
+				Amount:          100.0,
+			})
+
+			req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
+			rr := httptest.NewRecorder()
+
+			// Call the handler
+			handler.createTransaction()(rr, req)
+
+			// Check the results
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			assert.Contains(t, rr.Body.String(), errAccountInactive.Error())
+		})
+	}
+}
+
+func TestCreateTransactionHandler_AccountBlockedBeforeTheLock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, the account is blocked once its status was first checked, nothing is consumed or created
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehaviorNEGATIVE, nil)
+	mockRepo.EXPECT().CreateRiskDecision(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountId).Return(models.AccountStatusBLOCKED, nil)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateTransactionRequestData{
+		AccountId:       dummyAccountId,
+		OperationTypeId: dummyOperationType,
+		Amount:          100.0,
+	})
+
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+	assert.Contains(t, rr.Body.String(), errAccountInactive.Error())
+}
+
+func TestCreateTransactionHandler_BackdatedEventDate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	eventDate := dummyNow.Add(-48 * time.Hour)
+
+	// Prepare mock responses, the spending limits are counted at the processing time and the transaction at the event date
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehaviorNEGATIVE, nil)
+	mockRepo.EXPECT().CreateRiskDecision(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateTransactionParams) (*models.CreateTransactionRow, error) {
+			assert.True(t, eventDate.Equal(arg.EventDate))
+			return &models.CreateTransactionRow{Uuid: dummyTransactionID, EventDate: arg.EventDate}, nil
+		})
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateTransactionRequestData{
+		AccountId:       dummyAccountId,
+		OperationTypeId: dummyOperationType,
+		Amount:          100.0,
+		EventDate:       &eventDate,
+	})
+
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCreateTransactionHandler_AccruesRewards(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	rewardsEngine := newTestRewardsEngine(t, "rules:\n  - name: groceries\n    kind: CASHBACK\n    rate: 0.05\n    mccs: [\"5411\"]\n")
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), rewardsEngine, clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehaviorNEGATIVE, nil)
+	mockRepo.EXPECT().CreateRiskDecision(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateTransactionParams) (*models.CreateTransactionRow, error) {
+			assert.Equal(t, "5411", arg.Mcc.String)
+			return &models.CreateTransactionRow{
+				Uuid:            dummyTransactionID,
+				AccountID:       arg.AccountID,
+				OperationTypeID: arg.OperationTypeID,
+				Amount:          arg.Amount,
+				EventDate:       arg.EventDate,
+				Mcc:             arg.Mcc,
+			}, nil
+		})
+	mockRepo.EXPECT().CreateRewardAccrual(gomock.Any(), models.CreateRewardAccrualParams{
+		AccountID:     dummyAccountId,
+		TransactionID: dummyTransactionID,
+		RuleName:      "groceries",
+		Kind:          models.RewardKindCASHBACK,
+		Amount:        5,
+	}).Return(nil)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateTransactionRequestData{
+		AccountId:       dummyAccountId,
+		OperationTypeId: dummyOperationType,
+		Amount:          100.0,
+		Mcc:             "5411",
+	})
+
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestCreateTransactionHandler_EventDateOutOfRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		eventDate time.Time
+	}{
+		{"too far in the past", dummyNow.Add(-testConfig.MaxEventDateBackdate - time.Second)},
+		{"too far in the future", dummyNow.Add(testConfig.MaxEventDateForward + time.Second)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+			// Prepare the request
+			requestBody, _ := json.Marshal(CreateTransactionRequestData{
+				AccountId:       dummyAccountId,
+				OperationTypeId: dummyOperationType,
+				Amount:          100.0,
+				EventDate:       &tt.eventDate,
+			})
+
+			req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)))
+			rr := httptest.NewRecorder()
+
+			// Call the handler
+			handler.createTransaction()(rr, req)
+
+			// Check the results
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			assert.Contains(t, rr.Body.String(), errInvalidEventDate.Error())
+		})
+	}
+}
+
+func TestCreateTransactionHandler_OnTheAccountOfAnotherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	const (
+		ownerID     = "88e0e837-e7f2-47b1-a08c-3af267c03088"
+		otherUserID = "0b8b6c54-4c3e-4a8e-9a3f-6a4b1d3e2f10"
+	)
+
+	// Prepare mock response, the transaction is refused before it reaches the account
+	mockRepo.EXPECT().GetAccountOwner(gomock.Any(), dummyAccountId).Return(ownerID, nil)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateTransactionRequestData{
+		AccountId:       dummyAccountId,
+		OperationTypeId: dummyOperationType,
+		Amount:          100.0,
+	})
+
+	req := withIdentity(httptest.NewRequest(http.MethodPost, "/transactions", bytes.NewReader(requestBody)),
+		&auth.Identity{Subject: otherUserID, Method: auth.MethodJWT, UserID: otherUserID})
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"code":1012`)
+}
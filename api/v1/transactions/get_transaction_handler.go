@@ -3,10 +3,10 @@ package transactions
 import (
 	"context"
 	"errors"
-	"log"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/logging"
 	"github.com/imjenal/transaction-service/pkg/http/response"
 )
 
@@ -25,7 +25,7 @@ func (h *Handler) getTransactionDetails() http.HandlerFunc {
 func (h *Handler) fetchAndRespondTransactionDetails(ctx context.Context, w http.ResponseWriter, transactionID string) {
 	txnDetails, err := h.repository.getTransactionDetails(ctx, transactionID)
 	if errors.Is(err, errTransactionNotFound) {
-		log.Printf("fetchAndRespondTransactionDetails: transaction %s not found", transactionID)
+		logging.FromContext(ctx).Info("fetchAndRespondTransactionDetails: transaction not found", "transaction_id", transactionID)
 		h.writer.NotFound(w, &response.APIError{
 			Code:    response.ErrTransactionNotFound,
 			Message: errTransactionNotFound.Error(),
@@ -34,7 +34,7 @@ func (h *Handler) fetchAndRespondTransactionDetails(ctx context.Context, w http.
 	}
 
 	if err != nil {
-		log.Printf("fetchAndRespondTransactionDetails: failed to fetch transaction details: %v", err)
+		logging.FromContext(ctx).Error("fetchAndRespondTransactionDetails: failed to fetch transaction details", "transaction_id", transactionID, "error", err)
 		h.writer.Internal(w, &response.APIError{
 			Code:    response.DefaultErrorCode,
 			Message: "Failed to fetch transaction details.",
@@ -2,12 +2,14 @@ package transactions
 
 import (
 	"errors"
+	"github.com/imjenal/transaction-service/internal/auth"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/clock"
 	"github.com/imjenal/transaction-service/internal/db/models"
 	"github.com/imjenal/transaction-service/internal/db/models/mock"
 	"github.com/imjenal/transaction-service/pkg/http/request"
@@ -24,10 +26,10 @@ func TestGetTransactionHandler_Success(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock response
-	mockRepo.EXPECT().GetTransactionDetailsByTransactionId(gomock.Any(), dummyTransactionID).Return(&models.Transaction{Uuid: dummyTransactionID}, nil)
+	mockRepo.EXPECT().GetTransactionDetailsByTransactionId(gomock.Any(), dummyTransactionID).Return(&models.GetTransactionDetailsByTransactionIdRow{Uuid: dummyTransactionID}, nil)
 
 	// Prepare the request
 	req := httptest.NewRequest(http.MethodGet, "/transactions/"+dummyTransactionID, nil)
@@ -52,7 +54,7 @@ func TestGetTransactionHandler_TransactionNotFound(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock response
 	mockRepo.EXPECT().GetTransactionDetailsByTransactionId(gomock.Any(), dummyTransactionID).Return(nil, errTransactionNotFound)
@@ -79,7 +81,7 @@ func TestGetTransactionHandler_DBError(t *testing.T) {
 	writer := response.NewJSONWriter()
 	v := validator.New()
 	reader := request.NewReader(writer, v)
-	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
 
 	// Prepare mock response for database error
 	mockRepo.EXPECT().GetTransactionDetailsByTransactionId(gomock.Any(), dummyTransactionID).Return(nil, errors.New("database error"))
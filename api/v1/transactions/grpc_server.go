@@ -0,0 +1,245 @@
+package transactions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	pismov1 "github.com/imjenal/transaction-service/api/proto/pismo/v1"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/rpc"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// streamPageSize is the number of transactions read at once by ListTransactions
+const streamPageSize = 100
+
+// GRPCServer serves the TransactionService of the gRPC API, with the same repository, service and checks as the
+// handlers
+type GRPCServer struct {
+	pismov1.UnimplementedTransactionServiceServer
+	// reader validates the requests with the tags of the request data of the handlers
+	reader     *request.Reader
+	repository *Repository
+	service    *Service
+	policy     *auth.Policy
+}
+
+func NewGRPCServer(reader *request.Reader, repository *Repository, service *Service, policy *auth.Policy) *GRPCServer {
+	return &GRPCServer{
+		reader:     reader,
+		repository: repository,
+		service:    service,
+		policy:     policy,
+	}
+}
+
+// getTransactionRequestData is the transaction ID of GetTransaction, validated like the {transactionID} path variable
+type getTransactionRequestData struct {
+	TransactionId string `json:"transaction_id" validate:"required,uuid4"`
+}
+
+// CreateTransaction creates a transaction on the account of the request
+func (s *GRPCServer) CreateTransaction(ctx context.Context, req *pismov1.CreateTransactionRequest) (*pismov1.CreateTransactionResponse, error) {
+	requestBody := &CreateTransactionRequestData{
+		AccountId:       req.GetAccountId(),
+		OperationTypeId: req.GetOperationTypeId(),
+		Amount:          req.GetAmount(),
+		MerchantId:      req.GetMerchantId(),
+		MerchantCountry: req.GetMerchantCountry(),
+		Mcc:             req.GetMcc(),
+		Metadata:        req.GetMetadata(),
+	}
+
+	if req.GetEventDate() != nil {
+		eventDate := req.GetEventDate().AsTime()
+		requestBody.EventDate = &eventDate
+	}
+
+	if apiErr := s.reader.Validate(ctx, requestBody); apiErr != nil {
+		return nil, rpc.InvalidArgument(ctx, apiErr)
+	}
+
+	if err := s.policy.CheckAccount(ctx, auth.ScopeTransactionsWrite, requestBody.AccountId); err != nil {
+		return nil, rpc.AuthorizationError(ctx, auth.ScopeTransactionsWrite, err, "account_id", requestBody.AccountId)
+	}
+
+	txn, err := s.service.Create(ctx, requestBody)
+	if err != nil {
+		return nil, createTransactionError(ctx, requestBody, err)
+	}
+
+	return &pismov1.CreateTransactionResponse{Transaction: toProtoTransaction(&models.ListTransactionsRow{
+		Uuid:            txn.Uuid,
+		SerialID:        txn.SerialID,
+		AccountID:       txn.AccountID,
+		Amount:          txn.Amount,
+		OperationTypeID: txn.OperationTypeID,
+		EventDate:       txn.EventDate,
+		Balance:         txn.Balance,
+		MerchantID:      txn.MerchantID,
+		MerchantCountry: txn.MerchantCountry,
+		Mcc:             txn.Mcc,
+		Metadata:        txn.Metadata,
+		Tags:            txn.Tags,
+		Notes:           txn.Notes,
+		UpdatedAt:       txn.UpdatedAt,
+	})}, nil
+}
+
+// createTransactionError converts the error that failed the creation of the transaction, like
+// writeCreateTransactionError does for the handler
+func createTransactionError(ctx context.Context, requestBody *CreateTransactionRequestData, err error) error {
+	if apiErr := violationError(err); apiErr != nil {
+		logging.FromContext(ctx).Info("createTransactionError: transaction rejected", "account_id", requestBody.AccountId, "error", err)
+		return rpc.FailedPrecondition(ctx, apiErr)
+	}
+
+	switch {
+	case errors.Is(err, errAccountNotFound):
+		logging.FromContext(ctx).Info("createTransactionError: account does not exist", "account_id", requestBody.AccountId)
+		return rpc.NotFound(ctx, &response.APIError{
+			Code:    response.ErrAccountNotFound,
+			Message: errAccountNotFound.Error(),
+		})
+
+	case errors.Is(err, errOperationTypeNotFound):
+		logging.FromContext(ctx).Info("createTransactionError: operation type does not exist", "operation_type_id", requestBody.OperationTypeId)
+		return rpc.NotFound(ctx, &response.APIError{
+			Code:    response.ErrOperationTypeNotFound,
+			Message: errOperationTypeNotFound.Error(),
+		})
+
+	default:
+		logging.FromContext(ctx).Error("createTransactionError: failed to create transaction", "account_id", requestBody.AccountId, "error", err)
+		return rpc.Internal(ctx, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to create transaction.",
+		})
+	}
+}
+
+// GetTransaction returns the details of the transaction of the request
+func (s *GRPCServer) GetTransaction(ctx context.Context, req *pismov1.GetTransactionRequest) (*pismov1.GetTransactionResponse, error) {
+	requestData := &getTransactionRequestData{TransactionId: req.GetTransactionId()}
+	if apiErr := s.reader.Validate(ctx, requestData); apiErr != nil {
+		return nil, rpc.InvalidArgument(ctx, apiErr)
+	}
+
+	if err := s.policy.CheckTransaction(ctx, auth.ScopeTransactionsRead, requestData.TransactionId); err != nil {
+		return nil, rpc.AuthorizationError(ctx, auth.ScopeTransactionsRead, err, "transaction_id", requestData.TransactionId)
+	}
+
+	txn, err := s.repository.getTransactionDetails(ctx, requestData.TransactionId)
+	if errors.Is(err, errTransactionNotFound) {
+		logging.FromContext(ctx).Info("GetTransaction: transaction not found", "transaction_id", requestData.TransactionId)
+		return nil, rpc.NotFound(ctx, &response.APIError{
+			Code:    response.ErrTransactionNotFound,
+			Message: errTransactionNotFound.Error(),
+		})
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("GetTransaction: failed to fetch transaction details", "transaction_id", requestData.TransactionId, "error", err)
+		return nil, rpc.Internal(ctx, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to fetch transaction details.",
+		})
+	}
+
+	// The details have the same columns as the rows of the list
+	row := models.ListTransactionsRow(*txn)
+
+	return &pismov1.GetTransactionResponse{Transaction: toProtoTransaction(&row)}, nil
+}
+
+// ListTransactions streams the transactions matching the filters of the request, after its cursor. The transactions
+// are read by pages from the repository, until the limit of the request or the last transaction
+func (s *GRPCServer) ListTransactions(req *pismov1.ListTransactionsRequest, stream grpc.ServerStreamingServer[pismov1.ListTransactionsResponse]) error {
+	ctx := stream.Context()
+
+	queryParams := &ListTransactionsQueryParams{
+		AccountId: req.GetAccountId(),
+		Metadata:  req.GetMetadata(),
+		Tags:      req.GetTags(),
+		After:     req.GetAfter(),
+		Limit:     streamPageSize,
+	}
+	if apiErr := s.reader.Validate(ctx, queryParams); apiErr != nil {
+		return rpc.InvalidArgument(ctx, apiErr)
+	}
+
+	// The transactions of all the accounts are listed without an account, which the users are not allowed to
+	var err error
+	if queryParams.AccountId == "" {
+		err = s.policy.Check(ctx, auth.ScopeTransactionsRead)
+	} else {
+		err = s.policy.CheckAccount(ctx, auth.ScopeTransactionsRead, queryParams.AccountId)
+	}
+
+	if err != nil {
+		return rpc.AuthorizationError(ctx, auth.ScopeTransactionsRead, err, "account_id", queryParams.AccountId)
+	}
+
+	limit := req.GetLimit()
+	sent := uint32(0)
+
+	for {
+		if limit > 0 && limit-sent < streamPageSize {
+			queryParams.Limit = int32(limit - sent)
+		}
+
+		transactions, err := s.repository.listTransactions(ctx, queryParams.listParams())
+		if err != nil {
+			logging.FromContext(ctx).Error("ListTransactions: failed to list transactions", "error", err)
+			return rpc.Internal(ctx, &response.APIError{
+				Code:    response.DefaultErrorCode,
+				Message: "Failed to list transactions.",
+			})
+		}
+
+		for _, txn := range transactions {
+			if err := stream.Send(&pismov1.ListTransactionsResponse{Transaction: toProtoTransaction(txn)}); err != nil {
+				return err
+			}
+		}
+
+		sent += uint32(len(transactions))
+
+		// A page that isn't full is the last one
+		if len(transactions) < int(queryParams.Limit) || (limit > 0 && sent >= limit) {
+			return nil
+		}
+
+		queryParams.After = transactions[len(transactions)-1].SerialID
+	}
+}
+
+func toProtoTransaction(txn *models.ListTransactionsRow) *pismov1.Transaction {
+	// The metadata is always an object of strings, it is written from the metadata of the requests
+	var metadata map[string]string
+	_ = json.Unmarshal(txn.Metadata, &metadata)
+
+	return &pismov1.Transaction{
+		Id:              txn.Uuid,
+		SerialId:        txn.SerialID,
+		AccountId:       txn.AccountID,
+		Amount:          txn.Amount,
+		OperationTypeId: txn.OperationTypeID,
+		EventDate:       timestamppb.New(txn.EventDate),
+		Balance:         txn.Balance,
+		MerchantId:      txn.MerchantID.String,
+		MerchantCountry: txn.MerchantCountry.String,
+		Mcc:             txn.Mcc.String,
+		ReversalOf:      txn.ReversalOf.String,
+		Metadata:        metadata,
+		Tags:            txn.Tags,
+		Notes:           txn.Notes,
+		UpdatedAt:       timestamppb.New(txn.UpdatedAt),
+	}
+}
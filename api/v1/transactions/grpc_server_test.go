@@ -0,0 +1,253 @@
+package transactions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	pismov1 "github.com/imjenal/transaction-service/api/proto/pismo/v1"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/internal/rpc"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestGRPCServer(t *testing.T, mockRepo *mock.MockQuerier) *GRPCServer {
+	writer := response.NewJSONWriter()
+	repository := &Repository{querier: mockRepo}
+	service := NewService(repository, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig)
+
+	return NewGRPCServer(request.NewReader(writer, validator.New()), repository, service, auth.NewPolicy(mockRepo, writer))
+}
+
+// adminContext authenticates the call as an admin, like asAdmin does for the requests
+func adminContext() context.Context {
+	return auth.NewContext(context.Background(), &auth.Identity{Subject: "admin", Method: auth.MethodAPIKey, Scopes: []auth.Scope{auth.ScopeAdmin}})
+}
+
+// fakeStream records the messages of a server stream
+type fakeStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*pismov1.ListTransactionsResponse
+}
+
+func (s *fakeStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeStream) Send(res *pismov1.ListTransactionsResponse) error {
+	s.sent = append(s.sent, res)
+	return nil
+}
+
+func TestGRPCServer_CreateTransaction_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	server := newTestGRPCServer(t, mockRepo)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehaviorNEGATIVE, nil)
+	mockRepo.EXPECT().CreateRiskDecision(gomock.Any(), gomock.Any()).Return(nil)
+	mockRepo.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.CreateTransactionParams) (*models.CreateTransactionRow, error) {
+			assert.Equal(t, -100.0, arg.Amount)
+			return &models.CreateTransactionRow{Uuid: dummyTransactionID, Amount: arg.Amount, Metadata: arg.Metadata}, nil
+		})
+
+	// Call the server
+	res, err := server.CreateTransaction(adminContext(), &pismov1.CreateTransactionRequest{
+		AccountId:       dummyAccountId,
+		OperationTypeId: dummyOperationType,
+		Amount:          100.0,
+		Metadata:        map[string]string{"order_id": "ord_123"},
+	})
+
+	// Check the results
+	assert.Nil(t, err)
+	assert.Equal(t, dummyTransactionID, res.GetTransaction().GetId())
+	assert.Equal(t, -100.0, res.GetTransaction().GetAmount())
+	assert.Equal(t, map[string]string{"order_id": "ord_123"}, res.GetTransaction().GetMetadata())
+}
+
+func TestGRPCServer_CreateTransaction_ValidationError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestGRPCServer(t, mock.NewMockQuerier(ctrl))
+
+	// Call the server
+	_, err := server.CreateTransaction(adminContext(), &pismov1.CreateTransactionRequest{
+		AccountId:       "invalid-account-id",
+		OperationTypeId: dummyOperationType,
+		Amount:          -100.0,
+	})
+
+	// Check the results, the details are the error of the HTTP API
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	details := rpc.DetailsFromError(err)
+	assert.Equal(t, int32(response.ValidationFailed), details.GetCode())
+	assert.ElementsMatch(t, []any{"account_id", "amount"}, details.GetData().AsInterface())
+}
+
+func TestGRPCServer_CreateTransaction_AccountInactive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	server := newTestGRPCServer(t, mockRepo)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusBLOCKED, nil)
+	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehaviorNEGATIVE, nil)
+
+	// Call the server
+	_, err := server.CreateTransaction(adminContext(), &pismov1.CreateTransactionRequest{
+		AccountId:       dummyAccountId,
+		OperationTypeId: dummyOperationType,
+		Amount:          100.0,
+	})
+
+	// Check the results
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	assert.Equal(t, int32(response.ErrAccountInactive), rpc.DetailsFromError(err).GetCode())
+}
+
+func TestGRPCServer_GetTransaction_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	server := newTestGRPCServer(t, mockRepo)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetTransactionDetailsByTransactionId(gomock.Any(), dummyTransactionID).Return(nil, pgx.ErrNoRows)
+
+	// Call the server
+	_, err := server.GetTransaction(adminContext(), &pismov1.GetTransactionRequest{TransactionId: dummyTransactionID})
+
+	// Check the results
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, int32(response.ErrTransactionNotFound), rpc.DetailsFromError(err).GetCode())
+}
+
+func TestGRPCServer_GetTransaction_Forbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	server := newTestGRPCServer(t, mockRepo)
+
+	// Prepare mock responses, the transaction belongs to another user
+	mockRepo.EXPECT().GetTransactionOwner(gomock.Any(), dummyTransactionID).Return("another-user", nil)
+
+	ctx := auth.NewContext(context.Background(), &auth.Identity{Subject: "user", Method: auth.MethodJWT, UserID: "user"})
+
+	// Call the server
+	_, err := server.GetTransaction(ctx, &pismov1.GetTransactionRequest{TransactionId: dummyTransactionID})
+
+	// Check the results
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, int32(response.Forbidden), rpc.DetailsFromError(err).GetCode())
+}
+
+func TestGRPCServer_ListTransactions_StreamsPages(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	server := newTestGRPCServer(t, mockRepo)
+
+	// Prepare mock responses, the pages are read until the limit
+	page := func(after int64, size int) []*models.ListTransactionsRow {
+		rows := make([]*models.ListTransactionsRow, size)
+		for i := range rows {
+			rows[i] = &models.ListTransactionsRow{SerialID: after + int64(i) + 1, AccountID: dummyAccountId}
+		}
+		return rows
+	}
+
+	gomock.InOrder(
+		mockRepo.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, arg models.ListTransactionsParams) ([]*models.ListTransactionsRow, error) {
+				assert.Equal(t, int64(10), arg.AfterSerialID)
+				assert.Equal(t, int32(streamPageSize), arg.PageSize)
+				assert.Equal(t, []string{"groceries"}, arg.Tags)
+				return page(10, streamPageSize), nil
+			}),
+		mockRepo.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, arg models.ListTransactionsParams) ([]*models.ListTransactionsRow, error) {
+				assert.Equal(t, int64(110), arg.AfterSerialID)
+				assert.Equal(t, int32(20), arg.PageSize)
+				return page(110, 20), nil
+			}),
+	)
+
+	stream := &fakeStream{ctx: adminContext()}
+
+	// Call the server
+	err := server.ListTransactions(&pismov1.ListTransactionsRequest{
+		AccountId: dummyAccountId,
+		Tags:      []string{"Groceries"},
+		After:     10,
+		Limit:     120,
+	}, stream)
+
+	// Check the results
+	assert.Nil(t, err)
+	assert.Len(t, stream.sent, 120)
+	assert.Equal(t, int64(11), stream.sent[0].GetTransaction().GetSerialId())
+	assert.Equal(t, int64(130), stream.sent[119].GetTransaction().GetSerialId())
+}
+
+func TestGRPCServer_ListTransactions_LastPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	server := newTestGRPCServer(t, mockRepo)
+
+	// Prepare mock responses, a page that isn't full ends the stream
+	mockRepo.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).Return([]*models.ListTransactionsRow{{SerialID: 1}, {SerialID: 2}}, nil)
+
+	stream := &fakeStream{ctx: adminContext()}
+
+	// Call the server
+	err := server.ListTransactions(&pismov1.ListTransactionsRequest{}, stream)
+
+	// Check the results
+	assert.Nil(t, err)
+	assert.Len(t, stream.sent, 2)
+}
+
+func TestGRPCServer_ListTransactions_AllAccountsForbidden(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestGRPCServer(t, mock.NewMockQuerier(ctrl))
+
+	// The users can only list the transactions of their accounts
+	ctx := auth.NewContext(context.Background(), &auth.Identity{Subject: "user", Method: auth.MethodJWT, UserID: "user"})
+	stream := &fakeStream{ctx: ctx}
+
+	// Call the server
+	err := server.ListTransactions(&pismov1.ListTransactionsRequest{}, stream)
+
+	// Check the results
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Empty(t, stream.sent)
+}
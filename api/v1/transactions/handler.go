@@ -1,6 +1,11 @@
 package transactions
 
 import (
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/risk"
 	"github.com/imjenal/transaction-service/pkg/http/request"
 	"github.com/imjenal/transaction-service/pkg/http/response"
 )
@@ -9,12 +14,17 @@ type Handler struct {
 	reader     *request.Reader
 	writer     *response.JSONWriter
 	repository *Repository
+	service    *Service
+	// policy authorizes the callers on the accounts of the request bodies and the queries
+	policy *auth.Policy
 }
 
-func NewHandler(reader *request.Reader, writer *response.JSONWriter, repository *Repository) *Handler {
+func NewHandler(reader *request.Reader, writer *response.JSONWriter, repository *Repository, riskEngine *risk.Engine, rewardsEngine *rewards.Engine, clock clock.Clock, config *config.Transactions, policy *auth.Policy) *Handler {
 	return &Handler{
 		reader:     reader,
 		writer:     writer,
 		repository: repository,
+		service:    NewService(repository, riskEngine, rewardsEngine, clock, config),
+		policy:     policy,
 	}
 }
@@ -0,0 +1,117 @@
+package transactions
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"strings"
+
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+const defaultTransactionsPageSize = 20
+
+type ListTransactionsQueryParams struct {
+	AccountId string `schema:"account_id" validate:"omitempty,uuid"`
+	// Metadata filters on the metadata, key:value matches the transactions with the value for the key
+	// and key alone matches the transactions with the key. All the filters must match
+	Metadata []string `schema:"metadata" validate:"omitempty,max=10,dive,min=1,max=541"`
+	// Tags filters on the tags, the transactions must have all the tags
+	Tags []string `schema:"tag" validate:"omitempty,max=10,dive,min=1,max=32"`
+	// Limit is the maximum number of transactions in the page
+	Limit int32 `schema:"limit" validate:"omitempty,min=1,max=100"`
+	// After is the cursor returned as next_after by the previous page
+	After int64 `schema:"after" validate:"omitempty,min=0"`
+}
+
+type ListTransactionsResponseData struct {
+	Transactions []*models.ListTransactionsRow `json:"transactions"`
+	// NextAfter is the cursor of the next page, it's not set on the last page
+	NextAfter *int64 `json:"next_after,omitempty"`
+}
+
+// listTransactions handles listing the transactions, the transactions are paginated by the after cursor
+func (h *Handler) listTransactions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queryParams := &ListTransactionsQueryParams{}
+		if ok := h.reader.ReadQueryParamsAndValidate(w, r, queryParams); !ok {
+			return
+		}
+
+		// The transactions of all the accounts are listed without an account, which the users are not allowed to
+		if queryParams.AccountId == "" && !h.policy.Authorize(w, r, auth.ScopeTransactionsRead) {
+			return
+		}
+
+		if queryParams.AccountId != "" && !h.policy.AuthorizeAccount(w, r, auth.ScopeTransactionsRead, queryParams.AccountId) {
+			return
+		}
+
+		if queryParams.Limit == 0 {
+			queryParams.Limit = defaultTransactionsPageSize
+		}
+
+		h.fetchAndRespondTransactions(r.Context(), w, queryParams)
+	}
+}
+
+// fetchAndRespondTransactions fetches a page of transactions and responds to the client
+func (h *Handler) fetchAndRespondTransactions(ctx context.Context, w http.ResponseWriter, queryParams *ListTransactionsQueryParams) {
+	transactions, err := h.repository.listTransactions(ctx, queryParams.listParams())
+	if err != nil {
+		logging.FromContext(ctx).Error("fetchAndRespondTransactions: failed to list transactions", "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to list transactions.",
+		})
+		return
+	}
+
+	res := &ListTransactionsResponseData{Transactions: transactions}
+	if transactions == nil {
+		res.Transactions = []*models.ListTransactionsRow{}
+	}
+
+	// A full page means there may be more transactions
+	if len(transactions) == int(queryParams.Limit) {
+		res.NextAfter = &transactions[len(transactions)-1].SerialID
+	}
+
+	h.writer.Ok(w, res)
+}
+
+// listParams converts the query params to the params of the page of transactions
+func (q *ListTransactionsQueryParams) listParams() models.ListTransactionsParams {
+	metadata, metadataKeys := parseMetadataFilters(q.Metadata)
+
+	return models.ListTransactionsParams{
+		AccountID:     sql.NullString{String: q.AccountId, Valid: q.AccountId != ""},
+		Metadata:      metadataJSON(metadata),
+		MetadataKeys:  metadataKeys,
+		Tags:          normalizeTags(q.Tags),
+		AfterSerialID: q.After,
+		PageSize:      q.Limit,
+	}
+}
+
+// parseMetadataFilters splits the metadata filters into the pairs the metadata must contain and the keys it must have.
+// The keys are never nil, an empty list matches every transaction
+func parseMetadataFilters(filters []string) (map[string]string, []string) {
+	metadata := map[string]string{}
+	keys := make([]string, 0, len(filters))
+
+	for _, filter := range filters {
+		key, value, found := strings.Cut(filter, ":")
+		if !found {
+			keys = append(keys, key)
+			continue
+		}
+
+		metadata[key] = value
+	}
+
+	return metadata, keys
+}
@@ -0,0 +1,132 @@
+package transactions
+
+import (
+	"encoding/json"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListTransactionsHandler_Filters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, a full page has a cursor to the next page
+	mockRepo.EXPECT().ListTransactions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, arg models.ListTransactionsParams) ([]*models.ListTransactionsRow, error) {
+			assert.Equal(t, dummyAccountId, arg.AccountID.String)
+			assert.JSONEq(t, `{"order_id": "ord:123"}`, string(arg.Metadata))
+			assert.Equal(t, []string{"invoice"}, arg.MetadataKeys)
+			assert.Equal(t, []string{"groceries"}, arg.Tags)
+			assert.Equal(t, int64(10), arg.AfterSerialID)
+			assert.Equal(t, int32(2), arg.PageSize)
+			return []*models.ListTransactionsRow{{SerialID: 11}, {SerialID: 12}}, nil
+		})
+
+	// Prepare the request, the value of a metadata filter can contain a colon
+	req := asAdmin(httptest.NewRequest(http.MethodGet, "/transactions?account_id="+dummyAccountId+"&metadata=order_id:ord:123&metadata=invoice&tag=Groceries&limit=2&after=10", nil))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.listTransactions()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data *ListTransactionsResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Len(t, res.Data.Transactions, 2)
+	assert.Equal(t, int64(12), *res.Data.NextAfter)
+}
+
+func TestListTransactionsHandler_WithoutFilters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, the empty filters match every transaction
+	mockRepo.EXPECT().ListTransactions(gomock.Any(), models.ListTransactionsParams{
+		Metadata:     json.RawMessage("{}"),
+		MetadataKeys: []string{},
+		Tags:         []string{},
+		PageSize:     defaultTransactionsPageSize,
+	}).Return(nil, nil)
+
+	// Prepare the request
+	req := asAdmin(httptest.NewRequest(http.MethodGet, "/transactions", nil))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.listTransactions()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.JSONEq(t, `{"data": {"transactions": []}, "error": null}`, rr.Body.String())
+}
+
+func TestListTransactionsHandler_InvalidAccountID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare the request
+	req := asAdmin(httptest.NewRequest(http.MethodGet, "/transactions?account_id=not-a-uuid", nil))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.listTransactions()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
+
+func TestListTransactionsHandler_AllAccountsAsAUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare the request, only the partners and the admins list the transactions of every account
+	const userID = "88e0e837-e7f2-47b1-a08c-3af267c03088"
+	req := withIdentity(httptest.NewRequest(http.MethodGet, "/transactions", nil),
+		&auth.Identity{Subject: userID, Method: auth.MethodJWT, UserID: userID})
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.listTransactions()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusForbidden, rr.Code)
+}
@@ -0,0 +1,49 @@
+package transactions
+
+import (
+	"net/http"
+
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type QuoteResponseData struct {
+	*Quote
+	// Approved is true when the transaction doesn't break any rule
+	Approved   bool                 `json:"approved"`
+	Violations []*response.APIError `json:"violations"`
+}
+
+// quoteTransaction handles quoting a transaction, it responds with what creating the transaction would do
+// without creating it
+func (h *Handler) quoteTransaction() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CreateTransactionRequestData{}
+		if ok := h.reader.ReadJSONAndValidate(w, r, requestBody); !ok {
+			return
+		}
+
+		// A quote doesn't change anything, reading the transactions of the account is enough
+		if !h.policy.AuthorizeAccount(w, r, auth.ScopeTransactionsRead, requestBody.AccountId) {
+			return
+		}
+
+		quote, err := h.service.Quote(r.Context(), requestBody)
+		if err != nil {
+			h.writeCreateTransactionError(r.Context(), w, requestBody, err)
+			return
+		}
+
+		data := &QuoteResponseData{
+			Quote:      quote,
+			Approved:   len(quote.Violations) == 0,
+			Violations: make([]*response.APIError, 0, len(quote.Violations)),
+		}
+
+		for _, violation := range quote.Violations {
+			data.Violations = append(data.Violations, violationError(violation))
+		}
+
+		h.writer.Ok(w, data)
+	}
+}
@@ -0,0 +1,159 @@
+package transactions
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteTransactionHandler_Allocations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	olderDebtID := "4b7c8d9e-0f1a-4b2c-8d3e-4f5a6b7c8d9e"
+	newerDebtID := "5c8d9e0f-1a2b-4c3d-9e4f-5a6b7c8d9e0f"
+
+	// Prepare mock responses, nothing is written: no risk decision, no limit usage, no balance and no transaction
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusACTIVE, nil)
+	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), int64(4)).Return(models.AmountBehaviorPOSITIVE, nil)
+	mockRepo.EXPECT().GetAccountLimitsWithUsage(gomock.Any(), gomock.Any()).Return(nil, nil)
+	mockRepo.EXPECT().GetNegativeBalanceTransactionsByAccountID(gomock.Any(), dummyAccountId).Return([]*models.GetNegativeBalanceTransactionsByAccountIDRow{
+		{Uuid: olderDebtID, Balance: -30, EventDate: dummyNow.Add(-48 * time.Hour)},
+		{Uuid: newerDebtID, Balance: -50, EventDate: dummyNow.Add(-24 * time.Hour)},
+	}, nil)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateTransactionRequestData{
+		AccountId:       dummyAccountId,
+		OperationTypeId: 4,
+		Amount:          60,
+	})
+
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions/quote", bytes.NewReader(requestBody)))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.quoteTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data *QuoteResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.True(t, res.Data.Approved)
+	assert.Empty(t, res.Data.Violations)
+	assert.Equal(t, float64(60), res.Data.Amount)
+	assert.Equal(t, float64(0), res.Data.RemainingBalance)
+	assert.Len(t, res.Data.Allocations, 2)
+	assert.Equal(t, Allocation{
+		TransactionID: olderDebtID,
+		EventDate:     dummyNow.Add(-48 * time.Hour),
+		BalanceBefore: -30,
+		Amount:        30,
+		BalanceAfter:  0,
+	}, *res.Data.Allocations[0])
+	assert.Equal(t, float64(-20), res.Data.Allocations[1].BalanceAfter)
+}
+
+func TestQuoteTransactionHandler_Violations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses, a blocked account can't be debited and the daily limit would be breached
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatusBLOCKED, nil)
+	mockRepo.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationType).Return(models.AmountBehaviorNEGATIVE, nil)
+	mockRepo.EXPECT().GetAccountLimitsWithUsage(gomock.Any(), gomock.Any()).Return([]*models.GetAccountLimitsWithUsageRow{
+		{OperationTypeID: dummyOperationType, Period: models.LimitPeriodDAILY, MaxAmount: 100, UsedAmount: 50},
+	}, nil)
+
+	// Prepare the request, the event date is too far in the past
+	eventDate := dummyNow.Add(-96 * time.Hour)
+	requestBody, _ := json.Marshal(CreateTransactionRequestData{
+		AccountId:       dummyAccountId,
+		OperationTypeId: dummyOperationType,
+		Amount:          60,
+		EventDate:       &eventDate,
+	})
+
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions/quote", bytes.NewReader(requestBody)))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.quoteTransaction()(rr, req)
+
+	// Check the results, all the violations are reported
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data *QuoteResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.False(t, res.Data.Approved)
+	assert.Equal(t, float64(-60), res.Data.Amount)
+	assert.Equal(t, float64(-60), res.Data.RemainingBalance)
+	assert.Empty(t, res.Data.Allocations)
+
+	codes := make([]response.ErrorCode, 0, len(res.Data.Violations))
+	for _, violation := range res.Data.Violations {
+		codes = append(codes, violation.Code)
+	}
+	assert.Equal(t, []response.ErrorCode{response.ErrInvalidEventDate, response.ErrAccountInactive, response.ErrSpendingLimitExceeded}, codes)
+}
+
+func TestQuoteTransactionHandler_AccountNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountId).Return(models.AccountStatus(""), pgx.ErrNoRows)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateTransactionRequestData{
+		AccountId:       dummyAccountId,
+		OperationTypeId: dummyOperationType,
+		Amount:          60,
+	})
+
+	req := asAdmin(httptest.NewRequest(http.MethodPost, "/transactions/quote", bytes.NewReader(requestBody)))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.quoteTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -4,26 +4,52 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/imjenal/transaction-service/internal/db"
 	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/limits"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/tracing"
 	"github.com/jackc/pgx/v4"
 )
 
 type Repository struct {
 	querier models.Querier
+	// conn is used to start DB transactions. It is nil in unit tests, where the queries run on the querier directly
+	conn db.TxBeginner
 }
 
-func NewRepository(querier models.Querier) *Repository {
-	return &Repository{querier: querier}
+func NewRepository(querier models.Querier, conn db.TxBeginner) *Repository {
+	return &Repository{querier: querier, conn: conn}
+}
+
+// withTx runs fn in a DB transaction. All the queries of the repository passed to fn run in the DB transaction
+func (r *Repository) withTx(ctx context.Context, fn func(repo *Repository) error) error {
+	if r.conn == nil {
+		return fn(r)
+	}
+
+	return db.RunInTx(ctx, r.conn, func(tx pgx.Tx) error {
+		return fn(&Repository{querier: models.New(tx)})
+	})
 }
 
 var (
 	errTransactionNotFound   = errors.New("TRANSACTION_NOT_FOUND")
 	errOperationTypeNotFound = errors.New("OPERATION_TYPE_NOT_FOUND")
 	errAccountNotFound       = errors.New("ACCOUNT_NOT_FOUND")
+	errAccountInactive       = errors.New("ACCOUNT_INACTIVE")
+	errInvalidEventDate      = errors.New("INVALID_EVENT_DATE")
+	errTransactionDeclined   = errors.New("TRANSACTION_DECLINED")
+	errSpendingLimitExceeded = limits.ErrLimitExceeded
+	errReversalExceedsAmount = errors.New("REVERSAL_EXCEEDS_AMOUNT")
 )
 
 func (r *Repository) getTransactionDetails(ctx context.Context, uuid string) (*models.GetTransactionDetailsByTransactionIdRow, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.getTransactionDetails")
+	defer span.End()
+
 	transactionDetails, err := r.querier.GetTransactionDetailsByTransactionId(ctx, uuid)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -37,7 +63,40 @@ func (r *Repository) getTransactionDetails(ctx context.Context, uuid string) (*m
 	return transactionDetails, nil
 }
 
+// updateAnnotations updates the tags and the notes of the transaction, the fields that are null are left unchanged
+func (r *Repository) updateAnnotations(ctx context.Context, arg models.UpdateTransactionAnnotationsParams) (*models.UpdateTransactionAnnotationsRow, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.updateAnnotations")
+	defer span.End()
+
+	transactionDetails, err := r.querier.UpdateTransactionAnnotations(ctx, arg)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errTransactionNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("repo.updateAnnotations: error: %w", err)
+	}
+
+	return transactionDetails, nil
+}
+
+// listTransactions fetches a page of the transactions matching the filters
+func (r *Repository) listTransactions(ctx context.Context, arg models.ListTransactionsParams) ([]*models.ListTransactionsRow, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.listTransactions")
+	defer span.End()
+
+	transactions, err := r.querier.ListTransactions(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("repo.listTransactions: error: %w", err)
+	}
+
+	return transactions, nil
+}
+
 func (r *Repository) createTransaction(ctx context.Context, arg models.CreateTransactionParams) (*models.CreateTransactionRow, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.createTransaction")
+	defer span.End()
+
 	transactionDetails, err := r.querier.CreateTransaction(ctx, arg)
 
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -51,15 +110,82 @@ func (r *Repository) createTransaction(ctx context.Context, arg models.CreateTra
 	return transactionDetails, nil
 }
 
-func (r *Repository) accountExists(ctx context.Context, accountID string) (bool, error) {
-	exists, err := r.querier.AccountExists(ctx, accountID)
+func (r *Repository) getTransactionForUpdate(ctx context.Context, uuid string) (*models.GetTransactionForUpdateRow, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.getTransactionForUpdate")
+	defer span.End()
+
+	transaction, err := r.querier.GetTransactionForUpdate(ctx, uuid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errTransactionNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("repo.getTransactionForUpdate: error: %w", err)
+	}
+
+	return transaction, nil
+}
+
+func (r *Repository) getReversedAmount(ctx context.Context, uuid string) (float64, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.getReversedAmount")
+	defer span.End()
+
+	reversed, err := r.querier.GetReversedAmount(ctx, uuid)
+	if err != nil {
+		return 0, fmt.Errorf("repo.getReversedAmount: error: %w", err)
+	}
+
+	return reversed, nil
+}
+
+func (r *Repository) createReversalTransaction(ctx context.Context, arg models.CreateReversalTransactionParams) (*models.CreateReversalTransactionRow, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.createReversalTransaction")
+	defer span.End()
+
+	transactionDetails, err := r.querier.CreateReversalTransaction(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("repo.createReversalTransaction: error: %w", err)
+	}
+
+	return transactionDetails, nil
+}
+
+func (r *Repository) getAccountStatus(ctx context.Context, accountID string) (models.AccountStatus, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.getAccountStatus")
+	defer span.End()
+
+	status, err := r.querier.GetAccountStatus(ctx, accountID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", errAccountNotFound
+	}
+
 	if err != nil {
-		return false, fmt.Errorf("repo.accountExists: error checking account existence: %w", err)
+		return "", fmt.Errorf("repo.getAccountStatus: error fetching account status: %w", err)
 	}
-	return exists, nil
+	return status, nil
+}
+
+// getAccountStatusForUpdate fetches the status of the account and locks it until the end of the DB transaction, so that
+// the status can't change before the transaction is created
+func (r *Repository) getAccountStatusForUpdate(ctx context.Context, accountID string) (models.AccountStatus, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.getAccountStatusForUpdate")
+	defer span.End()
+
+	status, err := r.querier.GetAccountStatusForUpdate(ctx, accountID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", errAccountNotFound
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("repo.getAccountStatusForUpdate: error fetching account status: %w", err)
+	}
+	return status, nil
 }
 
 func (r *Repository) getAmountBehavior(ctx context.Context, operationTypeID int64) (models.AmountBehavior, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.getAmountBehavior")
+	defer span.End()
+
 	amountBehavior, err := r.querier.GetOperationTypeAmountBehavior(ctx, operationTypeID)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return "", errOperationTypeNotFound
@@ -72,6 +198,9 @@ func (r *Repository) getAmountBehavior(ctx context.Context, operationTypeID int6
 }
 
 func (r *Repository) getNegativeBalanceTransactionsByAccountID(ctx context.Context, accountID string) ([]*models.GetNegativeBalanceTransactionsByAccountIDRow, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.getNegativeBalanceTransactionsByAccountID")
+	defer span.End()
+
 	transactions, err := r.querier.GetNegativeBalanceTransactionsByAccountID(ctx, accountID)
 	if errors.Is(err, pgx.ErrNoRows) {
 		return nil, nil
@@ -84,6 +213,9 @@ func (r *Repository) getNegativeBalanceTransactionsByAccountID(ctx context.Conte
 }
 
 func (r *Repository) updateTransactionBalances(ctx context.Context, transactions []*models.GetNegativeBalanceTransactionsByAccountIDRow) error {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.updateTransactionBalances")
+	defer span.End()
+
 	for _, txn := range transactions {
 		args := models.UpdateTransactionBalancesParams{
 			Uuid:    txn.Uuid,
@@ -102,3 +234,36 @@ func (r *Repository) updateTransactionBalances(ctx context.Context, transactions
 
 	return nil
 }
+
+// consumeLimits increments the usage of the spending limits of the account, it fails when a limit would be breached
+// The limits are counted in the periods containing at, the processing time of the transaction
+func (r *Repository) consumeLimits(ctx context.Context, accountID string, operationTypeID int64, amount float64, at time.Time) error {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.consumeLimits")
+	defer span.End()
+
+	return limits.Consume(ctx, r.querier, accountID, operationTypeID, amount, at)
+}
+
+// checkLimits returns the spending limits of the account the amount would breach, without incrementing their usage
+func (r *Repository) checkLimits(ctx context.Context, accountID string, operationTypeID int64, amount float64, at time.Time) ([]*limits.ExceededError, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.checkLimits")
+	defer span.End()
+
+	return limits.Check(ctx, r.querier, accountID, operationTypeID, amount, at)
+}
+
+// accrueRewards records the rewards the transaction earns with the rules of the engine
+func (r *Repository) accrueRewards(ctx context.Context, engine *rewards.Engine, txn *rewards.Transaction) error {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.accrueRewards")
+	defer span.End()
+
+	return engine.Accrue(ctx, r.querier, txn)
+}
+
+// reverseRewards takes back the share of the rewards of the original transaction that is reversed
+func (r *Repository) reverseRewards(ctx context.Context, accountID, originalID, reversalID string, share float64) error {
+	ctx, span := tracing.Start(ctx, "transactions.Repository.reverseRewards")
+	defer span.End()
+
+	return rewards.Reverse(ctx, r.querier, accountID, originalID, reversalID, share)
+}
@@ -4,9 +4,21 @@ import (
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/pkg/http/request"
 )
 
-func Routes(r *mux.Router, h *Handler) {
-	r.HandleFunc("", h.createTransaction()).Methods(http.MethodPost)
-	r.HandleFunc("/{transactionID}", h.getTransactionDetails()).Methods(http.MethodGet)
+// maxBodyBytes is the size limit of the bodies of the transactions, the metadata is at most 4KB
+const maxBodyBytes = 16 << 10
+
+// Routes adds the routes of the transactions. The handlers of the routes without a transaction authorize
+// the callers on the account of the request
+func Routes(r *mux.Router, h *Handler, p *auth.Policy) {
+	limitBody := request.RouteLimit(maxBodyBytes)
+
+	r.Handle("", limitBody(h.createTransaction())).Methods(http.MethodPost)
+	r.HandleFunc("", h.listTransactions()).Methods(http.MethodGet)
+	r.Handle("/quote", limitBody(h.quoteTransaction())).Methods(http.MethodPost)
+	r.HandleFunc("/{transactionID}", p.Transaction(auth.ScopeTransactionsRead, h.getTransactionDetails())).Methods(http.MethodGet)
+	r.Handle("/{transactionID}", limitBody(p.Transaction(auth.ScopeTransactionsWrite, h.updateTransaction()))).Methods(http.MethodPatch)
 }
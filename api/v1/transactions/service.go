@@ -0,0 +1,537 @@
+package transactions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/lifecycle"
+	"github.com/imjenal/transaction-service/internal/metrics"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/risk"
+	"github.com/imjenal/transaction-service/internal/tracing"
+)
+
+// Service creates transactions. It is shared by the HTTP handler and the jobs that post transactions in the background,
+// so that every transaction goes through the same checks
+type Service struct {
+	repository    *Repository
+	riskEngine    *risk.Engine
+	rewardsEngine *rewards.Engine
+	clock         clock.Clock
+	config        *config.Transactions
+}
+
+func NewService(repository *Repository, riskEngine *risk.Engine, rewardsEngine *rewards.Engine, clock clock.Clock, config *config.Transactions) *Service {
+	return &Service{
+		repository:    repository,
+		riskEngine:    riskEngine,
+		rewardsEngine: rewardsEngine,
+		clock:         clock,
+		config:        config,
+	}
+}
+
+type (
+	// AccountInactiveError is returned when the status of the account doesn't allow the transaction
+	AccountInactiveError struct {
+		Status models.AccountStatus `json:"status"`
+	}
+
+	// DeclinedError is returned when the transaction is declined by the risk rules
+	DeclinedError struct {
+		Decision *risk.Decision
+	}
+
+	// EventDateError is returned when the event date is out of the configured range
+	EventDateError struct {
+		Earliest time.Time `json:"earliest"`
+		Latest   time.Time `json:"latest"`
+	}
+)
+
+func (e *AccountInactiveError) Error() string {
+	return fmt.Sprintf("%s: account is %s", errAccountInactive, e.Status)
+}
+
+func (e *AccountInactiveError) Unwrap() error {
+	return errAccountInactive
+}
+
+func (e *DeclinedError) Error() string {
+	return fmt.Sprintf("%s: rule %s: %s", errTransactionDeclined, e.Decision.Rule, e.Decision.ReasonCode)
+}
+
+func (e *DeclinedError) Unwrap() error {
+	return errTransactionDeclined
+}
+
+func (e *EventDateError) Error() string {
+	return fmt.Sprintf("%s: must be between %s and %s", errInvalidEventDate, e.Earliest, e.Latest)
+}
+
+func (e *EventDateError) Unwrap() error {
+	return errInvalidEventDate
+}
+
+// IsRejected checks if the transaction was rejected by a business rule.
+// A rejected transaction fails the same way when it is retried, unlike the other errors.
+func IsRejected(err error) bool {
+	for _, rejection := range []error{errAccountNotFound, errOperationTypeNotFound, errAccountInactive, errTransactionDeclined, errSpendingLimitExceeded, errInvalidEventDate, errReversalExceedsAmount} {
+		if errors.Is(err, rejection) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Create checks and creates the transaction. The event date of the request is set to now when it is missing.
+// The spending limits, the discharge of the debts, the creation of the transaction and the accrual of its rewards
+// run in a single DB transaction, so either all of them succeed or none of them do
+func (s *Service) Create(ctx context.Context, requestBody *CreateTransactionRequestData) (*models.CreateTransactionRow, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Service.Create")
+	defer span.End()
+
+	if err := s.validateEventDate(requestBody); err != nil {
+		return nil, err
+	}
+
+	accountStatus, err := s.repository.getAccountStatus(ctx, requestBody.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	amountBehavior, err := s.repository.getAmountBehavior(ctx, requestBody.OperationTypeId)
+	if err != nil {
+		return nil, err
+	}
+
+	// Blocked and suspended accounts can still receive credits, closed accounts can't transact at all
+	if !lifecycle.AllowsTransaction(accountStatus, amountBehavior) {
+		return nil, &AccountInactiveError{Status: accountStatus}
+	}
+
+	if err = s.evaluateRisk(ctx, requestBody); err != nil {
+		return nil, err
+	}
+
+	requestBody.Amount = adjustAmountBasedOnOperationTypeAmountBehavior(amountBehavior, requestBody.Amount)
+
+	var txnDetails *models.CreateTransactionRow
+
+	err = s.repository.withTx(ctx, func(repo *Repository) error {
+		// The account may have been blocked or closed since its status was checked, the status is checked again
+		// under the lock the status changes take
+		accountStatus, err := repo.getAccountStatusForUpdate(ctx, requestBody.AccountId)
+		if err != nil {
+			return err
+		}
+
+		if !lifecycle.AllowsTransaction(accountStatus, amountBehavior) {
+			return &AccountInactiveError{Status: accountStatus}
+		}
+
+		err = repo.consumeLimits(ctx, requestBody.AccountId, requestBody.OperationTypeId, math.Abs(requestBody.Amount), s.clock.Now())
+		if err != nil {
+			return err
+		}
+
+		if amountBehavior == models.AmountBehaviorPOSITIVE {
+			txnDetails, err = s.dischargeAndCreateTransaction(ctx, repo, requestBody)
+			return err
+		}
+
+		txnDetails, err = repo.createTransaction(ctx, models.CreateTransactionParams{
+			AccountID:       requestBody.AccountId,
+			OperationTypeID: requestBody.OperationTypeId,
+			Amount:          requestBody.Amount,
+			Balance:         requestBody.Amount,
+			MerchantID:      nullString(requestBody.MerchantId),
+			MerchantCountry: nullString(requestBody.MerchantCountry),
+			EventDate:       *requestBody.EventDate,
+			Mcc:             nullString(requestBody.Mcc),
+			Metadata:        metadataJSON(requestBody.Metadata),
+		})
+		if err != nil {
+			return err
+		}
+
+		// Only debits earn rewards, so the cashback credits can never earn rewards themselves
+		return repo.accrueRewards(ctx, s.rewardsEngine, &rewards.Transaction{
+			ID:              txnDetails.Uuid,
+			AccountID:       txnDetails.AccountID,
+			OperationTypeID: txnDetails.OperationTypeID,
+			MCC:             requestBody.Mcc,
+			Amount:          math.Abs(txnDetails.Amount),
+			EventDate:       txnDetails.EventDate,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.TransactionCreated(txnDetails.OperationTypeID, false)
+
+	// The part of a credit that isn't left in its balance paid the debts of the account
+	if amountBehavior == models.AmountBehaviorPOSITIVE {
+		metrics.Discharged(metrics.DischargeSourceCredit, txnDetails.Amount-txnDetails.Balance)
+	}
+
+	return txnDetails, nil
+}
+
+type (
+	// Allocation is the part of a credit that would pay a debt of the account
+	Allocation struct {
+		TransactionID string    `json:"transaction_id"`
+		EventDate     time.Time `json:"event_date"`
+		BalanceBefore float64   `json:"balance_before"`
+		Amount        float64   `json:"amount"`
+		BalanceAfter  float64   `json:"balance_after"`
+	}
+
+	// Quote is the projection of a transaction that isn't created
+	Quote struct {
+		AccountID       string    `json:"account_id"`
+		OperationTypeID int64     `json:"operation_type_id"`
+		Amount          float64   `json:"amount"`
+		EventDate       time.Time `json:"event_date"`
+		// Allocations are the debts a credit would pay, from the oldest to the newest
+		Allocations []*Allocation `json:"allocations"`
+		// RemainingBalance is the balance the transaction would have, i.e. the credit left after paying the debts
+		RemainingBalance float64 `json:"remaining_balance"`
+		// Violations are the rules the transaction breaks, it would be rejected when there is any
+		Violations []error `json:"-"`
+	}
+)
+
+// Quote runs the checks of Create and projects how a credit would pay the debts of the account, without persisting
+// anything, not even the risk decision. The rules the transaction breaks are returned as violations instead of
+// failing, so the client sees all of them at once. It fails like Create when the account or the operation type
+// doesn't exist. A quote is not a reservation, the transaction can still be rejected when it is created.
+func (s *Service) Quote(ctx context.Context, requestBody *CreateTransactionRequestData) (*Quote, error) {
+	quote := &Quote{
+		AccountID:       requestBody.AccountId,
+		OperationTypeID: requestBody.OperationTypeId,
+		Allocations:     []*Allocation{},
+	}
+
+	if err := s.validateEventDate(requestBody); err != nil {
+		quote.Violations = append(quote.Violations, err)
+	}
+
+	quote.EventDate = *requestBody.EventDate
+
+	accountStatus, err := s.repository.getAccountStatus(ctx, requestBody.AccountId)
+	if err != nil {
+		return nil, err
+	}
+
+	amountBehavior, err := s.repository.getAmountBehavior(ctx, requestBody.OperationTypeId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !lifecycle.AllowsTransaction(accountStatus, amountBehavior) {
+		quote.Violations = append(quote.Violations, &AccountInactiveError{Status: accountStatus})
+	}
+
+	decision, err := s.riskEngine.Simulate(ctx, riskTransaction(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("service.Quote: failed to evaluate risk rules: %w", err)
+	}
+
+	if !decision.Approved {
+		quote.Violations = append(quote.Violations, &DeclinedError{Decision: decision})
+	}
+
+	quote.Amount = adjustAmountBasedOnOperationTypeAmountBehavior(amountBehavior, requestBody.Amount)
+	quote.RemainingBalance = quote.Amount
+
+	exceeded, err := s.repository.checkLimits(ctx, requestBody.AccountId, requestBody.OperationTypeId, math.Abs(quote.Amount), s.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, limitErr := range exceeded {
+		quote.Violations = append(quote.Violations, limitErr)
+	}
+
+	if amountBehavior != models.AmountBehaviorPOSITIVE {
+		return quote, nil
+	}
+
+	// Outside a DB transaction the debts are only locked while they are fetched
+	debts, err := s.repository.getNegativeBalanceTransactionsByAccountID(ctx, requestBody.AccountId)
+	if err != nil {
+		return nil, fmt.Errorf("service.Quote: failed to fetch txns: %w", err)
+	}
+
+	balancesBefore := make([]float64, len(debts))
+	for i, debt := range debts {
+		balancesBefore[i] = debt.Balance
+	}
+
+	debts, quote.RemainingBalance = performDischarge(debts, quote.Amount)
+
+	for i, debt := range debts {
+		if debt.Balance == balancesBefore[i] {
+			continue
+		}
+
+		quote.Allocations = append(quote.Allocations, &Allocation{
+			TransactionID: debt.Uuid,
+			EventDate:     debt.EventDate,
+			BalanceBefore: balancesBefore[i],
+			Amount:        debt.Balance - balancesBefore[i],
+			BalanceAfter:  debt.Balance,
+		})
+	}
+
+	return quote, nil
+}
+
+// validateEventDate checks that the event date is within the configured limits, the event date is set to now when it is missing
+func (s *Service) validateEventDate(requestBody *CreateTransactionRequestData) error {
+	now := s.clock.Now()
+	if requestBody.EventDate == nil {
+		requestBody.EventDate = &now
+		return nil
+	}
+
+	earliest := now.Add(-s.config.MaxEventDateBackdate)
+	latest := now.Add(s.config.MaxEventDateForward)
+
+	if requestBody.EventDate.Before(earliest) || requestBody.EventDate.After(latest) {
+		return &EventDateError{Earliest: earliest, Latest: latest}
+	}
+
+	return nil
+}
+
+// evaluateRisk runs the risk rules against the transaction and declines it when a rule fails
+func (s *Service) evaluateRisk(ctx context.Context, requestBody *CreateTransactionRequestData) error {
+	ctx, span := tracing.Start(ctx, "transactions.Service.evaluateRisk")
+	defer span.End()
+
+	decision, err := s.riskEngine.Evaluate(ctx, riskTransaction(requestBody))
+	if err != nil {
+		return fmt.Errorf("service.evaluateRisk: failed to evaluate risk rules: %w", err)
+	}
+
+	if !decision.Approved {
+		return &DeclinedError{Decision: decision}
+	}
+
+	return nil
+}
+
+// riskTransaction is the transaction of the request evaluated by the risk rules
+func riskTransaction(requestBody *CreateTransactionRequestData) *risk.Transaction {
+	return &risk.Transaction{
+		AccountID:       requestBody.AccountId,
+		OperationTypeID: requestBody.OperationTypeId,
+		Amount:          math.Abs(requestBody.Amount),
+		MerchantID:      requestBody.MerchantId,
+		MerchantCountry: requestBody.MerchantCountry,
+	}
+}
+
+// dischargeAndCreateTransaction uses the amount of a credit to pay the oldest debts of the account first,
+// debts are ordered by their event date, so a backdated debt is paid before the debts that happened after it.
+// The remaining amount is stored as the balance of the new transaction
+func (s *Service) dischargeAndCreateTransaction(ctx context.Context, repo *Repository, requestBody *CreateTransactionRequestData) (*models.CreateTransactionRow, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Service.dischargeAndCreateTransaction")
+	defer span.End()
+
+	transactions, err := repo.getNegativeBalanceTransactionsByAccountID(ctx, requestBody.AccountId)
+	if err != nil {
+		return nil, fmt.Errorf("dischargeAndCreateTransaction: failed to fetch txns: %w", err)
+	}
+
+	dischargedTransactions, remainingBalance := performDischarge(transactions, requestBody.Amount)
+
+	if err := repo.updateTransactionBalances(ctx, dischargedTransactions); err != nil {
+		return nil, fmt.Errorf("dischargeAndCreateTransaction: failed to update transaction balances: %w", err)
+	}
+
+	newTxn, err := repo.createTransaction(ctx, models.CreateTransactionParams{
+		AccountID:       requestBody.AccountId,
+		OperationTypeID: requestBody.OperationTypeId,
+		Amount:          requestBody.Amount,
+		Balance:         remainingBalance,
+		MerchantID:      nullString(requestBody.MerchantId),
+		MerchantCountry: nullString(requestBody.MerchantCountry),
+		EventDate:       *requestBody.EventDate,
+		Mcc:             nullString(requestBody.Mcc),
+		Metadata:        metadataJSON(requestBody.Metadata),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("dischargeAndCreateTransaction: failed to create transaction: %w", err)
+	}
+
+	return newTxn, nil
+}
+
+// Reverse cancels the amount of the transaction with a transaction of the opposite sign that references it.
+// Reversals correct a transaction that was already accepted, so they skip the risk rules, the spending limits and
+// the account status checks. The reversed amount of a transaction can never exceed its amount.
+//
+// Reversing a debit pays its own outstanding balance first and discharges the other debts of the account with the rest.
+// Reversing a credit takes back what is left of its balance first, the rest becomes a debt of the account.
+// The same share of the rewards accrued by the transaction is taken back.
+func (s *Service) Reverse(ctx context.Context, transactionID string, amount float64) (*models.CreateReversalTransactionRow, error) {
+	ctx, span := tracing.Start(ctx, "transactions.Service.Reverse")
+	defer span.End()
+
+	var reversal *models.CreateReversalTransactionRow
+
+	err := s.repository.withTx(ctx, func(repo *Repository) error {
+		original, err := repo.getTransactionForUpdate(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+
+		reversed, err := repo.getReversedAmount(ctx, transactionID)
+		if err != nil {
+			return err
+		}
+
+		if amount > math.Abs(original.Amount)-reversed {
+			return errReversalExceedsAmount
+		}
+
+		arg := models.CreateReversalTransactionParams{
+			AccountID:       original.AccountID,
+			OperationTypeID: original.OperationTypeID,
+			EventDate:       s.clock.Now(),
+			ReversalOf:      sql.NullString{String: original.Uuid, Valid: true},
+		}
+
+		if original.Amount < 0 {
+			arg.Amount = amount
+			arg.Balance, err = s.reverseDebit(ctx, repo, original, amount)
+		} else {
+			arg.Amount = -amount
+			arg.Balance, err = s.reverseCredit(ctx, repo, original, amount)
+		}
+		if err != nil {
+			return err
+		}
+
+		reversal, err = repo.createReversalTransaction(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		return repo.reverseRewards(ctx, original.AccountID, original.Uuid, reversal.Uuid, amount/math.Abs(original.Amount))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.TransactionCreated(reversal.OperationTypeID, true)
+
+	// The part of the reversal of a debit that isn't left in its balance paid the debts of the account
+	if reversal.Amount > 0 {
+		metrics.Discharged(metrics.DischargeSourceReversal, reversal.Amount-reversal.Balance)
+	}
+
+	return reversal, nil
+}
+
+// reverseDebit pays the outstanding balance of the debit and discharges the other debts with the rest of the amount.
+// It returns the balance of the reversal
+func (s *Service) reverseDebit(ctx context.Context, repo *Repository, original *models.GetTransactionForUpdateRow, amount float64) (float64, error) {
+	paid := min(-math.Min(original.Balance, 0), amount)
+	if paid > 0 {
+		err := repo.updateTransactionBalances(ctx, []*models.GetNegativeBalanceTransactionsByAccountIDRow{
+			{Uuid: original.Uuid, Balance: original.Balance + paid},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("service.reverseDebit: failed to update original balance: %w", err)
+		}
+	}
+
+	remaining := amount - paid
+	if remaining <= 0 {
+		return 0, nil
+	}
+
+	transactions, err := repo.getNegativeBalanceTransactionsByAccountID(ctx, original.AccountID)
+	if err != nil {
+		return 0, fmt.Errorf("service.reverseDebit: failed to fetch txns: %w", err)
+	}
+
+	dischargedTransactions, remainingBalance := performDischarge(transactions, remaining)
+
+	if err = repo.updateTransactionBalances(ctx, dischargedTransactions); err != nil {
+		return 0, fmt.Errorf("service.reverseDebit: failed to update transaction balances: %w", err)
+	}
+
+	return remainingBalance, nil
+}
+
+// reverseCredit takes back the unused balance of the credit, the rest of the amount is owed by the account.
+// It returns the balance of the reversal
+func (s *Service) reverseCredit(ctx context.Context, repo *Repository, original *models.GetTransactionForUpdateRow, amount float64) (float64, error) {
+	taken := min(math.Max(original.Balance, 0), amount)
+	if taken > 0 {
+		err := repo.updateTransactionBalances(ctx, []*models.GetNegativeBalanceTransactionsByAccountIDRow{
+			{Uuid: original.Uuid, Balance: original.Balance - taken},
+		})
+		if err != nil {
+			return 0, fmt.Errorf("service.reverseCredit: failed to update original balance: %w", err)
+		}
+	}
+
+	owed := amount - taken
+	if owed <= 0 {
+		return 0, nil
+	}
+
+	return -owed, nil
+}
+
+func performDischarge(transactions []*models.GetNegativeBalanceTransactionsByAccountIDRow, amount float64) ([]*models.GetNegativeBalanceTransactionsByAccountIDRow, float64) {
+	for i := range transactions {
+		if amount <= 0 {
+			break
+		}
+		if transactions[i].Balance < 0 { //only discharge txns with negative balances
+			dischargeAmount := min(-transactions[i].Balance, amount)
+			transactions[i].Balance += dischargeAmount
+			amount -= dischargeAmount
+		}
+	}
+	return transactions, amount
+}
+
+func min(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Adjust the amount based on the amount behavior
+func adjustAmountBasedOnOperationTypeAmountBehavior(amountBehavior models.AmountBehavior, amount float64) float64 {
+	switch amountBehavior {
+	case models.AmountBehaviorNEGATIVE:
+		return -math.Abs(amount) // Store as negative
+	case models.AmountBehaviorPOSITIVE:
+		return math.Abs(amount) // Store as positive
+	default:
+		// In case of an unexpected value, return the absolute value by default
+		slog.Warn("adjustAmountBasedOnOperationTypeAmountBehavior: unknown amount behavior, defaulting to positive amount", "amount_behavior", amountBehavior)
+		return math.Abs(amount)
+	}
+}
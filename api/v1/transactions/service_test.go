@@ -0,0 +1,113 @@
+package transactions
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestService_Reverse(t *testing.T) {
+	ctx := context.Background()
+	otherDebtID := "0c5d6f0e-3b4a-4c8e-9a47-2f1d2f5b9c33"
+	reversalID := "6a7b8c9d-1e2f-4a3b-8c4d-5e6f7a8b9c0d"
+
+	t.Run("should pay the balance of the debit first and discharge the other debts with the rest", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockQuerier(ctrl)
+		service := NewService(&Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig)
+
+		mockRepo.EXPECT().GetTransactionForUpdate(gomock.Any(), dummyTransactionID).Return(&models.GetTransactionForUpdateRow{
+			Uuid:            dummyTransactionID,
+			AccountID:       dummyAccountId,
+			Amount:          -100,
+			OperationTypeID: dummyOperationType,
+			Balance:         -30,
+		}, nil)
+		mockRepo.EXPECT().GetReversedAmount(gomock.Any(), dummyTransactionID).Return(float64(20), nil)
+		mockRepo.EXPECT().UpdateTransactionBalances(gomock.Any(), models.UpdateTransactionBalancesParams{Uuid: dummyTransactionID, Balance: 0}).Return(nil)
+		mockRepo.EXPECT().GetNegativeBalanceTransactionsByAccountID(gomock.Any(), dummyAccountId).Return([]*models.GetNegativeBalanceTransactionsByAccountIDRow{
+			{Uuid: otherDebtID, Balance: -40},
+		}, nil)
+		mockRepo.EXPECT().UpdateTransactionBalances(gomock.Any(), models.UpdateTransactionBalancesParams{Uuid: otherDebtID, Balance: 0}).Return(nil)
+		mockRepo.EXPECT().CreateReversalTransaction(gomock.Any(), models.CreateReversalTransactionParams{
+			AccountID:       dummyAccountId,
+			Amount:          80,
+			OperationTypeID: dummyOperationType,
+			Balance:         10,
+			EventDate:       dummyNow,
+			ReversalOf:      sql.NullString{String: dummyTransactionID, Valid: true},
+		}).Return(&models.CreateReversalTransactionRow{Uuid: reversalID, Amount: 80, Balance: 10}, nil)
+		// 80% of the debit is reversed, so 80% of its rewards are taken back
+		mockRepo.EXPECT().GetRewardAccrualTotalsByTransactionID(gomock.Any(), dummyTransactionID).Return([]*models.GetRewardAccrualTotalsByTransactionIDRow{
+			{RuleName: "cashback", Kind: models.RewardKindCASHBACK, Amount: 1},
+			{RuleName: "points", Kind: models.RewardKindPOINTS, Amount: 100},
+		}, nil)
+		mockRepo.EXPECT().CreateRewardAccrual(gomock.Any(), models.CreateRewardAccrualParams{
+			AccountID:     dummyAccountId,
+			TransactionID: reversalID,
+			RuleName:      "cashback",
+			Kind:          models.RewardKindCASHBACK,
+			Amount:        -0.8,
+		}).Return(nil)
+		mockRepo.EXPECT().CreateRewardAccrual(gomock.Any(), models.CreateRewardAccrualParams{
+			AccountID:     dummyAccountId,
+			TransactionID: reversalID,
+			RuleName:      "points",
+			Kind:          models.RewardKindPOINTS,
+			Amount:        -80,
+		}).Return(nil)
+
+		reversal, err := service.Reverse(ctx, dummyTransactionID, 80)
+		assert.Nil(t, err)
+		assert.Equal(t, float64(10), reversal.Balance)
+	})
+
+	t.Run("should take back the unused balance of a credit and owe the rest", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockQuerier(ctrl)
+		service := NewService(&Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig)
+
+		mockRepo.EXPECT().GetTransactionForUpdate(gomock.Any(), dummyTransactionID).Return(&models.GetTransactionForUpdateRow{
+			Uuid:            dummyTransactionID,
+			AccountID:       dummyAccountId,
+			Amount:          50,
+			OperationTypeID: 4,
+			Balance:         20,
+		}, nil)
+		mockRepo.EXPECT().GetReversedAmount(gomock.Any(), dummyTransactionID).Return(float64(0), nil)
+		mockRepo.EXPECT().UpdateTransactionBalances(gomock.Any(), models.UpdateTransactionBalancesParams{Uuid: dummyTransactionID, Balance: 0}).Return(nil)
+		mockRepo.EXPECT().CreateReversalTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, arg models.CreateReversalTransactionParams) (*models.CreateReversalTransactionRow, error) {
+				assert.Equal(t, float64(-50), arg.Amount)
+				assert.Equal(t, float64(-30), arg.Balance)
+				return &models.CreateReversalTransactionRow{Amount: arg.Amount, Balance: arg.Balance}, nil
+			})
+		mockRepo.EXPECT().GetRewardAccrualTotalsByTransactionID(gomock.Any(), dummyTransactionID).Return(nil, nil)
+
+		_, err := service.Reverse(ctx, dummyTransactionID, 50)
+		assert.Nil(t, err)
+	})
+
+	t.Run("should not reverse more than what is left of the transaction", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		mockRepo := mock.NewMockQuerier(ctrl)
+		service := NewService(&Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig)
+
+		mockRepo.EXPECT().GetTransactionForUpdate(gomock.Any(), dummyTransactionID).Return(&models.GetTransactionForUpdateRow{
+			Uuid:    dummyTransactionID,
+			Amount:  -100,
+			Balance: -100,
+		}, nil)
+		mockRepo.EXPECT().GetReversedAmount(gomock.Any(), dummyTransactionID).Return(float64(60), nil)
+
+		_, err := service.Reverse(ctx, dummyTransactionID, 50)
+		assert.ErrorIs(t, err, errReversalExceedsAmount)
+		assert.True(t, IsRejected(err))
+	})
+}
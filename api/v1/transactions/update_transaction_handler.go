@@ -0,0 +1,89 @@
+package transactions
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+// UpdateTransactionRequestData has the fields of a transaction the user can update, fields that are not sent are
+// left unchanged. The tags replace all the tags of the transaction, an empty list removes them
+type UpdateTransactionRequestData struct {
+	Tags  *[]string `json:"tags,omitempty" validate:"omitempty,max=10,dive,trim,min=1,max=32"`
+	Notes *string   `json:"notes,omitempty" validate:"omitempty,max=1000"`
+}
+
+// updateTransaction handles updating the tags and the notes of a transaction
+func (h *Handler) updateTransaction() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transactionID := mux.Vars(r)["transactionID"]
+
+		requestBody := &UpdateTransactionRequestData{}
+		if ok := h.reader.ReadJSONAndValidate(w, r, requestBody); !ok {
+			return
+		}
+
+		// Update the transaction and respond
+		h.updateAndRespondTransaction(r.Context(), w, transactionID, requestBody)
+	}
+}
+
+// updateAndRespondTransaction updates the transaction in the database and sends the response
+func (h *Handler) updateAndRespondTransaction(ctx context.Context, w http.ResponseWriter, transactionID string, requestBody *UpdateTransactionRequestData) {
+	arg := models.UpdateTransactionAnnotationsParams{Uuid: transactionID}
+
+	if requestBody.Tags != nil {
+		arg.Tags = normalizeTags(*requestBody.Tags)
+	}
+
+	if requestBody.Notes != nil {
+		arg.Notes = sql.NullString{String: *requestBody.Notes, Valid: true}
+	}
+
+	txnDetails, err := h.repository.updateAnnotations(ctx, arg)
+	if errors.Is(err, errTransactionNotFound) {
+		logging.FromContext(ctx).Info("updateAndRespondTransaction: transaction not found", "transaction_id", transactionID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrTransactionNotFound,
+			Message: errTransactionNotFound.Error(),
+		})
+		return
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("updateAndRespondTransaction: failed to update transaction", "transaction_id", transactionID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to update transaction.",
+		})
+		return
+	}
+
+	h.writer.Ok(w, txnDetails)
+}
+
+// normalizeTags lower cases the tags and removes the duplicates, so that the tag filters are case-insensitive.
+// The result is never nil, since a nil list leaves the tags unchanged
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(tag)
+		if seen[tag] {
+			continue
+		}
+
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
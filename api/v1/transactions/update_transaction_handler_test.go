@@ -0,0 +1,140 @@
+package transactions
+
+import (
+	"bytes"
+	"database/sql"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateTransactionHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected models.UpdateTransactionAnnotationsParams
+	}{
+		{
+			name:     "should replace the tags with the normalized tags",
+			body:     `{"tags": [" Groceries ", "groceries", "Family"]}`,
+			expected: models.UpdateTransactionAnnotationsParams{Uuid: dummyTransactionID, Tags: []string{"groceries", "family"}},
+		},
+		{
+			name:     "should remove the tags with an empty list",
+			body:     `{"tags": []}`,
+			expected: models.UpdateTransactionAnnotationsParams{Uuid: dummyTransactionID, Tags: []string{}},
+		},
+		{
+			name:     "should only update the notes",
+			body:     `{"notes": "split with Ana"}`,
+			expected: models.UpdateTransactionAnnotationsParams{Uuid: dummyTransactionID, Notes: sql.NullString{String: "split with Ana", Valid: true}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+			// Prepare mock responses
+			mockRepo.EXPECT().UpdateTransactionAnnotations(gomock.Any(), tt.expected).Return(&models.UpdateTransactionAnnotationsRow{Uuid: dummyTransactionID}, nil)
+
+			// Prepare the request
+			req := httptest.NewRequest(http.MethodPatch, "/transactions/"+dummyTransactionID, bytes.NewReader([]byte(tt.body)))
+			rr := httptest.NewRecorder()
+
+			// Set mux variables
+			req = mux.SetURLVars(req, map[string]string{"transactionID": dummyTransactionID})
+
+			// Call the handler
+			handler.updateTransaction()(rr, req)
+
+			// Check the results
+			assert.Equal(t, http.StatusOK, rr.Code)
+		})
+	}
+}
+
+func TestUpdateTransactionHandler_InvalidRequest(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		code int
+	}{
+		{"metadata can't be updated", `{"metadata": {"order_id": "ord_123"}}`, http.StatusBadRequest},
+		{"amount can't be updated", `{"amount": 10}`, http.StatusBadRequest},
+		{"empty tag", `{"tags": ["  "]}`, http.StatusUnprocessableEntity},
+		{"too many tags", `{"tags": ["a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"]}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+			// Prepare the request
+			req := httptest.NewRequest(http.MethodPatch, "/transactions/"+dummyTransactionID, bytes.NewReader([]byte(tt.body)))
+			rr := httptest.NewRecorder()
+
+			// Set mux variables
+			req = mux.SetURLVars(req, map[string]string{"transactionID": dummyTransactionID})
+
+			// Call the handler
+			handler.updateTransaction()(rr, req)
+
+			// Check the results
+			assert.Equal(t, tt.code, rr.Code)
+		})
+	}
+}
+
+func TestUpdateTransactionHandler_NotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo}, newTestRiskEngine(t, mockRepo), newTestRewardsEngine(t, ""), clock.Fixed(dummyNow), testConfig, auth.NewPolicy(mockRepo, writer))
+
+	// Prepare mock responses
+	mockRepo.EXPECT().UpdateTransactionAnnotations(gomock.Any(), gomock.Any()).Return(nil, pgx.ErrNoRows)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodPatch, "/transactions/"+dummyTransactionID, bytes.NewReader([]byte(`{"notes": "rent"}`)))
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"transactionID": dummyTransactionID})
+
+	// Call the handler
+	handler.updateTransaction()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
@@ -0,0 +1,92 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type CreateUserRequestData struct {
+	FirstName   string `json:"first_name" validate:"required,trim,name"`
+	MiddleName  string `json:"middle_name,omitempty" validate:"omitempty,trim,name"`
+	LastName    string `json:"last_name" validate:"required,trim,name"`
+	PhoneNumber string `json:"phone_number" validate:"required,e164"`
+	Email       string `json:"email,omitempty" validate:"omitempty,trim,email,max=255"`
+}
+
+// createUser handles creating a user
+func (h *Handler) createUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		requestBody := &CreateUserRequestData{}
+		if ok := h.reader.ReadJSONAndValidate(w, r, requestBody); !ok {
+			return
+		}
+
+		// Create the user and respond
+		h.createAndRespondUser(r.Context(), w, requestBody)
+	}
+}
+
+// createAndRespondUser creates the user in the database and sends the response
+func (h *Handler) createAndRespondUser(ctx context.Context, w http.ResponseWriter, requestBody *CreateUserRequestData) {
+	userDetails, err := h.repository.createUser(ctx, models.CreateUserParams{
+		FirstName:   nullString(requestBody.FirstName),
+		MiddleName:  nullString(requestBody.MiddleName),
+		LastName:    nullString(requestBody.LastName),
+		PhoneNumber: requestBody.PhoneNumber,
+		Email:       nullString(normalizeEmail(requestBody.Email)),
+	})
+	if h.handleDuplicateUser(ctx, w, err) {
+		return
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("createAndRespondUser: failed to create a user", "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to create user.",
+		})
+		return
+	}
+
+	h.writer.Ok(w, userDetails)
+}
+
+// handleDuplicateUser responds with a conflict when the phone number or the email already belong to another user
+func (h *Handler) handleDuplicateUser(ctx context.Context, w http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, errPhoneNumberAlreadyExists):
+		logging.FromContext(ctx).Info("handleDuplicateUser: duplicate user", "error", err)
+		h.writer.Conflict(w, &response.APIError{
+			Code:    response.ErrPhoneNumberAlreadyExists,
+			Message: errPhoneNumberAlreadyExists.Error(),
+		})
+		return true
+
+	case errors.Is(err, errEmailAlreadyExists):
+		logging.FromContext(ctx).Info("handleDuplicateUser: duplicate user", "error", err)
+		h.writer.Conflict(w, &response.APIError{
+			Code:    response.ErrEmailAlreadyExists,
+			Message: errEmailAlreadyExists.Error(),
+		})
+		return true
+	}
+
+	return false
+}
+
+// normalizeEmail lowercases the email, so that the same address can't be used twice with a different case
+func normalizeEmail(email string) string {
+	return strings.ToLower(email)
+}
+
+// nullString converts an optional request field to a nullable DB column
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,141 @@
+package users
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dummyUserID    = "88e0e837-e7f2-47b1-a08c-3af267c03088"
+	dummyAccountID = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+)
+
+func TestCreateUserHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+	// Prepare mock responses
+	mockRepo.EXPECT().CreateUser(gomock.Any(), models.CreateUserParams{
+		FirstName:   nullString("John"),
+		LastName:    nullString("Doe"),
+		PhoneNumber: "+919109987654",
+		Email:       nullString("john.doe@gmail.com"),
+	}).Return(&models.User{Uuid: dummyUserID}, nil)
+
+	// Prepare the request
+	requestBody, _ := json.Marshal(CreateUserRequestData{
+		FirstName:   " John ",
+		LastName:    "Doe",
+		PhoneNumber: "+919109987654",
+		Email:       "John.Doe@gmail.com",
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.createUser()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), dummyUserID)
+}
+
+func TestCreateUserHandler_ValidationError(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestBody CreateUserRequestData
+	}{
+		{"phone number not in E.164", CreateUserRequestData{FirstName: "John", LastName: "Doe", PhoneNumber: "09109987654"}},
+		{"invalid email", CreateUserRequestData{FirstName: "John", LastName: "Doe", PhoneNumber: "+919109987654", Email: "john.doe"}},
+		{"invalid name", CreateUserRequestData{FirstName: "J0hn", LastName: "Doe", PhoneNumber: "+919109987654"}},
+		{"missing last name", CreateUserRequestData{FirstName: "John", PhoneNumber: "+919109987654"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+			// Prepare the request
+			requestBody, _ := json.Marshal(tt.requestBody)
+
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(requestBody))
+			rr := httptest.NewRecorder()
+
+			// Call the handler
+			handler.createUser()(rr, req)
+
+			// Check the results
+			assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+		})
+	}
+}
+
+func TestCreateUserHandler_Duplicate(t *testing.T) {
+	tests := []struct {
+		constraint string
+		message    string
+	}{
+		{phoneNumberUniqueConstraint, errPhoneNumberAlreadyExists.Error()},
+		{emailUniqueConstraint, errEmailAlreadyExists.Error()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+			// Prepare mock responses
+			mockRepo.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(nil, &pgconn.PgError{Code: "23505", ConstraintName: tt.constraint})
+
+			// Prepare the request
+			requestBody, _ := json.Marshal(CreateUserRequestData{
+				FirstName:   "John",
+				LastName:    "Doe",
+				PhoneNumber: "+919109987654",
+				Email:       "john.doe@gmail.com",
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(requestBody))
+			rr := httptest.NewRecorder()
+
+			// Call the handler
+			handler.createUser()(rr, req)
+
+			// Check the results
+			assert.Equal(t, http.StatusConflict, rr.Code)
+			assert.Contains(t, rr.Body.String(), tt.message)
+		})
+	}
+}
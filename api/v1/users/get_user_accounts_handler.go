@@ -0,0 +1,53 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+type UserAccountsResponseData struct {
+	Accounts []*models.Account `json:"accounts"`
+}
+
+// getUserAccounts handles fetching the accounts of a user
+func (h *Handler) getUserAccounts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := mux.Vars(r)["userID"]
+
+		h.fetchAndRespondUserAccounts(r.Context(), w, userID)
+	}
+}
+
+// fetchAndRespondUserAccounts fetches the accounts of the user and responds to the client
+func (h *Handler) fetchAndRespondUserAccounts(ctx context.Context, w http.ResponseWriter, userID string) {
+	accounts, err := h.repository.getUserAccounts(ctx, userID)
+	if errors.Is(err, errUserNotFound) {
+		logging.FromContext(ctx).Info("fetchAndRespondUserAccounts: user not found", "user_id", userID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrUserNotFound,
+			Message: errUserNotFound.Error(),
+		})
+		return
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("fetchAndRespondUserAccounts: failed to fetch user accounts", "user_id", userID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to fetch user accounts.",
+		})
+		return
+	}
+
+	if accounts == nil {
+		accounts = []*models.Account{}
+	}
+
+	h.writer.Ok(w, &UserAccountsResponseData{Accounts: accounts})
+}
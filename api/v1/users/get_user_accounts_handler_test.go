@@ -0,0 +1,72 @@
+package users
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserAccountsHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+	// Prepare mock responses
+	mockRepo.EXPECT().UserExists(gomock.Any(), dummyUserID).Return(true, nil)
+	mockRepo.EXPECT().GetAccountsByUserID(gomock.Any(), dummyUserID).Return([]*models.Account{{Uuid: dummyAccountID, UserID: dummyUserID}}, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/users/"+dummyUserID+"/accounts", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"userID": dummyUserID})
+
+	// Call the handler
+	handler.getUserAccounts()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), dummyAccountID)
+}
+
+func TestGetUserAccountsHandler_UserNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+	// Prepare mock responses
+	mockRepo.EXPECT().UserExists(gomock.Any(), dummyUserID).Return(false, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/users/"+dummyUserID+"/accounts", nil)
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"userID": dummyUserID})
+
+	// Call the handler
+	handler.getUserAccounts()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusNotFound, rr.Code)
+}
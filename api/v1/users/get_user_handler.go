@@ -0,0 +1,45 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+// getUserDetails handles fetching the user details
+func (h *Handler) getUserDetails() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := mux.Vars(r)["userID"]
+
+		// Fetch and respond with the user details
+		h.fetchAndRespondUserDetails(r.Context(), w, userID)
+	}
+}
+
+// fetchAndRespondUserDetails fetches the user details from the repository and responds to the client
+func (h *Handler) fetchAndRespondUserDetails(ctx context.Context, w http.ResponseWriter, userID string) {
+	userDetails, err := h.repository.getUserDetails(ctx, userID)
+	if errors.Is(err, errUserNotFound) {
+		logging.FromContext(ctx).Info("fetchAndRespondUserDetails: user not found", "user_id", userID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrUserNotFound,
+			Message: errUserNotFound.Error(),
+		})
+		return
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("fetchAndRespondUserDetails: failed to fetch user details", "user_id", userID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to fetch user details.",
+		})
+		return
+	}
+
+	h.writer.Ok(w, userDetails)
+}
@@ -0,0 +1,60 @@
+package users
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserDetailsHandler(t *testing.T) {
+	tests := []struct {
+		name   string
+		user   *models.User
+		err    error
+		status int
+	}{
+		{"success", &models.User{Uuid: dummyUserID}, nil, http.StatusOK},
+		{"user not found", nil, pgx.ErrNoRows, http.StatusNotFound},
+		{"database error", nil, errors.New("database error"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+			// Prepare mock responses
+			mockRepo.EXPECT().GetUserByUUID(gomock.Any(), dummyUserID).Return(tt.user, tt.err)
+
+			// Prepare the request
+			req := httptest.NewRequest(http.MethodGet, "/users/"+dummyUserID, nil)
+			rr := httptest.NewRecorder()
+
+			// Set mux variables
+			req = mux.SetURLVars(req, map[string]string{"userID": dummyUserID})
+
+			// Call the handler
+			handler.getUserDetails()(rr, req)
+
+			// Check the results
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+}
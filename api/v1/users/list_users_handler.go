@@ -0,0 +1,66 @@
+package users
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+const defaultUsersPageSize = 20
+
+type ListUsersQueryParams struct {
+	// Limit is the maximum number of users in the page
+	Limit int32 `schema:"limit" validate:"omitempty,min=1,max=100"`
+	// After is the cursor returned as next_after by the previous page
+	After int64 `schema:"after" validate:"omitempty,min=0"`
+}
+
+type ListUsersResponseData struct {
+	Users []*models.User `json:"users"`
+	// NextAfter is the cursor of the next page, it's not set on the last page
+	NextAfter *int64 `json:"next_after,omitempty"`
+}
+
+// listUsers handles listing the users, the users are paginated by the after cursor
+func (h *Handler) listUsers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queryParams := &ListUsersQueryParams{}
+		if ok := h.reader.ReadQueryParamsAndValidate(w, r, queryParams); !ok {
+			return
+		}
+
+		if queryParams.Limit == 0 {
+			queryParams.Limit = defaultUsersPageSize
+		}
+
+		h.fetchAndRespondUsers(r.Context(), w, queryParams)
+	}
+}
+
+// fetchAndRespondUsers fetches a page of users and responds to the client
+func (h *Handler) fetchAndRespondUsers(ctx context.Context, w http.ResponseWriter, queryParams *ListUsersQueryParams) {
+	users, err := h.repository.listUsers(ctx, queryParams.After, queryParams.Limit)
+	if err != nil {
+		logging.FromContext(ctx).Error("fetchAndRespondUsers: failed to list users", "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to list users.",
+		})
+		return
+	}
+
+	res := &ListUsersResponseData{Users: users}
+	if users == nil {
+		res.Users = []*models.User{}
+	}
+
+	// A full page means there may be more users
+	if len(users) == int(queryParams.Limit) {
+		res.NextAfter = &users[len(users)-1].SerialID
+	}
+
+	h.writer.Ok(w, res)
+}
@@ -0,0 +1,95 @@
+package users
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListUsersHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+	// Prepare mock responses, a full page has a cursor to the next page
+	mockRepo.EXPECT().ListUsers(gomock.Any(), models.ListUsersParams{AfterSerialID: 10, PageSize: 2}).
+		Return([]*models.User{{SerialID: 11}, {SerialID: 12}}, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/users?limit=2&after=10", nil)
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.listUsers()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	res := &struct {
+		Data *ListUsersResponseData `json:"data"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Len(t, res.Data.Users, 2)
+	assert.Equal(t, int64(12), *res.Data.NextAfter)
+}
+
+func TestListUsersHandler_LastPage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+	// Prepare mock responses
+	mockRepo.EXPECT().ListUsers(gomock.Any(), models.ListUsersParams{AfterSerialID: 0, PageSize: defaultUsersPageSize}).
+		Return([]*models.User{{SerialID: 1}}, nil)
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.listUsers()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "next_after")
+}
+
+func TestListUsersHandler_InvalidLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+	// Prepare the request
+	req := httptest.NewRequest(http.MethodGet, "/users?limit=1000", nil)
+	rr := httptest.NewRecorder()
+
+	// Call the handler
+	handler.listUsers()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+}
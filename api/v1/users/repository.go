@@ -0,0 +1,127 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/tracing"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type Repository struct {
+	querier models.Querier
+}
+
+func NewRepository(querier models.Querier) *Repository {
+	return &Repository{querier: querier}
+}
+
+var (
+	errUserNotFound             = errors.New("USER_NOT_FOUND")
+	errPhoneNumberAlreadyExists = errors.New("PHONE_NUMBER_ALREADY_EXISTS")
+	errEmailAlreadyExists       = errors.New("EMAIL_ALREADY_EXISTS")
+)
+
+// Names of the unique constraints of the users table, see the create users migration
+const (
+	phoneNumberUniqueConstraint = "users_phone_number_key"
+	emailUniqueConstraint       = "users_email_key"
+)
+
+func (r *Repository) createUser(ctx context.Context, arg models.CreateUserParams) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "users.Repository.createUser")
+	defer span.End()
+
+	userDetails, err := r.querier.CreateUser(ctx, arg)
+	if err = uniqueViolationError(err); err != nil {
+		return nil, fmt.Errorf("repo.createUser: error: %w", err)
+	}
+
+	return userDetails, nil
+}
+
+func (r *Repository) getUserDetails(ctx context.Context, uuid string) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "users.Repository.getUserDetails")
+	defer span.End()
+
+	userDetails, err := r.querier.GetUserByUUID(ctx, uuid)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errUserNotFound
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("repo.getUserDetails: error: %w", err)
+	}
+
+	return userDetails, nil
+}
+
+func (r *Repository) updateUser(ctx context.Context, arg models.UpdateUserParams) (*models.User, error) {
+	ctx, span := tracing.Start(ctx, "users.Repository.updateUser")
+	defer span.End()
+
+	userDetails, err := r.querier.UpdateUser(ctx, arg)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, errUserNotFound
+	}
+
+	if err = uniqueViolationError(err); err != nil {
+		return nil, fmt.Errorf("repo.updateUser: error: %w", err)
+	}
+
+	return userDetails, nil
+}
+
+func (r *Repository) listUsers(ctx context.Context, afterSerialID int64, pageSize int32) ([]*models.User, error) {
+	ctx, span := tracing.Start(ctx, "users.Repository.listUsers")
+	defer span.End()
+
+	users, err := r.querier.ListUsers(ctx, models.ListUsersParams{
+		AfterSerialID: afterSerialID,
+		PageSize:      pageSize,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("repo.listUsers: error: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *Repository) getUserAccounts(ctx context.Context, userID string) ([]*models.Account, error) {
+	ctx, span := tracing.Start(ctx, "users.Repository.getUserAccounts")
+	defer span.End()
+
+	exists, err := r.querier.UserExists(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repo.getUserAccounts: error checking user existence: %w", err)
+	}
+
+	if !exists {
+		return nil, errUserNotFound
+	}
+
+	accounts, err := r.querier.GetAccountsByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("repo.getUserAccounts: error: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// uniqueViolationError maps the unique violations of the users table to the duplicate phone number and email errors
+func uniqueViolationError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is a unique violation
+		switch pgErr.ConstraintName {
+		case phoneNumberUniqueConstraint:
+			return errPhoneNumberAlreadyExists
+		case emailUniqueConstraint:
+			return errEmailAlreadyExists
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,16 @@
+package users
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/auth"
+)
+
+func Routes(r *mux.Router, h *Handler, p *auth.Policy) {
+	r.HandleFunc("", p.Require(auth.ScopeUsersWrite, h.createUser())).Methods(http.MethodPost)
+	r.HandleFunc("", p.Require(auth.ScopeUsersRead, h.listUsers())).Methods(http.MethodGet)
+	r.HandleFunc("/{userID}", p.User(auth.ScopeUsersRead, h.getUserDetails())).Methods(http.MethodGet)
+	r.HandleFunc("/{userID}", p.User(auth.ScopeUsersWrite, h.updateUser())).Methods(http.MethodPatch)
+	r.HandleFunc("/{userID}/accounts", p.User(auth.ScopeAccountsRead, h.getUserAccounts())).Methods(http.MethodGet)
+}
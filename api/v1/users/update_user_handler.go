@@ -0,0 +1,88 @@
+package users
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+// UpdateUserRequestData has the fields that can be updated, fields that are not sent are left unchanged
+type UpdateUserRequestData struct {
+	FirstName   *string `json:"first_name,omitempty" validate:"omitempty,trim,name"`
+	MiddleName  *string `json:"middle_name,omitempty" validate:"omitempty,trim,name"`
+	LastName    *string `json:"last_name,omitempty" validate:"omitempty,trim,name"`
+	PhoneNumber *string `json:"phone_number,omitempty" validate:"omitempty,e164"`
+	Email       *string `json:"email,omitempty" validate:"omitempty,trim,email,max=255"`
+}
+
+// updateUser handles updating the details of a user
+func (h *Handler) updateUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID := mux.Vars(r)["userID"]
+
+		requestBody := &UpdateUserRequestData{}
+		if ok := h.reader.ReadJSONAndValidate(w, r, requestBody); !ok {
+			return
+		}
+
+		// Update the user and respond
+		h.updateAndRespondUser(r.Context(), w, userID, requestBody)
+	}
+}
+
+// updateAndRespondUser updates the user in the database and sends the response
+func (h *Handler) updateAndRespondUser(ctx context.Context, w http.ResponseWriter, userID string, requestBody *UpdateUserRequestData) {
+	var email *string
+	if requestBody.Email != nil {
+		normalized := normalizeEmail(*requestBody.Email)
+		email = &normalized
+	}
+
+	userDetails, err := h.repository.updateUser(ctx, models.UpdateUserParams{
+		Uuid:        userID,
+		FirstName:   optionalString(requestBody.FirstName),
+		MiddleName:  optionalString(requestBody.MiddleName),
+		LastName:    optionalString(requestBody.LastName),
+		PhoneNumber: optionalString(requestBody.PhoneNumber),
+		Email:       optionalString(email),
+	})
+	if errors.Is(err, errUserNotFound) {
+		logging.FromContext(ctx).Info("updateAndRespondUser: user not found", "user_id", userID)
+		h.writer.NotFound(w, &response.APIError{
+			Code:    response.ErrUserNotFound,
+			Message: errUserNotFound.Error(),
+		})
+		return
+	}
+
+	if h.handleDuplicateUser(ctx, w, err) {
+		return
+	}
+
+	if err != nil {
+		logging.FromContext(ctx).Error("updateAndRespondUser: failed to update user", "user_id", userID, "error", err)
+		h.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to update user.",
+		})
+		return
+	}
+
+	h.writer.Ok(w, userDetails)
+}
+
+// optionalString converts a field that may be missing from the request to a nullable query param,
+// a null param leaves the column unchanged
+func optionalString(s *string) sql.NullString {
+	if s == nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: *s, Valid: true}
+}
@@ -0,0 +1,98 @@
+package users
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/imjenal/transaction-service/pkg/validator"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateUserHandler_Success(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	writer := response.NewJSONWriter()
+	v := validator.New()
+	reader := request.NewReader(writer, v)
+	handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+	// Prepare mock responses, only the fields in the request are updated
+	mockRepo.EXPECT().UpdateUser(gomock.Any(), models.UpdateUserParams{
+		Uuid:     dummyUserID,
+		LastName: sql.NullString{String: "Smith", Valid: true},
+		Email:    sql.NullString{String: "john.smith@gmail.com", Valid: true},
+	}).Return(&models.User{Uuid: dummyUserID}, nil)
+
+	// Prepare the request
+	requestBody := []byte(`{"last_name": "Smith ", "email": "John.Smith@gmail.com"}`)
+
+	req := httptest.NewRequest(http.MethodPatch, "/users/"+dummyUserID, bytes.NewReader(requestBody))
+	rr := httptest.NewRecorder()
+
+	// Set mux variables
+	req = mux.SetURLVars(req, map[string]string{"userID": dummyUserID})
+
+	// Call the handler
+	handler.updateUser()(rr, req)
+
+	// Check the results
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestUpdateUserHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		requestBody string
+		err         error
+		status      int
+	}{
+		{"invalid phone number", `{"phone_number": "12345"}`, nil, http.StatusUnprocessableEntity},
+		{"empty request", `{}`, nil, http.StatusBadRequest},
+		{"user not found", `{"first_name": "John"}`, pgx.ErrNoRows, http.StatusNotFound},
+		{"duplicate phone number", `{"phone_number": "+918765230842"}`, &pgconn.PgError{Code: "23505", ConstraintName: phoneNumberUniqueConstraint}, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockRepo := mock.NewMockQuerier(ctrl)
+			writer := response.NewJSONWriter()
+			v := validator.New()
+			reader := request.NewReader(writer, v)
+			handler := NewHandler(reader, writer, &Repository{querier: mockRepo})
+
+			// Prepare mock responses
+			if tt.err != nil {
+				mockRepo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(nil, tt.err)
+			}
+
+			// Prepare the request
+			req := httptest.NewRequest(http.MethodPatch, "/users/"+dummyUserID, bytes.NewReader([]byte(tt.requestBody)))
+			rr := httptest.NewRecorder()
+
+			// Set mux variables
+			req = mux.SetURLVars(req, map[string]string{"userID": dummyUserID})
+
+			// Call the handler
+			handler.updateUser()(rr, req)
+
+			// Check the results
+			assert.Equal(t, tt.status, rr.Code)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/db/models"
+)
+
+// runCommand runs the command given in the arguments of the binary instead of the server
+func runCommand(ctx context.Context, querier models.Querier, args []string) error {
+	if len(args) >= 2 && args[0] == "apikey" && args[1] == "create" {
+		return createAPIKey(ctx, querier, args[2:])
+	}
+
+	return fmt.Errorf("unknown command %q, the available commands are: apikey create, config print", args)
+}
+
+// createAPIKey handles `pismo apikey create -name <name> [-user-id <uuid>] [-scopes <scopes>] [-expires-in <duration>]`.
+// The key is printed once, only its hash is stored
+func createAPIKey(ctx context.Context, querier models.Querier, args []string) error {
+	flags := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+	name := flags.String("name", "", "the name of the partner or the purpose of the key")
+	userID := flags.String("user-id", "", "the user the key belongs to, leave empty for a partner key")
+	scopes := flags.String("scopes", "", "the comma separated scopes of the key, e.g. accounts:read,transactions:write. "+
+		"A user key without scopes has every scope but admin on the resources of its user")
+	expiresIn := flags.Duration("expires-in", 0, "how long the key is valid, e.g. 2160h. The key doesn't expire when it is 0")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *name == "" {
+		return errors.New("createAPIKey: -name is required")
+	}
+
+	keyScopes, err := auth.ParseScopes(*scopes)
+	if err != nil {
+		return fmt.Errorf("createAPIKey: %w", err)
+	}
+
+	if len(keyScopes) == 0 && *userID == "" {
+		return errors.New("createAPIKey: a partner key needs -scopes")
+	}
+
+	key, hash, err := auth.GenerateAPIKey()
+	if err != nil {
+		return err
+	}
+
+	apiKey, err := querier.CreateAPIKey(ctx, models.CreateAPIKeyParams{
+		Name:      *name,
+		KeyHash:   hash,
+		UserID:    sql.NullString{String: *userID, Valid: *userID != ""},
+		ExpiresAt: sql.NullTime{Time: time.Now().Add(*expiresIn), Valid: *expiresIn > 0},
+		Scopes:    keyScopes,
+	})
+	if err != nil {
+		return fmt.Errorf("createAPIKey: error creating key: %w", err)
+	}
+
+	fmt.Printf("Created API key %s (%s). Store it now, it can't be shown again:\n%s\n", apiKey.Uuid, apiKey.Name, key)
+
+	return nil
+}
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"log"
 	"sync"
 
@@ -16,17 +18,84 @@ const (
 	keyPort    = "SERVER_PORT"
 	keyEnv     = "ENVIRONMENT"
 
+	keyAdminAddress = "ADMIN_ADDR"
+	keyAdminPort    = "ADMIN_PORT"
+
+	keyGRPCPort = "GRPC_PORT"
+
+	keyMaxBodyBytes = "MAX_BODY_BYTES"
+
+	keyServerReadTimeout     = "SERVER_READ_TIMEOUT"
+	keyServerWriteTimeout    = "SERVER_WRITE_TIMEOUT"
+	keyServerIdleTimeout     = "SERVER_IDLE_TIMEOUT"
+	keyServerShutdownTimeout = "SERVER_SHUTDOWN_TIMEOUT"
+
+	keyTLSCertFile             = "TLS_CERT_FILE"
+	keyTLSKeyFile              = "TLS_KEY_FILE"
+	keyTLSClientCAFile         = "TLS_CLIENT_CA_FILE"
+	keyTLSClientAuth           = "TLS_CLIENT_AUTH"
+	keyTLSClientIdentitiesFile = "TLS_CLIENT_IDENTITIES_FILE"
+
+	keyLogLevel = "LOG_LEVEL"
+
+	keyTracingExporter     = "TRACING_EXPORTER"
+	keyTracingOTLPEndpoint = "TRACING_OTLP_ENDPOINT"
+	keyTracingOTLPInsecure = "TRACING_OTLP_INSECURE"
+	keyTracingSampleRatio  = "TRACING_SAMPLE_RATIO"
+
 	keyDBHost     = "DB_HOST"
 	keyDBPort     = "DB_PORT"
 	keyDBUser     = "DB_USER"
 	keyDBPassword = "DB_PASSWORD"
 	keyDBName     = "DB_NAME"
+
+	keyDBForceTLS          = "DB_FORCE_TLS"
+	keyDBAppName           = "DB_APP_NAME"
+	keyDBMaxConns          = "DB_MAX_CONNS"
+	keyDBMinConns          = "DB_MIN_CONNS"
+	keyDBMaxConnLifetime   = "DB_MAX_CONN_LIFETIME"
+	keyDBMaxConnIdleTime   = "DB_MAX_CONN_IDLE_TIME"
+	keyDBHealthCheckPeriod = "DB_HEALTH_CHECK_PERIOD"
+	keyDBStatementTimeout  = "DB_STATEMENT_TIMEOUT"
+
+	keyRiskRulesFile = "RISK_RULES_FILE"
+
+	keyDocumentUniquenessScope = "DOCUMENT_UNIQUENESS_SCOPE"
+
+	keyEventDateMaxBackdate = "EVENT_DATE_MAX_BACKDATE"
+	keyEventDateMaxForward  = "EVENT_DATE_MAX_FORWARD"
+
+	keySchedulerInterval  = "SCHEDULER_INTERVAL"
+	keySchedulerBatchSize = "SCHEDULER_BATCH_SIZE"
+
+	keyRewardsRulesFile          = "REWARDS_RULES_FILE"
+	keyRewardsPayoutInterval     = "REWARDS_PAYOUT_INTERVAL"
+	keyRewardsPayoutBatchSize    = "REWARDS_PAYOUT_BATCH_SIZE"
+	keyRewardsPayoutRetryBackoff = "REWARDS_PAYOUT_RETRY_BACKOFF"
+
+	keyAuthJWTHMACSecret = "AUTH_JWT_HMAC_SECRET"
+	keyAuthJWKSFile      = "AUTH_JWKS_FILE"
+	keyAuthJWTIssuer     = "AUTH_JWT_ISSUER"
+	keyAuthJWTAudience   = "AUTH_JWT_AUDIENCE"
+
+	keyRateLimitRulesFile = "RATE_LIMIT_RULES_FILE"
+	keyRateLimitBackend   = "RATE_LIMIT_BACKEND"
 )
 
 // App Stores all the app config. The config is read from the .env file present in the project root.
 type App struct {
-	Server   *config.Server `validate:"required"`
-	Database *config.DB     `validate:"required"`
+	Server       *config.Server       `validate:"required"`
+	TLS          *config.TLS          `validate:"required"`
+	Log          *config.Log          `validate:"required"`
+	Tracing      *config.Tracing      `validate:"required"`
+	Database     *config.DB           `validate:"required"`
+	Risk         *config.Risk         `validate:"required"`
+	Accounts     *config.Accounts     `validate:"required"`
+	Transactions *config.Transactions `validate:"required"`
+	Scheduler    *config.Scheduler    `validate:"required"`
+	Rewards      *config.Rewards      `validate:"required"`
+	Auth         *config.Auth         `validate:"required"`
+	RateLimit    *config.RateLimit    `validate:"required"`
 }
 
 var (
@@ -40,12 +109,67 @@ const envFileName = ".env"
 // subsequent calls just return the previously read data.
 func GetConfig() *App {
 	once.Do(func() {
+		// Documents were only unique across all the users before the scope was configurable
+		viper.SetDefault(keyDocumentUniquenessScope, string(config.DocumentUniquenessGlobal))
+		viper.SetDefault(keyEventDateMaxBackdate, "720h")
+		viper.SetDefault(keyEventDateMaxForward, "24h")
+		viper.SetDefault(keySchedulerInterval, "1m")
+		viper.SetDefault(keySchedulerBatchSize, 100)
+		viper.SetDefault(keyRewardsPayoutInterval, "24h")
+		viper.SetDefault(keyRewardsPayoutBatchSize, 1000)
+		viper.SetDefault(keyRewardsPayoutRetryBackoff, "72h")
+		viper.SetDefault(keyLogLevel, "INFO")
+		viper.SetDefault(keyTracingExporter, "NONE")
+		viper.SetDefault(keyTracingSampleRatio, 1.0)
+		viper.SetDefault(keyAdminAddress, "127.0.0.1")
+		viper.SetDefault(keyAdminPort, 9101)
+		viper.SetDefault(keyGRPCPort, 9102)
+		viper.SetDefault(keyMaxBodyBytes, 1<<20)
+		viper.SetDefault(keyServerReadTimeout, "2s")
+		viper.SetDefault(keyServerWriteTimeout, "20s")
+		viper.SetDefault(keyServerIdleTimeout, "65s")
+		viper.SetDefault(keyServerShutdownTimeout, "5s")
+		viper.SetDefault(keyTLSClientAuth, string(config.TLSClientAuthOptional))
+		viper.SetDefault(keyDBAppName, "pismo")
+		viper.SetDefault(keyDBMaxConns, 5)
+		viper.SetDefault(keyDBMinConns, 2)
+		viper.SetDefault(keyDBMaxConnLifetime, "1h")
+		viper.SetDefault(keyDBMaxConnIdleTime, "30m")
+		viper.SetDefault(keyDBHealthCheckPeriod, "1m")
+		viper.SetDefault(keyDBStatementTimeout, "0s")
+		viper.SetDefault(keyRateLimitBackend, string(config.RateLimitBackendMemory))
+
 		config.Read(envFileName, keyEnv)
 		configs = &App{
 			Server: &config.Server{
-				Port:        viper.GetInt(keyPort),
-				Address:     viper.GetString(keyAddress),
-				Environment: config.Environment(viper.GetString(keyEnv)),
+				Port:         viper.GetInt(keyPort),
+				Address:      viper.GetString(keyAddress),
+				Environment:  config.Environment(viper.GetString(keyEnv)),
+				AdminPort:    viper.GetInt(keyAdminPort),
+				AdminAddress: viper.GetString(keyAdminAddress),
+				GRPCPort:     viper.GetInt(keyGRPCPort),
+				MaxBodyBytes: viper.GetInt64(keyMaxBodyBytes),
+
+				ReadTimeout:     viper.GetDuration(keyServerReadTimeout),
+				WriteTimeout:    viper.GetDuration(keyServerWriteTimeout),
+				IdleTimeout:     viper.GetDuration(keyServerIdleTimeout),
+				ShutdownTimeout: viper.GetDuration(keyServerShutdownTimeout),
+			},
+			TLS: &config.TLS{
+				CertFile:             viper.GetString(keyTLSCertFile),
+				KeyFile:              viper.GetString(keyTLSKeyFile),
+				ClientCAFile:         viper.GetString(keyTLSClientCAFile),
+				ClientAuth:           config.TLSClientAuth(viper.GetString(keyTLSClientAuth)),
+				ClientIdentitiesFile: viper.GetString(keyTLSClientIdentitiesFile),
+			},
+			Log: &config.Log{
+				Level: viper.GetString(keyLogLevel),
+			},
+			Tracing: &config.Tracing{
+				Exporter:     viper.GetString(keyTracingExporter),
+				OTLPEndpoint: viper.GetString(keyTracingOTLPEndpoint),
+				OTLPInsecure: viper.GetBool(keyTracingOTLPInsecure),
+				SampleRatio:  viper.GetFloat64(keyTracingSampleRatio),
 			},
 			Database: &config.DB{
 				Host:     viper.GetString(keyDBHost),
@@ -53,6 +177,45 @@ func GetConfig() *App {
 				User:     viper.GetString(keyDBUser),
 				Password: viper.GetString(keyDBPassword),
 				Name:     viper.GetString(keyDBName),
+
+				ForceTLS:          viper.GetBool(keyDBForceTLS),
+				AppName:           viper.GetString(keyDBAppName),
+				MaxConns:          viper.GetInt32(keyDBMaxConns),
+				MinConns:          viper.GetInt32(keyDBMinConns),
+				MaxConnLifetime:   viper.GetDuration(keyDBMaxConnLifetime),
+				MaxConnIdleTime:   viper.GetDuration(keyDBMaxConnIdleTime),
+				HealthCheckPeriod: viper.GetDuration(keyDBHealthCheckPeriod),
+				StatementTimeout:  viper.GetDuration(keyDBStatementTimeout),
+			},
+			Risk: &config.Risk{
+				RulesFile: viper.GetString(keyRiskRulesFile),
+			},
+			Accounts: &config.Accounts{
+				DocumentUniqueness: config.DocumentUniqueness(viper.GetString(keyDocumentUniquenessScope)),
+			},
+			Transactions: &config.Transactions{
+				MaxEventDateBackdate: viper.GetDuration(keyEventDateMaxBackdate),
+				MaxEventDateForward:  viper.GetDuration(keyEventDateMaxForward),
+			},
+			Scheduler: &config.Scheduler{
+				Interval:  viper.GetDuration(keySchedulerInterval),
+				BatchSize: viper.GetInt(keySchedulerBatchSize),
+			},
+			Rewards: &config.Rewards{
+				RulesFile:          viper.GetString(keyRewardsRulesFile),
+				PayoutInterval:     viper.GetDuration(keyRewardsPayoutInterval),
+				PayoutBatchSize:    viper.GetInt(keyRewardsPayoutBatchSize),
+				PayoutRetryBackoff: viper.GetDuration(keyRewardsPayoutRetryBackoff),
+			},
+			Auth: &config.Auth{
+				JWTHMACSecret: viper.GetString(keyAuthJWTHMACSecret),
+				JWKSFile:      viper.GetString(keyAuthJWKSFile),
+				JWTIssuer:     viper.GetString(keyAuthJWTIssuer),
+				JWTAudience:   viper.GetString(keyAuthJWTAudience),
+			},
+			RateLimit: &config.RateLimit{
+				RulesFile: viper.GetString(keyRateLimitRulesFile),
+				Backend:   config.RateLimitBackend(viper.GetString(keyRateLimitBackend)),
 			},
 		}
 
@@ -71,3 +234,13 @@ func GetConfig() *App {
 
 	return configs
 }
+
+// runConfigCommand handles `pismo config print`, which writes the effective config, after the defaults and the
+// environment variables are applied, with the secrets masked
+func runConfigCommand(w io.Writer, app *App, args []string) error {
+	if len(args) == 1 && args[0] == "print" {
+		return config.Print(w, app)
+	}
+
+	return fmt.Errorf("unknown command %q, the available commands are: config print", args)
+}
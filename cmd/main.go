@@ -3,17 +3,36 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/imjenal/transaction-service/internal/app"
 
 	"github.com/imjenal/transaction-service/api"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/clock"
 	"github.com/imjenal/transaction-service/internal/db"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/health"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/metrics"
+	"github.com/imjenal/transaction-service/internal/ratelimit"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/rewards/payout"
+	"github.com/imjenal/transaction-service/internal/risk"
+	"github.com/imjenal/transaction-service/internal/schedule"
 	"github.com/imjenal/transaction-service/internal/server"
+	"github.com/imjenal/transaction-service/internal/tlsconfig"
+	"github.com/imjenal/transaction-service/internal/tracing"
 	"github.com/imjenal/transaction-service/pkg/http/request"
 	"github.com/imjenal/transaction-service/pkg/http/response"
 	"github.com/imjenal/transaction-service/pkg/validator"
 )
 
+// healthCheckTimeout bounds each check of the readiness probe
+const healthCheckTimeout = 2 * time.Second
+
 var (
 	Version = "0.0.0" // Version stores the binary version of the Git tag. It is populated using LDFlags
 	Name    = "pismo"
@@ -26,19 +45,75 @@ func main() {
 
 	config := GetConfig()
 
+	// `pismo config print` only needs the config, it runs before anything is started
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		if err := runConfigCommand(os.Stdout, config, os.Args[2:]); err != nil {
+			log.Printf("failed to run command: %v", err)
+		}
+		return
+	}
+
+	// The logs are written as JSON. The logger is also the default logger, so that the logs of the packages that
+	// use the log package are JSON too
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(config.Log.Level)); err != nil {
+		log.Printf("invalid log level: %v", err)
+		return
+	}
+
+	logger := logging.New(os.Stdout, logLevel)
+	slog.SetDefault(logger)
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	// Initialize DB
-	conn, err := db.GetConnection(ctx, &db.Config{
+	// The spans of the requests and of the DB queries are exported as configured, the pending spans are flushed
+	// when the main function exits
+	shutdownTracing, err := tracing.Setup(ctx, &tracing.Config{
+		Exporter:    config.Tracing.Exporter,
+		Endpoint:    config.Tracing.OTLPEndpoint,
+		Insecure:    config.Tracing.OTLPInsecure,
+		SampleRatio: config.Tracing.SampleRatio,
+		ServiceName: Name,
+		Stdout:      os.Stdout,
+	})
+	if err != nil {
+		log.Printf("failed to set up tracing: %v", err)
+		return
+	}
+
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			logger.Error("failed to flush the spans", "error", err)
+		}
+	}()
+
+	dbConfig := &db.Config{
 		Host:     config.Database.Host,
 		Port:     config.Database.Port,
 		User:     config.Database.User,
 		Password: config.Database.Password,
 		Name:     config.Database.Name,
+		ForceTLS: config.Database.ForceTLS,
+		AppName:  config.Database.AppName,
 		Migrate:  true, // Always migrate the database
 		SeedDB:   string(config.Server.Environment) == "DEV",
-	})
+
+		MaxConns:          config.Database.MaxConns,
+		MinConns:          config.Database.MinConns,
+		MaxConnLifetime:   config.Database.MaxConnLifetime,
+		MaxConnIdleTime:   config.Database.MaxConnIdleTime,
+		HealthCheckPeriod: config.Database.HealthCheckPeriod,
+		StatementTimeout:  config.Database.StatementTimeout,
+	}
+
+	// The queries are only traced when the spans are exported
+	if config.Tracing.Exporter != tracing.ExporterNone {
+		dbConfig.QueryTracer = tracing.NewQueryTracer()
+	}
+
+	// Initialize DB
+	conn, err := db.GetConnection(ctx, dbConfig)
 
 	if err != nil {
 		log.Printf("failed to connect to database: %v", err)
@@ -48,6 +123,109 @@ func main() {
 	// Defer closing the database connection, so that it is closed when the main function exits
 	defer conn.Conn.Close()
 
+	// The stats of the connection pool are read whenever the metrics are scraped
+	metrics.RegisterPool(conn.Conn)
+
+	// The arguments run a command, e.g. `pismo apikey create -name partner`, instead of the server
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, models.New(conn.Conn), os.Args[1:]); err != nil {
+			log.Printf("failed to run command: %v", err)
+		}
+		return
+	}
+
+	// The risk engine evaluates every transaction before it is persisted.
+	// The rules are reloaded whenever the rules file changes, so they can be tuned without a restart
+	riskEngine, err := risk.NewEngine(config.Risk.RulesFile, risk.NewStore(models.New(conn.Conn)), clock.System{})
+	if err != nil {
+		log.Printf("failed to load risk rules: %v", err)
+		return
+	}
+
+	go func() {
+		if err := riskEngine.Watch(ctx); err != nil {
+			log.Printf("failed to watch risk rules: %v", err)
+		}
+	}()
+
+	// The rewards engine accrues the cashback and points of every debit, the rules are read once at startup
+	rewardsEngine, err := rewards.NewEngine(config.Rewards.RulesFile)
+	if err != nil {
+		log.Printf("failed to load reward rules: %v", err)
+		return
+	}
+
+	// The scheduler posts the due scheduled transactions through the same checks as the API
+	scheduler := schedule.NewScheduler(conn.Conn, riskEngine, rewardsEngine, clock.System{}, config.Transactions, config.Scheduler)
+	go scheduler.Run(ctx)
+
+	// The poster pays out the pending cashback periodically as credits
+	poster := payout.NewPoster(models.New(conn.Conn), conn.Conn, riskEngine, rewardsEngine, clock.System{}, config.Transactions, config.Rewards)
+	go poster.Run(ctx)
+
+	// The service is ready when the database is reachable and migrated and the background workers are running
+	migrationChecker, err := conn.MigrationChecker()
+	if err != nil {
+		log.Printf("failed to read the migrations: %v", err)
+		return
+	}
+
+	healthChecks := health.NewRegistry(healthCheckTimeout)
+	healthChecks.Register(conn.PingChecker(), migrationChecker, scheduler.HealthChecker(), poster.HealthChecker())
+
+	// The bearer tokens are only accepted when a key to verify them is configured
+	jwtVerifier, err := auth.NewJWTVerifier(auth.JWTConfig{
+		HMACSecret: config.Auth.JWTHMACSecret,
+		JWKSFile:   config.Auth.JWKSFile,
+		Issuer:     config.Auth.JWTIssuer,
+		Audience:   config.Auth.JWTAudience,
+	}, clock.System{})
+	if err != nil {
+		log.Printf("failed to load JWT keys: %v", err)
+		return
+	}
+
+	// The verified client certificates of the mTLS callers authenticate them when they are mapped to an identity
+	clientCerts, err := auth.LoadClientCerts(config.TLS.ClientIdentitiesFile)
+	if err != nil {
+		log.Printf("failed to load client certificate identities: %v", err)
+		return
+	}
+
+	// The listeners serve TLS when a certificate is configured. The certificates are reloaded whenever the files
+	// change, so they can be renewed without a restart
+	var certs *tlsconfig.Reloader
+	if config.TLS.CertFile != "" {
+		certs, err = tlsconfig.NewReloader(&tlsconfig.Config{
+			CertFile:     config.TLS.CertFile,
+			KeyFile:      config.TLS.KeyFile,
+			ClientCAFile: config.TLS.ClientCAFile,
+			ClientAuth:   tlsconfig.ClientAuth(config.TLS.ClientAuth),
+		})
+		if err != nil {
+			log.Printf("failed to load TLS certificates: %v", err)
+			return
+		}
+
+		go func() {
+			if err := certs.Watch(ctx); err != nil {
+				log.Printf("failed to watch TLS certificates: %v", err)
+			}
+		}()
+	}
+
+	// The rate limits are counted in each instance unless they must be shared by all the instances
+	var rateLimitStore ratelimit.Store = ratelimit.NewMemoryStore(clock.System{})
+	if string(config.RateLimit.Backend) == "POSTGRES" {
+		rateLimitStore = ratelimit.NewPostgresStore(models.New(conn.Conn), clock.System{})
+	}
+
+	rateLimiter, err := ratelimit.NewLimiter(config.RateLimit.RulesFile, rateLimitStore)
+	if err != nil {
+		log.Printf("failed to load rate limits: %v", err)
+		return
+	}
+
 	jsonWriter := response.NewJSONWriter()
 	v := validator.New()
 
@@ -57,15 +235,34 @@ func main() {
 	// and also make is easier to test the code by passing in a mock implementation of the dependencies
 	// instead of the actual implementation
 	params := &api.Params{
-		DB:        conn,
-		Reader:    request.NewReader(jsonWriter, v),
-		Writer:    jsonWriter,
-		Validator: v,
+		DB:            conn,
+		Reader:        request.NewReader(jsonWriter, v),
+		Writer:        jsonWriter,
+		Validator:     v,
+		RiskEngine:    riskEngine,
+		RewardsEngine: rewardsEngine,
+		Accounts:      config.Accounts,
+		Transactions:  config.Transactions,
+		Clock:         clock.System{},
+		Authenticator: auth.NewAuthenticator(models.New(conn.Conn), jwtVerifier, clientCerts, clock.System{}),
+		RateLimiter:   rateLimiter,
+		Logger:        logger,
+		MaxBodyBytes:  config.Server.MaxBodyBytes,
+		Health:        healthChecks,
 	}
 
 	serverConfig := &server.Config{
-		Port: config.Server.Port,
-		Host: config.Server.Address,
+		Port:      config.Server.Port,
+		Host:      config.Server.Address,
+		AdminPort: config.Server.AdminPort,
+		AdminHost: config.Server.AdminAddress,
+		GRPCPort:  config.Server.GRPCPort,
+
+		ReadTimeout:     config.Server.ReadTimeout,
+		WriteTimeout:    config.Server.WriteTimeout,
+		IdleTimeout:     config.Server.IdleTimeout,
+		ShutdownTimeout: config.Server.ShutdownTimeout,
+		TLS:             certs,
 	}
 
 	s := server.New(serverConfig, params) // Initialize the server
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// masked replaces the values of the secrets when the config is printed
+const masked = "********"
+
+// Print writes the sections of the config, a struct of section structs, one field per line. The fields tagged
+// `secret:"true"` are masked, they are only shown as empty when they aren't set
+func Print(w io.Writer, cfg any) error {
+	sections := reflect.Indirect(reflect.ValueOf(cfg))
+	if sections.Kind() != reflect.Struct {
+		return fmt.Errorf("config.Print: the config is a %s, not a struct", sections.Kind())
+	}
+
+	for i := 0; i < sections.NumField(); i++ {
+		section := reflect.Indirect(sections.Field(i))
+		if section.Kind() != reflect.Struct {
+			continue
+		}
+
+		if _, err := fmt.Fprintf(w, "%s:\n", sections.Type().Field(i).Name); err != nil {
+			return fmt.Errorf("config.Print: %w", err)
+		}
+
+		for j := 0; j < section.NumField(); j++ {
+			field := section.Type().Field(j)
+
+			value := fmt.Sprint(section.Field(j).Interface())
+			if field.Tag.Get("secret") == "true" && value != "" {
+				value = masked
+			}
+
+			if _, err := fmt.Fprintf(w, "  %s: %s\n", field.Name, value); err != nil {
+				return fmt.Errorf("config.Print: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
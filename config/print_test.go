@@ -0,0 +1,52 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrint(t *testing.T) {
+	cfg := &struct {
+		Database *DB
+		Auth     *Auth
+		// Fields that aren't sections are left out
+		Version string
+	}{
+		Database: &DB{
+			Host:             "localhost",
+			Port:             "5432",
+			User:             "pismo",
+			Password:         "db_password",
+			Name:             "pismo",
+			MaxConns:         5,
+			StatementTimeout: 30 * time.Second,
+		},
+		Auth: &Auth{
+			JWTIssuer: "https://auth.example.com",
+		},
+		Version: "1.0.0",
+	}
+
+	buf := &bytes.Buffer{}
+	assert.Nil(t, Print(buf, cfg))
+
+	out := buf.String()
+	assert.Contains(t, out, "Database:\n  Host: localhost\n  Port: 5432\n")
+	assert.Contains(t, out, "  MaxConns: 5\n")
+	assert.Contains(t, out, "  StatementTimeout: 30s\n")
+	assert.Contains(t, out, "Auth:\n")
+	assert.Contains(t, out, "  JWTIssuer: https://auth.example.com\n")
+	assert.NotContains(t, out, "Version")
+
+	// The secrets are masked, the unset ones are shown as empty
+	assert.Contains(t, out, "  Password: ********\n")
+	assert.NotContains(t, out, "db_password")
+	assert.Contains(t, out, "  JWTHMACSecret: \n")
+}
+
+func TestPrint_NotAStruct(t *testing.T) {
+	assert.NotNil(t, Print(&bytes.Buffer{}, "config"))
+}
@@ -1,20 +1,167 @@
 package config
 
+import "time"
+
 type (
 	Environment string
 
+	// DocumentUniqueness is the scope in which the document number of an account must be unique
+	DocumentUniqueness string
+
+	// RateLimitBackend is where the rate limiter counts the requests
+	RateLimitBackend string
+
+	// TLSClientAuth is whether the TLS listeners require a client certificate
+	TLSClientAuth string
+
 	//Server has all the server related config
 	Server struct {
 		Port        int         `validate:"required"`
 		Address     string      `validate:"required"`
 		Environment Environment `validate:"required,oneof=PROD STAGING DEV TEST"`
+		// AdminPort and AdminAddress are where the metrics are served, apart from the API. 0 disables the admin listener
+		AdminPort    int    `validate:"min=0,max=65535"`
+		AdminAddress string `validate:"required_unless=AdminPort 0"`
+		// GRPCPort is where the gRPC API is served, on the address of the HTTP API. 0 disables the gRPC listener
+		GRPCPort int `validate:"min=0,max=65535"`
+		// MaxBodyBytes is the size limit of the request bodies, of the routes that don't set their own
+		MaxBodyBytes int64 `validate:"required,min=1"`
+		// ReadTimeout, WriteTimeout and IdleTimeout bound the requests and the idle keep-alive connections of the
+		// HTTP listeners
+		ReadTimeout  time.Duration `validate:"required,gt=0"`
+		WriteTimeout time.Duration `validate:"required,gt=0"`
+		IdleTimeout  time.Duration `validate:"required,gt=0"`
+		// ShutdownTimeout is how long the pending requests have to finish once the server is asked to stop
+		ShutdownTimeout time.Duration `validate:"required,gt=0"`
+	}
+
+	//TLS has the config for the TLS of the HTTP and gRPC listeners, they serve plaintext when no certificate is set.
+	// The files are read again when they change
+	TLS struct {
+		// CertFile and KeyFile are the PEM certificate chain and private key of the listeners
+		CertFile string `validate:"required_with=KeyFile ClientCAFile,omitempty,file"`
+		KeyFile  string `validate:"required_with=CertFile,omitempty,file"`
+		// ClientCAFile is the PEM bundle of the CAs of the client certificates, it enables mTLS
+		ClientCAFile string `validate:"required_with=ClientIdentitiesFile,omitempty,file"`
+		// ClientAuth is whether every client must send a certificate, REQUIRED, or only the ones without credentials,
+		// OPTIONAL
+		ClientAuth TLSClientAuth `validate:"required,oneof=OPTIONAL REQUIRED"`
+		// ClientIdentitiesFile is the YAML file that maps the subjects of the client certificates to API identities
+		ClientIdentitiesFile string `validate:"omitempty,file"`
+	}
+
+	//Log has the config for the logs, they are written to the standard output as JSON
+	Log struct {
+		// Level is the minimum level of the logs that are written
+		Level string `validate:"required,oneof=DEBUG INFO WARN ERROR"`
 	}
 
 	DB struct {
 		Host     string `validate:"required,hostname_rfc1123"`
 		Port     string `validate:"required,number"`
 		User     string `validate:"required"`
-		Password string `validate:"required"`
+		Password string `validate:"required" secret:"true"`
 		Name     string `validate:"required"`
+		// ForceTLS requires TLS on the connections, they only prefer it otherwise
+		ForceTLS bool
+		// AppName is the application_name of the connections, it tells the service apart in pg_stat_activity
+		AppName string `validate:"max=63"`
+		// MaxConns and MinConns are the size bounds of the connection pool
+		MaxConns int32 `validate:"required,min=1"`
+		MinConns int32 `validate:"min=0,ltefield=MaxConns"`
+		// MaxConnLifetime and MaxConnIdleTime are how long a connection is used and kept idle before it is closed
+		MaxConnLifetime time.Duration `validate:"required,gt=0"`
+		MaxConnIdleTime time.Duration `validate:"required,gt=0"`
+		// HealthCheckPeriod is how often the idle connections are checked
+		HealthCheckPeriod time.Duration `validate:"required,gt=0"`
+		// StatementTimeout aborts the statements that run longer. The statements aren't bounded when it is 0
+		StatementTimeout time.Duration `validate:"min=0"`
+	}
+
+	//Risk has the config for the transaction risk rules engine
+	Risk struct {
+		// RulesFile is the YAML file with the risk rules. No rules are applied when it is empty
+		RulesFile string `validate:"omitempty,file"`
+	}
+
+	//Transactions has the config for the transactions API
+	Transactions struct {
+		// MaxEventDateBackdate is how far in the past the event date of a transaction can be
+		MaxEventDateBackdate time.Duration `validate:"min=0"`
+		// MaxEventDateForward is how far in the future the event date of a transaction can be
+		MaxEventDateForward time.Duration `validate:"min=0"`
+	}
+
+	//Scheduler has the config for the worker that posts the scheduled transactions
+	Scheduler struct {
+		// Interval is how often the due occurrences are posted
+		Interval time.Duration `validate:"required,gt=0"`
+		// BatchSize is the maximum number of occurrences posted per interval
+		BatchSize int `validate:"required,min=1"`
 	}
+
+	//Rewards has the config for the cashback and points rewards
+	Rewards struct {
+		// RulesFile is the YAML file with the reward rules. No reward is accrued when it is empty
+		RulesFile string `validate:"omitempty,file"`
+		// PayoutInterval is how often the pending cashback is paid out
+		PayoutInterval time.Duration `validate:"required,gt=0"`
+		// PayoutBatchSize is the maximum number of accounts paid out per interval
+		PayoutBatchSize int `validate:"required,min=1"`
+		// PayoutRetryBackoff is how long an account whose payout failed waits before it is paid out again
+		PayoutRetryBackoff time.Duration `validate:"required,gt=0"`
+	}
+
+	//Auth has the config for the authentication of the API. API keys are always accepted,
+	// bearer tokens are only accepted when an HMAC secret or a JWKS file is set
+	Auth struct {
+		// JWTHMACSecret verifies the tokens signed with HS256, HS384 or HS512
+		JWTHMACSecret string `validate:"omitempty,min=32" secret:"true"`
+		// JWKSFile is a JSON Web Key Set with the public keys that verify the tokens signed with RSA or ECDSA
+		JWKSFile string `validate:"omitempty,file"`
+		// JWTIssuer and JWTAudience are the iss and aud claims required in the tokens, they are not checked when empty
+		JWTIssuer   string
+		JWTAudience string
+	}
+
+	//RateLimit has the config for the rate limits of the API
+	RateLimit struct {
+		// RulesFile is the YAML file with the rate limits of the routes. No request is limited when it is empty
+		RulesFile string `validate:"omitempty,file"`
+		// Backend is where the requests are counted: MEMORY in each instance or POSTGRES, shared by all the instances
+		Backend RateLimitBackend `validate:"required,oneof=MEMORY POSTGRES"`
+	}
+
+	//Tracing has the config for the OpenTelemetry traces of the requests and of the DB queries
+	Tracing struct {
+		// Exporter is where the spans are sent: NONE, STDOUT or OTLP (OTLP/HTTP)
+		Exporter string `validate:"required,oneof=NONE STDOUT OTLP"`
+		// OTLPEndpoint is the host:port of the collector. When empty the OTEL_EXPORTER_OTLP_* variables apply
+		OTLPEndpoint string `validate:"omitempty,hostname_port"`
+		OTLPInsecure bool
+		// SampleRatio is the share of the traces started by the service that are sampled
+		SampleRatio float64 `validate:"min=0,max=1"`
+	}
+
+	//Accounts has the config for the accounts API
+	Accounts struct {
+		DocumentUniqueness DocumentUniqueness `validate:"required,oneof=GLOBAL USER"`
+	}
+)
+
+const (
+	// DocumentUniquenessGlobal allows a document number in a single account across all the users
+	DocumentUniquenessGlobal DocumentUniqueness = "GLOBAL"
+	// DocumentUniquenessUser allows a document number in a single account of each user
+	DocumentUniquenessUser DocumentUniqueness = "USER"
+
+	// RateLimitBackendMemory counts the requests in token buckets in the memory of each instance
+	RateLimitBackendMemory RateLimitBackend = "MEMORY"
+	// RateLimitBackendPostgres counts the requests in Postgres, the limits are shared by all the instances
+	RateLimitBackendPostgres RateLimitBackend = "POSTGRES"
+
+	// TLSClientAuthOptional verifies the client certificates that are sent, the other callers send credentials
+	TLSClientAuthOptional TLSClientAuth = "OPTIONAL"
+	// TLSClientAuthRequired rejects the connections without a client certificate signed by the client CA
+	TLSClientAuthRequired TLSClientAuth = "REQUIRED"
 )
@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// apiKeyPrefix makes the API keys easy to recognize, e.g. by secret scanners
+const apiKeyPrefix = "pk_"
+
+// GenerateAPIKey returns a new random API key and the hash to store
+func GenerateAPIKey() (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", fmt.Errorf("auth.GenerateAPIKey: error generating key: %w", err)
+	}
+
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return key, HashAPIKey(key), nil
+}
+
+// HashAPIKey returns the hex SHA-256 of the key. The keys are 256 bits of randomness, so a fast hash is enough to
+// make the stored hashes useless to an attacker, and it lets the key be looked up by its hash
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
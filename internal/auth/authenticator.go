@@ -0,0 +1,125 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/jackc/pgx/v4"
+)
+
+const apiKeyHeader = "X-API-Key"
+
+var (
+	ErrMissingCredentials = errors.New("MISSING_CREDENTIALS")
+	ErrInvalidCredentials = errors.New("INVALID_CREDENTIALS")
+	ErrExpiredCredentials = errors.New("EXPIRED_CREDENTIALS")
+)
+
+// Authenticator authenticates the requests with an API key in the X-API-Key header, a JWT in the Authorization
+// header with the Bearer scheme or the client certificate of the TLS connection
+type Authenticator struct {
+	querier models.Querier
+	// jwt is nil when no key to verify the tokens is configured
+	jwt *JWTVerifier
+	// certs is nil when the client certificates don't authenticate the callers
+	certs *ClientCerts
+	clock clock.Clock
+}
+
+func NewAuthenticator(querier models.Querier, jwt *JWTVerifier, certs *ClientCerts, clock clock.Clock) *Authenticator {
+	return &Authenticator{querier: querier, jwt: jwt, certs: certs, clock: clock}
+}
+
+// Authenticate returns the identity of the caller of the request
+func (a *Authenticator) Authenticate(r *http.Request) (*Identity, error) {
+	return a.AuthenticateConnection(r.Context(), r.Header.Get(apiKeyHeader), r.Header.Get("Authorization"), r.TLS)
+}
+
+// AuthenticateConnection returns the identity of the caller from its credentials, or from the client certificate of
+// the TLS connection when it sent none. The credentials come first, so that a partner connected with mTLS can act
+// with the token of a user
+func (a *Authenticator) AuthenticateConnection(ctx context.Context, apiKey, authorization string, state *tls.ConnectionState) (*Identity, error) {
+	identity, err := a.AuthenticateCredentials(ctx, apiKey, authorization)
+	if errors.Is(err, ErrMissingCredentials) && a.certs != nil {
+		return a.certs.Identity(state)
+	}
+
+	return identity, err
+}
+
+// AuthenticateCredentials returns the identity of the caller from the API key or the value of the Authorization
+// header, whichever transport they were sent on. The API key is used when both are set
+func (a *Authenticator) AuthenticateCredentials(ctx context.Context, apiKey, authorization string) (*Identity, error) {
+	if apiKey != "" {
+		return a.authenticateAPIKey(ctx, apiKey)
+	}
+
+	scheme, token, found := strings.Cut(authorization, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, ErrMissingCredentials
+	}
+
+	return a.authenticateToken(token)
+}
+
+func (a *Authenticator) authenticateAPIKey(ctx context.Context, key string) (*Identity, error) {
+	apiKey, err := a.querier.GetAPIKeyByHash(ctx, HashAPIKey(key))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("auth.authenticateAPIKey: error fetching key: %w", err)
+	}
+
+	if apiKey.RevokedAt.Valid {
+		return nil, ErrInvalidCredentials
+	}
+
+	if apiKey.ExpiresAt.Valid && !a.clock.Now().Before(apiKey.ExpiresAt.Time) {
+		return nil, ErrExpiredCredentials
+	}
+
+	// The last use is only informative, a failure to record it doesn't fail the request
+	if err = a.querier.TouchAPIKey(ctx, apiKey.Uuid); err != nil {
+		logging.FromContext(ctx).Warn("authenticateAPIKey: failed to record the use of the key", "api_key_id", apiKey.Uuid, "error", err)
+	}
+
+	return &Identity{
+		Subject: apiKey.Uuid,
+		Method:  MethodAPIKey,
+		UserID:  apiKey.UserID.String,
+		Scopes:  toScopes(apiKey.Scopes),
+	}, nil
+}
+
+func (a *Authenticator) authenticateToken(token string) (*Identity, error) {
+	if a.jwt == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	claims, err := a.jwt.Verify(token)
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return nil, ErrExpiredCredentials
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCredentials, err)
+	}
+
+	// The subject of the tokens is the user they are issued to
+	return &Identity{
+		Subject: claims.Subject,
+		Method:  MethodJWT,
+		UserID:  claims.Subject,
+		Scopes:  toScopes(strings.Fields(claims.Scope)),
+	}, nil
+}
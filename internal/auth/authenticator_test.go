@@ -0,0 +1,309 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dummyKeyID  = "9b1c6f3e-2a4d-4e8f-a1b2-c3d4e5f60718"
+	dummyUserID = "77e0e837-e7f2-47b1-a08c-3af267c03077"
+	testSecret  = "a-test-secret-that-is-at-least-32-bytes"
+)
+
+var dummyNow = time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+
+func newRequest(header, value string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/v1/accounts", nil)
+	if header != "" {
+		req.Header.Set(header, value)
+	}
+
+	return req
+}
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, hash, err := GenerateAPIKey()
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(key, "pk_"))
+	assert.Equal(t, HashAPIKey(key), hash)
+	assert.NotContains(t, hash, key)
+
+	other, _, err := GenerateAPIKey()
+	assert.Nil(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestAuthenticate_APIKey(t *testing.T) {
+	const key = "pk_test"
+
+	tests := []struct {
+		name     string
+		row      *models.GetAPIKeyByHashRow
+		err      error
+		expected *Identity
+		errIs    error
+	}{
+		{
+			name:     "authenticates a partner key",
+			row:      &models.GetAPIKeyByHashRow{Uuid: dummyKeyID},
+			expected: &Identity{Subject: dummyKeyID, Method: MethodAPIKey},
+		},
+		{
+			name:     "authenticates the key of a user",
+			row:      &models.GetAPIKeyByHashRow{Uuid: dummyKeyID, UserID: sql.NullString{String: dummyUserID, Valid: true}},
+			expected: &Identity{Subject: dummyKeyID, Method: MethodAPIKey, UserID: dummyUserID},
+		},
+		{
+			name:  "rejects an unknown key",
+			err:   pgx.ErrNoRows,
+			errIs: ErrInvalidCredentials,
+		},
+		{
+			name:  "rejects a revoked key",
+			row:   &models.GetAPIKeyByHashRow{Uuid: dummyKeyID, RevokedAt: sql.NullTime{Time: dummyNow.Add(-time.Hour), Valid: true}},
+			errIs: ErrInvalidCredentials,
+		},
+		{
+			name:  "rejects an expired key",
+			row:   &models.GetAPIKeyByHashRow{Uuid: dummyKeyID, ExpiresAt: sql.NullTime{Time: dummyNow, Valid: true}},
+			errIs: ErrExpiredCredentials,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			querier := mock.NewMockQuerier(ctrl)
+
+			querier.EXPECT().GetAPIKeyByHash(gomock.Any(), HashAPIKey(key)).Return(tt.row, tt.err)
+			if tt.expected != nil {
+				querier.EXPECT().TouchAPIKey(gomock.Any(), dummyKeyID).Return(nil)
+			}
+
+			identity, err := NewAuthenticator(querier, nil, nil, clock.Fixed(dummyNow)).Authenticate(newRequest("X-API-Key", key))
+			assert.ErrorIs(t, err, tt.errIs)
+			assert.Equal(t, tt.expected, identity)
+		})
+	}
+}
+
+func TestAuthenticate_MissingCredentials(t *testing.T) {
+	authenticator := NewAuthenticator(nil, nil, nil, clock.Fixed(dummyNow))
+
+	for _, value := range []string{"", "Basic dXNlcjpwYXNz", "Bearer", "Bearer "} {
+		_, err := authenticator.Authenticate(newRequest("Authorization", value))
+		assert.ErrorIs(t, err, ErrMissingCredentials, value)
+	}
+}
+
+func TestAuthenticate_HMACToken(t *testing.T) {
+	verifier, err := NewJWTVerifier(JWTConfig{HMACSecret: testSecret, Issuer: "https://auth.test", Audience: "pismo"}, clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	authenticator := NewAuthenticator(nil, verifier, nil, clock.Fixed(dummyNow))
+
+	claims := func(issuer string, expiresAt time.Time) jwt.RegisteredClaims {
+		return jwt.RegisteredClaims{
+			Subject:   dummyUserID,
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{"pismo"},
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		}
+	}
+
+	sign := func(method jwt.SigningMethod, claims jwt.Claims, key any) string {
+		token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		assert.Nil(t, err)
+		return token
+	}
+
+	t.Run("authenticates a valid token", func(t *testing.T) {
+		token := sign(jwt.SigningMethodHS256, claims("https://auth.test", dummyNow.Add(time.Hour)), []byte(testSecret))
+
+		identity, err := authenticator.Authenticate(newRequest("Authorization", "Bearer "+token))
+		assert.Nil(t, err)
+		assert.Equal(t, &Identity{Subject: dummyUserID, Method: MethodJWT, UserID: dummyUserID}, identity)
+	})
+
+	t.Run("rejects an expired token", func(t *testing.T) {
+		token := sign(jwt.SigningMethodHS256, claims("https://auth.test", dummyNow.Add(-time.Minute)), []byte(testSecret))
+
+		_, err := authenticator.Authenticate(newRequest("Authorization", "Bearer "+token))
+		assert.ErrorIs(t, err, ErrExpiredCredentials)
+	})
+
+	t.Run("rejects a token of another issuer", func(t *testing.T) {
+		token := sign(jwt.SigningMethodHS256, claims("https://evil.test", dummyNow.Add(time.Hour)), []byte(testSecret))
+
+		_, err := authenticator.Authenticate(newRequest("Authorization", "Bearer "+token))
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("rejects a token signed with another secret", func(t *testing.T) {
+		token := sign(jwt.SigningMethodHS256, claims("https://auth.test", dummyNow.Add(time.Hour)), []byte("another-secret-that-is-32-bytes-long"))
+
+		_, err := authenticator.Authenticate(newRequest("Authorization", "Bearer "+token))
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("rejects an unsigned token", func(t *testing.T) {
+		token := sign(jwt.SigningMethodNone, claims("https://auth.test", dummyNow.Add(time.Hour)), jwt.UnsafeAllowNoneSignatureType)
+
+		_, err := authenticator.Authenticate(newRequest("Authorization", "Bearer "+token))
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+}
+
+func TestAuthenticate_JWKSToken(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	encode := func(i *big.Int) string { return base64.RawURLEncoding.EncodeToString(i.Bytes()) }
+
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": encode(rsaKey.N), "e": encode(big.NewInt(int64(rsaKey.E)))},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": encode(ecKey.X), "y": encode(ecKey.Y)},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "invalid"},
+	}})
+	assert.Nil(t, err)
+
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	assert.Nil(t, os.WriteFile(file, jwks, 0o600))
+
+	verifier, err := NewJWTVerifier(JWTConfig{JWKSFile: file}, clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	authenticator := NewAuthenticator(nil, verifier, nil, clock.Fixed(dummyNow))
+	claims := jwt.RegisteredClaims{Subject: dummyUserID, ExpiresAt: jwt.NewNumericDate(dummyNow.Add(time.Hour))}
+
+	sign := func(method jwt.SigningMethod, kid string, key any) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = kid
+
+		signed, err := token.SignedString(key)
+		assert.Nil(t, err)
+		return signed
+	}
+
+	tests := map[string]struct {
+		token string
+		valid bool
+	}{
+		"RSA key":        {sign(jwt.SigningMethodRS256, "rsa-1", rsaKey), true},
+		"EC key":         {sign(jwt.SigningMethodES256, "ec-1", ecKey), true},
+		"unknown key ID": {sign(jwt.SigningMethodRS256, "rsa-2", rsaKey), false},
+		"wrong key":      {sign(jwt.SigningMethodES256, "ec-1", mustECKey(t)), false},
+		// Without an HMAC secret, a token signed with HS256 is never accepted
+		"HMAC token": {sign(jwt.SigningMethodHS256, "rsa-1", []byte(testSecret)), false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			identity, err := authenticator.Authenticate(newRequest("Authorization", "Bearer "+tt.token))
+			if tt.valid {
+				assert.Nil(t, err)
+				assert.Equal(t, dummyUserID, identity.UserID)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrInvalidCredentials)
+		})
+	}
+}
+
+func TestAuthenticate_TokensDisabled(t *testing.T) {
+	verifier, err := NewJWTVerifier(JWTConfig{}, clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+	assert.Nil(t, verifier)
+
+	_, err = NewAuthenticator(nil, verifier, nil, clock.Fixed(dummyNow)).Authenticate(newRequest("Authorization", "Bearer token"))
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestMiddleware(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	querier := mock.NewMockQuerier(ctrl)
+
+	querier.EXPECT().GetAPIKeyByHash(gomock.Any(), HashAPIKey("pk_valid")).Return(&models.GetAPIKeyByHashRow{Uuid: dummyKeyID}, nil)
+	querier.EXPECT().GetAPIKeyByHash(gomock.Any(), HashAPIKey("pk_unknown")).Return(nil, pgx.ErrNoRows)
+	querier.EXPECT().TouchAPIKey(gomock.Any(), dummyKeyID).Return(nil)
+
+	var identity *Identity
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		identity, _ = FromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	handler := NewMiddleware(NewAuthenticator(querier, nil, nil, clock.Fixed(dummyNow)), response.NewJSONWriter())(next)
+
+	t.Run("adds the identity to the context", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, newRequest("X-API-Key", "pk_valid"))
+
+		assert.Equal(t, http.StatusNoContent, rr.Code)
+		assert.Equal(t, &Identity{Subject: dummyKeyID, Method: MethodAPIKey}, identity)
+	})
+
+	for name, tt := range map[string]struct {
+		header, value string
+		code          response.ErrorCode
+	}{
+		"missing credentials": {"", "", response.MissingCredentials},
+		"invalid credentials": {"X-API-Key", "pk_unknown", response.InvalidCredentials},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, newRequest(tt.header, tt.value))
+
+			assert.Equal(t, http.StatusUnauthorized, rr.Code)
+			assert.Equal(t, "Bearer", rr.Header().Get("WWW-Authenticate"))
+
+			res := &struct {
+				Error *response.APIError `json:"error"`
+			}{}
+			assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+			assert.Equal(t, tt.code, res.Error.Code)
+		})
+	}
+
+	t.Run("the context of unauthenticated requests has no identity", func(t *testing.T) {
+		_, ok := FromContext(context.Background())
+		assert.False(t, ok)
+	})
+}
+
+func mustECKey(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	return key
+}
@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// ClientCerts maps the subjects of the client certificates of the mTLS callers, e.g. the partners, to their
+	// identities. The certificates are verified by the TLS listener against the client CA
+	ClientCerts struct {
+		clients map[string]*Identity
+	}
+
+	// clientCertsFile is the YAML file of the client certificates
+	clientCertsFile struct {
+		Clients []clientCert `yaml:"clients"`
+	}
+
+	// clientCert is the identity of the callers whose certificate has the subject
+	clientCert struct {
+		// Subject is the distinguished name of the certificate in the RFC 2253 format, e.g. CN=partner-x,O=Partner X
+		Subject string `yaml:"subject"`
+		// UserID is the user the certificate belongs to, it is empty for the certificates of the partners
+		UserID string   `yaml:"user_id"`
+		Scopes []string `yaml:"scopes"`
+	}
+)
+
+// LoadClientCerts reads the identities of the client certificates in the given YAML file. It returns nil when path is
+// empty, then the client certificates don't authenticate the callers
+func LoadClientCerts(path string) (*ClientCerts, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("auth.LoadClientCerts: failed to read client certificates file: %w", err)
+	}
+
+	file := &clientCertsFile{}
+	if err = yaml.Unmarshal(contents, file); err != nil {
+		return nil, fmt.Errorf("auth.LoadClientCerts: failed to parse client certificates file: %w", err)
+	}
+
+	certs := &ClientCerts{clients: make(map[string]*Identity, len(file.Clients))}
+	for _, client := range file.Clients {
+		if client.Subject == "" {
+			return nil, fmt.Errorf("auth.LoadClientCerts: client certificate without a subject")
+		}
+
+		if certs.clients[client.Subject] != nil {
+			return nil, fmt.Errorf("auth.LoadClientCerts: client certificate %q: the subject is listed twice", client.Subject)
+		}
+
+		scopes, err := ParseScopes(strings.Join(client.Scopes, ","))
+		if err != nil {
+			return nil, fmt.Errorf("auth.LoadClientCerts: client certificate %q: %w", client.Subject, err)
+		}
+
+		// Like the API keys, only the certificates of the users get every scope when they have none
+		if len(scopes) == 0 && client.UserID == "" {
+			return nil, fmt.Errorf("auth.LoadClientCerts: client certificate %q: a partner certificate needs scopes", client.Subject)
+		}
+
+		certs.clients[client.Subject] = &Identity{
+			Subject: client.Subject,
+			Method:  MethodClientCert,
+			UserID:  client.UserID,
+			Scopes:  toScopes(scopes),
+		}
+	}
+
+	return certs, nil
+}
+
+// Identity returns the identity of the client certificate of the connection. It is ErrMissingCredentials when the
+// connection has no verified client certificate, and ErrInvalidCredentials when the subject of the certificate is
+// unknown
+func (c *ClientCerts) Identity(state *tls.ConnectionState) (*Identity, error) {
+	if c == nil || state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, ErrMissingCredentials
+	}
+
+	subject := state.VerifiedChains[0][0].Subject.String()
+
+	identity, ok := c.clients[subject]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown client certificate %q", ErrInvalidCredentials, subject)
+	}
+
+	// The identity is copied, the identities are shared by all the connections
+	copied := *identity
+
+	return &copied, nil
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+const testClientCerts = `
+clients:
+  - subject: CN=partner-x,O=Partner X
+    scopes: [accounts:read, transactions:write]
+  - subject: CN=user-app
+    user_id: 77e0e837-e7f2-47b1-a08c-3af267c03077
+`
+
+func writeClientCerts(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "client_identities.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	return path
+}
+
+// verifiedState is the state of a TLS connection with a verified client certificate of the subject
+func verifiedState(subject pkix.Name) *tls.ConnectionState {
+	return &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{Subject: subject}}}}
+}
+
+func TestLoadClientCerts(t *testing.T) {
+	t.Run("no file", func(t *testing.T) {
+		certs, err := LoadClientCerts("")
+		assert.Nil(t, err)
+		assert.Nil(t, certs)
+	})
+
+	t.Run("valid file", func(t *testing.T) {
+		certs, err := LoadClientCerts(writeClientCerts(t, testClientCerts))
+		assert.Nil(t, err)
+		assert.Len(t, certs.clients, 2)
+	})
+
+	for name, contents := range map[string]string{
+		"no subject":       "clients:\n  - scopes: [accounts:read]\n",
+		"same subject":     "clients:\n  - subject: CN=a\n    scopes: [admin]\n  - subject: CN=a\n    scopes: [admin]\n",
+		"unknown scope":    "clients:\n  - subject: CN=a\n    scopes: [accounts:delete]\n",
+		"partner no scope": "clients:\n  - subject: CN=a\n",
+		"invalid YAML":     "clients: {",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadClientCerts(writeClientCerts(t, contents))
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestAuthenticate_ClientCert(t *testing.T) {
+	certs, err := LoadClientCerts(writeClientCerts(t, testClientCerts))
+	assert.Nil(t, err)
+
+	authenticator := NewAuthenticator(nil, nil, certs, clock.Fixed(dummyNow))
+
+	t.Run("partner certificate", func(t *testing.T) {
+		req := newRequest("", "")
+		req.TLS = verifiedState(pkix.Name{CommonName: "partner-x", Organization: []string{"Partner X"}})
+
+		identity, err := authenticator.Authenticate(req)
+		assert.Nil(t, err)
+		assert.Equal(t, &Identity{
+			Subject: "CN=partner-x,O=Partner X",
+			Method:  MethodClientCert,
+			Scopes:  []Scope{ScopeAccountsRead, ScopeTransactionsWrite},
+		}, identity)
+		assert.False(t, identity.HasScope(ScopeUsersRead))
+	})
+
+	t.Run("user certificate", func(t *testing.T) {
+		req := newRequest("", "")
+		req.TLS = verifiedState(pkix.Name{CommonName: "user-app"})
+
+		identity, err := authenticator.Authenticate(req)
+		assert.Nil(t, err)
+		assert.Equal(t, dummyUserID, identity.UserID)
+		assert.True(t, identity.HasScope(ScopeAccountsRead))
+	})
+
+	t.Run("unknown subject", func(t *testing.T) {
+		req := newRequest("", "")
+		req.TLS = verifiedState(pkix.Name{CommonName: "partner-y"})
+
+		_, err := authenticator.Authenticate(req)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("no verified certificate", func(t *testing.T) {
+		req := newRequest("", "")
+		req.TLS = &tls.ConnectionState{}
+
+		_, err := authenticator.Authenticate(req)
+		assert.ErrorIs(t, err, ErrMissingCredentials)
+
+		// Plaintext requests have no TLS state
+		_, err = authenticator.Authenticate(newRequest("", ""))
+		assert.ErrorIs(t, err, ErrMissingCredentials)
+	})
+
+	t.Run("the credentials come before the certificate", func(t *testing.T) {
+		req := newRequest("Authorization", "Bearer token")
+		req.TLS = verifiedState(pkix.Name{CommonName: "partner-x", Organization: []string{"Partner X"}})
+
+		// No key verifies the tokens, the token is rejected instead of falling back to the certificate
+		_, err := authenticator.Authenticate(req)
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+
+	t.Run("client certificates are not configured", func(t *testing.T) {
+		req := newRequest("", "")
+		req.TLS = verifiedState(pkix.Name{CommonName: "partner-x", Organization: []string{"Partner X"}})
+
+		_, err := NewAuthenticator(nil, nil, nil, clock.Fixed(dummyNow)).Authenticate(req)
+		assert.ErrorIs(t, err, ErrMissingCredentials)
+	})
+}
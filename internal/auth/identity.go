@@ -0,0 +1,54 @@
+package auth
+
+import "context"
+
+// Method is how the caller authenticated
+type Method string
+
+const (
+	MethodAPIKey Method = "API_KEY"
+	MethodJWT    Method = "JWT"
+	// MethodClientCert is a verified client certificate of a TLS connection
+	MethodClientCert Method = "CLIENT_CERT"
+)
+
+// Identity is the authenticated caller of a request
+type Identity struct {
+	// Subject is the ID of the API key, the subject of the token or the subject of the client certificate
+	Subject string
+	Method  Method
+	// UserID is the user the credentials belong to, it is empty for the API keys of the partners
+	UserID string
+	// Scopes are what the caller is allowed to do. The users get all the scopes but admin on their own resources
+	// when their credentials have no scopes
+	Scopes []Scope
+}
+
+// HasScope tells whether the caller is allowed the scope, admin allows every scope
+func (i *Identity) HasScope(scope Scope) bool {
+	scopes := i.Scopes
+	if len(scopes) == 0 && i.UserID != "" {
+		scopes = userScopes
+	}
+
+	for _, s := range scopes {
+		if s == scope || s == ScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+type identityKey struct{}
+
+// NewContext returns a copy of the context with the identity of the caller
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the identity of the caller set by the middleware, it is false for unauthenticated requests
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
@@ -0,0 +1,229 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/imjenal/transaction-service/internal/clock"
+)
+
+// leeway is the clock skew tolerated when checking the expiry and not before of the tokens
+const leeway = 30 * time.Second
+
+var (
+	hmacMethods       = []string{"HS256", "HS384", "HS512"}
+	asymmetricMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+)
+
+// JWTConfig has the keys and the expected claims of the bearer tokens
+type JWTConfig struct {
+	// HMACSecret verifies the tokens signed with HS256, HS384 or HS512
+	HMACSecret string
+	// JWKSFile is a JSON Web Key Set with the public keys that verify the tokens signed with RSA or ECDSA
+	JWKSFile string
+	// Issuer and Audience are checked when they are set
+	Issuer   string
+	Audience string
+}
+
+// JWTVerifier verifies the signature and the claims of the bearer tokens
+type JWTVerifier struct {
+	secret []byte
+	// keys are the public keys of the JWKS by key ID
+	keys   map[string]crypto.PublicKey
+	parser *jwt.Parser
+}
+
+// NewJWTVerifier reads the keys of the config. It returns nil when neither a secret nor a JWKS is configured,
+// then bearer tokens are not accepted
+func NewJWTVerifier(cfg JWTConfig, clock clock.Clock) (*JWTVerifier, error) {
+	if cfg.HMACSecret == "" && cfg.JWKSFile == "" {
+		return nil, nil
+	}
+
+	v := &JWTVerifier{secret: []byte(cfg.HMACSecret)}
+
+	var methods []string
+	if cfg.HMACSecret != "" {
+		methods = append(methods, hmacMethods...)
+	}
+
+	if cfg.JWKSFile != "" {
+		keys, err := readJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+
+		v.keys = keys
+		methods = append(methods, asymmetricMethods...)
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+		jwt.WithTimeFunc(clock.Now),
+	}
+
+	if cfg.Issuer != "" {
+		options = append(options, jwt.WithIssuer(cfg.Issuer))
+	}
+
+	if cfg.Audience != "" {
+		options = append(options, jwt.WithAudience(cfg.Audience))
+	}
+
+	v.parser = jwt.NewParser(options...)
+
+	return v, nil
+}
+
+// Claims are the claims of the bearer tokens
+type Claims struct {
+	jwt.RegisteredClaims
+	// Scope is the space separated list of the scopes of the token (RFC 8693)
+	Scope string `json:"scope,omitempty"`
+}
+
+// Verify checks the token and returns its claims
+func (v *JWTVerifier) Verify(token string) (*Claims, error) {
+	claims := &Claims{}
+
+	if _, err := v.parser.ParseWithClaims(token, claims, v.key); err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: the token has no subject", jwt.ErrTokenInvalidClaims)
+	}
+
+	return claims, nil
+}
+
+// key returns the key that verifies the signature of the token. The parser only accepts the methods of the
+// configured keys, so a token can't be signed with the public keys of the JWKS used as an HMAC secret
+func (v *JWTVerifier) key(token *jwt.Token) (any, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return v.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+
+	// A token without a key ID is verified by the only key of the set
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, nil
+		}
+	}
+
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// jwk is a JSON Web Key (RFC 7517) with the fields of the RSA and EC public keys
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// readJWKS reads the public signing keys of the JSON Web Key Set file
+func readJWKS(file string) (map[string]crypto.PublicKey, error) {
+	contents, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("auth.readJWKS: error reading JWKS file: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+
+	if err = json.Unmarshal(contents, &set); err != nil {
+		return nil, fmt.Errorf("auth.readJWKS: error parsing JWKS file: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("auth.readJWKS: invalid key %q: %w", k.Kid, err)
+		}
+
+		keys[k.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, errors.New("auth.readJWKS: the JWKS has no signing key")
+	}
+
+	return keys, nil
+}
+
+func (k *jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curves := map[string]elliptic.Curve{"P-256": elliptic.P256(), "P-384": elliptic.P384(), "P-521": elliptic.P521()}
+
+		curve, ok := curves[k.Crv]
+		if !ok {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(bytes) == 0 {
+		return nil, fmt.Errorf("invalid base64url value %q", value)
+	}
+
+	return new(big.Int).SetBytes(bytes), nil
+}
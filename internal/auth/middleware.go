@@ -0,0 +1,44 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+// NewMiddleware returns a middleware that rejects the unauthenticated requests with a 401
+// and adds the identity of the caller to the context of the others
+func NewMiddleware(authenticator *Authenticator, jsonWriter *response.JSONWriter) func(h http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, err := authenticator.Authenticate(r)
+			if err != nil {
+				unauthorized(w, r, jsonWriter, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), identity)))
+		})
+	}
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, jsonWriter *response.JSONWriter, err error) {
+	w.Header().Set("WWW-Authenticate", "Bearer")
+
+	switch {
+	case errors.Is(err, ErrMissingCredentials):
+		jsonWriter.Unauthorized(w, response.NewError(response.MissingCredentials, ErrMissingCredentials.Error(),
+			"Send an API key in the X-API-Key header or a token in the Authorization header with the Bearer scheme", nil))
+	case errors.Is(err, ErrExpiredCredentials):
+		jsonWriter.Unauthorized(w, response.NewError(response.ExpiredCredentials, ErrExpiredCredentials.Error(),
+			"Request a new token or API key", nil))
+	case errors.Is(err, ErrInvalidCredentials):
+		logging.FromContext(r.Context()).Info("authMiddleware: invalid credentials", "error", err)
+		jsonWriter.Unauthorized(w, response.NewError(response.InvalidCredentials, ErrInvalidCredentials.Error(), "", nil))
+	default:
+		logging.FromContext(r.Context()).Error("authMiddleware: failed to authenticate request", "error", err)
+		jsonWriter.DefaultError(w)
+	}
+}
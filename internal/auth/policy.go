@@ -0,0 +1,267 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/jackc/pgx/v4"
+)
+
+// Scope is an operation the caller is allowed to do
+type Scope string
+
+const (
+	ScopeAccountsRead      Scope = "accounts:read"
+	ScopeAccountsWrite     Scope = "accounts:write"
+	ScopeTransactionsRead  Scope = "transactions:read"
+	ScopeTransactionsWrite Scope = "transactions:write"
+	ScopeUsersRead         Scope = "users:read"
+	ScopeUsersWrite        Scope = "users:write"
+	// ScopeAdmin allows every operation on every resource, and the operations of the back office
+	ScopeAdmin Scope = "admin"
+)
+
+// Scopes are all the scopes that can be given to the credentials
+var Scopes = []Scope{
+	ScopeAccountsRead, ScopeAccountsWrite,
+	ScopeTransactionsRead, ScopeTransactionsWrite,
+	ScopeUsersRead, ScopeUsersWrite,
+	ScopeAdmin,
+}
+
+// userScopes are the scopes of the users whose credentials have no scopes, on their own resources
+var userScopes = []Scope{
+	ScopeAccountsRead, ScopeAccountsWrite,
+	ScopeTransactionsRead, ScopeTransactionsWrite,
+	ScopeUsersRead, ScopeUsersWrite,
+}
+
+var ErrForbidden = errors.New("FORBIDDEN")
+
+// toScopes converts the scopes of the credentials, the unknown scopes are kept and never match
+func toScopes(values []string) []Scope {
+	var scopes []Scope
+	for _, value := range values {
+		scopes = append(scopes, Scope(value))
+	}
+
+	return scopes
+}
+
+// ParseScopes parses the comma separated scopes given to new credentials
+func ParseScopes(value string) ([]string, error) {
+	// The scopes are never nil, pgx sends a nil slice as NULL
+	scopes := []string{}
+	for _, scope := range strings.Split(value, ",") {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+
+		if !slices.Contains(Scopes, Scope(scope)) {
+			return nil, fmt.Errorf("unknown scope %q, the scopes are: %v", scope, Scopes)
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	return scopes, nil
+}
+
+// Authorize returns ErrForbidden when the caller can't use the scope on a resource of the owner.
+// The owner is the user the resource belongs to, it is empty for the resources of no user, e.g. the list of all the
+// users. The users only access their own resources, the partners access the resources of every user with the scope
+func Authorize(identity *Identity, scope Scope, owner string) error {
+	if identity == nil {
+		return fmt.Errorf("%w: the request is not authenticated", ErrForbidden)
+	}
+
+	if !identity.HasScope(scope) {
+		return fmt.Errorf("%w: %s is missing the %s scope", ErrForbidden, identity.Subject, scope)
+	}
+
+	if identity.UserID != "" && identity.UserID != owner && !identity.HasScope(ScopeAdmin) {
+		return fmt.Errorf("%w: %s is not the owner of the resource", ErrForbidden, identity.Subject)
+	}
+
+	return nil
+}
+
+// Policy authorizes the callers of the handlers. It finds the owners of the resources and responds with a 403 when
+// the caller isn't authorized
+type Policy struct {
+	querier models.Querier
+	writer  *response.JSONWriter
+}
+
+func NewPolicy(querier models.Querier, writer *response.JSONWriter) *Policy {
+	return &Policy{querier: querier, writer: writer}
+}
+
+// ownerFn finds the user that owns a resource
+type ownerFn func(ctx context.Context, id string) (string, error)
+
+// Authorize checks that the caller can use the scope on the resources of no user
+func (p *Policy) Authorize(w http.ResponseWriter, r *http.Request, scope Scope) bool {
+	return p.authorize(w, r, scope, "")
+}
+
+// AuthorizeUser checks that the caller can use the scope on the user and its resources
+func (p *Policy) AuthorizeUser(w http.ResponseWriter, r *http.Request, scope Scope, userID string) bool {
+	return p.authorize(w, r, scope, userID)
+}
+
+// AuthorizeAccount checks that the caller can use the scope on the account
+func (p *Policy) AuthorizeAccount(w http.ResponseWriter, r *http.Request, scope Scope, accountID string) bool {
+	return p.authorizeOwned(w, r, scope, "account_id", accountID, p.querier.GetAccountOwner)
+}
+
+// AuthorizeTransaction checks that the caller can use the scope on the transaction
+func (p *Policy) AuthorizeTransaction(w http.ResponseWriter, r *http.Request, scope Scope, transactionID string) bool {
+	return p.authorizeOwned(w, r, scope, "transaction_id", transactionID, p.querier.GetTransactionOwner)
+}
+
+// AuthorizeDispute checks that the caller can use the scope on the dispute
+func (p *Policy) AuthorizeDispute(w http.ResponseWriter, r *http.Request, scope Scope, disputeID string) bool {
+	return p.authorizeOwned(w, r, scope, "dispute_id", disputeID, p.querier.GetDisputeOwner)
+}
+
+// authorizeOwned finds the owner of the resource before authorizing the caller. The ID of the resource is logged
+// under idKey, so that the IDs of the redacted attributes are masked, e.g. account_id
+func (p *Policy) authorizeOwned(w http.ResponseWriter, r *http.Request, scope Scope, idKey, id string, owner ownerFn) bool {
+	return p.respond(w, r, scope, p.checkOwned(r.Context(), scope, id, owner), idKey, id)
+}
+
+func (p *Policy) authorize(w http.ResponseWriter, r *http.Request, scope Scope, owner string) bool {
+	identity, _ := FromContext(r.Context())
+
+	return p.respond(w, r, scope, Authorize(identity, scope, owner))
+}
+
+// respond responds with a 403 when the caller isn't authorized and with a 500 when the owner of the resource can't
+// be found. It tells whether the caller is authorized, the attributes of the resource are logged with the error
+func (p *Policy) respond(w http.ResponseWriter, r *http.Request, scope Scope, err error, attrs ...any) bool {
+	switch {
+	case err == nil:
+		return true
+
+	case errors.Is(err, ErrForbidden):
+		logging.FromContext(r.Context()).Info("authorize: forbidden", append([]any{"scope", scope, "error", err}, attrs...)...)
+		p.writer.Forbidden(w, r, response.NewError(response.Forbidden, ErrForbidden.Error(),
+			ForbiddenFix(scope), nil))
+
+	default:
+		logging.FromContext(r.Context()).Error("authorizeOwned: failed to fetch the owner of the resource", append([]any{"error", err}, attrs...)...)
+		p.writer.Internal(w, &response.APIError{
+			Code:    response.DefaultErrorCode,
+			Message: "Failed to authorize the request.",
+		})
+	}
+
+	return false
+}
+
+// ForbiddenFix tells the callers without the scope how to fix the error
+func ForbiddenFix(scope Scope) string {
+	return fmt.Sprintf("The credentials must have the %s scope and belong to the owner of the resource", scope)
+}
+
+// Check returns ErrForbidden when the caller of the context can't use the scope on the resources of no user
+func (p *Policy) Check(ctx context.Context, scope Scope) error {
+	identity, _ := FromContext(ctx)
+
+	return Authorize(identity, scope, "")
+}
+
+// CheckUser returns ErrForbidden when the caller of the context can't use the scope on the user and its resources
+func (p *Policy) CheckUser(ctx context.Context, scope Scope, userID string) error {
+	identity, _ := FromContext(ctx)
+
+	return Authorize(identity, scope, userID)
+}
+
+// CheckAccount returns ErrForbidden when the caller of the context can't use the scope on the account
+func (p *Policy) CheckAccount(ctx context.Context, scope Scope, accountID string) error {
+	return p.checkOwned(ctx, scope, accountID, p.querier.GetAccountOwner)
+}
+
+// CheckTransaction returns ErrForbidden when the caller of the context can't use the scope on the transaction
+func (p *Policy) CheckTransaction(ctx context.Context, scope Scope, transactionID string) error {
+	return p.checkOwned(ctx, scope, transactionID, p.querier.GetTransactionOwner)
+}
+
+// checkOwned finds the owner of the resource before authorizing the caller. The resources that don't exist are
+// authorized, so that the handler responds with its not found error
+func (p *Policy) checkOwned(ctx context.Context, scope Scope, id string, owner ownerFn) error {
+	identity, _ := FromContext(ctx)
+
+	// The owner doesn't matter to the partners and the admins, they may use the scope on every resource
+	if identity == nil || identity.UserID == "" || identity.HasScope(ScopeAdmin) {
+		return Authorize(identity, scope, "")
+	}
+
+	userID, err := owner(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Authorize(identity, scope, identity.UserID)
+	}
+
+	if err != nil {
+		// The ID isn't part of the error, the callers log it in an attribute that is redacted
+		return fmt.Errorf("auth.checkOwned: error fetching the owner of the resource: %w", err)
+	}
+
+	return Authorize(identity, scope, userID)
+}
+
+// Require wraps the handler of a route on the resources of no user
+func (p *Policy) Require(scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if p.Authorize(w, r, scope) {
+			next(w, r)
+		}
+	}
+}
+
+// User wraps the handler of a route with the {userID} path variable
+func (p *Policy) User(scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if p.AuthorizeUser(w, r, scope, mux.Vars(r)["userID"]) {
+			next(w, r)
+		}
+	}
+}
+
+// Account wraps the handler of a route with the {accountID} path variable
+func (p *Policy) Account(scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if p.AuthorizeAccount(w, r, scope, mux.Vars(r)["accountID"]) {
+			next(w, r)
+		}
+	}
+}
+
+// Transaction wraps the handler of a route with the {transactionID} path variable
+func (p *Policy) Transaction(scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if p.AuthorizeTransaction(w, r, scope, mux.Vars(r)["transactionID"]) {
+			next(w, r)
+		}
+	}
+}
+
+// Dispute wraps the handler of a route with the {disputeID} path variable
+func (p *Policy) Dispute(scope Scope, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if p.AuthorizeDispute(w, r, scope, mux.Vars(r)["disputeID"]) {
+			next(w, r)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dummyAccountID   = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+	dummyOtherUserID = "88e0e837-e7f2-47b1-a08c-3af267c03088"
+)
+
+func TestAuthorize(t *testing.T) {
+	user := &Identity{Subject: dummyUserID, Method: MethodJWT, UserID: dummyUserID}
+	readOnlyUser := &Identity{Subject: dummyKeyID, Method: MethodAPIKey, UserID: dummyUserID, Scopes: []Scope{ScopeAccountsRead}}
+	partner := &Identity{Subject: dummyKeyID, Method: MethodAPIKey, Scopes: []Scope{ScopeTransactionsWrite, ScopeAccountsRead}}
+	admin := &Identity{Subject: dummyKeyID, Method: MethodAPIKey, Scopes: []Scope{ScopeAdmin}}
+
+	tests := []struct {
+		name     string
+		identity *Identity
+		scope    Scope
+		owner    string
+		allowed  bool
+	}{
+		{"a user on their own account", user, ScopeTransactionsWrite, dummyUserID, true},
+		{"a user on the account of another user", user, ScopeAccountsRead, dummyOtherUserID, false},
+		{"a user on the resources of no user", user, ScopeUsersRead, "", false},
+		{"a user without the admin scope", user, ScopeAdmin, dummyUserID, false},
+		{"a user key on its own account with the scope", readOnlyUser, ScopeAccountsRead, dummyUserID, true},
+		{"a user key on its own account without the scope", readOnlyUser, ScopeTransactionsWrite, dummyUserID, false},
+		{"a partner on the account of any user", partner, ScopeTransactionsWrite, dummyOtherUserID, true},
+		{"a partner on the resources of no user", partner, ScopeAccountsRead, "", true},
+		{"a partner without the scope", partner, ScopeUsersWrite, dummyUserID, false},
+		{"a partner without scopes", &Identity{Subject: dummyKeyID, Method: MethodAPIKey}, ScopeAccountsRead, "", false},
+		{"an admin on anything", admin, ScopeUsersWrite, dummyOtherUserID, true},
+		{"an unauthenticated request", nil, ScopeAccountsRead, dummyUserID, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(tt.identity, tt.scope, tt.owner)
+			if tt.allowed {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.ErrorIs(t, err, ErrForbidden)
+		})
+	}
+}
+
+func TestPolicy_Account(t *testing.T) {
+	user := &Identity{Subject: dummyUserID, Method: MethodJWT, UserID: dummyUserID}
+
+	tests := []struct {
+		name     string
+		identity *Identity
+		// owner is the owner returned by the DB, the owner isn't fetched when it is empty
+		owner    string
+		ownerErr error
+		status   int
+	}{
+		{name: "allows the owner", identity: user, owner: dummyUserID, status: http.StatusNoContent},
+		{name: "forbids another user", identity: user, owner: dummyOtherUserID, status: http.StatusForbidden},
+		{name: "lets the handler respond when the account doesn't exist", identity: user, ownerErr: pgx.ErrNoRows, status: http.StatusNoContent},
+		{name: "fails when the owner can't be fetched", identity: user, ownerErr: errors.New("connection reset"), status: http.StatusInternalServerError},
+		{
+			name:     "allows a partner with the scope without fetching the owner",
+			identity: &Identity{Subject: dummyKeyID, Method: MethodAPIKey, Scopes: []Scope{ScopeAccountsRead}},
+			status:   http.StatusNoContent,
+		},
+		{
+			name:     "forbids a partner without the scope",
+			identity: &Identity{Subject: dummyKeyID, Method: MethodAPIKey, Scopes: []Scope{ScopeTransactionsWrite}},
+			status:   http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			querier := mock.NewMockQuerier(ctrl)
+
+			if tt.owner != "" || tt.ownerErr != nil {
+				querier.EXPECT().GetAccountOwner(gomock.Any(), dummyAccountID).Return(tt.owner, tt.ownerErr)
+			}
+
+			next := func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNoContent)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID, nil)
+			req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+			req = req.WithContext(NewContext(req.Context(), tt.identity))
+			rr := httptest.NewRecorder()
+
+			NewPolicy(querier, response.NewJSONWriter()).Account(ScopeAccountsRead, next)(rr, req)
+
+			assert.Equal(t, tt.status, rr.Code)
+
+			if tt.status == http.StatusForbidden {
+				res := &struct {
+					Error *response.APIError `json:"error"`
+				}{}
+				assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+				assert.Equal(t, response.Forbidden, res.Error.Code)
+			}
+		})
+	}
+}
+
+func TestPolicy_Account_RedactsTheLoggedAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	querier := mock.NewMockQuerier(ctrl)
+	querier.EXPECT().GetAccountOwner(gomock.Any(), dummyAccountID).Return("", errors.New("connection reset"))
+
+	next := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	logs := &bytes.Buffer{}
+	ctx := logging.NewContext(context.Background(), logging.New(logs, slog.LevelInfo))
+	ctx = NewContext(ctx, &Identity{Subject: dummyUserID, Method: MethodJWT, UserID: dummyUserID})
+
+	req := httptest.NewRequest(http.MethodGet, "/accounts/"+dummyAccountID, nil).WithContext(ctx)
+	req = mux.SetURLVars(req, map[string]string{"accountID": dummyAccountID})
+	rr := httptest.NewRecorder()
+
+	NewPolicy(querier, response.NewJSONWriter()).Account(ScopeAccountsRead, next)(rr, req)
+
+	// The account is only logged in its attribute, which is masked
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.NotContains(t, logs.String(), dummyAccountID)
+	assert.Contains(t, logs.String(), `"account_id":"********************************d611"`)
+}
+
+func TestParseScopes(t *testing.T) {
+	scopes, err := ParseScopes(" accounts:read, transactions:write,")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"accounts:read", "transactions:write"}, scopes)
+
+	scopes, err = ParseScopes("")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{}, scopes)
+
+	_, err = ParseScopes("accounts:delete")
+	assert.ErrorContains(t, err, "accounts:delete")
+}
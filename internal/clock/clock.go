@@ -0,0 +1,22 @@
+package clock
+
+import "time"
+
+// Clock tells the current time. It is injected wherever the current time is needed, so that tests can control time.
+type Clock interface {
+	Now() time.Time
+}
+
+// System is the Clock backed by the system time
+type System struct{}
+
+func (System) Now() time.Time {
+	return time.Now()
+}
+
+// Fixed is a Clock that always returns the same time, it is meant for tests
+type Fixed time.Time
+
+func (f Fixed) Now() time.Time {
+	return time.Time(f)
+}
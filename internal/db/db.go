@@ -7,8 +7,11 @@ import (
 	"log"
 	"net"
 	"net/url"
+	"strconv"
 	"sync"
+	"time"
 
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -28,6 +31,20 @@ type Config struct {
 	Migrate  bool
 	AppName  string
 	SeedDB   bool
+	// MaxConns and MinConns are the size bounds of the pool
+	MaxConns int32
+	MinConns int32
+	// MaxConnLifetime and MaxConnIdleTime are how long a connection is used and kept idle before it is closed
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
+	// HealthCheckPeriod is how often the pool checks its idle connections
+	HealthCheckPeriod time.Duration
+	// StatementTimeout aborts the statements of the pool that run longer, the migrations aren't bounded.
+	// The statements aren't bounded when it is 0
+	StatementTimeout time.Duration
+	// QueryTracer gets every query run on the pool once it is done, e.g. to trace it. The queries aren't reported
+	// when it is nil
+	QueryTracer pgx.Logger
 }
 
 // Link sqlc with go generate, now we need to just run go generate to generate models and functions for DB
@@ -123,9 +140,34 @@ func getPgxConfig(cfg *Config) (*pgxpool.Config, error) {
 		return nil, fmt.Errorf("getPgxConfig: %w", err)
 	}
 
-	// Connection pool configuration
-	connConfig.MaxConns = 5
-	connConfig.MinConns = 2
+	// Connection pool configuration, the defaults of pgx apply to the settings that aren't set
+	if cfg.MaxConns > 0 {
+		connConfig.MaxConns = cfg.MaxConns
+	}
+	connConfig.MinConns = cfg.MinConns
+
+	if cfg.MaxConnLifetime > 0 {
+		connConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	}
+
+	if cfg.MaxConnIdleTime > 0 {
+		connConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	}
+
+	if cfg.HealthCheckPeriod > 0 {
+		connConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+	}
+
+	// The timeout is a parameter of the sessions, Postgres aborts the statements
+	if cfg.StatementTimeout > 0 {
+		connConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+
+	// pgx reports the queries to its logger, at the info level
+	if cfg.QueryTracer != nil {
+		connConfig.ConnConfig.Logger = cfg.QueryTracer
+		connConfig.ConnConfig.LogLevel = pgx.LogLevelInfo
+	}
 
 	return connConfig, nil
 }
@@ -152,7 +194,7 @@ func (cfg *Config) ConnString() string {
 
 	// If the app name is set, add it to the connection URL
 	if cfg.AppName != "" {
-		dbURL.RawQuery += "&application_name=" + cfg.AppName
+		dbURL.RawQuery += "&application_name=" + url.QueryEscape(cfg.AppName)
 	}
 
 	return dbURL.String()
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPgxConfig(t *testing.T) {
+	t.Run("the pool is configured", func(t *testing.T) {
+		cfg := &Config{
+			Host:              "localhost",
+			Port:              "5432",
+			User:              "pismo",
+			Password:          "db_password",
+			Name:              "pismo",
+			ForceTLS:          true,
+			AppName:           "pismo api",
+			MaxConns:          20,
+			MinConns:          4,
+			MaxConnLifetime:   time.Hour,
+			MaxConnIdleTime:   10 * time.Minute,
+			HealthCheckPeriod: 30 * time.Second,
+			StatementTimeout:  15 * time.Second,
+		}
+
+		pgxConfig, err := getPgxConfig(cfg)
+		assert.Nil(t, err)
+
+		assert.Equal(t, int32(20), pgxConfig.MaxConns)
+		assert.Equal(t, int32(4), pgxConfig.MinConns)
+		assert.Equal(t, time.Hour, pgxConfig.MaxConnLifetime)
+		assert.Equal(t, 10*time.Minute, pgxConfig.MaxConnIdleTime)
+		assert.Equal(t, 30*time.Second, pgxConfig.HealthCheckPeriod)
+		assert.Equal(t, "15000", pgxConfig.ConnConfig.RuntimeParams["statement_timeout"])
+		assert.Equal(t, "pismo api", pgxConfig.ConnConfig.RuntimeParams["application_name"])
+		assert.NotNil(t, pgxConfig.ConnConfig.TLSConfig)
+		assert.Contains(t, cfg.ConnString(), "sslmode=require")
+	})
+
+	t.Run("the defaults of pgx apply to the settings that aren't set", func(t *testing.T) {
+		cfg := &Config{Host: "localhost", Port: "5432", User: "pismo", Password: "db_password", Name: "pismo"}
+
+		pgxConfig, err := getPgxConfig(cfg)
+		assert.Nil(t, err)
+
+		assert.Greater(t, pgxConfig.MaxConns, int32(0))
+		assert.Equal(t, int32(0), pgxConfig.MinConns)
+		assert.Greater(t, pgxConfig.HealthCheckPeriod, time.Duration(0))
+		assert.NotContains(t, pgxConfig.ConnConfig.RuntimeParams, "statement_timeout")
+		assert.Contains(t, cfg.ConnString(), "sslmode=prefer")
+	})
+}
+
+func TestCheckMigrationVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		version int64
+		dirty   bool
+		ready   bool
+	}{
+		{"at the latest migration", 20, false, true},
+		{"ahead of the binary", 21, false, true},
+		{"behind the binary", 19, false, false},
+		{"dirty", 20, true, false},
+		{"dirty ahead of the binary", 21, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkMigrationVersion(tt.version, tt.dirty, 20)
+			assert.Equal(t, tt.ready, err == nil)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/imjenal/transaction-service/internal/health"
+)
+
+// PingChecker returns the check that the pool can reach the database
+func (d *DB) PingChecker() health.Checker {
+	return health.NewChecker("database", d.Conn.Ping)
+}
+
+// MigrationChecker returns the check that the database is migrated at least to the latest embedded migration, and that
+// the last migration didn't fail half way
+func (d *DB) MigrationChecker() (health.Checker, error) {
+	latest, err := LatestMigrationVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	return health.NewChecker("migrations", func(ctx context.Context) error {
+		var (
+			version int64
+			dirty   bool
+		)
+
+		// The table is the one of golang-migrate, it has the version of the last migration
+		err := d.Conn.QueryRow(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+		if err != nil {
+			return fmt.Errorf("failed to read the migration version: %w", err)
+		}
+
+		return checkMigrationVersion(version, dirty, latest)
+	}), nil
+}
+
+// checkMigrationVersion checks the migration of the database against the latest embedded migration. A database ahead
+// of the binary is ready, during a rolling deploy the instances of the previous release serve the migrated database
+func checkMigrationVersion(version int64, dirty bool, latest uint) error {
+	if dirty {
+		return fmt.Errorf("the migration %d failed and must be fixed", version)
+	}
+
+	if version < int64(latest) {
+		return fmt.Errorf("the database is at migration %d, the latest migration is %d", version, latest)
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 
 	"github.com/golang-migrate/migrate/v4"
@@ -80,3 +81,30 @@ func buildMigrationClient(db *sql.DB) (*migrate.Migrate, error) {
 
 	return m, nil
 }
+
+// LatestMigrationVersion returns the version of the latest embedded migration, the version the database is migrated to
+func LatestMigrationVersion() (uint, error) {
+	source, err := iofs.New(migrationFiles, migrationDirectory)
+	if err != nil {
+		return 0, fmt.Errorf("db.LatestMigrationVersion: failed to read migration source: %w", err)
+	}
+	defer source.Close()
+
+	version, err := source.First()
+	if err != nil {
+		return 0, fmt.Errorf("db.LatestMigrationVersion: failed to read the first migration: %w", err)
+	}
+
+	for {
+		next, err := source.Next(version)
+		if errors.Is(err, fs.ErrNotExist) {
+			return version, nil
+		}
+
+		if err != nil {
+			return 0, fmt.Errorf("db.LatestMigrationVersion: failed to read the migration after %d: %w", version, err)
+		}
+
+		version = next
+	}
+}
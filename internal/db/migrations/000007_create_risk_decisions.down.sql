@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS public.transactions_account_id_event_date_idx;
+
+DROP TABLE IF EXISTS public.risk_decisions;
+
+DROP TYPE IF EXISTS public.risk_outcome;
+
+ALTER TABLE public.transactions
+    DROP COLUMN IF EXISTS merchant_id,
+    DROP COLUMN IF EXISTS merchant_country;
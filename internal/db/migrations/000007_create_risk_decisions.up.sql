@@ -0,0 +1,26 @@
+ALTER TABLE public.transactions
+    ADD COLUMN merchant_id      VARCHAR(255),
+    ADD COLUMN merchant_country CHAR(2);
+
+CREATE TYPE public.risk_outcome AS ENUM ('APPROVED', 'DECLINED');
+
+CREATE TABLE IF NOT EXISTS public.risk_decisions
+(
+    uuid              UUID PRIMARY KEY         NOT NULL DEFAULT gen_random_uuid(),
+    serial_id         BIGSERIAL UNIQUE         NOT NULL,
+    account_id        UUID                     NOT NULL REFERENCES public.accounts (uuid),
+    operation_type_id BIGINT                   NOT NULL REFERENCES public.operation_types (serial_id),
+    amount            FLOAT                    NOT NULL,
+    merchant_id       VARCHAR(255),
+    merchant_country  CHAR(2),
+    outcome           public.risk_outcome      NOT NULL,
+    reason_code       VARCHAR(64),
+    rule_name         VARCHAR(255),
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS risk_decisions_account_id_created_at_idx
+    ON public.risk_decisions (account_id, created_at);
+
+CREATE INDEX IF NOT EXISTS transactions_account_id_event_date_idx
+    ON public.transactions (account_id, event_date);
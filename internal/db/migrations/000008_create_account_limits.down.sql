@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS set_updated_at_on_account_limit_usage_update
+    ON public.account_limit_usage;
+
+DROP TABLE IF EXISTS public.account_limit_usage;
+
+DROP TRIGGER IF EXISTS set_updated_at_on_account_limits_update
+    ON public.account_limits;
+
+DROP TABLE IF EXISTS public.account_limits;
+
+DROP TYPE IF EXISTS public.limit_period;
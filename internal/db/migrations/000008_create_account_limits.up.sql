@@ -0,0 +1,39 @@
+CREATE TYPE public.limit_period AS ENUM ('DAILY', 'WEEKLY', 'MONTHLY');
+
+CREATE TABLE IF NOT EXISTS public.account_limits
+(
+    uuid              UUID PRIMARY KEY         NOT NULL DEFAULT gen_random_uuid(),
+    serial_id         BIGSERIAL UNIQUE         NOT NULL,
+    account_id        UUID                     NOT NULL REFERENCES public.accounts (uuid),
+    operation_type_id BIGINT                   NOT NULL REFERENCES public.operation_types (serial_id),
+    period            public.limit_period      NOT NULL,
+    max_amount        FLOAT                    NOT NULL CHECK (max_amount > 0),
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    UNIQUE (account_id, operation_type_id, period)
+);
+
+CREATE TRIGGER set_updated_at_on_account_limits_update
+    BEFORE UPDATE
+    ON public.account_limits
+    FOR EACH ROW
+EXECUTE PROCEDURE set_updated_at();
+
+-- Running counters of the amount used per limit and period.
+-- A new row is created for every period, so the counters never need to be reset.
+CREATE TABLE IF NOT EXISTS public.account_limit_usage
+(
+    account_id        UUID                     NOT NULL REFERENCES public.accounts (uuid),
+    operation_type_id BIGINT                   NOT NULL REFERENCES public.operation_types (serial_id),
+    period            public.limit_period      NOT NULL,
+    period_start      TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_amount       FLOAT                    NOT NULL DEFAULT 0.0,
+    updated_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (account_id, operation_type_id, period, period_start)
+);
+
+CREATE TRIGGER set_updated_at_on_account_limit_usage_update
+    BEFORE UPDATE
+    ON public.account_limit_usage
+    FOR EACH ROW
+EXECUTE PROCEDURE set_updated_at();
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS public.account_status_history;
+
+ALTER TABLE public.accounts
+    DROP COLUMN IF EXISTS status;
+
+DROP TYPE IF EXISTS public.account_status;
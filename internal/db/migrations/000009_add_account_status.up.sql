@@ -0,0 +1,20 @@
+CREATE TYPE public.account_status AS ENUM ('ACTIVE', 'BLOCKED', 'SUSPENDED', 'CLOSED');
+
+ALTER TABLE public.accounts
+    ADD COLUMN status public.account_status NOT NULL DEFAULT 'ACTIVE';
+
+-- Every status transition of an account is recorded here
+CREATE TABLE IF NOT EXISTS public.account_status_history
+(
+    uuid        UUID PRIMARY KEY         NOT NULL DEFAULT gen_random_uuid(),
+    serial_id   BIGSERIAL UNIQUE         NOT NULL,
+    account_id  UUID                     NOT NULL REFERENCES public.accounts (uuid),
+    from_status public.account_status    NOT NULL,
+    to_status   public.account_status    NOT NULL,
+    reason_code VARCHAR(64)              NOT NULL,
+    note        TEXT,
+    created_at  TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS account_status_history_account_id_idx
+    ON public.account_status_history (account_id, created_at);
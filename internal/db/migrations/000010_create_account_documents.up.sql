@@ -0,0 +1,21 @@
+-- The document numbers of the accounts, it enforces the uniqueness of the documents.
+-- scope_key is empty when the document must be unique across all the users and it's the user id when the document
+-- only has to be unique for the user, see DOCUMENT_UNIQUENESS_SCOPE. Changing the scope only applies to new accounts.
+CREATE TABLE IF NOT EXISTS public.account_documents
+(
+    account_id      UUID PRIMARY KEY         NOT NULL REFERENCES public.accounts (uuid),
+    document_number VARCHAR(255)             NOT NULL,
+    scope_key       VARCHAR(36)              NOT NULL,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS account_documents_document_number_scope_key_idx
+    ON public.account_documents (document_number, scope_key);
+
+-- Existing accounts are only unique per user, so that the documents already shared by several users don't fail the migration.
+-- Duplicates of the same user are kept as they are and only the oldest account gets the document.
+INSERT INTO public.account_documents (account_id, document_number, scope_key)
+SELECT uuid, regexp_replace(upper(document_number), '[[:punct:][:space:]]', '', 'g'), user_id::TEXT
+FROM public.accounts
+ORDER BY serial_id
+ON CONFLICT DO NOTHING;
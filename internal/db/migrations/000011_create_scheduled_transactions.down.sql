@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS public.scheduled_transaction_runs;
+
+DROP TABLE IF EXISTS public.scheduled_transactions;
+
+DROP TYPE IF EXISTS public.schedule_run_status;
+
+DROP TYPE IF EXISTS public.schedule_status;
+
+DROP TYPE IF EXISTS public.recurrence_type;
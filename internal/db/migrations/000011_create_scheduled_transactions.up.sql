@@ -0,0 +1,50 @@
+CREATE TYPE public.recurrence_type AS ENUM ('CRON', 'RRULE');
+
+CREATE TYPE public.schedule_status AS ENUM ('ACTIVE', 'PAUSED', 'CANCELLED', 'COMPLETED');
+
+CREATE TYPE public.schedule_run_status AS ENUM ('POSTED', 'FAILED');
+
+-- Transactions that are posted on a schedule, e.g. subscriptions and autopay
+CREATE TABLE IF NOT EXISTS public.scheduled_transactions
+(
+    uuid              UUID PRIMARY KEY         NOT NULL DEFAULT gen_random_uuid(),
+    serial_id         BIGSERIAL UNIQUE         NOT NULL,
+    account_id        UUID                     NOT NULL REFERENCES public.accounts (uuid),
+    operation_type_id BIGINT                   NOT NULL REFERENCES public.operation_types (serial_id),
+    amount            FLOAT                    NOT NULL CHECK (amount > 0),
+    recurrence_type   public.recurrence_type   NOT NULL,
+    recurrence        TEXT                     NOT NULL,
+    starts_at         TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at           TIMESTAMP WITH TIME ZONE,
+    -- next_run_at is the next occurrence to post, it is null when there are no more occurrences
+    next_run_at       TIMESTAMP WITH TIME ZONE,
+    status            public.schedule_status   NOT NULL DEFAULT 'ACTIVE',
+    created_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at        TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER set_updated_at_on_scheduled_transactions_update
+    BEFORE UPDATE
+    ON public.scheduled_transactions
+    FOR EACH ROW
+EXECUTE PROCEDURE set_updated_at();
+
+CREATE INDEX IF NOT EXISTS scheduled_transactions_account_id_idx
+    ON public.scheduled_transactions (account_id);
+
+CREATE INDEX IF NOT EXISTS scheduled_transactions_due_idx
+    ON public.scheduled_transactions (next_run_at) WHERE status = 'ACTIVE';
+
+-- Every occurrence of a schedule is posted once, the unique index makes the posting idempotent
+CREATE TABLE IF NOT EXISTS public.scheduled_transaction_runs
+(
+    uuid           UUID PRIMARY KEY               NOT NULL DEFAULT gen_random_uuid(),
+    serial_id      BIGSERIAL UNIQUE               NOT NULL,
+    schedule_id    UUID                           NOT NULL REFERENCES public.scheduled_transactions (uuid),
+    scheduled_for  TIMESTAMP WITH TIME ZONE       NOT NULL,
+    status         public.schedule_run_status     NOT NULL,
+    transaction_id UUID REFERENCES public.transactions (uuid),
+    error          TEXT,
+    created_at     TIMESTAMP WITH TIME ZONE       NOT NULL DEFAULT NOW(),
+    UNIQUE (schedule_id, scheduled_for)
+);
@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS public.dispute_events;
+
+DROP TABLE IF EXISTS public.disputes;
+
+DROP TYPE IF EXISTS public.dispute_event_type;
+
+DROP TYPE IF EXISTS public.dispute_status;
+
+DROP INDEX IF EXISTS public.transactions_reversal_of_idx;
+
+ALTER TABLE public.transactions
+    DROP COLUMN IF EXISTS reversal_of;
@@ -0,0 +1,65 @@
+-- A reversal cancels all or part of another transaction, reversal_of is the transaction it reverses
+ALTER TABLE public.transactions
+    ADD COLUMN reversal_of UUID REFERENCES public.transactions (uuid);
+
+CREATE INDEX IF NOT EXISTS transactions_reversal_of_idx
+    ON public.transactions (reversal_of) WHERE reversal_of IS NOT NULL;
+
+CREATE TYPE public.dispute_status AS ENUM ('OPENED', 'UNDER_REVIEW', 'WON', 'LOST');
+
+CREATE TYPE public.dispute_event_type AS ENUM (
+    'OPENED',
+    'PROVISIONAL_CREDIT_POSTED',
+    'UNDER_REVIEW',
+    'CREDIT_POSTED',
+    'PROVISIONAL_CREDIT_FINALIZED',
+    'PROVISIONAL_CREDIT_REVERSED',
+    'WON',
+    'LOST'
+    );
+
+-- Disputes raised by the cardholder against a debit of their account
+CREATE TABLE IF NOT EXISTS public.disputes
+(
+    uuid                             UUID PRIMARY KEY         NOT NULL DEFAULT gen_random_uuid(),
+    serial_id                        BIGSERIAL UNIQUE         NOT NULL,
+    transaction_id                   UUID                     NOT NULL REFERENCES public.transactions (uuid),
+    account_id                       UUID                     NOT NULL REFERENCES public.accounts (uuid),
+    amount                           FLOAT                    NOT NULL CHECK (amount > 0),
+    reason_code                      TEXT                     NOT NULL,
+    status                           public.dispute_status    NOT NULL DEFAULT 'OPENED',
+    -- provisional_credit_transaction_id is the credit given to the cardholder while the dispute is open
+    provisional_credit_transaction_id UUID REFERENCES public.transactions (uuid),
+    -- resolution_transaction_id is the final credit of a won dispute or the claw back of a lost one
+    resolution_transaction_id        UUID REFERENCES public.transactions (uuid),
+    resolved_at                      TIMESTAMP WITH TIME ZONE,
+    created_at                       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at                       TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER set_updated_at_on_disputes_update
+    BEFORE UPDATE
+    ON public.disputes
+    FOR EACH ROW
+EXECUTE PROCEDURE set_updated_at();
+
+-- A transaction can only have one dispute in progress at a time
+CREATE UNIQUE INDEX IF NOT EXISTS disputes_transaction_id_in_progress_idx
+    ON public.disputes (transaction_id) WHERE status IN ('OPENED', 'UNDER_REVIEW');
+
+CREATE INDEX IF NOT EXISTS disputes_transaction_id_idx
+    ON public.disputes (transaction_id);
+
+-- The timeline of a dispute, events are never updated
+CREATE TABLE IF NOT EXISTS public.dispute_events
+(
+    serial_id      BIGSERIAL PRIMARY KEY        NOT NULL,
+    dispute_id     UUID                         NOT NULL REFERENCES public.disputes (uuid),
+    type           public.dispute_event_type    NOT NULL,
+    transaction_id UUID REFERENCES public.transactions (uuid),
+    note           TEXT,
+    created_at     TIMESTAMP WITH TIME ZONE     NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS dispute_events_dispute_id_idx
+    ON public.dispute_events (dispute_id);
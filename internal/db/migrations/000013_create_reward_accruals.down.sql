@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS public.reward_accruals;
+
+DROP TYPE IF EXISTS public.reward_kind;
+
+ALTER TABLE public.transactions
+    DROP COLUMN IF EXISTS mcc;
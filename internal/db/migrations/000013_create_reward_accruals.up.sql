@@ -0,0 +1,29 @@
+-- The merchant category code (ISO 18245) of the transaction, it is used by the reward rules
+ALTER TABLE public.transactions
+    ADD COLUMN mcc TEXT;
+
+CREATE TYPE public.reward_kind AS ENUM ('CASHBACK', 'POINTS');
+
+-- The ledger of the rewards of the accounts. Accruals are never updated, a reversed transaction gets a negative
+-- accrual instead. Cashback accruals are paid out periodically, payout_transaction_id is the credit that paid them
+CREATE TABLE IF NOT EXISTS public.reward_accruals
+(
+    uuid                  UUID PRIMARY KEY         NOT NULL DEFAULT gen_random_uuid(),
+    serial_id             BIGSERIAL UNIQUE         NOT NULL,
+    account_id            UUID                     NOT NULL REFERENCES public.accounts (uuid),
+    transaction_id        UUID                     NOT NULL REFERENCES public.transactions (uuid),
+    rule_name             TEXT                     NOT NULL,
+    kind                  public.reward_kind       NOT NULL,
+    amount                FLOAT                    NOT NULL CHECK (amount <> 0),
+    payout_transaction_id UUID REFERENCES public.transactions (uuid),
+    created_at            TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS reward_accruals_account_id_idx
+    ON public.reward_accruals (account_id);
+
+CREATE INDEX IF NOT EXISTS reward_accruals_transaction_id_idx
+    ON public.reward_accruals (transaction_id);
+
+CREATE INDEX IF NOT EXISTS reward_accruals_pending_cashback_idx
+    ON public.reward_accruals (account_id) WHERE kind = 'CASHBACK' AND payout_transaction_id IS NULL;
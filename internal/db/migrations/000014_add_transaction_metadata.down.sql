@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS public.transactions_tags_idx;
+
+DROP INDEX IF EXISTS public.transactions_metadata_idx;
+
+ALTER TABLE public.transactions
+    DROP COLUMN IF EXISTS notes,
+    DROP COLUMN IF EXISTS tags,
+    DROP COLUMN IF EXISTS metadata;
@@ -0,0 +1,13 @@
+-- metadata is set by the integrator when the transaction is created, e.g. their order ID, and is never updated.
+-- tags and notes belong to the user and are the only fields of a transaction that can be updated
+ALTER TABLE public.transactions
+    ADD COLUMN metadata JSONB  NOT NULL DEFAULT '{}'::JSONB,
+    ADD COLUMN tags     TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN notes    TEXT   NOT NULL DEFAULT '';
+
+-- The list of transactions filters on metadata keys and values and on tags
+CREATE INDEX IF NOT EXISTS transactions_metadata_idx
+    ON public.transactions USING GIN (metadata);
+
+CREATE INDEX IF NOT EXISTS transactions_tags_idx
+    ON public.transactions USING GIN (tags);
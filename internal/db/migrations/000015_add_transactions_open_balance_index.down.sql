@@ -0,0 +1 @@
+DROP INDEX IF EXISTS public.transactions_account_id_open_balance_idx;
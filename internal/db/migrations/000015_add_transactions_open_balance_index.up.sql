@@ -0,0 +1,4 @@
+-- The account summary sums the open balances of the account, debts are negative and unused credits positive.
+-- Most transactions are settled, so the partial index is much smaller than the table
+CREATE INDEX IF NOT EXISTS transactions_account_id_open_balance_idx
+    ON public.transactions (account_id, operation_type_id) INCLUDE (balance) WHERE balance <> 0;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS rollup_spend_on_transactions_insert ON public.transactions;
+DROP FUNCTION IF EXISTS public.rollup_spend;
+DROP TABLE IF EXISTS public.daily_spend_rollups;
+DROP FUNCTION IF EXISTS public.mcc_category;
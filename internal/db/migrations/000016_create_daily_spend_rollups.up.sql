@@ -0,0 +1,74 @@
+-- mcc_category maps a merchant category code (ISO 18245) to the spend category of the analytics
+CREATE OR REPLACE FUNCTION public.mcc_category(mcc TEXT) RETURNS TEXT AS
+$$
+SELECT CASE
+           WHEN mcc IS NULL OR mcc !~ '^[0-9]{4}$' THEN 'UNCATEGORIZED'
+           WHEN mcc IN ('5411', '5422', '5441', '5451', '5462', '5499') THEN 'GROCERIES'
+           WHEN mcc IN ('5812', '5813', '5814') THEN 'RESTAURANTS'
+           WHEN mcc IN ('5541', '5542', '5983') THEN 'FUEL'
+           WHEN mcc = '4511' OR mcc::INT BETWEEN 3000 AND 3299 THEN 'AIRLINES'
+           WHEN mcc = '7011' OR mcc::INT BETWEEN 3501 AND 3999 THEN 'LODGING'
+           WHEN mcc::INT BETWEEN 3300 AND 3500 OR mcc::INT BETWEEN 4000 AND 4799 THEN 'TRANSPORTATION'
+           WHEN mcc::INT BETWEEN 4800 AND 4999 THEN 'UTILITIES'
+           WHEN mcc::INT BETWEEN 5000 AND 5999 THEN 'RETAIL'
+           WHEN mcc::INT BETWEEN 6000 AND 6999 THEN 'FINANCIAL'
+           WHEN mcc::INT BETWEEN 8000 AND 8099 THEN 'HEALTH'
+           WHEN mcc::INT BETWEEN 7000 AND 8999 THEN 'SERVICES'
+           WHEN mcc::INT BETWEEN 9000 AND 9999 THEN 'GOVERNMENT'
+           ELSE 'OTHER'
+           END
+$$ LANGUAGE SQL IMMUTABLE;
+
+-- The spend of the accounts rolled up per UTC day, operation type and category. The spend is the debits that are not
+-- reversals. The rows are incremented when the transactions are inserted, the amount of a transaction never changes
+CREATE TABLE IF NOT EXISTS public.daily_spend_rollups
+(
+    account_id        UUID   NOT NULL REFERENCES public.accounts (uuid),
+    day               DATE   NOT NULL,
+    operation_type_id BIGINT NOT NULL REFERENCES public.operation_types (serial_id),
+    category          TEXT   NOT NULL,
+    txn_count         BIGINT NOT NULL,
+    total_amount      FLOAT  NOT NULL,
+    PRIMARY KEY (account_id, day, operation_type_id, category)
+);
+
+-- The portfolio analytics aggregate the rollups of all the accounts by day
+CREATE INDEX IF NOT EXISTS daily_spend_rollups_day_idx
+    ON public.daily_spend_rollups (day);
+
+CREATE OR REPLACE FUNCTION public.rollup_spend()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF NEW.amount < 0 AND NEW.reversal_of IS NULL THEN
+        INSERT INTO public.daily_spend_rollups AS r (account_id, day, operation_type_id, category, txn_count, total_amount)
+        VALUES (NEW.account_id, (NEW.event_date AT TIME ZONE 'UTC')::DATE, NEW.operation_type_id,
+                public.mcc_category(NEW.mcc), 1, -NEW.amount)
+        ON CONFLICT (account_id, day, operation_type_id, category) DO UPDATE
+            SET txn_count    = r.txn_count + 1,
+                total_amount = r.total_amount + EXCLUDED.total_amount;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER rollup_spend_on_transactions_insert
+    AFTER INSERT
+    ON public.transactions
+    FOR EACH ROW
+EXECUTE PROCEDURE public.rollup_spend();
+
+-- Backfill the rollups with the existing transactions. The trigger blocks the inserts until the migration commits,
+-- so no transaction is counted twice or missed
+INSERT INTO public.daily_spend_rollups (account_id, day, operation_type_id, category, txn_count, total_amount)
+SELECT account_id,
+       (event_date AT TIME ZONE 'UTC')::DATE,
+       operation_type_id,
+       public.mcc_category(mcc),
+       COUNT(*),
+       SUM(-amount)
+FROM public.transactions
+WHERE amount < 0
+  AND reversal_of IS NULL
+GROUP BY 1, 2, 3, 4;
@@ -0,0 +1,14 @@
+-- The API keys of the partners and the users. Only the SHA-256 hash of a key is stored, the key itself is shown once
+-- when it is created. A key without a user belongs to a partner
+CREATE TABLE IF NOT EXISTS public.api_keys
+(
+    uuid         UUID PRIMARY KEY         NOT NULL DEFAULT gen_random_uuid(),
+    serial_id    BIGSERIAL UNIQUE         NOT NULL,
+    name         TEXT                     NOT NULL,
+    key_hash     TEXT UNIQUE              NOT NULL,
+    user_id      UUID REFERENCES public.users (uuid),
+    expires_at   TIMESTAMP WITH TIME ZONE,
+    revoked_at   TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,3 @@
+-- The scopes of the API key, e.g. transactions:write. The keys of the users are limited to the resources of the user
+ALTER TABLE public.api_keys
+    ADD COLUMN scopes TEXT[] NOT NULL DEFAULT '{}';
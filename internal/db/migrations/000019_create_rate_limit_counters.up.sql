@@ -0,0 +1,12 @@
+-- The requests counted by the rate limiter in each fixed window, shared by all the instances of the service.
+-- The counters are transient, so the table is not written to the WAL and is emptied after a crash
+CREATE UNLOGGED TABLE IF NOT EXISTS public.rate_limit_counters
+(
+    key          TEXT        NOT NULL,
+    window_start TIMESTAMPTZ NOT NULL,
+    window_end   TIMESTAMPTZ NOT NULL,
+    count        INT         NOT NULL DEFAULT 1,
+    PRIMARY KEY (key, window_start)
+);
+
+CREATE INDEX IF NOT EXISTS rate_limit_counters_window_end_idx ON public.rate_limit_counters (window_end);
@@ -0,0 +1,6 @@
+-- The re-keyed documents can't be told apart from the global ones, they are all keyed by the user of the account
+UPDATE public.account_documents ad
+SET scope_key = a.user_id::TEXT
+FROM public.accounts a
+WHERE a.uuid = ad.account_id
+  AND ad.scope_key = '';
@@ -0,0 +1,12 @@
+-- The backfill of 000010 keyed the documents of the existing accounts by user, while the GLOBAL scope inserts an
+-- empty key, so the unique index never compared the new global documents with them.
+-- The documents held by a single user are keyed like the global ones. The documents already shared by several users
+-- keep their user keys, the accounts service checks the existing documents before creating an account anyway.
+-- 000024 marks them, the unique index isn't authoritative for them.
+UPDATE public.account_documents ad
+SET scope_key = ''
+WHERE ad.scope_key <> ''
+  AND NOT EXISTS (SELECT 1
+                  FROM public.account_documents other
+                  WHERE other.document_number = ad.document_number
+                    AND other.account_id <> ad.account_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS public.transactions_account_id_created_at_idx;
+
+ALTER TABLE public.transactions
+    DROP COLUMN IF EXISTS created_at;
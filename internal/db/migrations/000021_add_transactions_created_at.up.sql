@@ -0,0 +1,15 @@
+-- created_at is the time the transaction was persisted, unlike event_date that the client sets and can backdate.
+-- The creation time of the existing transactions is unknown, their event date is the closest to it
+ALTER TABLE public.transactions
+    ADD COLUMN created_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE public.transactions
+SET created_at = event_date;
+
+ALTER TABLE public.transactions
+    ALTER COLUMN created_at SET DEFAULT NOW(),
+    ALTER COLUMN created_at SET NOT NULL;
+
+-- The velocity risk rules count the transactions of the account created in their window
+CREATE INDEX IF NOT EXISTS transactions_account_id_created_at_idx
+    ON public.transactions (account_id, created_at);
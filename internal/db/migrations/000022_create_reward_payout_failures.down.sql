@@ -0,0 +1 @@
+DROP TABLE IF EXISTS public.reward_payout_failures;
@@ -0,0 +1,9 @@
+-- The last failed cashback payout of the accounts, e.g. a credit rejected by the risk rules. The account waits for the
+-- retry backoff before its payout is tried again, so that the accounts that always fail don't fill the batches.
+-- The row is deleted once the cashback of the account is paid out
+CREATE TABLE IF NOT EXISTS public.reward_payout_failures
+(
+    account_id UUID PRIMARY KEY         NOT NULL REFERENCES public.accounts (uuid),
+    failures   INT                      NOT NULL DEFAULT 1,
+    failed_at  TIMESTAMP WITH TIME ZONE NOT NULL
+);
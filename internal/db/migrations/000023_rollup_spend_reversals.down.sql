@@ -0,0 +1,33 @@
+CREATE OR REPLACE FUNCTION public.rollup_spend()
+    RETURNS TRIGGER AS
+$$
+BEGIN
+    IF NEW.amount < 0 AND NEW.reversal_of IS NULL THEN
+        INSERT INTO public.daily_spend_rollups AS r (account_id, day, operation_type_id, category, txn_count, total_amount)
+        VALUES (NEW.account_id, (NEW.event_date AT TIME ZONE 'UTC')::DATE, NEW.operation_type_id,
+                public.mcc_category(NEW.mcc), 1, -NEW.amount)
+        ON CONFLICT (account_id, day, operation_type_id, category) DO UPDATE
+            SET txn_count    = r.txn_count + 1,
+                total_amount = r.total_amount + EXCLUDED.total_amount;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+LOCK TABLE public.transactions IN SHARE ROW EXCLUSIVE MODE;
+
+DELETE
+FROM public.daily_spend_rollups;
+
+INSERT INTO public.daily_spend_rollups (account_id, day, operation_type_id, category, txn_count, total_amount)
+SELECT account_id,
+       (event_date AT TIME ZONE 'UTC')::DATE,
+       operation_type_id,
+       public.mcc_category(mcc),
+       COUNT(*),
+       SUM(-amount)
+FROM public.transactions
+WHERE amount < 0
+  AND reversal_of IS NULL
+GROUP BY 1, 2, 3, 4;
@@ -0,0 +1,75 @@
+-- The reversals of the debits are subtracted from the rollup of the debit they reverse: its day, operation type and
+-- category, whatever the date of the reversal. A debit is no longer counted once it is fully reversed, a partial
+-- reversal only subtracts its amount. The rollups that are left without any debit are deleted
+CREATE OR REPLACE FUNCTION public.rollup_spend()
+    RETURNS TRIGGER AS
+$$
+DECLARE
+    original public.transactions%ROWTYPE;
+    reversed FLOAT;
+BEGIN
+    IF NEW.amount < 0 AND NEW.reversal_of IS NULL THEN
+        INSERT INTO public.daily_spend_rollups AS r (account_id, day, operation_type_id, category, txn_count, total_amount)
+        VALUES (NEW.account_id, (NEW.event_date AT TIME ZONE 'UTC')::DATE, NEW.operation_type_id,
+                public.mcc_category(NEW.mcc), 1, -NEW.amount)
+        ON CONFLICT (account_id, day, operation_type_id, category) DO UPDATE
+            SET txn_count    = r.txn_count + 1,
+                total_amount = r.total_amount + EXCLUDED.total_amount;
+    END IF;
+
+    IF NEW.reversal_of IS NOT NULL THEN
+        SELECT * INTO original FROM public.transactions WHERE uuid = NEW.reversal_of;
+
+        IF original.amount < 0 AND original.reversal_of IS NULL THEN
+            -- The reversals of the debit so far, including the new one. The amounts are rounded to cents
+            SELECT COALESCE(SUM(amount), 0) INTO reversed FROM public.transactions WHERE reversal_of = original.uuid;
+
+            UPDATE public.daily_spend_rollups
+            SET txn_count    = txn_count - (CASE
+                                                WHEN ROUND(reversed::NUMERIC, 2) >= ROUND(-original.amount::NUMERIC, 2)
+                                                    AND ROUND((reversed - NEW.amount)::NUMERIC, 2) <
+                                                        ROUND(-original.amount::NUMERIC, 2) THEN 1
+                                                ELSE 0 END),
+                total_amount = total_amount - NEW.amount
+            WHERE account_id = original.account_id
+              AND day = (original.event_date AT TIME ZONE 'UTC')::DATE
+              AND operation_type_id = original.operation_type_id
+              AND category = public.mcc_category(original.mcc);
+
+            DELETE
+            FROM public.daily_spend_rollups
+            WHERE account_id = original.account_id
+              AND day = (original.event_date AT TIME ZONE 'UTC')::DATE
+              AND operation_type_id = original.operation_type_id
+              AND category = public.mcc_category(original.mcc)
+              AND txn_count <= 0;
+        END IF;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- Rebuild the rollups with the reversals subtracted. The lock blocks the inserts until the migration commits, so no
+-- transaction is counted twice or missed
+LOCK TABLE public.transactions IN SHARE ROW EXCLUSIVE MODE;
+
+DELETE
+FROM public.daily_spend_rollups;
+
+INSERT INTO public.daily_spend_rollups (account_id, day, operation_type_id, category, txn_count, total_amount)
+SELECT t.account_id,
+       (t.event_date AT TIME ZONE 'UTC')::DATE,
+       t.operation_type_id,
+       public.mcc_category(t.mcc),
+       COUNT(*) FILTER (WHERE ROUND(COALESCE(r.reversed, 0)::NUMERIC, 2) < ROUND(-t.amount::NUMERIC, 2)),
+       SUM(-t.amount - COALESCE(r.reversed, 0))
+FROM public.transactions t
+         LEFT JOIN (SELECT reversal_of, SUM(amount) AS reversed
+                    FROM public.transactions
+                    WHERE reversal_of IS NOT NULL
+                    GROUP BY reversal_of) r ON r.reversal_of = t.uuid
+WHERE t.amount < 0
+  AND t.reversal_of IS NULL
+GROUP BY 1, 2, 3, 4
+HAVING COUNT(*) FILTER (WHERE ROUND(COALESCE(r.reversed, 0)::NUMERIC, 2) < ROUND(-t.amount::NUMERIC, 2)) > 0;
@@ -0,0 +1,2 @@
+ALTER TABLE public.account_documents
+    DROP COLUMN IF EXISTS shared_before_global;
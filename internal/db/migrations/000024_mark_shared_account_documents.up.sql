@@ -0,0 +1,15 @@
+-- The documents that were already shared by the accounts of several users before the GLOBAL scope keep the user keys
+-- of the backfill of 000010, see 000020. The unique index isn't authoritative for them: it never compares them with
+-- the global keys, nor with each other. shared_before_global marks them, the accounts service looks the documents up
+-- across all the keys before it creates an account, so no new account gets one of them under the GLOBAL scope.
+-- They are kept as they are, merging or closing the accounts of several users is up to the operators
+ALTER TABLE public.account_documents
+    ADD COLUMN shared_before_global BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE public.account_documents ad
+SET shared_before_global = TRUE
+WHERE ad.scope_key <> ''
+  AND EXISTS (SELECT 1
+              FROM public.account_documents other
+              WHERE other.document_number = ad.document_number
+                AND other.account_id <> ad.account_id);
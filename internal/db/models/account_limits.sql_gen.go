@@ -0,0 +1,188 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: account_limits.sql
+
+package models
+
+import (
+	"context"
+	"time"
+)
+
+const createAccountLimit = `-- name: CreateAccountLimit :one
+INSERT INTO public.account_limits (account_id, operation_type_id, period, max_amount)
+VALUES ($1, $2, $3, $4)
+RETURNING uuid, serial_id, account_id, operation_type_id, period, max_amount, created_at, updated_at
+`
+
+type CreateAccountLimitParams struct {
+	AccountID       string      `db:"account_id" json:"account_id"`
+	OperationTypeID int64       `db:"operation_type_id" json:"operation_type_id"`
+	Period          LimitPeriod `db:"period" json:"period"`
+	MaxAmount       float64     `db:"max_amount" json:"max_amount"`
+}
+
+func (q *Queries) CreateAccountLimit(ctx context.Context, arg CreateAccountLimitParams) (*AccountLimit, error) {
+	row := q.db.QueryRow(ctx, createAccountLimit,
+		arg.AccountID,
+		arg.OperationTypeID,
+		arg.Period,
+		arg.MaxAmount,
+	)
+	var i AccountLimit
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.AccountID,
+		&i.OperationTypeID,
+		&i.Period,
+		&i.MaxAmount,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const deleteAccountLimits = `-- name: DeleteAccountLimits :exec
+DELETE FROM public.account_limits WHERE account_id = $1
+`
+
+func (q *Queries) DeleteAccountLimits(ctx context.Context, accountID string) error {
+	_, err := q.db.Exec(ctx, deleteAccountLimits, accountID)
+	return err
+}
+
+const getAccountLimitsByOperationType = `-- name: GetAccountLimitsByOperationType :many
+SELECT uuid, serial_id, account_id, operation_type_id, period, max_amount, created_at, updated_at
+FROM public.account_limits
+WHERE account_id = $1 AND operation_type_id = $2
+ORDER BY period
+`
+
+type GetAccountLimitsByOperationTypeParams struct {
+	AccountID       string `db:"account_id" json:"account_id"`
+	OperationTypeID int64  `db:"operation_type_id" json:"operation_type_id"`
+}
+
+func (q *Queries) GetAccountLimitsByOperationType(ctx context.Context, arg GetAccountLimitsByOperationTypeParams) ([]*AccountLimit, error) {
+	rows, err := q.db.Query(ctx, getAccountLimitsByOperationType, arg.AccountID, arg.OperationTypeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*AccountLimit
+	for rows.Next() {
+		var i AccountLimit
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.SerialID,
+			&i.AccountID,
+			&i.OperationTypeID,
+			&i.Period,
+			&i.MaxAmount,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAccountLimitsWithUsage = `-- name: GetAccountLimitsWithUsage :many
+SELECT l.operation_type_id, l.period, l.max_amount, COALESCE(u.used_amount, 0)::FLOAT AS used_amount
+FROM public.account_limits l
+         LEFT JOIN public.account_limit_usage u
+                   ON u.account_id = l.account_id
+                       AND u.operation_type_id = l.operation_type_id
+                       AND u.period = l.period
+                       AND u.period_start = CASE l.period
+                                                WHEN 'DAILY' THEN $1::TIMESTAMPTZ
+                                                WHEN 'WEEKLY' THEN $2::TIMESTAMPTZ
+                                                ELSE $3::TIMESTAMPTZ END
+WHERE l.account_id = $4
+ORDER BY l.operation_type_id, l.period
+`
+
+type GetAccountLimitsWithUsageParams struct {
+	DailyStart   time.Time `db:"daily_start" json:"daily_start"`
+	WeeklyStart  time.Time `db:"weekly_start" json:"weekly_start"`
+	MonthlyStart time.Time `db:"monthly_start" json:"monthly_start"`
+	AccountID    string    `db:"account_id" json:"account_id"`
+}
+
+type GetAccountLimitsWithUsageRow struct {
+	OperationTypeID int64       `db:"operation_type_id" json:"operation_type_id"`
+	Period          LimitPeriod `db:"period" json:"period"`
+	MaxAmount       float64     `db:"max_amount" json:"max_amount"`
+	UsedAmount      float64     `db:"used_amount" json:"used_amount"`
+}
+
+func (q *Queries) GetAccountLimitsWithUsage(ctx context.Context, arg GetAccountLimitsWithUsageParams) ([]*GetAccountLimitsWithUsageRow, error) {
+	rows, err := q.db.Query(ctx, getAccountLimitsWithUsage,
+		arg.DailyStart,
+		arg.WeeklyStart,
+		arg.MonthlyStart,
+		arg.AccountID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetAccountLimitsWithUsageRow
+	for rows.Next() {
+		var i GetAccountLimitsWithUsageRow
+		if err := rows.Scan(
+			&i.OperationTypeID,
+			&i.Period,
+			&i.MaxAmount,
+			&i.UsedAmount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const incrementAccountLimitUsage = `-- name: IncrementAccountLimitUsage :one
+INSERT INTO public.account_limit_usage AS u (account_id, operation_type_id, period, period_start, used_amount)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (account_id, operation_type_id, period, period_start)
+    DO UPDATE SET used_amount = u.used_amount + EXCLUDED.used_amount
+WHERE u.used_amount + EXCLUDED.used_amount <= $6::FLOAT
+RETURNING used_amount
+`
+
+type IncrementAccountLimitUsageParams struct {
+	AccountID       string      `db:"account_id" json:"account_id"`
+	OperationTypeID int64       `db:"operation_type_id" json:"operation_type_id"`
+	Period          LimitPeriod `db:"period" json:"period"`
+	PeriodStart     time.Time   `db:"period_start" json:"period_start"`
+	Amount          float64     `db:"amount" json:"amount"`
+	MaxAmount       float64     `db:"max_amount" json:"max_amount"`
+}
+
+// Adds the amount to the counter of the period, only if the counter stays within max_amount.
+// No row is returned when the limit would be breached.
+func (q *Queries) IncrementAccountLimitUsage(ctx context.Context, arg IncrementAccountLimitUsageParams) (float64, error) {
+	row := q.db.QueryRow(ctx, incrementAccountLimitUsage,
+		arg.AccountID,
+		arg.OperationTypeID,
+		arg.Period,
+		arg.PeriodStart,
+		arg.Amount,
+		arg.MaxAmount,
+	)
+	var used_amount float64
+	err := row.Scan(&used_amount)
+	return used_amount, err
+}
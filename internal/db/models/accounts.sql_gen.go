@@ -7,6 +7,7 @@ package models
 
 import (
 	"context"
+	"database/sql"
 )
 
 const accountExists = `-- name: AccountExists :one
@@ -23,7 +24,7 @@ func (q *Queries) AccountExists(ctx context.Context, uuid string) (bool, error)
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO public.accounts (document_number, current_balance, user_id)
 VALUES ($1, $2, $3)
-RETURNING uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at
+RETURNING uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at, status
 `
 
 type CreateAccountParams struct {
@@ -43,12 +44,88 @@ func (q *Queries) CreateAccount(ctx context.Context, arg CreateAccountParams) (*
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
 	)
 	return &i, err
 }
 
+const createAccountDocument = `-- name: CreateAccountDocument :exec
+INSERT INTO public.account_documents (account_id, document_number, scope_key)
+VALUES ($1, $2, $3)
+`
+
+type CreateAccountDocumentParams struct {
+	AccountID      string `db:"account_id" json:"account_id"`
+	DocumentNumber string `db:"document_number" json:"document_number"`
+	ScopeKey       string `db:"scope_key" json:"scope_key"`
+}
+
+func (q *Queries) CreateAccountDocument(ctx context.Context, arg CreateAccountDocumentParams) error {
+	_, err := q.db.Exec(ctx, createAccountDocument, arg.AccountID, arg.DocumentNumber, arg.ScopeKey)
+	return err
+}
+
+const createAccountStatusHistory = `-- name: CreateAccountStatusHistory :one
+INSERT INTO public.account_status_history (account_id, from_status, to_status, reason_code, note)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING uuid, serial_id, account_id, from_status, to_status, reason_code, note, created_at
+`
+
+type CreateAccountStatusHistoryParams struct {
+	AccountID  string         `db:"account_id" json:"account_id"`
+	FromStatus AccountStatus  `db:"from_status" json:"from_status"`
+	ToStatus   AccountStatus  `db:"to_status" json:"to_status"`
+	ReasonCode string         `db:"reason_code" json:"reason_code"`
+	Note       sql.NullString `db:"note" json:"note"`
+}
+
+func (q *Queries) CreateAccountStatusHistory(ctx context.Context, arg CreateAccountStatusHistoryParams) (*AccountStatusHistory, error) {
+	row := q.db.QueryRow(ctx, createAccountStatusHistory,
+		arg.AccountID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.ReasonCode,
+		arg.Note,
+	)
+	var i AccountStatusHistory
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.AccountID,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.ReasonCode,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const documentNumberExists = `-- name: DocumentNumberExists :one
+SELECT EXISTS(
+    SELECT 1
+    FROM public.account_documents ad
+             JOIN public.accounts a ON a.uuid = ad.account_id
+    WHERE ad.document_number = $1
+      AND ($2::TEXT = '' OR a.user_id::TEXT = $2::TEXT)
+) AS exists
+`
+
+type DocumentNumberExistsParams struct {
+	DocumentNumber string `db:"document_number" json:"document_number"`
+	UserID         string `db:"user_id" json:"user_id"`
+}
+
+// An empty user_id looks for the document across all the users, the GLOBAL scope, otherwise in the accounts of the user
+func (q *Queries) DocumentNumberExists(ctx context.Context, arg DocumentNumberExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, documentNumberExists, arg.DocumentNumber, arg.UserID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const getAccountDetailsByUUID = `-- name: GetAccountDetailsByUUID :one
-SELECT uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at
+SELECT uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at, status
 FROM public.accounts
 WHERE uuid = $1
 `
@@ -64,6 +141,101 @@ func (q *Queries) GetAccountDetailsByUUID(ctx context.Context, uuid string) (*Ac
 		&i.UserID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Status,
+	)
+	return &i, err
+}
+
+const getAccountStatus = `-- name: GetAccountStatus :one
+SELECT status FROM public.accounts WHERE uuid = $1
+`
+
+func (q *Queries) GetAccountStatus(ctx context.Context, uuid string) (AccountStatus, error) {
+	row := q.db.QueryRow(ctx, getAccountStatus, uuid)
+	var status AccountStatus
+	err := row.Scan(&status)
+	return status, err
+}
+
+const getAccountStatusForUpdate = `-- name: GetAccountStatusForUpdate :one
+SELECT status FROM public.accounts WHERE uuid = $1 FOR UPDATE
+`
+
+func (q *Queries) GetAccountStatusForUpdate(ctx context.Context, uuid string) (AccountStatus, error) {
+	row := q.db.QueryRow(ctx, getAccountStatusForUpdate, uuid)
+	var status AccountStatus
+	err := row.Scan(&status)
+	return status, err
+}
+
+const getAccountsByUserID = `-- name: GetAccountsByUserID :many
+SELECT uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at, status
+FROM public.accounts
+WHERE user_id = $1
+ORDER BY serial_id
+`
+
+func (q *Queries) GetAccountsByUserID(ctx context.Context, userID string) ([]*Account, error) {
+	rows, err := q.db.Query(ctx, getAccountsByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*Account
+	for rows.Next() {
+		var i Account
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.SerialID,
+			&i.DocumentNumber,
+			&i.CurrentBalance,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Status,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockDocumentNumber = `-- name: LockDocumentNumber :exec
+SELECT pg_advisory_xact_lock(hashtext('account_documents:' || $1::TEXT))
+`
+
+// The lock serializes the accounts created with the same document number until the end of the transaction
+func (q *Queries) LockDocumentNumber(ctx context.Context, documentNumber string) error {
+	_, err := q.db.Exec(ctx, lockDocumentNumber, documentNumber)
+	return err
+}
+
+const updateAccountStatus = `-- name: UpdateAccountStatus :one
+UPDATE public.accounts SET status = $2 WHERE uuid = $1
+RETURNING uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at, status
+`
+
+type UpdateAccountStatusParams struct {
+	Uuid   string        `db:"uuid" json:"uuid"`
+	Status AccountStatus `db:"status" json:"status"`
+}
+
+func (q *Queries) UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (*Account, error) {
+	row := q.db.QueryRow(ctx, updateAccountStatus, arg.Uuid, arg.Status)
+	var i Account
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.DocumentNumber,
+		&i.CurrentBalance,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Status,
 	)
 	return &i, err
 }
@@ -0,0 +1,140 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: api_keys.sql
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO public.api_keys (name, key_hash, user_id, expires_at, scopes)
+VALUES ($1, $2, $3, $4, $5::TEXT[])
+RETURNING uuid, serial_id, name, user_id, expires_at, scopes, created_at
+`
+
+type CreateAPIKeyParams struct {
+	Name      string         `db:"name" json:"name"`
+	KeyHash   string         `db:"key_hash" json:"key_hash"`
+	UserID    sql.NullString `db:"user_id" json:"user_id"`
+	ExpiresAt sql.NullTime   `db:"expires_at" json:"expires_at"`
+	Scopes    []string       `db:"scopes" json:"scopes"`
+}
+
+type CreateAPIKeyRow struct {
+	Uuid      string         `db:"uuid" json:"uuid"`
+	SerialID  int64          `db:"serial_id" json:"serial_id"`
+	Name      string         `db:"name" json:"name"`
+	UserID    sql.NullString `db:"user_id" json:"user_id"`
+	ExpiresAt sql.NullTime   `db:"expires_at" json:"expires_at"`
+	Scopes    []string       `db:"scopes" json:"scopes"`
+	CreatedAt time.Time      `db:"created_at" json:"created_at"`
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (*CreateAPIKeyRow, error) {
+	row := q.db.QueryRow(ctx, createAPIKey,
+		arg.Name,
+		arg.KeyHash,
+		arg.UserID,
+		arg.ExpiresAt,
+		arg.Scopes,
+	)
+	var i CreateAPIKeyRow
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.Name,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.Scopes,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const getAPIKeyByHash = `-- name: GetAPIKeyByHash :one
+SELECT uuid, name, user_id, expires_at, revoked_at, scopes
+FROM public.api_keys
+WHERE key_hash = $1
+`
+
+type GetAPIKeyByHashRow struct {
+	Uuid      string         `db:"uuid" json:"uuid"`
+	Name      string         `db:"name" json:"name"`
+	UserID    sql.NullString `db:"user_id" json:"user_id"`
+	ExpiresAt sql.NullTime   `db:"expires_at" json:"expires_at"`
+	RevokedAt sql.NullTime   `db:"revoked_at" json:"revoked_at"`
+	Scopes    []string       `db:"scopes" json:"scopes"`
+}
+
+func (q *Queries) GetAPIKeyByHash(ctx context.Context, keyHash string) (*GetAPIKeyByHashRow, error) {
+	row := q.db.QueryRow(ctx, getAPIKeyByHash, keyHash)
+	var i GetAPIKeyByHashRow
+	err := row.Scan(
+		&i.Uuid,
+		&i.Name,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.Scopes,
+	)
+	return &i, err
+}
+
+const getAccountOwner = `-- name: GetAccountOwner :one
+SELECT user_id
+FROM public.accounts
+WHERE uuid = $1
+`
+
+func (q *Queries) GetAccountOwner(ctx context.Context, uuid string) (string, error) {
+	row := q.db.QueryRow(ctx, getAccountOwner, uuid)
+	var user_id string
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getDisputeOwner = `-- name: GetDisputeOwner :one
+SELECT a.user_id
+FROM public.disputes d
+         JOIN public.accounts a ON a.uuid = d.account_id
+WHERE d.uuid = $1
+`
+
+func (q *Queries) GetDisputeOwner(ctx context.Context, uuid string) (string, error) {
+	row := q.db.QueryRow(ctx, getDisputeOwner, uuid)
+	var user_id string
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getTransactionOwner = `-- name: GetTransactionOwner :one
+SELECT a.user_id
+FROM public.transactions t
+         JOIN public.accounts a ON a.uuid = t.account_id
+WHERE t.uuid = $1
+`
+
+func (q *Queries) GetTransactionOwner(ctx context.Context, uuid string) (string, error) {
+	row := q.db.QueryRow(ctx, getTransactionOwner, uuid)
+	var user_id string
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE public.api_keys
+SET last_used_at = NOW()
+WHERE uuid = $1
+  AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute')
+`
+
+// last_used_at is only updated once a minute, so that the keys used by every request are not written every time
+func (q *Queries) TouchAPIKey(ctx context.Context, uuid string) error {
+	_, err := q.db.Exec(ctx, touchAPIKey, uuid)
+	return err
+}
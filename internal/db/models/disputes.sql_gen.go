@@ -0,0 +1,256 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: disputes.sql
+
+package models
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createDispute = `-- name: CreateDispute :one
+INSERT INTO public.disputes (transaction_id, account_id, amount, reason_code)
+VALUES ($1, $2, $3, $4)
+RETURNING uuid, serial_id, transaction_id, account_id, amount, reason_code, status, provisional_credit_transaction_id, resolution_transaction_id, resolved_at, created_at, updated_at
+`
+
+type CreateDisputeParams struct {
+	TransactionID string  `db:"transaction_id" json:"transaction_id"`
+	AccountID     string  `db:"account_id" json:"account_id"`
+	Amount        float64 `db:"amount" json:"amount"`
+	ReasonCode    string  `db:"reason_code" json:"reason_code"`
+}
+
+func (q *Queries) CreateDispute(ctx context.Context, arg CreateDisputeParams) (*Dispute, error) {
+	row := q.db.QueryRow(ctx, createDispute,
+		arg.TransactionID,
+		arg.AccountID,
+		arg.Amount,
+		arg.ReasonCode,
+	)
+	var i Dispute
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.Amount,
+		&i.ReasonCode,
+		&i.Status,
+		&i.ProvisionalCreditTransactionID,
+		&i.ResolutionTransactionID,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const createDisputeEvent = `-- name: CreateDisputeEvent :one
+INSERT INTO public.dispute_events (dispute_id, type, transaction_id, note)
+VALUES ($1, $2, $3, $4)
+RETURNING serial_id, dispute_id, type, transaction_id, note, created_at
+`
+
+type CreateDisputeEventParams struct {
+	DisputeID     string           `db:"dispute_id" json:"dispute_id"`
+	Type          DisputeEventType `db:"type" json:"type"`
+	TransactionID sql.NullString   `db:"transaction_id" json:"transaction_id"`
+	Note          sql.NullString   `db:"note" json:"note"`
+}
+
+func (q *Queries) CreateDisputeEvent(ctx context.Context, arg CreateDisputeEventParams) (*DisputeEvent, error) {
+	row := q.db.QueryRow(ctx, createDisputeEvent,
+		arg.DisputeID,
+		arg.Type,
+		arg.TransactionID,
+		arg.Note,
+	)
+	var i DisputeEvent
+	err := row.Scan(
+		&i.SerialID,
+		&i.DisputeID,
+		&i.Type,
+		&i.TransactionID,
+		&i.Note,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const getDispute = `-- name: GetDispute :one
+SELECT uuid, serial_id, transaction_id, account_id, amount, reason_code, status, provisional_credit_transaction_id, resolution_transaction_id, resolved_at, created_at, updated_at FROM public.disputes WHERE uuid = $1
+`
+
+func (q *Queries) GetDispute(ctx context.Context, uuid string) (*Dispute, error) {
+	row := q.db.QueryRow(ctx, getDispute, uuid)
+	var i Dispute
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.Amount,
+		&i.ReasonCode,
+		&i.Status,
+		&i.ProvisionalCreditTransactionID,
+		&i.ResolutionTransactionID,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getDisputeEvents = `-- name: GetDisputeEvents :many
+SELECT serial_id, dispute_id, type, transaction_id, note, created_at FROM public.dispute_events
+WHERE dispute_id = $1
+ORDER BY serial_id
+`
+
+func (q *Queries) GetDisputeEvents(ctx context.Context, disputeID string) ([]*DisputeEvent, error) {
+	rows, err := q.db.Query(ctx, getDisputeEvents, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*DisputeEvent
+	for rows.Next() {
+		var i DisputeEvent
+		if err := rows.Scan(
+			&i.SerialID,
+			&i.DisputeID,
+			&i.Type,
+			&i.TransactionID,
+			&i.Note,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDisputeForUpdate = `-- name: GetDisputeForUpdate :one
+SELECT uuid, serial_id, transaction_id, account_id, amount, reason_code, status, provisional_credit_transaction_id, resolution_transaction_id, resolved_at, created_at, updated_at FROM public.disputes WHERE uuid = $1 FOR UPDATE
+`
+
+func (q *Queries) GetDisputeForUpdate(ctx context.Context, uuid string) (*Dispute, error) {
+	row := q.db.QueryRow(ctx, getDisputeForUpdate, uuid)
+	var i Dispute
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.Amount,
+		&i.ReasonCode,
+		&i.Status,
+		&i.ProvisionalCreditTransactionID,
+		&i.ResolutionTransactionID,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const resolveDispute = `-- name: ResolveDispute :one
+UPDATE public.disputes SET status = $2, resolution_transaction_id = $3, resolved_at = NOW()
+WHERE uuid = $1
+RETURNING uuid, serial_id, transaction_id, account_id, amount, reason_code, status, provisional_credit_transaction_id, resolution_transaction_id, resolved_at, created_at, updated_at
+`
+
+type ResolveDisputeParams struct {
+	Uuid                    string         `db:"uuid" json:"uuid"`
+	Status                  DisputeStatus  `db:"status" json:"status"`
+	ResolutionTransactionID sql.NullString `db:"resolution_transaction_id" json:"resolution_transaction_id"`
+}
+
+func (q *Queries) ResolveDispute(ctx context.Context, arg ResolveDisputeParams) (*Dispute, error) {
+	row := q.db.QueryRow(ctx, resolveDispute, arg.Uuid, arg.Status, arg.ResolutionTransactionID)
+	var i Dispute
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.Amount,
+		&i.ReasonCode,
+		&i.Status,
+		&i.ProvisionalCreditTransactionID,
+		&i.ResolutionTransactionID,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const setDisputeProvisionalCredit = `-- name: SetDisputeProvisionalCredit :one
+UPDATE public.disputes SET provisional_credit_transaction_id = $2
+WHERE uuid = $1
+RETURNING uuid, serial_id, transaction_id, account_id, amount, reason_code, status, provisional_credit_transaction_id, resolution_transaction_id, resolved_at, created_at, updated_at
+`
+
+type SetDisputeProvisionalCreditParams struct {
+	Uuid                           string         `db:"uuid" json:"uuid"`
+	ProvisionalCreditTransactionID sql.NullString `db:"provisional_credit_transaction_id" json:"provisional_credit_transaction_id"`
+}
+
+func (q *Queries) SetDisputeProvisionalCredit(ctx context.Context, arg SetDisputeProvisionalCreditParams) (*Dispute, error) {
+	row := q.db.QueryRow(ctx, setDisputeProvisionalCredit, arg.Uuid, arg.ProvisionalCreditTransactionID)
+	var i Dispute
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.Amount,
+		&i.ReasonCode,
+		&i.Status,
+		&i.ProvisionalCreditTransactionID,
+		&i.ResolutionTransactionID,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const updateDisputeStatus = `-- name: UpdateDisputeStatus :one
+UPDATE public.disputes SET status = $2
+WHERE uuid = $1
+RETURNING uuid, serial_id, transaction_id, account_id, amount, reason_code, status, provisional_credit_transaction_id, resolution_transaction_id, resolved_at, created_at, updated_at
+`
+
+type UpdateDisputeStatusParams struct {
+	Uuid   string        `db:"uuid" json:"uuid"`
+	Status DisputeStatus `db:"status" json:"status"`
+}
+
+func (q *Queries) UpdateDisputeStatus(ctx context.Context, arg UpdateDisputeStatusParams) (*Dispute, error) {
+	row := q.db.QueryRow(ctx, updateDisputeStatus, arg.Uuid, arg.Status)
+	var i Dispute
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.TransactionID,
+		&i.AccountID,
+		&i.Amount,
+		&i.ReasonCode,
+		&i.Status,
+		&i.ProvisionalCreditTransactionID,
+		&i.ResolutionTransactionID,
+		&i.ResolvedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
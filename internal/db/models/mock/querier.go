@@ -6,7 +6,9 @@ package mock
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/imjenal/transaction-service/internal/db/models"
@@ -50,6 +52,21 @@ func (mr *MockQuerierMockRecorder) AccountExists(ctx, uuid interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccountExists", reflect.TypeOf((*MockQuerier)(nil).AccountExists), ctx, uuid)
 }
 
+// CreateAPIKey mocks base method.
+func (m *MockQuerier) CreateAPIKey(ctx context.Context, arg models.CreateAPIKeyParams) (*models.CreateAPIKeyRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, arg)
+	ret0, _ := ret[0].(*models.CreateAPIKeyRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockQuerierMockRecorder) CreateAPIKey(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockQuerier)(nil).CreateAPIKey), ctx, arg)
+}
+
 // CreateAccount mocks base method.
 func (m *MockQuerier) CreateAccount(ctx context.Context, arg models.CreateAccountParams) (*models.Account, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +82,153 @@ func (mr *MockQuerierMockRecorder) CreateAccount(ctx, arg interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccount", reflect.TypeOf((*MockQuerier)(nil).CreateAccount), ctx, arg)
 }
 
+// CreateAccountDocument mocks base method.
+func (m *MockQuerier) CreateAccountDocument(ctx context.Context, arg models.CreateAccountDocumentParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountDocument", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAccountDocument indicates an expected call of CreateAccountDocument.
+func (mr *MockQuerierMockRecorder) CreateAccountDocument(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountDocument", reflect.TypeOf((*MockQuerier)(nil).CreateAccountDocument), ctx, arg)
+}
+
+// CreateAccountLimit mocks base method.
+func (m *MockQuerier) CreateAccountLimit(ctx context.Context, arg models.CreateAccountLimitParams) (*models.AccountLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountLimit", ctx, arg)
+	ret0, _ := ret[0].(*models.AccountLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountLimit indicates an expected call of CreateAccountLimit.
+func (mr *MockQuerierMockRecorder) CreateAccountLimit(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountLimit", reflect.TypeOf((*MockQuerier)(nil).CreateAccountLimit), ctx, arg)
+}
+
+// CreateAccountStatusHistory mocks base method.
+func (m *MockQuerier) CreateAccountStatusHistory(ctx context.Context, arg models.CreateAccountStatusHistoryParams) (*models.AccountStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAccountStatusHistory", ctx, arg)
+	ret0, _ := ret[0].(*models.AccountStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAccountStatusHistory indicates an expected call of CreateAccountStatusHistory.
+func (mr *MockQuerierMockRecorder) CreateAccountStatusHistory(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccountStatusHistory", reflect.TypeOf((*MockQuerier)(nil).CreateAccountStatusHistory), ctx, arg)
+}
+
+// CreateDispute mocks base method.
+func (m *MockQuerier) CreateDispute(ctx context.Context, arg models.CreateDisputeParams) (*models.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDispute", ctx, arg)
+	ret0, _ := ret[0].(*models.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDispute indicates an expected call of CreateDispute.
+func (mr *MockQuerierMockRecorder) CreateDispute(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDispute", reflect.TypeOf((*MockQuerier)(nil).CreateDispute), ctx, arg)
+}
+
+// CreateDisputeEvent mocks base method.
+func (m *MockQuerier) CreateDisputeEvent(ctx context.Context, arg models.CreateDisputeEventParams) (*models.DisputeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDisputeEvent", ctx, arg)
+	ret0, _ := ret[0].(*models.DisputeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDisputeEvent indicates an expected call of CreateDisputeEvent.
+func (mr *MockQuerierMockRecorder) CreateDisputeEvent(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDisputeEvent", reflect.TypeOf((*MockQuerier)(nil).CreateDisputeEvent), ctx, arg)
+}
+
+// CreateReversalTransaction mocks base method.
+func (m *MockQuerier) CreateReversalTransaction(ctx context.Context, arg models.CreateReversalTransactionParams) (*models.CreateReversalTransactionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReversalTransaction", ctx, arg)
+	ret0, _ := ret[0].(*models.CreateReversalTransactionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReversalTransaction indicates an expected call of CreateReversalTransaction.
+func (mr *MockQuerierMockRecorder) CreateReversalTransaction(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReversalTransaction", reflect.TypeOf((*MockQuerier)(nil).CreateReversalTransaction), ctx, arg)
+}
+
+// CreateRewardAccrual mocks base method.
+func (m *MockQuerier) CreateRewardAccrual(ctx context.Context, arg models.CreateRewardAccrualParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRewardAccrual", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRewardAccrual indicates an expected call of CreateRewardAccrual.
+func (mr *MockQuerierMockRecorder) CreateRewardAccrual(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRewardAccrual", reflect.TypeOf((*MockQuerier)(nil).CreateRewardAccrual), ctx, arg)
+}
+
+// CreateRiskDecision mocks base method.
+func (m *MockQuerier) CreateRiskDecision(ctx context.Context, arg models.CreateRiskDecisionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRiskDecision", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateRiskDecision indicates an expected call of CreateRiskDecision.
+func (mr *MockQuerierMockRecorder) CreateRiskDecision(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRiskDecision", reflect.TypeOf((*MockQuerier)(nil).CreateRiskDecision), ctx, arg)
+}
+
+// CreateScheduledTransaction mocks base method.
+func (m *MockQuerier) CreateScheduledTransaction(ctx context.Context, arg models.CreateScheduledTransactionParams) (*models.ScheduledTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledTransaction", ctx, arg)
+	ret0, _ := ret[0].(*models.ScheduledTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledTransaction indicates an expected call of CreateScheduledTransaction.
+func (mr *MockQuerierMockRecorder) CreateScheduledTransaction(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledTransaction", reflect.TypeOf((*MockQuerier)(nil).CreateScheduledTransaction), ctx, arg)
+}
+
+// CreateScheduledTransactionRun mocks base method.
+func (m *MockQuerier) CreateScheduledTransactionRun(ctx context.Context, arg models.CreateScheduledTransactionRunParams) (*models.ScheduledTransactionRun, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateScheduledTransactionRun", ctx, arg)
+	ret0, _ := ret[0].(*models.ScheduledTransactionRun)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateScheduledTransactionRun indicates an expected call of CreateScheduledTransactionRun.
+func (mr *MockQuerierMockRecorder) CreateScheduledTransactionRun(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScheduledTransactionRun", reflect.TypeOf((*MockQuerier)(nil).CreateScheduledTransactionRun), ctx, arg)
+}
+
 // CreateTransaction mocks base method.
 func (m *MockQuerier) CreateTransaction(ctx context.Context, arg models.CreateTransactionParams) (*models.CreateTransactionRow, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +244,94 @@ func (mr *MockQuerierMockRecorder) CreateTransaction(ctx, arg interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransaction", reflect.TypeOf((*MockQuerier)(nil).CreateTransaction), ctx, arg)
 }
 
+// CreateUser mocks base method.
+func (m *MockQuerier) CreateUser(ctx context.Context, arg models.CreateUserParams) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", ctx, arg)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockQuerierMockRecorder) CreateUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockQuerier)(nil).CreateUser), ctx, arg)
+}
+
+// DeleteAccountLimits mocks base method.
+func (m *MockQuerier) DeleteAccountLimits(ctx context.Context, accountID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteAccountLimits", ctx, accountID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteAccountLimits indicates an expected call of DeleteAccountLimits.
+func (mr *MockQuerierMockRecorder) DeleteAccountLimits(ctx, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteAccountLimits", reflect.TypeOf((*MockQuerier)(nil).DeleteAccountLimits), ctx, accountID)
+}
+
+// DeleteExpiredRateLimitCounters mocks base method.
+func (m *MockQuerier) DeleteExpiredRateLimitCounters(ctx context.Context, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredRateLimitCounters", ctx, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpiredRateLimitCounters indicates an expected call of DeleteExpiredRateLimitCounters.
+func (mr *MockQuerierMockRecorder) DeleteExpiredRateLimitCounters(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredRateLimitCounters", reflect.TypeOf((*MockQuerier)(nil).DeleteExpiredRateLimitCounters), ctx, before)
+}
+
+// DeleteRewardPayoutFailure mocks base method.
+func (m *MockQuerier) DeleteRewardPayoutFailure(ctx context.Context, accountID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRewardPayoutFailure", ctx, accountID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRewardPayoutFailure indicates an expected call of DeleteRewardPayoutFailure.
+func (mr *MockQuerierMockRecorder) DeleteRewardPayoutFailure(ctx, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRewardPayoutFailure", reflect.TypeOf((*MockQuerier)(nil).DeleteRewardPayoutFailure), ctx, accountID)
+}
+
+// DocumentNumberExists mocks base method.
+func (m *MockQuerier) DocumentNumberExists(ctx context.Context, arg models.DocumentNumberExistsParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DocumentNumberExists", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DocumentNumberExists indicates an expected call of DocumentNumberExists.
+func (mr *MockQuerierMockRecorder) DocumentNumberExists(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DocumentNumberExists", reflect.TypeOf((*MockQuerier)(nil).DocumentNumberExists), ctx, arg)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockQuerier) GetAPIKeyByHash(ctx context.Context, keyHash string) (*models.GetAPIKeyByHashRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, keyHash)
+	ret0, _ := ret[0].(*models.GetAPIKeyByHashRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockQuerierMockRecorder) GetAPIKeyByHash(ctx, keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockQuerier)(nil).GetAPIKeyByHash), ctx, keyHash)
+}
+
 // GetAccountDetailsByUUID mocks base method.
 func (m *MockQuerier) GetAccountDetailsByUUID(ctx context.Context, uuid string) (*models.Account, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +347,216 @@ func (mr *MockQuerierMockRecorder) GetAccountDetailsByUUID(ctx, uuid interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountDetailsByUUID", reflect.TypeOf((*MockQuerier)(nil).GetAccountDetailsByUUID), ctx, uuid)
 }
 
+// GetAccountLimitsByOperationType mocks base method.
+func (m *MockQuerier) GetAccountLimitsByOperationType(ctx context.Context, arg models.GetAccountLimitsByOperationTypeParams) ([]*models.AccountLimit, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountLimitsByOperationType", ctx, arg)
+	ret0, _ := ret[0].([]*models.AccountLimit)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountLimitsByOperationType indicates an expected call of GetAccountLimitsByOperationType.
+func (mr *MockQuerierMockRecorder) GetAccountLimitsByOperationType(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountLimitsByOperationType", reflect.TypeOf((*MockQuerier)(nil).GetAccountLimitsByOperationType), ctx, arg)
+}
+
+// GetAccountLimitsWithUsage mocks base method.
+func (m *MockQuerier) GetAccountLimitsWithUsage(ctx context.Context, arg models.GetAccountLimitsWithUsageParams) ([]*models.GetAccountLimitsWithUsageRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountLimitsWithUsage", ctx, arg)
+	ret0, _ := ret[0].([]*models.GetAccountLimitsWithUsageRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountLimitsWithUsage indicates an expected call of GetAccountLimitsWithUsage.
+func (mr *MockQuerierMockRecorder) GetAccountLimitsWithUsage(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountLimitsWithUsage", reflect.TypeOf((*MockQuerier)(nil).GetAccountLimitsWithUsage), ctx, arg)
+}
+
+// GetAccountOwner mocks base method.
+func (m *MockQuerier) GetAccountOwner(ctx context.Context, uuid string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountOwner", ctx, uuid)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountOwner indicates an expected call of GetAccountOwner.
+func (mr *MockQuerierMockRecorder) GetAccountOwner(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountOwner", reflect.TypeOf((*MockQuerier)(nil).GetAccountOwner), ctx, uuid)
+}
+
+// GetAccountStatus mocks base method.
+func (m *MockQuerier) GetAccountStatus(ctx context.Context, uuid string) (models.AccountStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountStatus", ctx, uuid)
+	ret0, _ := ret[0].(models.AccountStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountStatus indicates an expected call of GetAccountStatus.
+func (mr *MockQuerierMockRecorder) GetAccountStatus(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountStatus", reflect.TypeOf((*MockQuerier)(nil).GetAccountStatus), ctx, uuid)
+}
+
+// GetAccountStatusForUpdate mocks base method.
+func (m *MockQuerier) GetAccountStatusForUpdate(ctx context.Context, uuid string) (models.AccountStatus, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountStatusForUpdate", ctx, uuid)
+	ret0, _ := ret[0].(models.AccountStatus)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountStatusForUpdate indicates an expected call of GetAccountStatusForUpdate.
+func (mr *MockQuerierMockRecorder) GetAccountStatusForUpdate(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountStatusForUpdate", reflect.TypeOf((*MockQuerier)(nil).GetAccountStatusForUpdate), ctx, uuid)
+}
+
+// GetAccountSummary mocks base method.
+func (m *MockQuerier) GetAccountSummary(ctx context.Context, arg models.GetAccountSummaryParams) (*models.GetAccountSummaryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountSummary", ctx, arg)
+	ret0, _ := ret[0].(*models.GetAccountSummaryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountSummary indicates an expected call of GetAccountSummary.
+func (mr *MockQuerierMockRecorder) GetAccountSummary(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountSummary", reflect.TypeOf((*MockQuerier)(nil).GetAccountSummary), ctx, arg)
+}
+
+// GetAccountTransactionAmountStats mocks base method.
+func (m *MockQuerier) GetAccountTransactionAmountStats(ctx context.Context, arg models.GetAccountTransactionAmountStatsParams) (*models.GetAccountTransactionAmountStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountTransactionAmountStats", ctx, arg)
+	ret0, _ := ret[0].(*models.GetAccountTransactionAmountStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountTransactionAmountStats indicates an expected call of GetAccountTransactionAmountStats.
+func (mr *MockQuerierMockRecorder) GetAccountTransactionAmountStats(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountTransactionAmountStats", reflect.TypeOf((*MockQuerier)(nil).GetAccountTransactionAmountStats), ctx, arg)
+}
+
+// GetAccountTransactionVelocity mocks base method.
+func (m *MockQuerier) GetAccountTransactionVelocity(ctx context.Context, arg models.GetAccountTransactionVelocityParams) (*models.GetAccountTransactionVelocityRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountTransactionVelocity", ctx, arg)
+	ret0, _ := ret[0].(*models.GetAccountTransactionVelocityRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountTransactionVelocity indicates an expected call of GetAccountTransactionVelocity.
+func (mr *MockQuerierMockRecorder) GetAccountTransactionVelocity(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountTransactionVelocity", reflect.TypeOf((*MockQuerier)(nil).GetAccountTransactionVelocity), ctx, arg)
+}
+
+// GetAccountsByUserID mocks base method.
+func (m *MockQuerier) GetAccountsByUserID(ctx context.Context, userID string) ([]*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountsByUserID indicates an expected call of GetAccountsByUserID.
+func (mr *MockQuerierMockRecorder) GetAccountsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountsByUserID", reflect.TypeOf((*MockQuerier)(nil).GetAccountsByUserID), ctx, userID)
+}
+
+// GetAccountsWithPendingCashback mocks base method.
+func (m *MockQuerier) GetAccountsWithPendingCashback(ctx context.Context, arg models.GetAccountsWithPendingCashbackParams) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAccountsWithPendingCashback", ctx, arg)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAccountsWithPendingCashback indicates an expected call of GetAccountsWithPendingCashback.
+func (mr *MockQuerierMockRecorder) GetAccountsWithPendingCashback(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountsWithPendingCashback", reflect.TypeOf((*MockQuerier)(nil).GetAccountsWithPendingCashback), ctx, arg)
+}
+
+// GetDispute mocks base method.
+func (m *MockQuerier) GetDispute(ctx context.Context, uuid string) (*models.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDispute", ctx, uuid)
+	ret0, _ := ret[0].(*models.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDispute indicates an expected call of GetDispute.
+func (mr *MockQuerierMockRecorder) GetDispute(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDispute", reflect.TypeOf((*MockQuerier)(nil).GetDispute), ctx, uuid)
+}
+
+// GetDisputeEvents mocks base method.
+func (m *MockQuerier) GetDisputeEvents(ctx context.Context, disputeID string) ([]*models.DisputeEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputeEvents", ctx, disputeID)
+	ret0, _ := ret[0].([]*models.DisputeEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputeEvents indicates an expected call of GetDisputeEvents.
+func (mr *MockQuerierMockRecorder) GetDisputeEvents(ctx, disputeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputeEvents", reflect.TypeOf((*MockQuerier)(nil).GetDisputeEvents), ctx, disputeID)
+}
+
+// GetDisputeForUpdate mocks base method.
+func (m *MockQuerier) GetDisputeForUpdate(ctx context.Context, uuid string) (*models.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputeForUpdate", ctx, uuid)
+	ret0, _ := ret[0].(*models.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputeForUpdate indicates an expected call of GetDisputeForUpdate.
+func (mr *MockQuerierMockRecorder) GetDisputeForUpdate(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputeForUpdate", reflect.TypeOf((*MockQuerier)(nil).GetDisputeForUpdate), ctx, uuid)
+}
+
+// GetDisputeOwner mocks base method.
+func (m *MockQuerier) GetDisputeOwner(ctx context.Context, uuid string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDisputeOwner", ctx, uuid)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDisputeOwner indicates an expected call of GetDisputeOwner.
+func (mr *MockQuerierMockRecorder) GetDisputeOwner(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDisputeOwner", reflect.TypeOf((*MockQuerier)(nil).GetDisputeOwner), ctx, uuid)
+}
+
 // GetNegativeBalanceTransactionsByAccountID mocks base method.
 func (m *MockQuerier) GetNegativeBalanceTransactionsByAccountID(ctx context.Context, accountID string) ([]*models.GetNegativeBalanceTransactionsByAccountIDRow, error) {
 	m.ctrl.T.Helper()
@@ -110,6 +572,21 @@ func (mr *MockQuerierMockRecorder) GetNegativeBalanceTransactionsByAccountID(ctx
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNegativeBalanceTransactionsByAccountID", reflect.TypeOf((*MockQuerier)(nil).GetNegativeBalanceTransactionsByAccountID), ctx, accountID)
 }
 
+// GetNextDueScheduledTransaction mocks base method.
+func (m *MockQuerier) GetNextDueScheduledTransaction(ctx context.Context, nextRunAt sql.NullTime) (*models.ScheduledTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNextDueScheduledTransaction", ctx, nextRunAt)
+	ret0, _ := ret[0].(*models.ScheduledTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNextDueScheduledTransaction indicates an expected call of GetNextDueScheduledTransaction.
+func (mr *MockQuerierMockRecorder) GetNextDueScheduledTransaction(ctx, nextRunAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNextDueScheduledTransaction", reflect.TypeOf((*MockQuerier)(nil).GetNextDueScheduledTransaction), ctx, nextRunAt)
+}
+
 // GetOperationTypeAmountBehavior mocks base method.
 func (m *MockQuerier) GetOperationTypeAmountBehavior(ctx context.Context, serialID int64) (models.AmountBehavior, error) {
 	m.ctrl.T.Helper()
@@ -125,6 +602,126 @@ func (mr *MockQuerierMockRecorder) GetOperationTypeAmountBehavior(ctx, serialID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperationTypeAmountBehavior", reflect.TypeOf((*MockQuerier)(nil).GetOperationTypeAmountBehavior), ctx, serialID)
 }
 
+// GetOperationTypeIDByDescription mocks base method.
+func (m *MockQuerier) GetOperationTypeIDByDescription(ctx context.Context, description models.TransactionType) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOperationTypeIDByDescription", ctx, description)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOperationTypeIDByDescription indicates an expected call of GetOperationTypeIDByDescription.
+func (mr *MockQuerierMockRecorder) GetOperationTypeIDByDescription(ctx, description interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOperationTypeIDByDescription", reflect.TypeOf((*MockQuerier)(nil).GetOperationTypeIDByDescription), ctx, description)
+}
+
+// GetPendingCashbackAccrualsForUpdate mocks base method.
+func (m *MockQuerier) GetPendingCashbackAccrualsForUpdate(ctx context.Context, accountID string) ([]*models.GetPendingCashbackAccrualsForUpdateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingCashbackAccrualsForUpdate", ctx, accountID)
+	ret0, _ := ret[0].([]*models.GetPendingCashbackAccrualsForUpdateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingCashbackAccrualsForUpdate indicates an expected call of GetPendingCashbackAccrualsForUpdate.
+func (mr *MockQuerierMockRecorder) GetPendingCashbackAccrualsForUpdate(ctx, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingCashbackAccrualsForUpdate", reflect.TypeOf((*MockQuerier)(nil).GetPendingCashbackAccrualsForUpdate), ctx, accountID)
+}
+
+// GetReversedAmount mocks base method.
+func (m *MockQuerier) GetReversedAmount(ctx context.Context, transactionID string) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReversedAmount", ctx, transactionID)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReversedAmount indicates an expected call of GetReversedAmount.
+func (mr *MockQuerierMockRecorder) GetReversedAmount(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReversedAmount", reflect.TypeOf((*MockQuerier)(nil).GetReversedAmount), ctx, transactionID)
+}
+
+// GetRewardAccrualTotalsByTransactionID mocks base method.
+func (m *MockQuerier) GetRewardAccrualTotalsByTransactionID(ctx context.Context, transactionID string) ([]*models.GetRewardAccrualTotalsByTransactionIDRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewardAccrualTotalsByTransactionID", ctx, transactionID)
+	ret0, _ := ret[0].([]*models.GetRewardAccrualTotalsByTransactionIDRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewardAccrualTotalsByTransactionID indicates an expected call of GetRewardAccrualTotalsByTransactionID.
+func (mr *MockQuerierMockRecorder) GetRewardAccrualTotalsByTransactionID(ctx, transactionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewardAccrualTotalsByTransactionID", reflect.TypeOf((*MockQuerier)(nil).GetRewardAccrualTotalsByTransactionID), ctx, transactionID)
+}
+
+// GetRewardsBalance mocks base method.
+func (m *MockQuerier) GetRewardsBalance(ctx context.Context, accountID string) ([]*models.GetRewardsBalanceRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRewardsBalance", ctx, accountID)
+	ret0, _ := ret[0].([]*models.GetRewardsBalanceRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRewardsBalance indicates an expected call of GetRewardsBalance.
+func (mr *MockQuerierMockRecorder) GetRewardsBalance(ctx, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRewardsBalance", reflect.TypeOf((*MockQuerier)(nil).GetRewardsBalance), ctx, accountID)
+}
+
+// GetScheduledTransactionForUpdate mocks base method.
+func (m *MockQuerier) GetScheduledTransactionForUpdate(ctx context.Context, arg models.GetScheduledTransactionForUpdateParams) (*models.ScheduledTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledTransactionForUpdate", ctx, arg)
+	ret0, _ := ret[0].(*models.ScheduledTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledTransactionForUpdate indicates an expected call of GetScheduledTransactionForUpdate.
+func (mr *MockQuerierMockRecorder) GetScheduledTransactionForUpdate(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransactionForUpdate", reflect.TypeOf((*MockQuerier)(nil).GetScheduledTransactionForUpdate), ctx, arg)
+}
+
+// GetScheduledTransactionsByAccountID mocks base method.
+func (m *MockQuerier) GetScheduledTransactionsByAccountID(ctx context.Context, accountID string) ([]*models.ScheduledTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScheduledTransactionsByAccountID", ctx, accountID)
+	ret0, _ := ret[0].([]*models.ScheduledTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetScheduledTransactionsByAccountID indicates an expected call of GetScheduledTransactionsByAccountID.
+func (mr *MockQuerierMockRecorder) GetScheduledTransactionsByAccountID(ctx, accountID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScheduledTransactionsByAccountID", reflect.TypeOf((*MockQuerier)(nil).GetScheduledTransactionsByAccountID), ctx, accountID)
+}
+
+// GetSpendAnalytics mocks base method.
+func (m *MockQuerier) GetSpendAnalytics(ctx context.Context, arg models.GetSpendAnalyticsParams) ([]*models.GetSpendAnalyticsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpendAnalytics", ctx, arg)
+	ret0, _ := ret[0].([]*models.GetSpendAnalyticsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpendAnalytics indicates an expected call of GetSpendAnalytics.
+func (mr *MockQuerierMockRecorder) GetSpendAnalytics(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpendAnalytics", reflect.TypeOf((*MockQuerier)(nil).GetSpendAnalytics), ctx, arg)
+}
+
 // GetTransactionDetailsByTransactionId mocks base method.
 func (m *MockQuerier) GetTransactionDetailsByTransactionId(ctx context.Context, uuid string) (*models.GetTransactionDetailsByTransactionIdRow, error) {
 	m.ctrl.T.Helper()
@@ -140,6 +737,272 @@ func (mr *MockQuerierMockRecorder) GetTransactionDetailsByTransactionId(ctx, uui
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionDetailsByTransactionId", reflect.TypeOf((*MockQuerier)(nil).GetTransactionDetailsByTransactionId), ctx, uuid)
 }
 
+// GetTransactionForUpdate mocks base method.
+func (m *MockQuerier) GetTransactionForUpdate(ctx context.Context, uuid string) (*models.GetTransactionForUpdateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionForUpdate", ctx, uuid)
+	ret0, _ := ret[0].(*models.GetTransactionForUpdateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionForUpdate indicates an expected call of GetTransactionForUpdate.
+func (mr *MockQuerierMockRecorder) GetTransactionForUpdate(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionForUpdate", reflect.TypeOf((*MockQuerier)(nil).GetTransactionForUpdate), ctx, uuid)
+}
+
+// GetTransactionOwner mocks base method.
+func (m *MockQuerier) GetTransactionOwner(ctx context.Context, uuid string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionOwner", ctx, uuid)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionOwner indicates an expected call of GetTransactionOwner.
+func (mr *MockQuerierMockRecorder) GetTransactionOwner(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionOwner", reflect.TypeOf((*MockQuerier)(nil).GetTransactionOwner), ctx, uuid)
+}
+
+// GetUserByUUID mocks base method.
+func (m *MockQuerier) GetUserByUUID(ctx context.Context, uuid string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUUID", ctx, uuid)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUUID indicates an expected call of GetUserByUUID.
+func (mr *MockQuerierMockRecorder) GetUserByUUID(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUUID", reflect.TypeOf((*MockQuerier)(nil).GetUserByUUID), ctx, uuid)
+}
+
+// IncrementAccountLimitUsage mocks base method.
+func (m *MockQuerier) IncrementAccountLimitUsage(ctx context.Context, arg models.IncrementAccountLimitUsageParams) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementAccountLimitUsage", ctx, arg)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementAccountLimitUsage indicates an expected call of IncrementAccountLimitUsage.
+func (mr *MockQuerierMockRecorder) IncrementAccountLimitUsage(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementAccountLimitUsage", reflect.TypeOf((*MockQuerier)(nil).IncrementAccountLimitUsage), ctx, arg)
+}
+
+// IncrementRateLimitCounter mocks base method.
+func (m *MockQuerier) IncrementRateLimitCounter(ctx context.Context, arg models.IncrementRateLimitCounterParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementRateLimitCounter", ctx, arg)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementRateLimitCounter indicates an expected call of IncrementRateLimitCounter.
+func (mr *MockQuerierMockRecorder) IncrementRateLimitCounter(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementRateLimitCounter", reflect.TypeOf((*MockQuerier)(nil).IncrementRateLimitCounter), ctx, arg)
+}
+
+// ListTransactions mocks base method.
+func (m *MockQuerier) ListTransactions(ctx context.Context, arg models.ListTransactionsParams) ([]*models.ListTransactionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransactions", ctx, arg)
+	ret0, _ := ret[0].([]*models.ListTransactionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransactions indicates an expected call of ListTransactions.
+func (mr *MockQuerierMockRecorder) ListTransactions(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransactions", reflect.TypeOf((*MockQuerier)(nil).ListTransactions), ctx, arg)
+}
+
+// ListUsers mocks base method.
+func (m *MockQuerier) ListUsers(ctx context.Context, arg models.ListUsersParams) ([]*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, arg)
+	ret0, _ := ret[0].([]*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockQuerierMockRecorder) ListUsers(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockQuerier)(nil).ListUsers), ctx, arg)
+}
+
+// LockDocumentNumber mocks base method.
+func (m *MockQuerier) LockDocumentNumber(ctx context.Context, documentNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockDocumentNumber", ctx, documentNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockDocumentNumber indicates an expected call of LockDocumentNumber.
+func (mr *MockQuerierMockRecorder) LockDocumentNumber(ctx, documentNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockDocumentNumber", reflect.TypeOf((*MockQuerier)(nil).LockDocumentNumber), ctx, documentNumber)
+}
+
+// RecordRewardPayoutFailure mocks base method.
+func (m *MockQuerier) RecordRewardPayoutFailure(ctx context.Context, arg models.RecordRewardPayoutFailureParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordRewardPayoutFailure", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordRewardPayoutFailure indicates an expected call of RecordRewardPayoutFailure.
+func (mr *MockQuerierMockRecorder) RecordRewardPayoutFailure(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordRewardPayoutFailure", reflect.TypeOf((*MockQuerier)(nil).RecordRewardPayoutFailure), ctx, arg)
+}
+
+// ResolveDispute mocks base method.
+func (m *MockQuerier) ResolveDispute(ctx context.Context, arg models.ResolveDisputeParams) (*models.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveDispute", ctx, arg)
+	ret0, _ := ret[0].(*models.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveDispute indicates an expected call of ResolveDispute.
+func (mr *MockQuerierMockRecorder) ResolveDispute(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveDispute", reflect.TypeOf((*MockQuerier)(nil).ResolveDispute), ctx, arg)
+}
+
+// ScheduledTransactionRunExists mocks base method.
+func (m *MockQuerier) ScheduledTransactionRunExists(ctx context.Context, arg models.ScheduledTransactionRunExistsParams) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScheduledTransactionRunExists", ctx, arg)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScheduledTransactionRunExists indicates an expected call of ScheduledTransactionRunExists.
+func (mr *MockQuerierMockRecorder) ScheduledTransactionRunExists(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScheduledTransactionRunExists", reflect.TypeOf((*MockQuerier)(nil).ScheduledTransactionRunExists), ctx, arg)
+}
+
+// SetDisputeProvisionalCredit mocks base method.
+func (m *MockQuerier) SetDisputeProvisionalCredit(ctx context.Context, arg models.SetDisputeProvisionalCreditParams) (*models.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDisputeProvisionalCredit", ctx, arg)
+	ret0, _ := ret[0].(*models.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDisputeProvisionalCredit indicates an expected call of SetDisputeProvisionalCredit.
+func (mr *MockQuerierMockRecorder) SetDisputeProvisionalCredit(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDisputeProvisionalCredit", reflect.TypeOf((*MockQuerier)(nil).SetDisputeProvisionalCredit), ctx, arg)
+}
+
+// SetRewardAccrualsPayout mocks base method.
+func (m *MockQuerier) SetRewardAccrualsPayout(ctx context.Context, arg models.SetRewardAccrualsPayoutParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRewardAccrualsPayout", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRewardAccrualsPayout indicates an expected call of SetRewardAccrualsPayout.
+func (mr *MockQuerierMockRecorder) SetRewardAccrualsPayout(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRewardAccrualsPayout", reflect.TypeOf((*MockQuerier)(nil).SetRewardAccrualsPayout), ctx, arg)
+}
+
+// TouchAPIKey mocks base method.
+func (m *MockQuerier) TouchAPIKey(ctx context.Context, uuid string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TouchAPIKey", ctx, uuid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TouchAPIKey indicates an expected call of TouchAPIKey.
+func (mr *MockQuerierMockRecorder) TouchAPIKey(ctx, uuid interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TouchAPIKey", reflect.TypeOf((*MockQuerier)(nil).TouchAPIKey), ctx, uuid)
+}
+
+// UpdateAccountStatus mocks base method.
+func (m *MockQuerier) UpdateAccountStatus(ctx context.Context, arg models.UpdateAccountStatusParams) (*models.Account, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAccountStatus", ctx, arg)
+	ret0, _ := ret[0].(*models.Account)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateAccountStatus indicates an expected call of UpdateAccountStatus.
+func (mr *MockQuerierMockRecorder) UpdateAccountStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAccountStatus", reflect.TypeOf((*MockQuerier)(nil).UpdateAccountStatus), ctx, arg)
+}
+
+// UpdateDisputeStatus mocks base method.
+func (m *MockQuerier) UpdateDisputeStatus(ctx context.Context, arg models.UpdateDisputeStatusParams) (*models.Dispute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDisputeStatus", ctx, arg)
+	ret0, _ := ret[0].(*models.Dispute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateDisputeStatus indicates an expected call of UpdateDisputeStatus.
+func (mr *MockQuerierMockRecorder) UpdateDisputeStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDisputeStatus", reflect.TypeOf((*MockQuerier)(nil).UpdateDisputeStatus), ctx, arg)
+}
+
+// UpdateScheduledTransactionStatus mocks base method.
+func (m *MockQuerier) UpdateScheduledTransactionStatus(ctx context.Context, arg models.UpdateScheduledTransactionStatusParams) (*models.ScheduledTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateScheduledTransactionStatus", ctx, arg)
+	ret0, _ := ret[0].(*models.ScheduledTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateScheduledTransactionStatus indicates an expected call of UpdateScheduledTransactionStatus.
+func (mr *MockQuerierMockRecorder) UpdateScheduledTransactionStatus(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateScheduledTransactionStatus", reflect.TypeOf((*MockQuerier)(nil).UpdateScheduledTransactionStatus), ctx, arg)
+}
+
+// UpdateTransactionAnnotations mocks base method.
+func (m *MockQuerier) UpdateTransactionAnnotations(ctx context.Context, arg models.UpdateTransactionAnnotationsParams) (*models.UpdateTransactionAnnotationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransactionAnnotations", ctx, arg)
+	ret0, _ := ret[0].(*models.UpdateTransactionAnnotationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTransactionAnnotations indicates an expected call of UpdateTransactionAnnotations.
+func (mr *MockQuerierMockRecorder) UpdateTransactionAnnotations(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionAnnotations", reflect.TypeOf((*MockQuerier)(nil).UpdateTransactionAnnotations), ctx, arg)
+}
+
 // UpdateTransactionBalances mocks base method.
 func (m *MockQuerier) UpdateTransactionBalances(ctx context.Context, arg models.UpdateTransactionBalancesParams) error {
 	m.ctrl.T.Helper()
@@ -154,6 +1017,21 @@ func (mr *MockQuerierMockRecorder) UpdateTransactionBalances(ctx, arg interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransactionBalances", reflect.TypeOf((*MockQuerier)(nil).UpdateTransactionBalances), ctx, arg)
 }
 
+// UpdateUser mocks base method.
+func (m *MockQuerier) UpdateUser(ctx context.Context, arg models.UpdateUserParams) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, arg)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockQuerierMockRecorder) UpdateUser(ctx, arg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockQuerier)(nil).UpdateUser), ctx, arg)
+}
+
 // UserExists mocks base method.
 func (m *MockQuerier) UserExists(ctx context.Context, uuid string) (bool, error) {
 	m.ctrl.T.Helper()
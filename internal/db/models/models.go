@@ -7,10 +7,55 @@ package models
 import (
 	"database/sql"
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"time"
 )
 
+type AccountStatus string
+
+const (
+	AccountStatusACTIVE    AccountStatus = "ACTIVE"
+	AccountStatusBLOCKED   AccountStatus = "BLOCKED"
+	AccountStatusSUSPENDED AccountStatus = "SUSPENDED"
+	AccountStatusCLOSED    AccountStatus = "CLOSED"
+)
+
+func (e *AccountStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = AccountStatus(s)
+	case string:
+		*e = AccountStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for AccountStatus: %T", src)
+	}
+	return nil
+}
+
+type NullAccountStatus struct {
+	AccountStatus AccountStatus
+	Valid         bool // Valid is true if AccountStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullAccountStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.AccountStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.AccountStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullAccountStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.AccountStatus, nil
+}
+
 type AmountBehavior string
 
 const (
@@ -53,6 +98,353 @@ func (ns NullAmountBehavior) Value() (driver.Value, error) {
 	return ns.AmountBehavior, nil
 }
 
+type DisputeEventType string
+
+const (
+	DisputeEventTypeOPENED                     DisputeEventType = "OPENED"
+	DisputeEventTypePROVISIONALCREDITPOSTED    DisputeEventType = "PROVISIONAL_CREDIT_POSTED"
+	DisputeEventTypeUNDERREVIEW                DisputeEventType = "UNDER_REVIEW"
+	DisputeEventTypeCREDITPOSTED               DisputeEventType = "CREDIT_POSTED"
+	DisputeEventTypePROVISIONALCREDITFINALIZED DisputeEventType = "PROVISIONAL_CREDIT_FINALIZED"
+	DisputeEventTypePROVISIONALCREDITREVERSED  DisputeEventType = "PROVISIONAL_CREDIT_REVERSED"
+	DisputeEventTypeWON                        DisputeEventType = "WON"
+	DisputeEventTypeLOST                       DisputeEventType = "LOST"
+)
+
+func (e *DisputeEventType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DisputeEventType(s)
+	case string:
+		*e = DisputeEventType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DisputeEventType: %T", src)
+	}
+	return nil
+}
+
+type NullDisputeEventType struct {
+	DisputeEventType DisputeEventType
+	Valid            bool // Valid is true if DisputeEventType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDisputeEventType) Scan(value interface{}) error {
+	if value == nil {
+		ns.DisputeEventType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DisputeEventType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDisputeEventType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.DisputeEventType, nil
+}
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOPENED      DisputeStatus = "OPENED"
+	DisputeStatusUNDERREVIEW DisputeStatus = "UNDER_REVIEW"
+	DisputeStatusWON         DisputeStatus = "WON"
+	DisputeStatusLOST        DisputeStatus = "LOST"
+)
+
+func (e *DisputeStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = DisputeStatus(s)
+	case string:
+		*e = DisputeStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for DisputeStatus: %T", src)
+	}
+	return nil
+}
+
+type NullDisputeStatus struct {
+	DisputeStatus DisputeStatus
+	Valid         bool // Valid is true if DisputeStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullDisputeStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.DisputeStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.DisputeStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullDisputeStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.DisputeStatus, nil
+}
+
+type LimitPeriod string
+
+const (
+	LimitPeriodDAILY   LimitPeriod = "DAILY"
+	LimitPeriodWEEKLY  LimitPeriod = "WEEKLY"
+	LimitPeriodMONTHLY LimitPeriod = "MONTHLY"
+)
+
+func (e *LimitPeriod) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = LimitPeriod(s)
+	case string:
+		*e = LimitPeriod(s)
+	default:
+		return fmt.Errorf("unsupported scan type for LimitPeriod: %T", src)
+	}
+	return nil
+}
+
+type NullLimitPeriod struct {
+	LimitPeriod LimitPeriod
+	Valid       bool // Valid is true if LimitPeriod is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullLimitPeriod) Scan(value interface{}) error {
+	if value == nil {
+		ns.LimitPeriod, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.LimitPeriod.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullLimitPeriod) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.LimitPeriod, nil
+}
+
+type RecurrenceType string
+
+const (
+	RecurrenceTypeCRON  RecurrenceType = "CRON"
+	RecurrenceTypeRRULE RecurrenceType = "RRULE"
+)
+
+func (e *RecurrenceType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RecurrenceType(s)
+	case string:
+		*e = RecurrenceType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RecurrenceType: %T", src)
+	}
+	return nil
+}
+
+type NullRecurrenceType struct {
+	RecurrenceType RecurrenceType
+	Valid          bool // Valid is true if RecurrenceType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRecurrenceType) Scan(value interface{}) error {
+	if value == nil {
+		ns.RecurrenceType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RecurrenceType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRecurrenceType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.RecurrenceType, nil
+}
+
+type RewardKind string
+
+const (
+	RewardKindCASHBACK RewardKind = "CASHBACK"
+	RewardKindPOINTS   RewardKind = "POINTS"
+)
+
+func (e *RewardKind) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RewardKind(s)
+	case string:
+		*e = RewardKind(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RewardKind: %T", src)
+	}
+	return nil
+}
+
+type NullRewardKind struct {
+	RewardKind RewardKind
+	Valid      bool // Valid is true if RewardKind is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRewardKind) Scan(value interface{}) error {
+	if value == nil {
+		ns.RewardKind, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RewardKind.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRewardKind) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.RewardKind, nil
+}
+
+type RiskOutcome string
+
+const (
+	RiskOutcomeAPPROVED RiskOutcome = "APPROVED"
+	RiskOutcomeDECLINED RiskOutcome = "DECLINED"
+)
+
+func (e *RiskOutcome) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = RiskOutcome(s)
+	case string:
+		*e = RiskOutcome(s)
+	default:
+		return fmt.Errorf("unsupported scan type for RiskOutcome: %T", src)
+	}
+	return nil
+}
+
+type NullRiskOutcome struct {
+	RiskOutcome RiskOutcome
+	Valid       bool // Valid is true if RiskOutcome is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullRiskOutcome) Scan(value interface{}) error {
+	if value == nil {
+		ns.RiskOutcome, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.RiskOutcome.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullRiskOutcome) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.RiskOutcome, nil
+}
+
+type ScheduleRunStatus string
+
+const (
+	ScheduleRunStatusPOSTED ScheduleRunStatus = "POSTED"
+	ScheduleRunStatusFAILED ScheduleRunStatus = "FAILED"
+)
+
+func (e *ScheduleRunStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduleRunStatus(s)
+	case string:
+		*e = ScheduleRunStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduleRunStatus: %T", src)
+	}
+	return nil
+}
+
+type NullScheduleRunStatus struct {
+	ScheduleRunStatus ScheduleRunStatus
+	Valid             bool // Valid is true if ScheduleRunStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduleRunStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduleRunStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduleRunStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduleRunStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.ScheduleRunStatus, nil
+}
+
+type ScheduleStatus string
+
+const (
+	ScheduleStatusACTIVE    ScheduleStatus = "ACTIVE"
+	ScheduleStatusPAUSED    ScheduleStatus = "PAUSED"
+	ScheduleStatusCANCELLED ScheduleStatus = "CANCELLED"
+	ScheduleStatusCOMPLETED ScheduleStatus = "COMPLETED"
+)
+
+func (e *ScheduleStatus) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = ScheduleStatus(s)
+	case string:
+		*e = ScheduleStatus(s)
+	default:
+		return fmt.Errorf("unsupported scan type for ScheduleStatus: %T", src)
+	}
+	return nil
+}
+
+type NullScheduleStatus struct {
+	ScheduleStatus ScheduleStatus
+	Valid          bool // Valid is true if ScheduleStatus is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullScheduleStatus) Scan(value interface{}) error {
+	if value == nil {
+		ns.ScheduleStatus, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.ScheduleStatus.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullScheduleStatus) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return ns.ScheduleStatus, nil
+}
+
 type TransactionType string
 
 const (
@@ -98,13 +490,99 @@ func (ns NullTransactionType) Value() (driver.Value, error) {
 }
 
 type Account struct {
-	Uuid           string    `db:"uuid" json:"uuid"`
-	SerialID       int64     `db:"serial_id" json:"serial_id"`
-	DocumentNumber string    `db:"document_number" json:"document_number"`
-	CurrentBalance float64   `db:"current_balance" json:"current_balance"`
-	UserID         string    `db:"user_id" json:"user_id"`
-	CreatedAt      time.Time `db:"created_at" json:"created_at"`
-	UpdatedAt      time.Time `db:"updated_at" json:"updated_at"`
+	Uuid           string        `db:"uuid" json:"uuid"`
+	SerialID       int64         `db:"serial_id" json:"serial_id"`
+	DocumentNumber string        `db:"document_number" json:"document_number"`
+	CurrentBalance float64       `db:"current_balance" json:"current_balance"`
+	UserID         string        `db:"user_id" json:"user_id"`
+	CreatedAt      time.Time     `db:"created_at" json:"created_at"`
+	UpdatedAt      time.Time     `db:"updated_at" json:"updated_at"`
+	Status         AccountStatus `db:"status" json:"status"`
+}
+
+type AccountDocument struct {
+	AccountID          string    `db:"account_id" json:"account_id"`
+	DocumentNumber     string    `db:"document_number" json:"document_number"`
+	ScopeKey           string    `db:"scope_key" json:"scope_key"`
+	CreatedAt          time.Time `db:"created_at" json:"created_at"`
+	SharedBeforeGlobal bool      `db:"shared_before_global" json:"shared_before_global"`
+}
+
+type AccountLimit struct {
+	Uuid            string      `db:"uuid" json:"uuid"`
+	SerialID        int64       `db:"serial_id" json:"serial_id"`
+	AccountID       string      `db:"account_id" json:"account_id"`
+	OperationTypeID int64       `db:"operation_type_id" json:"operation_type_id"`
+	Period          LimitPeriod `db:"period" json:"period"`
+	MaxAmount       float64     `db:"max_amount" json:"max_amount"`
+	CreatedAt       time.Time   `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+type AccountLimitUsage struct {
+	AccountID       string      `db:"account_id" json:"account_id"`
+	OperationTypeID int64       `db:"operation_type_id" json:"operation_type_id"`
+	Period          LimitPeriod `db:"period" json:"period"`
+	PeriodStart     time.Time   `db:"period_start" json:"period_start"`
+	UsedAmount      float64     `db:"used_amount" json:"used_amount"`
+	UpdatedAt       time.Time   `db:"updated_at" json:"updated_at"`
+}
+
+type AccountStatusHistory struct {
+	Uuid       string         `db:"uuid" json:"uuid"`
+	SerialID   int64          `db:"serial_id" json:"serial_id"`
+	AccountID  string         `db:"account_id" json:"account_id"`
+	FromStatus AccountStatus  `db:"from_status" json:"from_status"`
+	ToStatus   AccountStatus  `db:"to_status" json:"to_status"`
+	ReasonCode string         `db:"reason_code" json:"reason_code"`
+	Note       sql.NullString `db:"note" json:"note"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+}
+
+type ApiKey struct {
+	Uuid       string         `db:"uuid" json:"uuid"`
+	SerialID   int64          `db:"serial_id" json:"serial_id"`
+	Name       string         `db:"name" json:"name"`
+	KeyHash    string         `db:"key_hash" json:"key_hash"`
+	UserID     sql.NullString `db:"user_id" json:"user_id"`
+	ExpiresAt  sql.NullTime   `db:"expires_at" json:"expires_at"`
+	RevokedAt  sql.NullTime   `db:"revoked_at" json:"revoked_at"`
+	LastUsedAt sql.NullTime   `db:"last_used_at" json:"last_used_at"`
+	CreatedAt  time.Time      `db:"created_at" json:"created_at"`
+	Scopes     []string       `db:"scopes" json:"scopes"`
+}
+
+type DailySpendRollup struct {
+	AccountID       string    `db:"account_id" json:"account_id"`
+	Day             time.Time `db:"day" json:"day"`
+	OperationTypeID int64     `db:"operation_type_id" json:"operation_type_id"`
+	Category        string    `db:"category" json:"category"`
+	TxnCount        int64     `db:"txn_count" json:"txn_count"`
+	TotalAmount     float64   `db:"total_amount" json:"total_amount"`
+}
+
+type Dispute struct {
+	Uuid                           string         `db:"uuid" json:"uuid"`
+	SerialID                       int64          `db:"serial_id" json:"serial_id"`
+	TransactionID                  string         `db:"transaction_id" json:"transaction_id"`
+	AccountID                      string         `db:"account_id" json:"account_id"`
+	Amount                         float64        `db:"amount" json:"amount"`
+	ReasonCode                     string         `db:"reason_code" json:"reason_code"`
+	Status                         DisputeStatus  `db:"status" json:"status"`
+	ProvisionalCreditTransactionID sql.NullString `db:"provisional_credit_transaction_id" json:"provisional_credit_transaction_id"`
+	ResolutionTransactionID        sql.NullString `db:"resolution_transaction_id" json:"resolution_transaction_id"`
+	ResolvedAt                     sql.NullTime   `db:"resolved_at" json:"resolved_at"`
+	CreatedAt                      time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt                      time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type DisputeEvent struct {
+	SerialID      int64            `db:"serial_id" json:"serial_id"`
+	DisputeID     string           `db:"dispute_id" json:"dispute_id"`
+	Type          DisputeEventType `db:"type" json:"type"`
+	TransactionID sql.NullString   `db:"transaction_id" json:"transaction_id"`
+	Note          sql.NullString   `db:"note" json:"note"`
+	CreatedAt     time.Time        `db:"created_at" json:"created_at"`
 }
 
 type OperationType struct {
@@ -116,15 +594,89 @@ type OperationType struct {
 	UpdatedAt      time.Time       `db:"updated_at" json:"updated_at"`
 }
 
+type RateLimitCounter struct {
+	Key         string    `db:"key" json:"key"`
+	WindowStart time.Time `db:"window_start" json:"window_start"`
+	WindowEnd   time.Time `db:"window_end" json:"window_end"`
+	Count       int32     `db:"count" json:"count"`
+}
+
+type RewardAccrual struct {
+	Uuid                string         `db:"uuid" json:"uuid"`
+	SerialID            int64          `db:"serial_id" json:"serial_id"`
+	AccountID           string         `db:"account_id" json:"account_id"`
+	TransactionID       string         `db:"transaction_id" json:"transaction_id"`
+	RuleName            string         `db:"rule_name" json:"rule_name"`
+	Kind                RewardKind     `db:"kind" json:"kind"`
+	Amount              float64        `db:"amount" json:"amount"`
+	PayoutTransactionID sql.NullString `db:"payout_transaction_id" json:"payout_transaction_id"`
+	CreatedAt           time.Time      `db:"created_at" json:"created_at"`
+}
+
+type RewardPayoutFailure struct {
+	AccountID string    `db:"account_id" json:"account_id"`
+	Failures  int32     `db:"failures" json:"failures"`
+	FailedAt  time.Time `db:"failed_at" json:"failed_at"`
+}
+
+type RiskDecision struct {
+	Uuid            string         `db:"uuid" json:"uuid"`
+	SerialID        int64          `db:"serial_id" json:"serial_id"`
+	AccountID       string         `db:"account_id" json:"account_id"`
+	OperationTypeID int64          `db:"operation_type_id" json:"operation_type_id"`
+	Amount          float64        `db:"amount" json:"amount"`
+	MerchantID      sql.NullString `db:"merchant_id" json:"merchant_id"`
+	MerchantCountry sql.NullString `db:"merchant_country" json:"merchant_country"`
+	Outcome         RiskOutcome    `db:"outcome" json:"outcome"`
+	ReasonCode      sql.NullString `db:"reason_code" json:"reason_code"`
+	RuleName        sql.NullString `db:"rule_name" json:"rule_name"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+}
+
+type ScheduledTransaction struct {
+	Uuid            string         `db:"uuid" json:"uuid"`
+	SerialID        int64          `db:"serial_id" json:"serial_id"`
+	AccountID       string         `db:"account_id" json:"account_id"`
+	OperationTypeID int64          `db:"operation_type_id" json:"operation_type_id"`
+	Amount          float64        `db:"amount" json:"amount"`
+	RecurrenceType  RecurrenceType `db:"recurrence_type" json:"recurrence_type"`
+	Recurrence      string         `db:"recurrence" json:"recurrence"`
+	StartsAt        time.Time      `db:"starts_at" json:"starts_at"`
+	EndsAt          sql.NullTime   `db:"ends_at" json:"ends_at"`
+	NextRunAt       sql.NullTime   `db:"next_run_at" json:"next_run_at"`
+	Status          ScheduleStatus `db:"status" json:"status"`
+	CreatedAt       time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+type ScheduledTransactionRun struct {
+	Uuid          string            `db:"uuid" json:"uuid"`
+	SerialID      int64             `db:"serial_id" json:"serial_id"`
+	ScheduleID    string            `db:"schedule_id" json:"schedule_id"`
+	ScheduledFor  time.Time         `db:"scheduled_for" json:"scheduled_for"`
+	Status        ScheduleRunStatus `db:"status" json:"status"`
+	TransactionID sql.NullString    `db:"transaction_id" json:"transaction_id"`
+	Error         sql.NullString    `db:"error" json:"error"`
+	CreatedAt     time.Time         `db:"created_at" json:"created_at"`
+}
+
 type Transaction struct {
-	Uuid            string    `db:"uuid" json:"uuid"`
-	SerialID        int64     `db:"serial_id" json:"serial_id"`
-	AccountID       string    `db:"account_id" json:"account_id"`
-	Amount          float64   `db:"amount" json:"amount"`
-	OperationTypeID int64     `db:"operation_type_id" json:"operation_type_id"`
-	EventDate       time.Time `db:"event_date" json:"event_date"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
-	Balance         float64   `db:"balance" json:"balance"`
+	Uuid            string          `db:"uuid" json:"uuid"`
+	SerialID        int64           `db:"serial_id" json:"serial_id"`
+	AccountID       string          `db:"account_id" json:"account_id"`
+	Amount          float64         `db:"amount" json:"amount"`
+	OperationTypeID int64           `db:"operation_type_id" json:"operation_type_id"`
+	EventDate       time.Time       `db:"event_date" json:"event_date"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+	Balance         float64         `db:"balance" json:"balance"`
+	MerchantID      sql.NullString  `db:"merchant_id" json:"merchant_id"`
+	MerchantCountry sql.NullString  `db:"merchant_country" json:"merchant_country"`
+	ReversalOf      sql.NullString  `db:"reversal_of" json:"reversal_of"`
+	Mcc             sql.NullString  `db:"mcc" json:"mcc"`
+	Metadata        json.RawMessage `db:"metadata" json:"metadata"`
+	Tags            []string        `db:"tags" json:"tags"`
+	Notes           string          `db:"notes" json:"notes"`
+	CreatedAt       time.Time       `db:"created_at" json:"created_at"`
 }
 
 type User struct {
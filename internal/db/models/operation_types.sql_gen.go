@@ -19,3 +19,14 @@ func (q *Queries) GetOperationTypeAmountBehavior(ctx context.Context, serialID i
 	err := row.Scan(&amount_behavior)
 	return amount_behavior, err
 }
+
+const getOperationTypeIDByDescription = `-- name: GetOperationTypeIDByDescription :one
+SELECT serial_id FROM public.operation_types WHERE description = $1 ORDER BY serial_id LIMIT 1
+`
+
+func (q *Queries) GetOperationTypeIDByDescription(ctx context.Context, description TransactionType) (int64, error) {
+	row := q.db.QueryRow(ctx, getOperationTypeIDByDescription, description)
+	var serial_id int64
+	err := row.Scan(&serial_id)
+	return serial_id, err
+}
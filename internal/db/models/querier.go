@@ -6,17 +6,106 @@ package models
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
 	AccountExists(ctx context.Context, uuid string) (bool, error)
+	CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (*CreateAPIKeyRow, error)
 	CreateAccount(ctx context.Context, arg CreateAccountParams) (*Account, error)
+	CreateAccountDocument(ctx context.Context, arg CreateAccountDocumentParams) error
+	CreateAccountLimit(ctx context.Context, arg CreateAccountLimitParams) (*AccountLimit, error)
+	CreateAccountStatusHistory(ctx context.Context, arg CreateAccountStatusHistoryParams) (*AccountStatusHistory, error)
+	CreateDispute(ctx context.Context, arg CreateDisputeParams) (*Dispute, error)
+	CreateDisputeEvent(ctx context.Context, arg CreateDisputeEventParams) (*DisputeEvent, error)
+	CreateReversalTransaction(ctx context.Context, arg CreateReversalTransactionParams) (*CreateReversalTransactionRow, error)
+	CreateRewardAccrual(ctx context.Context, arg CreateRewardAccrualParams) error
+	CreateRiskDecision(ctx context.Context, arg CreateRiskDecisionParams) error
+	CreateScheduledTransaction(ctx context.Context, arg CreateScheduledTransactionParams) (*ScheduledTransaction, error)
+	// Returns no rows when the occurrence was already posted
+	CreateScheduledTransactionRun(ctx context.Context, arg CreateScheduledTransactionRunParams) (*ScheduledTransactionRun, error)
 	CreateTransaction(ctx context.Context, arg CreateTransactionParams) (*CreateTransactionRow, error)
+	CreateUser(ctx context.Context, arg CreateUserParams) (*User, error)
+	DeleteAccountLimits(ctx context.Context, accountID string) error
+	DeleteExpiredRateLimitCounters(ctx context.Context, before time.Time) (int64, error)
+	DeleteRewardPayoutFailure(ctx context.Context, accountID string) error
+	// An empty user_id looks for the document across all the users, the GLOBAL scope, otherwise in the accounts of the user
+	DocumentNumberExists(ctx context.Context, arg DocumentNumberExistsParams) (bool, error)
+	GetAPIKeyByHash(ctx context.Context, keyHash string) (*GetAPIKeyByHashRow, error)
 	GetAccountDetailsByUUID(ctx context.Context, uuid string) (*Account, error)
+	GetAccountLimitsByOperationType(ctx context.Context, arg GetAccountLimitsByOperationTypeParams) ([]*AccountLimit, error)
+	GetAccountLimitsWithUsage(ctx context.Context, arg GetAccountLimitsWithUsageParams) ([]*GetAccountLimitsWithUsageRow, error)
+	GetAccountOwner(ctx context.Context, uuid string) (string, error)
+	GetAccountStatus(ctx context.Context, uuid string) (AccountStatus, error)
+	GetAccountStatusForUpdate(ctx context.Context, uuid string) (AccountStatus, error)
+	// The summary of the account in a single round trip, no row is returned when the account doesn't exist.
+	// The billing cycle is the calendar month in UTC, it starts at monthly_start and ends at cycle_end.
+	// Pending holds are the amounts committed but not posted yet: the disputes in progress without a provisional credit
+	// and the next run of the active schedules that is due in the billing cycle
+	GetAccountSummary(ctx context.Context, arg GetAccountSummaryParams) (*GetAccountSummaryRow, error)
+	// Only the transactions of the operation types are counted, all of them when operation_type_ids is empty.
+	// The reversals are not counted
+	GetAccountTransactionAmountStats(ctx context.Context, arg GetAccountTransactionAmountStatsParams) (*GetAccountTransactionAmountStatsRow, error)
+	// The window is on the creation time, the event date is set by the client. Only the transactions of the operation
+	// types are counted, all of them when operation_type_ids is empty. The reversals are not counted
+	GetAccountTransactionVelocity(ctx context.Context, arg GetAccountTransactionVelocityParams) (*GetAccountTransactionVelocityRow, error)
+	GetAccountsByUserID(ctx context.Context, userID string) ([]*Account, error)
+	// Closed accounts can't be credited, their cashback stays pending. The accounts whose payout failed after retry_before
+	// are skipped, and the accounts with the oldest pending cashback are paid first
+	GetAccountsWithPendingCashback(ctx context.Context, arg GetAccountsWithPendingCashbackParams) ([]string, error)
+	GetDispute(ctx context.Context, uuid string) (*Dispute, error)
+	GetDisputeEvents(ctx context.Context, disputeID string) ([]*DisputeEvent, error)
+	GetDisputeForUpdate(ctx context.Context, uuid string) (*Dispute, error)
+	GetDisputeOwner(ctx context.Context, uuid string) (string, error)
 	GetNegativeBalanceTransactionsByAccountID(ctx context.Context, accountID string) ([]*GetNegativeBalanceTransactionsByAccountIDRow, error)
+	// Schedules locked by another scheduler are skipped, so several instances of the service can post in parallel
+	GetNextDueScheduledTransaction(ctx context.Context, nextRunAt sql.NullTime) (*ScheduledTransaction, error)
 	GetOperationTypeAmountBehavior(ctx context.Context, serialID int64) (AmountBehavior, error)
+	GetOperationTypeIDByDescription(ctx context.Context, description TransactionType) (int64, error)
+	// Concurrent payouts of the same account wait for each other, the second one doesn't see the accruals paid by the first
+	GetPendingCashbackAccrualsForUpdate(ctx context.Context, accountID string) ([]*GetPendingCashbackAccrualsForUpdateRow, error)
+	// The amount of the transaction already reversed. A reversal that was reversed itself, e.g. a clawed back
+	// provisional credit, doesn't count
+	GetReversedAmount(ctx context.Context, transactionID string) (float64, error)
+	GetRewardAccrualTotalsByTransactionID(ctx context.Context, transactionID string) ([]*GetRewardAccrualTotalsByTransactionIDRow, error)
+	GetRewardsBalance(ctx context.Context, accountID string) ([]*GetRewardsBalanceRow, error)
+	GetScheduledTransactionForUpdate(ctx context.Context, arg GetScheduledTransactionForUpdateParams) (*ScheduledTransaction, error)
+	GetScheduledTransactionsByAccountID(ctx context.Context, accountID string) ([]*ScheduledTransaction, error)
+	// Aggregates the daily spend rollups of the account, or of all the accounts when the account is null, grouped by
+	// the operation type, the month (YYYY-MM) or the category of the spend
+	GetSpendAnalytics(ctx context.Context, arg GetSpendAnalyticsParams) ([]*GetSpendAnalyticsRow, error)
 	GetTransactionDetailsByTransactionId(ctx context.Context, uuid string) (*GetTransactionDetailsByTransactionIdRow, error)
+	GetTransactionForUpdate(ctx context.Context, uuid string) (*GetTransactionForUpdateRow, error)
+	GetTransactionOwner(ctx context.Context, uuid string) (string, error)
+	GetUserByUUID(ctx context.Context, uuid string) (*User, error)
+	// Adds the amount to the counter of the period, only if the counter stays within max_amount.
+	// No row is returned when the limit would be breached.
+	IncrementAccountLimitUsage(ctx context.Context, arg IncrementAccountLimitUsageParams) (float64, error)
+	IncrementRateLimitCounter(ctx context.Context, arg IncrementRateLimitCounterParams) (int32, error)
+	// Keyset pagination on serial_id, pass 0 as after_serial_id to get the first page.
+	// The metadata must contain all the pairs in metadata and all the keys in metadata_keys, and the tags all the tags.
+	// Empty filters match every transaction
+	ListTransactions(ctx context.Context, arg ListTransactionsParams) ([]*ListTransactionsRow, error)
+	// Keyset pagination on serial_id, pass 0 as after_serial_id to get the first page
+	ListUsers(ctx context.Context, arg ListUsersParams) ([]*User, error)
+	// The lock serializes the accounts created with the same document number until the end of the transaction
+	LockDocumentNumber(ctx context.Context, documentNumber string) error
+	RecordRewardPayoutFailure(ctx context.Context, arg RecordRewardPayoutFailureParams) error
+	ResolveDispute(ctx context.Context, arg ResolveDisputeParams) (*Dispute, error)
+	ScheduledTransactionRunExists(ctx context.Context, arg ScheduledTransactionRunExistsParams) (bool, error)
+	SetDisputeProvisionalCredit(ctx context.Context, arg SetDisputeProvisionalCreditParams) (*Dispute, error)
+	SetRewardAccrualsPayout(ctx context.Context, arg SetRewardAccrualsPayoutParams) error
+	// last_used_at is only updated once a minute, so that the keys used by every request are not written every time
+	TouchAPIKey(ctx context.Context, uuid string) error
+	UpdateAccountStatus(ctx context.Context, arg UpdateAccountStatusParams) (*Account, error)
+	UpdateDisputeStatus(ctx context.Context, arg UpdateDisputeStatusParams) (*Dispute, error)
+	UpdateScheduledTransactionStatus(ctx context.Context, arg UpdateScheduledTransactionStatusParams) (*ScheduledTransaction, error)
+	// Only the tags and the notes of a transaction can be updated, the fields that are null are left unchanged
+	UpdateTransactionAnnotations(ctx context.Context, arg UpdateTransactionAnnotationsParams) (*UpdateTransactionAnnotationsRow, error)
 	UpdateTransactionBalances(ctx context.Context, arg UpdateTransactionBalancesParams) error
+	// Only the fields that are not null are updated
+	UpdateUser(ctx context.Context, arg UpdateUserParams) (*User, error)
 	UserExists(ctx context.Context, uuid string) (bool, error)
 }
 
@@ -0,0 +1,45 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: rate_limits.sql
+
+package models
+
+import (
+	"context"
+	"time"
+)
+
+const deleteExpiredRateLimitCounters = `-- name: DeleteExpiredRateLimitCounters :execrows
+DELETE
+FROM public.rate_limit_counters
+WHERE window_end < $1
+`
+
+func (q *Queries) DeleteExpiredRateLimitCounters(ctx context.Context, before time.Time) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredRateLimitCounters, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const incrementRateLimitCounter = `-- name: IncrementRateLimitCounter :one
+INSERT INTO public.rate_limit_counters (key, window_start, window_end)
+VALUES ($1, $2, $3)
+ON CONFLICT (key, window_start) DO UPDATE SET count = rate_limit_counters.count + 1
+RETURNING count
+`
+
+type IncrementRateLimitCounterParams struct {
+	Key         string    `db:"key" json:"key"`
+	WindowStart time.Time `db:"window_start" json:"window_start"`
+	WindowEnd   time.Time `db:"window_end" json:"window_end"`
+}
+
+func (q *Queries) IncrementRateLimitCounter(ctx context.Context, arg IncrementRateLimitCounterParams) (int32, error) {
+	row := q.db.QueryRow(ctx, incrementRateLimitCounter, arg.Key, arg.WindowStart, arg.WindowEnd)
+	var count int32
+	err := row.Scan(&count)
+	return count, err
+}
@@ -0,0 +1,220 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: reward_accruals.sql
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createRewardAccrual = `-- name: CreateRewardAccrual :exec
+INSERT INTO public.reward_accruals (account_id, transaction_id, rule_name, kind, amount)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateRewardAccrualParams struct {
+	AccountID     string     `db:"account_id" json:"account_id"`
+	TransactionID string     `db:"transaction_id" json:"transaction_id"`
+	RuleName      string     `db:"rule_name" json:"rule_name"`
+	Kind          RewardKind `db:"kind" json:"kind"`
+	Amount        float64    `db:"amount" json:"amount"`
+}
+
+func (q *Queries) CreateRewardAccrual(ctx context.Context, arg CreateRewardAccrualParams) error {
+	_, err := q.db.Exec(ctx, createRewardAccrual,
+		arg.AccountID,
+		arg.TransactionID,
+		arg.RuleName,
+		arg.Kind,
+		arg.Amount,
+	)
+	return err
+}
+
+const deleteRewardPayoutFailure = `-- name: DeleteRewardPayoutFailure :exec
+DELETE FROM public.reward_payout_failures WHERE account_id = $1
+`
+
+func (q *Queries) DeleteRewardPayoutFailure(ctx context.Context, accountID string) error {
+	_, err := q.db.Exec(ctx, deleteRewardPayoutFailure, accountID)
+	return err
+}
+
+const getAccountsWithPendingCashback = `-- name: GetAccountsWithPendingCashback :many
+SELECT ra.account_id
+FROM public.reward_accruals ra
+         JOIN public.accounts a ON a.uuid = ra.account_id
+         LEFT JOIN public.reward_payout_failures f ON f.account_id = ra.account_id
+WHERE ra.kind = 'CASHBACK' AND ra.payout_transaction_id IS NULL AND a.status <> 'CLOSED'
+  AND (f.failed_at IS NULL OR f.failed_at <= $1)
+GROUP BY ra.account_id
+HAVING SUM(ra.amount) > 0
+ORDER BY MIN(ra.serial_id)
+LIMIT $2
+`
+
+type GetAccountsWithPendingCashbackParams struct {
+	RetryBefore time.Time `db:"retry_before" json:"retry_before"`
+	BatchSize   int32     `db:"batch_size" json:"batch_size"`
+}
+
+// Closed accounts can't be credited, their cashback stays pending. The accounts whose payout failed after retry_before
+// are skipped, and the accounts with the oldest pending cashback are paid first
+func (q *Queries) GetAccountsWithPendingCashback(ctx context.Context, arg GetAccountsWithPendingCashbackParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, getAccountsWithPendingCashback, arg.RetryBefore, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var account_id string
+		if err := rows.Scan(&account_id); err != nil {
+			return nil, err
+		}
+		items = append(items, account_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getPendingCashbackAccrualsForUpdate = `-- name: GetPendingCashbackAccrualsForUpdate :many
+SELECT uuid, amount
+FROM public.reward_accruals
+WHERE account_id = $1 AND kind = 'CASHBACK' AND payout_transaction_id IS NULL
+ORDER BY serial_id
+FOR UPDATE
+`
+
+type GetPendingCashbackAccrualsForUpdateRow struct {
+	Uuid   string  `db:"uuid" json:"uuid"`
+	Amount float64 `db:"amount" json:"amount"`
+}
+
+// Concurrent payouts of the same account wait for each other, the second one doesn't see the accruals paid by the first
+func (q *Queries) GetPendingCashbackAccrualsForUpdate(ctx context.Context, accountID string) ([]*GetPendingCashbackAccrualsForUpdateRow, error) {
+	rows, err := q.db.Query(ctx, getPendingCashbackAccrualsForUpdate, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetPendingCashbackAccrualsForUpdateRow
+	for rows.Next() {
+		var i GetPendingCashbackAccrualsForUpdateRow
+		if err := rows.Scan(&i.Uuid, &i.Amount); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRewardAccrualTotalsByTransactionID = `-- name: GetRewardAccrualTotalsByTransactionID :many
+SELECT rule_name, kind, SUM(amount)::FLOAT AS amount
+FROM public.reward_accruals
+WHERE transaction_id = $1
+GROUP BY rule_name, kind
+ORDER BY rule_name, kind
+`
+
+type GetRewardAccrualTotalsByTransactionIDRow struct {
+	RuleName string     `db:"rule_name" json:"rule_name"`
+	Kind     RewardKind `db:"kind" json:"kind"`
+	Amount   float64    `db:"amount" json:"amount"`
+}
+
+func (q *Queries) GetRewardAccrualTotalsByTransactionID(ctx context.Context, transactionID string) ([]*GetRewardAccrualTotalsByTransactionIDRow, error) {
+	rows, err := q.db.Query(ctx, getRewardAccrualTotalsByTransactionID, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetRewardAccrualTotalsByTransactionIDRow
+	for rows.Next() {
+		var i GetRewardAccrualTotalsByTransactionIDRow
+		if err := rows.Scan(&i.RuleName, &i.Kind, &i.Amount); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRewardsBalance = `-- name: GetRewardsBalance :many
+SELECT kind,
+       COALESCE(SUM(amount), 0)::FLOAT                                               AS accrued,
+       COALESCE(SUM(amount) FILTER (WHERE payout_transaction_id IS NOT NULL), 0)::FLOAT AS paid_out
+FROM public.reward_accruals
+WHERE account_id = $1
+GROUP BY kind
+`
+
+type GetRewardsBalanceRow struct {
+	Kind    RewardKind `db:"kind" json:"kind"`
+	Accrued float64    `db:"accrued" json:"accrued"`
+	PaidOut float64    `db:"paid_out" json:"paid_out"`
+}
+
+func (q *Queries) GetRewardsBalance(ctx context.Context, accountID string) ([]*GetRewardsBalanceRow, error) {
+	rows, err := q.db.Query(ctx, getRewardsBalance, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetRewardsBalanceRow
+	for rows.Next() {
+		var i GetRewardsBalanceRow
+		if err := rows.Scan(&i.Kind, &i.Accrued, &i.PaidOut); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const recordRewardPayoutFailure = `-- name: RecordRewardPayoutFailure :exec
+INSERT INTO public.reward_payout_failures (account_id, failed_at)
+VALUES ($1, $2)
+ON CONFLICT (account_id) DO UPDATE SET failures  = reward_payout_failures.failures + 1,
+                                       failed_at = EXCLUDED.failed_at
+`
+
+type RecordRewardPayoutFailureParams struct {
+	AccountID string    `db:"account_id" json:"account_id"`
+	FailedAt  time.Time `db:"failed_at" json:"failed_at"`
+}
+
+func (q *Queries) RecordRewardPayoutFailure(ctx context.Context, arg RecordRewardPayoutFailureParams) error {
+	_, err := q.db.Exec(ctx, recordRewardPayoutFailure, arg.AccountID, arg.FailedAt)
+	return err
+}
+
+const setRewardAccrualsPayout = `-- name: SetRewardAccrualsPayout :exec
+UPDATE public.reward_accruals SET payout_transaction_id = $1
+WHERE uuid = ANY($2::UUID[])
+`
+
+type SetRewardAccrualsPayoutParams struct {
+	PayoutTransactionID sql.NullString `db:"payout_transaction_id" json:"payout_transaction_id"`
+	Uuids               []string       `db:"uuids" json:"uuids"`
+}
+
+func (q *Queries) SetRewardAccrualsPayout(ctx context.Context, arg SetRewardAccrualsPayoutParams) error {
+	_, err := q.db.Exec(ctx, setRewardAccrualsPayout, arg.PayoutTransactionID, arg.Uuids)
+	return err
+}
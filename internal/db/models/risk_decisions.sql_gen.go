@@ -0,0 +1,41 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: risk_decisions.sql
+
+package models
+
+import (
+	"context"
+	"database/sql"
+)
+
+const createRiskDecision = `-- name: CreateRiskDecision :exec
+INSERT INTO public.risk_decisions (account_id, operation_type_id, amount, merchant_id, merchant_country, outcome, reason_code, rule_name)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type CreateRiskDecisionParams struct {
+	AccountID       string         `db:"account_id" json:"account_id"`
+	OperationTypeID int64          `db:"operation_type_id" json:"operation_type_id"`
+	Amount          float64        `db:"amount" json:"amount"`
+	MerchantID      sql.NullString `db:"merchant_id" json:"merchant_id"`
+	MerchantCountry sql.NullString `db:"merchant_country" json:"merchant_country"`
+	Outcome         RiskOutcome    `db:"outcome" json:"outcome"`
+	ReasonCode      sql.NullString `db:"reason_code" json:"reason_code"`
+	RuleName        sql.NullString `db:"rule_name" json:"rule_name"`
+}
+
+func (q *Queries) CreateRiskDecision(ctx context.Context, arg CreateRiskDecisionParams) error {
+	_, err := q.db.Exec(ctx, createRiskDecision,
+		arg.AccountID,
+		arg.OperationTypeID,
+		arg.Amount,
+		arg.MerchantID,
+		arg.MerchantCountry,
+		arg.Outcome,
+		arg.ReasonCode,
+		arg.RuleName,
+	)
+	return err
+}
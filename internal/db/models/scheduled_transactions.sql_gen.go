@@ -0,0 +1,248 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: scheduled_transactions.sql
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const createScheduledTransaction = `-- name: CreateScheduledTransaction :one
+INSERT INTO public.scheduled_transactions (account_id, operation_type_id, amount, recurrence_type, recurrence, starts_at, ends_at, next_run_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING uuid, serial_id, account_id, operation_type_id, amount, recurrence_type, recurrence, starts_at, ends_at, next_run_at, status, created_at, updated_at
+`
+
+type CreateScheduledTransactionParams struct {
+	AccountID       string         `db:"account_id" json:"account_id"`
+	OperationTypeID int64          `db:"operation_type_id" json:"operation_type_id"`
+	Amount          float64        `db:"amount" json:"amount"`
+	RecurrenceType  RecurrenceType `db:"recurrence_type" json:"recurrence_type"`
+	Recurrence      string         `db:"recurrence" json:"recurrence"`
+	StartsAt        time.Time      `db:"starts_at" json:"starts_at"`
+	EndsAt          sql.NullTime   `db:"ends_at" json:"ends_at"`
+	NextRunAt       sql.NullTime   `db:"next_run_at" json:"next_run_at"`
+}
+
+func (q *Queries) CreateScheduledTransaction(ctx context.Context, arg CreateScheduledTransactionParams) (*ScheduledTransaction, error) {
+	row := q.db.QueryRow(ctx, createScheduledTransaction,
+		arg.AccountID,
+		arg.OperationTypeID,
+		arg.Amount,
+		arg.RecurrenceType,
+		arg.Recurrence,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.NextRunAt,
+	)
+	var i ScheduledTransaction
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.AccountID,
+		&i.OperationTypeID,
+		&i.Amount,
+		&i.RecurrenceType,
+		&i.Recurrence,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.NextRunAt,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const createScheduledTransactionRun = `-- name: CreateScheduledTransactionRun :one
+INSERT INTO public.scheduled_transaction_runs (schedule_id, scheduled_for, status, transaction_id, error)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (schedule_id, scheduled_for) DO NOTHING
+RETURNING uuid, serial_id, schedule_id, scheduled_for, status, transaction_id, error, created_at
+`
+
+type CreateScheduledTransactionRunParams struct {
+	ScheduleID    string            `db:"schedule_id" json:"schedule_id"`
+	ScheduledFor  time.Time         `db:"scheduled_for" json:"scheduled_for"`
+	Status        ScheduleRunStatus `db:"status" json:"status"`
+	TransactionID sql.NullString    `db:"transaction_id" json:"transaction_id"`
+	Error         sql.NullString    `db:"error" json:"error"`
+}
+
+// Returns no rows when the occurrence was already posted
+func (q *Queries) CreateScheduledTransactionRun(ctx context.Context, arg CreateScheduledTransactionRunParams) (*ScheduledTransactionRun, error) {
+	row := q.db.QueryRow(ctx, createScheduledTransactionRun,
+		arg.ScheduleID,
+		arg.ScheduledFor,
+		arg.Status,
+		arg.TransactionID,
+		arg.Error,
+	)
+	var i ScheduledTransactionRun
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.ScheduleID,
+		&i.ScheduledFor,
+		&i.Status,
+		&i.TransactionID,
+		&i.Error,
+		&i.CreatedAt,
+	)
+	return &i, err
+}
+
+const getNextDueScheduledTransaction = `-- name: GetNextDueScheduledTransaction :one
+SELECT uuid, serial_id, account_id, operation_type_id, amount, recurrence_type, recurrence, starts_at, ends_at, next_run_at, status, created_at, updated_at FROM public.scheduled_transactions
+WHERE status = 'ACTIVE' AND next_run_at <= $1
+ORDER BY next_run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED
+`
+
+// Schedules locked by another scheduler are skipped, so several instances of the service can post in parallel
+func (q *Queries) GetNextDueScheduledTransaction(ctx context.Context, nextRunAt sql.NullTime) (*ScheduledTransaction, error) {
+	row := q.db.QueryRow(ctx, getNextDueScheduledTransaction, nextRunAt)
+	var i ScheduledTransaction
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.AccountID,
+		&i.OperationTypeID,
+		&i.Amount,
+		&i.RecurrenceType,
+		&i.Recurrence,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.NextRunAt,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getScheduledTransactionForUpdate = `-- name: GetScheduledTransactionForUpdate :one
+SELECT uuid, serial_id, account_id, operation_type_id, amount, recurrence_type, recurrence, starts_at, ends_at, next_run_at, status, created_at, updated_at FROM public.scheduled_transactions
+WHERE uuid = $1 AND account_id = $2
+FOR UPDATE
+`
+
+type GetScheduledTransactionForUpdateParams struct {
+	Uuid      string `db:"uuid" json:"uuid"`
+	AccountID string `db:"account_id" json:"account_id"`
+}
+
+func (q *Queries) GetScheduledTransactionForUpdate(ctx context.Context, arg GetScheduledTransactionForUpdateParams) (*ScheduledTransaction, error) {
+	row := q.db.QueryRow(ctx, getScheduledTransactionForUpdate, arg.Uuid, arg.AccountID)
+	var i ScheduledTransaction
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.AccountID,
+		&i.OperationTypeID,
+		&i.Amount,
+		&i.RecurrenceType,
+		&i.Recurrence,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.NextRunAt,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getScheduledTransactionsByAccountID = `-- name: GetScheduledTransactionsByAccountID :many
+SELECT uuid, serial_id, account_id, operation_type_id, amount, recurrence_type, recurrence, starts_at, ends_at, next_run_at, status, created_at, updated_at FROM public.scheduled_transactions
+WHERE account_id = $1
+ORDER BY serial_id
+`
+
+func (q *Queries) GetScheduledTransactionsByAccountID(ctx context.Context, accountID string) ([]*ScheduledTransaction, error) {
+	rows, err := q.db.Query(ctx, getScheduledTransactionsByAccountID, accountID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ScheduledTransaction
+	for rows.Next() {
+		var i ScheduledTransaction
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.SerialID,
+			&i.AccountID,
+			&i.OperationTypeID,
+			&i.Amount,
+			&i.RecurrenceType,
+			&i.Recurrence,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.NextRunAt,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const scheduledTransactionRunExists = `-- name: ScheduledTransactionRunExists :one
+SELECT EXISTS(SELECT 1 FROM public.scheduled_transaction_runs WHERE schedule_id = $1 AND scheduled_for = $2) AS exists
+`
+
+type ScheduledTransactionRunExistsParams struct {
+	ScheduleID   string    `db:"schedule_id" json:"schedule_id"`
+	ScheduledFor time.Time `db:"scheduled_for" json:"scheduled_for"`
+}
+
+func (q *Queries) ScheduledTransactionRunExists(ctx context.Context, arg ScheduledTransactionRunExistsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, scheduledTransactionRunExists, arg.ScheduleID, arg.ScheduledFor)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const updateScheduledTransactionStatus = `-- name: UpdateScheduledTransactionStatus :one
+UPDATE public.scheduled_transactions SET status = $2, next_run_at = $3
+WHERE uuid = $1
+RETURNING uuid, serial_id, account_id, operation_type_id, amount, recurrence_type, recurrence, starts_at, ends_at, next_run_at, status, created_at, updated_at
+`
+
+type UpdateScheduledTransactionStatusParams struct {
+	Uuid      string         `db:"uuid" json:"uuid"`
+	Status    ScheduleStatus `db:"status" json:"status"`
+	NextRunAt sql.NullTime   `db:"next_run_at" json:"next_run_at"`
+}
+
+func (q *Queries) UpdateScheduledTransactionStatus(ctx context.Context, arg UpdateScheduledTransactionStatusParams) (*ScheduledTransaction, error) {
+	row := q.db.QueryRow(ctx, updateScheduledTransactionStatus, arg.Uuid, arg.Status, arg.NextRunAt)
+	var i ScheduledTransaction
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.AccountID,
+		&i.OperationTypeID,
+		&i.Amount,
+		&i.RecurrenceType,
+		&i.Recurrence,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.NextRunAt,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
@@ -0,0 +1,75 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.16.0
+// source: spend_analytics.sql
+
+package models
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const getSpendAnalytics = `-- name: GetSpendAnalytics :many
+SELECT (CASE $1::TEXT
+            WHEN 'operation_type' THEN o.description::TEXT
+            WHEN 'month' THEN to_char(r.day, 'YYYY-MM')
+            ELSE r.category
+    END)::TEXT                               AS group_key,
+       SUM(r.txn_count)::BIGINT             AS txn_count,
+       SUM(r.total_amount)::FLOAT           AS total_amount,
+       COUNT(DISTINCT r.account_id)::BIGINT AS account_count
+FROM public.daily_spend_rollups r
+         JOIN public.operation_types o ON o.serial_id = r.operation_type_id
+WHERE ($2::UUID IS NULL OR r.account_id = $2::UUID)
+  AND r.day BETWEEN $3::DATE AND $4::DATE
+GROUP BY 1
+ORDER BY 1
+`
+
+type GetSpendAnalyticsParams struct {
+	GroupBy   string         `db:"group_by" json:"group_by"`
+	AccountID sql.NullString `db:"account_id" json:"account_id"`
+	FromDay   time.Time      `db:"from_day" json:"from_day"`
+	ToDay     time.Time      `db:"to_day" json:"to_day"`
+}
+
+type GetSpendAnalyticsRow struct {
+	GroupKey     string  `db:"group_key" json:"group_key"`
+	TxnCount     int64   `db:"txn_count" json:"txn_count"`
+	TotalAmount  float64 `db:"total_amount" json:"total_amount"`
+	AccountCount int64   `db:"account_count" json:"account_count"`
+}
+
+// Aggregates the daily spend rollups of the account, or of all the accounts when the account is null, grouped by
+// the operation type, the month (YYYY-MM) or the category of the spend
+func (q *Queries) GetSpendAnalytics(ctx context.Context, arg GetSpendAnalyticsParams) ([]*GetSpendAnalyticsRow, error) {
+	rows, err := q.db.Query(ctx, getSpendAnalytics,
+		arg.GroupBy,
+		arg.AccountID,
+		arg.FromDay,
+		arg.ToDay,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*GetSpendAnalyticsRow
+	for rows.Next() {
+		var i GetSpendAnalyticsRow
+		if err := rows.Scan(
+			&i.GroupKey,
+			&i.TxnCount,
+			&i.TotalAmount,
+			&i.AccountCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
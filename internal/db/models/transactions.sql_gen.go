@@ -7,31 +7,95 @@ package models
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"time"
 )
 
+const createReversalTransaction = `-- name: CreateReversalTransaction :one
+INSERT INTO public.transactions (account_id, amount, operation_type_id, balance, event_date, reversal_of)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, reversal_of, updated_at
+`
+
+type CreateReversalTransactionParams struct {
+	AccountID       string         `db:"account_id" json:"account_id"`
+	Amount          float64        `db:"amount" json:"amount"`
+	OperationTypeID int64          `db:"operation_type_id" json:"operation_type_id"`
+	Balance         float64        `db:"balance" json:"balance"`
+	EventDate       time.Time      `db:"event_date" json:"event_date"`
+	ReversalOf      sql.NullString `db:"reversal_of" json:"reversal_of"`
+}
+
+type CreateReversalTransactionRow struct {
+	Uuid            string         `db:"uuid" json:"uuid"`
+	SerialID        int64          `db:"serial_id" json:"serial_id"`
+	AccountID       string         `db:"account_id" json:"account_id"`
+	Amount          float64        `db:"amount" json:"amount"`
+	OperationTypeID int64          `db:"operation_type_id" json:"operation_type_id"`
+	EventDate       time.Time      `db:"event_date" json:"event_date"`
+	Balance         float64        `db:"balance" json:"balance"`
+	ReversalOf      sql.NullString `db:"reversal_of" json:"reversal_of"`
+	UpdatedAt       time.Time      `db:"updated_at" json:"updated_at"`
+}
+
+func (q *Queries) CreateReversalTransaction(ctx context.Context, arg CreateReversalTransactionParams) (*CreateReversalTransactionRow, error) {
+	row := q.db.QueryRow(ctx, createReversalTransaction,
+		arg.AccountID,
+		arg.Amount,
+		arg.OperationTypeID,
+		arg.Balance,
+		arg.EventDate,
+		arg.ReversalOf,
+	)
+	var i CreateReversalTransactionRow
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.AccountID,
+		&i.Amount,
+		&i.OperationTypeID,
+		&i.EventDate,
+		&i.Balance,
+		&i.ReversalOf,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
 const createTransaction = `-- name: CreateTransaction :one
-INSERT INTO public.transactions (account_id, amount, operation_type_id, balance, event_date)
-VALUES ($1, $2, $3, $4, NOW())
-RETURNING uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, updated_at
+INSERT INTO public.transactions (account_id, amount, operation_type_id, balance, merchant_id, merchant_country, event_date, mcc, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, merchant_id, merchant_country, mcc, metadata, tags, notes, updated_at
 `
 
 type CreateTransactionParams struct {
-	AccountID       string  `db:"account_id" json:"account_id"`
-	Amount          float64 `db:"amount" json:"amount"`
-	OperationTypeID int64   `db:"operation_type_id" json:"operation_type_id"`
-	Balance         float64 `db:"balance" json:"balance"`
+	AccountID       string          `db:"account_id" json:"account_id"`
+	Amount          float64         `db:"amount" json:"amount"`
+	OperationTypeID int64           `db:"operation_type_id" json:"operation_type_id"`
+	Balance         float64         `db:"balance" json:"balance"`
+	MerchantID      sql.NullString  `db:"merchant_id" json:"merchant_id"`
+	MerchantCountry sql.NullString  `db:"merchant_country" json:"merchant_country"`
+	EventDate       time.Time       `db:"event_date" json:"event_date"`
+	Mcc             sql.NullString  `db:"mcc" json:"mcc"`
+	Metadata        json.RawMessage `db:"metadata" json:"metadata"`
 }
 
 type CreateTransactionRow struct {
-	Uuid            string    `db:"uuid" json:"uuid"`
-	SerialID        int64     `db:"serial_id" json:"serial_id"`
-	AccountID       string    `db:"account_id" json:"account_id"`
-	Amount          float64   `db:"amount" json:"amount"`
-	OperationTypeID int64     `db:"operation_type_id" json:"operation_type_id"`
-	EventDate       time.Time `db:"event_date" json:"event_date"`
-	Balance         float64   `db:"balance" json:"balance"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	Uuid            string          `db:"uuid" json:"uuid"`
+	SerialID        int64           `db:"serial_id" json:"serial_id"`
+	AccountID       string          `db:"account_id" json:"account_id"`
+	Amount          float64         `db:"amount" json:"amount"`
+	OperationTypeID int64           `db:"operation_type_id" json:"operation_type_id"`
+	EventDate       time.Time       `db:"event_date" json:"event_date"`
+	Balance         float64         `db:"balance" json:"balance"`
+	MerchantID      sql.NullString  `db:"merchant_id" json:"merchant_id"`
+	MerchantCountry sql.NullString  `db:"merchant_country" json:"merchant_country"`
+	Mcc             sql.NullString  `db:"mcc" json:"mcc"`
+	Metadata        json.RawMessage `db:"metadata" json:"metadata"`
+	Tags            []string        `db:"tags" json:"tags"`
+	Notes           string          `db:"notes" json:"notes"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
 }
 
 func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionParams) (*CreateTransactionRow, error) {
@@ -40,6 +104,11 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		arg.Amount,
 		arg.OperationTypeID,
 		arg.Balance,
+		arg.MerchantID,
+		arg.MerchantCountry,
+		arg.EventDate,
+		arg.Mcc,
+		arg.Metadata,
 	)
 	var i CreateTransactionRow
 	err := row.Scan(
@@ -50,15 +119,82 @@ func (q *Queries) CreateTransaction(ctx context.Context, arg CreateTransactionPa
 		&i.OperationTypeID,
 		&i.EventDate,
 		&i.Balance,
+		&i.MerchantID,
+		&i.MerchantCountry,
+		&i.Mcc,
+		&i.Metadata,
+		&i.Tags,
+		&i.Notes,
 		&i.UpdatedAt,
 	)
 	return &i, err
 }
 
+const getAccountTransactionAmountStats = `-- name: GetAccountTransactionAmountStats :one
+SELECT COUNT(*)::BIGINT                               AS txn_count,
+       COALESCE(AVG(ABS(amount)), 0)::FLOAT         AS mean_amount,
+       COALESCE(STDDEV_SAMP(ABS(amount)), 0)::FLOAT AS stddev_amount
+FROM public.transactions
+WHERE account_id = $1
+  AND reversal_of IS NULL
+  AND (COALESCE(CARDINALITY($2::BIGINT[]), 0) = 0 OR operation_type_id = ANY($2::BIGINT[]))
+`
+
+type GetAccountTransactionAmountStatsParams struct {
+	AccountID        string  `db:"account_id" json:"account_id"`
+	OperationTypeIds []int64 `db:"operation_type_ids" json:"operation_type_ids"`
+}
+
+type GetAccountTransactionAmountStatsRow struct {
+	TxnCount     int64   `db:"txn_count" json:"txn_count"`
+	MeanAmount   float64 `db:"mean_amount" json:"mean_amount"`
+	StddevAmount float64 `db:"stddev_amount" json:"stddev_amount"`
+}
+
+// Only the transactions of the operation types are counted, all of them when operation_type_ids is empty.
+// The reversals are not counted
+func (q *Queries) GetAccountTransactionAmountStats(ctx context.Context, arg GetAccountTransactionAmountStatsParams) (*GetAccountTransactionAmountStatsRow, error) {
+	row := q.db.QueryRow(ctx, getAccountTransactionAmountStats, arg.AccountID, arg.OperationTypeIds)
+	var i GetAccountTransactionAmountStatsRow
+	err := row.Scan(&i.TxnCount, &i.MeanAmount, &i.StddevAmount)
+	return &i, err
+}
+
+const getAccountTransactionVelocity = `-- name: GetAccountTransactionVelocity :one
+SELECT COUNT(*)::BIGINT                       AS txn_count,
+       COALESCE(SUM(ABS(amount)), 0)::FLOAT AS total_amount
+FROM public.transactions
+WHERE account_id = $1
+  AND created_at >= $2
+  AND reversal_of IS NULL
+  AND (COALESCE(CARDINALITY($3::BIGINT[]), 0) = 0 OR operation_type_id = ANY($3::BIGINT[]))
+`
+
+type GetAccountTransactionVelocityParams struct {
+	AccountID        string    `db:"account_id" json:"account_id"`
+	Since            time.Time `db:"since" json:"since"`
+	OperationTypeIds []int64   `db:"operation_type_ids" json:"operation_type_ids"`
+}
+
+type GetAccountTransactionVelocityRow struct {
+	TxnCount    int64   `db:"txn_count" json:"txn_count"`
+	TotalAmount float64 `db:"total_amount" json:"total_amount"`
+}
+
+// The window is on the creation time, the event date is set by the client. Only the transactions of the operation
+// types are counted, all of them when operation_type_ids is empty. The reversals are not counted
+func (q *Queries) GetAccountTransactionVelocity(ctx context.Context, arg GetAccountTransactionVelocityParams) (*GetAccountTransactionVelocityRow, error) {
+	row := q.db.QueryRow(ctx, getAccountTransactionVelocity, arg.AccountID, arg.Since, arg.OperationTypeIds)
+	var i GetAccountTransactionVelocityRow
+	err := row.Scan(&i.TxnCount, &i.TotalAmount)
+	return &i, err
+}
+
 const getNegativeBalanceTransactionsByAccountID = `-- name: GetNegativeBalanceTransactionsByAccountID :many
 SELECT uuid, account_id, operation_type_id, amount, balance, event_date FROM public.transactions
 WHERE  account_id = $1 AND balance < 0
-ORDER BY event_date
+ORDER BY event_date, serial_id
+FOR UPDATE
 `
 
 type GetNegativeBalanceTransactionsByAccountIDRow struct {
@@ -97,21 +233,43 @@ func (q *Queries) GetNegativeBalanceTransactionsByAccountID(ctx context.Context,
 	return items, nil
 }
 
+const getReversedAmount = `-- name: GetReversedAmount :one
+SELECT COALESCE(SUM(ABS(r.amount) - COALESCE((SELECT SUM(ABS(rr.amount)) FROM public.transactions rr WHERE rr.reversal_of = r.uuid), 0)), 0)::FLOAT AS reversed_amount
+FROM public.transactions r
+WHERE r.reversal_of = $1::UUID
+`
+
+// The amount of the transaction already reversed. A reversal that was reversed itself, e.g. a clawed back
+// provisional credit, doesn't count
+func (q *Queries) GetReversedAmount(ctx context.Context, transactionID string) (float64, error) {
+	row := q.db.QueryRow(ctx, getReversedAmount, transactionID)
+	var reversed_amount float64
+	err := row.Scan(&reversed_amount)
+	return reversed_amount, err
+}
+
 const getTransactionDetailsByTransactionId = `-- name: GetTransactionDetailsByTransactionId :one
-SELECT uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, updated_at
+SELECT uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, merchant_id, merchant_country, mcc, reversal_of, metadata, tags, notes, updated_at
 FROM public.transactions
 WHERE uuid = $1
 `
 
 type GetTransactionDetailsByTransactionIdRow struct {
-	Uuid            string    `db:"uuid" json:"uuid"`
-	SerialID        int64     `db:"serial_id" json:"serial_id"`
-	AccountID       string    `db:"account_id" json:"account_id"`
-	Amount          float64   `db:"amount" json:"amount"`
-	OperationTypeID int64     `db:"operation_type_id" json:"operation_type_id"`
-	EventDate       time.Time `db:"event_date" json:"event_date"`
-	Balance         float64   `db:"balance" json:"balance"`
-	UpdatedAt       time.Time `db:"updated_at" json:"updated_at"`
+	Uuid            string          `db:"uuid" json:"uuid"`
+	SerialID        int64           `db:"serial_id" json:"serial_id"`
+	AccountID       string          `db:"account_id" json:"account_id"`
+	Amount          float64         `db:"amount" json:"amount"`
+	OperationTypeID int64           `db:"operation_type_id" json:"operation_type_id"`
+	EventDate       time.Time       `db:"event_date" json:"event_date"`
+	Balance         float64         `db:"balance" json:"balance"`
+	MerchantID      sql.NullString  `db:"merchant_id" json:"merchant_id"`
+	MerchantCountry sql.NullString  `db:"merchant_country" json:"merchant_country"`
+	Mcc             sql.NullString  `db:"mcc" json:"mcc"`
+	ReversalOf      sql.NullString  `db:"reversal_of" json:"reversal_of"`
+	Metadata        json.RawMessage `db:"metadata" json:"metadata"`
+	Tags            []string        `db:"tags" json:"tags"`
+	Notes           string          `db:"notes" json:"notes"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
 }
 
 func (q *Queries) GetTransactionDetailsByTransactionId(ctx context.Context, uuid string) (*GetTransactionDetailsByTransactionIdRow, error) {
@@ -125,6 +283,184 @@ func (q *Queries) GetTransactionDetailsByTransactionId(ctx context.Context, uuid
 		&i.OperationTypeID,
 		&i.EventDate,
 		&i.Balance,
+		&i.MerchantID,
+		&i.MerchantCountry,
+		&i.Mcc,
+		&i.ReversalOf,
+		&i.Metadata,
+		&i.Tags,
+		&i.Notes,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getTransactionForUpdate = `-- name: GetTransactionForUpdate :one
+SELECT uuid, account_id, amount, operation_type_id, balance, reversal_of
+FROM public.transactions
+WHERE uuid = $1
+FOR UPDATE
+`
+
+type GetTransactionForUpdateRow struct {
+	Uuid            string         `db:"uuid" json:"uuid"`
+	AccountID       string         `db:"account_id" json:"account_id"`
+	Amount          float64        `db:"amount" json:"amount"`
+	OperationTypeID int64          `db:"operation_type_id" json:"operation_type_id"`
+	Balance         float64        `db:"balance" json:"balance"`
+	ReversalOf      sql.NullString `db:"reversal_of" json:"reversal_of"`
+}
+
+func (q *Queries) GetTransactionForUpdate(ctx context.Context, uuid string) (*GetTransactionForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getTransactionForUpdate, uuid)
+	var i GetTransactionForUpdateRow
+	err := row.Scan(
+		&i.Uuid,
+		&i.AccountID,
+		&i.Amount,
+		&i.OperationTypeID,
+		&i.Balance,
+		&i.ReversalOf,
+	)
+	return &i, err
+}
+
+const listTransactions = `-- name: ListTransactions :many
+SELECT uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, merchant_id, merchant_country, mcc, reversal_of, metadata, tags, notes, updated_at
+FROM public.transactions
+WHERE ($1::UUID IS NULL OR account_id = $1::UUID)
+  AND metadata @> $2::JSONB
+  AND metadata ?& $3::TEXT[]
+  AND tags @> $4::TEXT[]
+  AND serial_id > $5
+ORDER BY serial_id
+LIMIT $6
+`
+
+type ListTransactionsParams struct {
+	AccountID     sql.NullString  `db:"account_id" json:"account_id"`
+	Metadata      json.RawMessage `db:"metadata" json:"metadata"`
+	MetadataKeys  []string        `db:"metadata_keys" json:"metadata_keys"`
+	Tags          []string        `db:"tags" json:"tags"`
+	AfterSerialID int64           `db:"after_serial_id" json:"after_serial_id"`
+	PageSize      int32           `db:"page_size" json:"page_size"`
+}
+
+type ListTransactionsRow struct {
+	Uuid            string          `db:"uuid" json:"uuid"`
+	SerialID        int64           `db:"serial_id" json:"serial_id"`
+	AccountID       string          `db:"account_id" json:"account_id"`
+	Amount          float64         `db:"amount" json:"amount"`
+	OperationTypeID int64           `db:"operation_type_id" json:"operation_type_id"`
+	EventDate       time.Time       `db:"event_date" json:"event_date"`
+	Balance         float64         `db:"balance" json:"balance"`
+	MerchantID      sql.NullString  `db:"merchant_id" json:"merchant_id"`
+	MerchantCountry sql.NullString  `db:"merchant_country" json:"merchant_country"`
+	Mcc             sql.NullString  `db:"mcc" json:"mcc"`
+	ReversalOf      sql.NullString  `db:"reversal_of" json:"reversal_of"`
+	Metadata        json.RawMessage `db:"metadata" json:"metadata"`
+	Tags            []string        `db:"tags" json:"tags"`
+	Notes           string          `db:"notes" json:"notes"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// Keyset pagination on serial_id, pass 0 as after_serial_id to get the first page.
+// The metadata must contain all the pairs in metadata and all the keys in metadata_keys, and the tags all the tags.
+// Empty filters match every transaction
+func (q *Queries) ListTransactions(ctx context.Context, arg ListTransactionsParams) ([]*ListTransactionsRow, error) {
+	rows, err := q.db.Query(ctx, listTransactions,
+		arg.AccountID,
+		arg.Metadata,
+		arg.MetadataKeys,
+		arg.Tags,
+		arg.AfterSerialID,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*ListTransactionsRow
+	for rows.Next() {
+		var i ListTransactionsRow
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.SerialID,
+			&i.AccountID,
+			&i.Amount,
+			&i.OperationTypeID,
+			&i.EventDate,
+			&i.Balance,
+			&i.MerchantID,
+			&i.MerchantCountry,
+			&i.Mcc,
+			&i.ReversalOf,
+			&i.Metadata,
+			&i.Tags,
+			&i.Notes,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateTransactionAnnotations = `-- name: UpdateTransactionAnnotations :one
+UPDATE public.transactions
+SET tags  = COALESCE($1::TEXT[], tags),
+    notes = COALESCE($2, notes)
+WHERE uuid = $3
+RETURNING uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, merchant_id, merchant_country, mcc, reversal_of, metadata, tags, notes, updated_at
+`
+
+type UpdateTransactionAnnotationsParams struct {
+	Tags  []string       `db:"tags" json:"tags"`
+	Notes sql.NullString `db:"notes" json:"notes"`
+	Uuid  string         `db:"uuid" json:"uuid"`
+}
+
+type UpdateTransactionAnnotationsRow struct {
+	Uuid            string          `db:"uuid" json:"uuid"`
+	SerialID        int64           `db:"serial_id" json:"serial_id"`
+	AccountID       string          `db:"account_id" json:"account_id"`
+	Amount          float64         `db:"amount" json:"amount"`
+	OperationTypeID int64           `db:"operation_type_id" json:"operation_type_id"`
+	EventDate       time.Time       `db:"event_date" json:"event_date"`
+	Balance         float64         `db:"balance" json:"balance"`
+	MerchantID      sql.NullString  `db:"merchant_id" json:"merchant_id"`
+	MerchantCountry sql.NullString  `db:"merchant_country" json:"merchant_country"`
+	Mcc             sql.NullString  `db:"mcc" json:"mcc"`
+	ReversalOf      sql.NullString  `db:"reversal_of" json:"reversal_of"`
+	Metadata        json.RawMessage `db:"metadata" json:"metadata"`
+	Tags            []string        `db:"tags" json:"tags"`
+	Notes           string          `db:"notes" json:"notes"`
+	UpdatedAt       time.Time       `db:"updated_at" json:"updated_at"`
+}
+
+// Only the tags and the notes of a transaction can be updated, the fields that are null are left unchanged
+func (q *Queries) UpdateTransactionAnnotations(ctx context.Context, arg UpdateTransactionAnnotationsParams) (*UpdateTransactionAnnotationsRow, error) {
+	row := q.db.QueryRow(ctx, updateTransactionAnnotations, arg.Tags, arg.Notes, arg.Uuid)
+	var i UpdateTransactionAnnotationsRow
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.AccountID,
+		&i.Amount,
+		&i.OperationTypeID,
+		&i.EventDate,
+		&i.Balance,
+		&i.MerchantID,
+		&i.MerchantCountry,
+		&i.Mcc,
+		&i.ReversalOf,
+		&i.Metadata,
+		&i.Tags,
+		&i.Notes,
 		&i.UpdatedAt,
 	)
 	return &i, err
@@ -7,8 +7,155 @@ package models
 
 import (
 	"context"
+	"database/sql"
 )
 
+const createUser = `-- name: CreateUser :one
+INSERT INTO public.users (first_name, middle_name, last_name, phone_number, email)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING uuid, serial_id, first_name, middle_name, last_name, phone_number, email, created_at, updated_at
+`
+
+type CreateUserParams struct {
+	FirstName   sql.NullString `db:"first_name" json:"first_name"`
+	MiddleName  sql.NullString `db:"middle_name" json:"middle_name"`
+	LastName    sql.NullString `db:"last_name" json:"last_name"`
+	PhoneNumber string         `db:"phone_number" json:"phone_number"`
+	Email       sql.NullString `db:"email" json:"email"`
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (*User, error) {
+	row := q.db.QueryRow(ctx, createUser,
+		arg.FirstName,
+		arg.MiddleName,
+		arg.LastName,
+		arg.PhoneNumber,
+		arg.Email,
+	)
+	var i User
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.FirstName,
+		&i.MiddleName,
+		&i.LastName,
+		&i.PhoneNumber,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getUserByUUID = `-- name: GetUserByUUID :one
+SELECT uuid, serial_id, first_name, middle_name, last_name, phone_number, email, created_at, updated_at FROM public.users WHERE uuid = $1
+`
+
+func (q *Queries) GetUserByUUID(ctx context.Context, uuid string) (*User, error) {
+	row := q.db.QueryRow(ctx, getUserByUUID, uuid)
+	var i User
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.FirstName,
+		&i.MiddleName,
+		&i.LastName,
+		&i.PhoneNumber,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT uuid, serial_id, first_name, middle_name, last_name, phone_number, email, created_at, updated_at FROM public.users
+WHERE serial_id > $1
+ORDER BY serial_id
+LIMIT $2
+`
+
+type ListUsersParams struct {
+	AfterSerialID int64 `db:"after_serial_id" json:"after_serial_id"`
+	PageSize      int32 `db:"page_size" json:"page_size"`
+}
+
+// Keyset pagination on serial_id, pass 0 as after_serial_id to get the first page
+func (q *Queries) ListUsers(ctx context.Context, arg ListUsersParams) ([]*User, error) {
+	rows, err := q.db.Query(ctx, listUsers, arg.AfterSerialID, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []*User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.Uuid,
+			&i.SerialID,
+			&i.FirstName,
+			&i.MiddleName,
+			&i.LastName,
+			&i.PhoneNumber,
+			&i.Email,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, &i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUser = `-- name: UpdateUser :one
+UPDATE public.users
+SET first_name   = COALESCE($1, first_name),
+    middle_name  = COALESCE($2, middle_name),
+    last_name    = COALESCE($3, last_name),
+    phone_number = COALESCE($4, phone_number),
+    email        = COALESCE($5, email)
+WHERE uuid = $6
+RETURNING uuid, serial_id, first_name, middle_name, last_name, phone_number, email, created_at, updated_at
+`
+
+type UpdateUserParams struct {
+	FirstName   sql.NullString `db:"first_name" json:"first_name"`
+	MiddleName  sql.NullString `db:"middle_name" json:"middle_name"`
+	LastName    sql.NullString `db:"last_name" json:"last_name"`
+	PhoneNumber sql.NullString `db:"phone_number" json:"phone_number"`
+	Email       sql.NullString `db:"email" json:"email"`
+	Uuid        string         `db:"uuid" json:"uuid"`
+}
+
+// Only the fields that are not null are updated
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (*User, error) {
+	row := q.db.QueryRow(ctx, updateUser,
+		arg.FirstName,
+		arg.MiddleName,
+		arg.LastName,
+		arg.PhoneNumber,
+		arg.Email,
+		arg.Uuid,
+	)
+	var i User
+	err := row.Scan(
+		&i.Uuid,
+		&i.SerialID,
+		&i.FirstName,
+		&i.MiddleName,
+		&i.LastName,
+		&i.PhoneNumber,
+		&i.Email,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
 const userExists = `-- name: UserExists :one
 SELECT EXISTS(SELECT 1 FROM public.users WHERE uuid = $1) AS exists
 `
@@ -1,5 +1,9 @@
 INSERT INTO public.accounts (uuid, document_number, current_balance, user_id)
-VALUES ('005be6d7-6d9a-4391-b3ee-1d753ac7d600', 'DOC123456', 1200.0, '77e0e837-e7f2-47b1-a08c-3af267c03077');
+VALUES ('005be6d7-6d9a-4391-b3ee-1d753ac7d600', '52998224725', 1200.0, '77e0e837-e7f2-47b1-a08c-3af267c03077');
 
 INSERT INTO public.accounts (uuid, document_number, current_balance, user_id)
-VALUES ('115be6d7-6d9a-4391-b3ee-1d753ac7d611', 'DOC231212', 5000.0, '88e0e837-e7f2-47b1-a08c-3af267c03088');
\ No newline at end of file
+VALUES ('115be6d7-6d9a-4391-b3ee-1d753ac7d611', '11144477735', 5000.0, '88e0e837-e7f2-47b1-a08c-3af267c03088');
+
+INSERT INTO public.account_documents (account_id, document_number, scope_key)
+VALUES ('005be6d7-6d9a-4391-b3ee-1d753ac7d600', '52998224725', ''),
+       ('115be6d7-6d9a-4391-b3ee-1d753ac7d611', '11144477735', '');
@@ -0,0 +1,4 @@
+-- The API key of local development, pk_dev_local_only_do_not_use_in_production. Seeds only run in DEV
+INSERT INTO public.api_keys (uuid, name, key_hash)
+VALUES ('de7a9e10-0000-4000-8000-000000000001', 'local development',
+        '135c42e421b42f93e9d64ce34c7e4b66d400f51ff153c8bdaa22eff05a4982df');
@@ -0,0 +1,2 @@
+-- The dev key administers the API
+UPDATE public.api_keys SET scopes = '{admin}' WHERE uuid = 'de7a9e10-0000-4000-8000-000000000001';
@@ -0,0 +1,37 @@
+-- name: DeleteAccountLimits :exec
+DELETE FROM public.account_limits WHERE account_id = $1;
+
+-- name: CreateAccountLimit :one
+INSERT INTO public.account_limits (account_id, operation_type_id, period, max_amount)
+VALUES ($1, $2, $3, $4)
+RETURNING uuid, serial_id, account_id, operation_type_id, period, max_amount, created_at, updated_at;
+
+-- name: GetAccountLimitsByOperationType :many
+SELECT uuid, serial_id, account_id, operation_type_id, period, max_amount, created_at, updated_at
+FROM public.account_limits
+WHERE account_id = $1 AND operation_type_id = $2
+ORDER BY period;
+
+-- name: GetAccountLimitsWithUsage :many
+SELECT l.operation_type_id, l.period, l.max_amount, COALESCE(u.used_amount, 0)::FLOAT AS used_amount
+FROM public.account_limits l
+         LEFT JOIN public.account_limit_usage u
+                   ON u.account_id = l.account_id
+                       AND u.operation_type_id = l.operation_type_id
+                       AND u.period = l.period
+                       AND u.period_start = CASE l.period
+                                                WHEN 'DAILY' THEN @daily_start::TIMESTAMPTZ
+                                                WHEN 'WEEKLY' THEN @weekly_start::TIMESTAMPTZ
+                                                ELSE @monthly_start::TIMESTAMPTZ END
+WHERE l.account_id = @account_id
+ORDER BY l.operation_type_id, l.period;
+
+-- name: IncrementAccountLimitUsage :one
+-- Adds the amount to the counter of the period, only if the counter stays within max_amount.
+-- No row is returned when the limit would be breached.
+INSERT INTO public.account_limit_usage AS u (account_id, operation_type_id, period, period_start, used_amount)
+VALUES (@account_id, @operation_type_id, @period, @period_start, @amount)
+ON CONFLICT (account_id, operation_type_id, period, period_start)
+    DO UPDATE SET used_amount = u.used_amount + EXCLUDED.used_amount
+WHERE u.used_amount + EXCLUDED.used_amount <= @max_amount::FLOAT
+RETURNING used_amount;
@@ -0,0 +1,79 @@
+-- name: GetAccountSummary :one
+-- The summary of the account in a single round trip, no row is returned when the account doesn't exist.
+-- The billing cycle is the calendar month in UTC, it starts at monthly_start and ends at cycle_end.
+-- Pending holds are the amounts committed but not posted yet: the disputes in progress without a provisional credit
+-- and the next run of the active schedules that is due in the billing cycle
+WITH open_balances AS (SELECT operation_type_id, balance
+                       FROM public.transactions
+                       WHERE account_id = @account_id AND balance <> 0),
+     debts AS (SELECT b.operation_type_id, o.description, COUNT(*) AS txn_count, -SUM(b.balance) AS amount
+               FROM open_balances b
+                        JOIN public.operation_types o ON o.serial_id = b.operation_type_id
+               WHERE b.balance < 0
+               GROUP BY b.operation_type_id, o.description),
+     cycle AS (SELECT COUNT(*)                                           AS txn_count,
+                      COALESCE(-SUM(amount) FILTER (WHERE amount < 0), 0) AS debits,
+                      COALESCE(SUM(amount) FILTER (WHERE amount > 0), 0)  AS credits
+               FROM public.transactions
+               WHERE account_id = @account_id
+                 AND event_date >= @monthly_start::TIMESTAMPTZ
+                 AND event_date < @cycle_end::TIMESTAMPTZ),
+     available_limits AS (SELECT l.operation_type_id, l.period, l.max_amount, COALESCE(u.used_amount, 0) AS used_amount
+                          FROM public.account_limits l
+                                   LEFT JOIN public.account_limit_usage u
+                                             ON u.account_id = l.account_id
+                                                 AND u.operation_type_id = l.operation_type_id
+                                                 AND u.period = l.period
+                                                 AND u.period_start = CASE l.period
+                                                                          WHEN 'DAILY' THEN @daily_start::TIMESTAMPTZ
+                                                                          WHEN 'WEEKLY' THEN @weekly_start::TIMESTAMPTZ
+                                                                          ELSE @monthly_start::TIMESTAMPTZ END
+                          WHERE l.account_id = @account_id),
+     last_transaction AS (SELECT uuid, operation_type_id, amount, balance, event_date
+                          FROM public.transactions
+                          WHERE account_id = @account_id
+                          ORDER BY event_date DESC, serial_id DESC
+                          LIMIT 1)
+SELECT a.uuid,
+       a.status,
+       COALESCE((SELECT jsonb_agg(jsonb_build_object(
+               'operation_type_id', d.operation_type_id,
+               'description', d.description,
+               'count', d.txn_count,
+               'amount', d.amount) ORDER BY d.operation_type_id) FROM debts d), '[]')::JSONB     AS outstanding_debt,
+       (SELECT COALESCE(SUM(balance), 0) FROM open_balances WHERE balance > 0)::FLOAT           AS unused_credit,
+       COALESCE((SELECT jsonb_agg(jsonb_build_object(
+               'operation_type_id', l.operation_type_id,
+               'period', l.period,
+               'max_amount', l.max_amount,
+               'used_amount', l.used_amount,
+               'remaining_amount', GREATEST(l.max_amount - l.used_amount, 0))
+               ORDER BY l.operation_type_id, l.period) FROM available_limits l), '[]')::JSONB   AS available_limits,
+       (SELECT COUNT(*)
+        FROM public.disputes d
+        WHERE d.account_id = a.uuid
+          AND d.status IN ('OPENED', 'UNDER_REVIEW')
+          AND d.provisional_credit_transaction_id IS NULL)::BIGINT                              AS disputed_count,
+       (SELECT COALESCE(SUM(d.amount), 0)
+        FROM public.disputes d
+        WHERE d.account_id = a.uuid
+          AND d.status IN ('OPENED', 'UNDER_REVIEW')
+          AND d.provisional_credit_transaction_id IS NULL)::FLOAT                               AS disputed_amount,
+       (SELECT COUNT(*)
+        FROM public.scheduled_transactions s
+        WHERE s.account_id = a.uuid
+          AND s.status = 'ACTIVE'
+          AND s.next_run_at < @cycle_end::TIMESTAMPTZ)::BIGINT                                  AS scheduled_count,
+       (SELECT COALESCE(SUM(CASE o.amount_behavior WHEN 'NEGATIVE' THEN -s.amount ELSE s.amount END), 0)
+        FROM public.scheduled_transactions s
+                 JOIN public.operation_types o ON o.serial_id = s.operation_type_id
+        WHERE s.account_id = a.uuid
+          AND s.status = 'ACTIVE'
+          AND s.next_run_at < @cycle_end::TIMESTAMPTZ)::FLOAT                                   AS scheduled_amount,
+       (SELECT to_jsonb(t) FROM last_transaction t)::JSONB                                      AS last_transaction,
+       c.txn_count::BIGINT                                                                      AS cycle_count,
+       c.debits::FLOAT                                                                          AS cycle_debits,
+       c.credits::FLOAT                                                                         AS cycle_credits
+FROM public.accounts a,
+     cycle c
+WHERE a.uuid = @account_id;
@@ -1,12 +1,51 @@
 -- name: CreateAccount :one
 INSERT INTO public.accounts (document_number, current_balance, user_id)
 VALUES ($1, $2, $3)
-RETURNING uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at;
+RETURNING uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at, status;
 
 -- name: GetAccountDetailsByUUID :one
-SELECT uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at
+SELECT uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at, status
 FROM public.accounts
 WHERE uuid = $1;
 
 -- name: AccountExists :one
-SELECT EXISTS(SELECT 1 FROM public.accounts WHERE uuid = $1) AS exists;
\ No newline at end of file
+SELECT EXISTS(SELECT 1 FROM public.accounts WHERE uuid = $1) AS exists;
+
+-- name: GetAccountStatus :one
+SELECT status FROM public.accounts WHERE uuid = $1;
+
+-- name: GetAccountStatusForUpdate :one
+SELECT status FROM public.accounts WHERE uuid = $1 FOR UPDATE;
+
+-- name: UpdateAccountStatus :one
+UPDATE public.accounts SET status = $2 WHERE uuid = $1
+RETURNING uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at, status;
+
+-- name: CreateAccountStatusHistory :one
+INSERT INTO public.account_status_history (account_id, from_status, to_status, reason_code, note)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING uuid, serial_id, account_id, from_status, to_status, reason_code, note, created_at;
+
+-- name: GetAccountsByUserID :many
+SELECT uuid, serial_id, document_number, current_balance, user_id, created_at, updated_at, status
+FROM public.accounts
+WHERE user_id = $1
+ORDER BY serial_id;
+
+-- name: CreateAccountDocument :exec
+INSERT INTO public.account_documents (account_id, document_number, scope_key)
+VALUES ($1, $2, $3);
+
+-- name: LockDocumentNumber :exec
+-- The lock serializes the accounts created with the same document number until the end of the transaction
+SELECT pg_advisory_xact_lock(hashtext('account_documents:' || sqlc.arg(document_number)::TEXT));
+
+-- name: DocumentNumberExists :one
+-- An empty user_id looks for the document across all the users, the GLOBAL scope, otherwise in the accounts of the user
+SELECT EXISTS(
+    SELECT 1
+    FROM public.account_documents ad
+             JOIN public.accounts a ON a.uuid = ad.account_id
+    WHERE ad.document_number = sqlc.arg(document_number)
+      AND (sqlc.arg(user_id)::TEXT = '' OR a.user_id::TEXT = sqlc.arg(user_id)::TEXT)
+) AS exists;
@@ -0,0 +1,33 @@
+-- name: CreateAPIKey :one
+INSERT INTO public.api_keys (name, key_hash, user_id, expires_at, scopes)
+VALUES (@name, @key_hash, sqlc.narg('user_id'), sqlc.narg('expires_at'), @scopes::TEXT[])
+RETURNING uuid, serial_id, name, user_id, expires_at, scopes, created_at;
+
+-- name: GetAPIKeyByHash :one
+SELECT uuid, name, user_id, expires_at, revoked_at, scopes
+FROM public.api_keys
+WHERE key_hash = $1;
+
+-- name: TouchAPIKey :exec
+-- last_used_at is only updated once a minute, so that the keys used by every request are not written every time
+UPDATE public.api_keys
+SET last_used_at = NOW()
+WHERE uuid = $1
+  AND (last_used_at IS NULL OR last_used_at < NOW() - INTERVAL '1 minute');
+
+-- name: GetAccountOwner :one
+SELECT user_id
+FROM public.accounts
+WHERE uuid = $1;
+
+-- name: GetTransactionOwner :one
+SELECT a.user_id
+FROM public.transactions t
+         JOIN public.accounts a ON a.uuid = t.account_id
+WHERE t.uuid = $1;
+
+-- name: GetDisputeOwner :one
+SELECT a.user_id
+FROM public.disputes d
+         JOIN public.accounts a ON a.uuid = d.account_id
+WHERE d.uuid = $1;
@@ -0,0 +1,35 @@
+-- name: CreateDispute :one
+INSERT INTO public.disputes (transaction_id, account_id, amount, reason_code)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetDispute :one
+SELECT * FROM public.disputes WHERE uuid = $1;
+
+-- name: GetDisputeForUpdate :one
+SELECT * FROM public.disputes WHERE uuid = $1 FOR UPDATE;
+
+-- name: SetDisputeProvisionalCredit :one
+UPDATE public.disputes SET provisional_credit_transaction_id = $2
+WHERE uuid = $1
+RETURNING *;
+
+-- name: UpdateDisputeStatus :one
+UPDATE public.disputes SET status = $2
+WHERE uuid = $1
+RETURNING *;
+
+-- name: ResolveDispute :one
+UPDATE public.disputes SET status = $2, resolution_transaction_id = $3, resolved_at = NOW()
+WHERE uuid = $1
+RETURNING *;
+
+-- name: CreateDisputeEvent :one
+INSERT INTO public.dispute_events (dispute_id, type, transaction_id, note)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetDisputeEvents :many
+SELECT * FROM public.dispute_events
+WHERE dispute_id = $1
+ORDER BY serial_id;
@@ -1,2 +1,5 @@
 -- name: GetOperationTypeAmountBehavior :one
-SELECT amount_behavior FROM public.operation_types WHERE serial_id = $1;
\ No newline at end of file
+SELECT amount_behavior FROM public.operation_types WHERE serial_id = $1;
+
+-- name: GetOperationTypeIDByDescription :one
+SELECT serial_id FROM public.operation_types WHERE description = $1 ORDER BY serial_id LIMIT 1;
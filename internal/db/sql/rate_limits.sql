@@ -0,0 +1,10 @@
+-- name: IncrementRateLimitCounter :one
+INSERT INTO public.rate_limit_counters (key, window_start, window_end)
+VALUES (@key, @window_start, @window_end)
+ON CONFLICT (key, window_start) DO UPDATE SET count = rate_limit_counters.count + 1
+RETURNING count;
+
+-- name: DeleteExpiredRateLimitCounters :execrows
+DELETE
+FROM public.rate_limit_counters
+WHERE window_end < @before;
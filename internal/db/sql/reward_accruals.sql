@@ -0,0 +1,53 @@
+-- name: CreateRewardAccrual :exec
+INSERT INTO public.reward_accruals (account_id, transaction_id, rule_name, kind, amount)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetRewardAccrualTotalsByTransactionID :many
+SELECT rule_name, kind, SUM(amount)::FLOAT AS amount
+FROM public.reward_accruals
+WHERE transaction_id = $1
+GROUP BY rule_name, kind
+ORDER BY rule_name, kind;
+
+-- name: GetRewardsBalance :many
+SELECT kind,
+       COALESCE(SUM(amount), 0)::FLOAT                                               AS accrued,
+       COALESCE(SUM(amount) FILTER (WHERE payout_transaction_id IS NOT NULL), 0)::FLOAT AS paid_out
+FROM public.reward_accruals
+WHERE account_id = $1
+GROUP BY kind;
+
+-- name: GetAccountsWithPendingCashback :many
+-- Closed accounts can't be credited, their cashback stays pending. The accounts whose payout failed after retry_before
+-- are skipped, and the accounts with the oldest pending cashback are paid first
+SELECT ra.account_id
+FROM public.reward_accruals ra
+         JOIN public.accounts a ON a.uuid = ra.account_id
+         LEFT JOIN public.reward_payout_failures f ON f.account_id = ra.account_id
+WHERE ra.kind = 'CASHBACK' AND ra.payout_transaction_id IS NULL AND a.status <> 'CLOSED'
+  AND (f.failed_at IS NULL OR f.failed_at <= sqlc.arg(retry_before))
+GROUP BY ra.account_id
+HAVING SUM(ra.amount) > 0
+ORDER BY MIN(ra.serial_id)
+LIMIT sqlc.arg(batch_size);
+
+-- name: RecordRewardPayoutFailure :exec
+INSERT INTO public.reward_payout_failures (account_id, failed_at)
+VALUES ($1, $2)
+ON CONFLICT (account_id) DO UPDATE SET failures  = reward_payout_failures.failures + 1,
+                                       failed_at = EXCLUDED.failed_at;
+
+-- name: DeleteRewardPayoutFailure :exec
+DELETE FROM public.reward_payout_failures WHERE account_id = $1;
+
+-- name: GetPendingCashbackAccrualsForUpdate :many
+-- Concurrent payouts of the same account wait for each other, the second one doesn't see the accruals paid by the first
+SELECT uuid, amount
+FROM public.reward_accruals
+WHERE account_id = $1 AND kind = 'CASHBACK' AND payout_transaction_id IS NULL
+ORDER BY serial_id
+FOR UPDATE;
+
+-- name: SetRewardAccrualsPayout :exec
+UPDATE public.reward_accruals SET payout_transaction_id = $1
+WHERE uuid = ANY(sqlc.arg(uuids)::UUID[]);
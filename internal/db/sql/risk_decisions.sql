@@ -0,0 +1,3 @@
+-- name: CreateRiskDecision :exec
+INSERT INTO public.risk_decisions (account_id, operation_type_id, amount, merchant_id, merchant_country, outcome, reason_code, rule_name)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
@@ -0,0 +1,37 @@
+-- name: CreateScheduledTransaction :one
+INSERT INTO public.scheduled_transactions (account_id, operation_type_id, amount, recurrence_type, recurrence, starts_at, ends_at, next_run_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetScheduledTransactionsByAccountID :many
+SELECT * FROM public.scheduled_transactions
+WHERE account_id = $1
+ORDER BY serial_id;
+
+-- name: GetScheduledTransactionForUpdate :one
+SELECT * FROM public.scheduled_transactions
+WHERE uuid = $1 AND account_id = $2
+FOR UPDATE;
+
+-- name: UpdateScheduledTransactionStatus :one
+UPDATE public.scheduled_transactions SET status = $2, next_run_at = $3
+WHERE uuid = $1
+RETURNING *;
+
+-- name: GetNextDueScheduledTransaction :one
+-- Schedules locked by another scheduler are skipped, so several instances of the service can post in parallel
+SELECT * FROM public.scheduled_transactions
+WHERE status = 'ACTIVE' AND next_run_at <= $1
+ORDER BY next_run_at
+LIMIT 1
+FOR UPDATE SKIP LOCKED;
+
+-- name: CreateScheduledTransactionRun :one
+-- Returns no rows when the occurrence was already posted
+INSERT INTO public.scheduled_transaction_runs (schedule_id, scheduled_for, status, transaction_id, error)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (schedule_id, scheduled_for) DO NOTHING
+RETURNING *;
+
+-- name: ScheduledTransactionRunExists :one
+SELECT EXISTS(SELECT 1 FROM public.scheduled_transaction_runs WHERE schedule_id = $1 AND scheduled_for = $2) AS exists;
@@ -0,0 +1,17 @@
+-- name: GetSpendAnalytics :many
+-- Aggregates the daily spend rollups of the account, or of all the accounts when the account is null, grouped by
+-- the operation type, the month (YYYY-MM) or the category of the spend
+SELECT (CASE @group_by::TEXT
+            WHEN 'operation_type' THEN o.description::TEXT
+            WHEN 'month' THEN to_char(r.day, 'YYYY-MM')
+            ELSE r.category
+    END)::TEXT                               AS group_key,
+       SUM(r.txn_count)::BIGINT             AS txn_count,
+       SUM(r.total_amount)::FLOAT           AS total_amount,
+       COUNT(DISTINCT r.account_id)::BIGINT AS account_count
+FROM public.daily_spend_rollups r
+         JOIN public.operation_types o ON o.serial_id = r.operation_type_id
+WHERE (sqlc.narg('account_id')::UUID IS NULL OR r.account_id = sqlc.narg('account_id')::UUID)
+  AND r.day BETWEEN @from_day::DATE AND @to_day::DATE
+GROUP BY 1
+ORDER BY 1;
@@ -1,17 +1,81 @@
 -- name: CreateTransaction :one
-INSERT INTO public.transactions (account_id, amount, operation_type_id, balance, event_date)
-VALUES ($1, $2, $3, $4, NOW())
-RETURNING uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, updated_at;
+INSERT INTO public.transactions (account_id, amount, operation_type_id, balance, merchant_id, merchant_country, event_date, mcc, metadata)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, merchant_id, merchant_country, mcc, metadata, tags, notes, updated_at;
 
 -- name: GetTransactionDetailsByTransactionId :one
-SELECT uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, updated_at
+SELECT uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, merchant_id, merchant_country, mcc, reversal_of, metadata, tags, notes, updated_at
 FROM public.transactions
 WHERE uuid = $1;
 
 -- name: GetNegativeBalanceTransactionsByAccountID :many
 SELECT uuid, account_id, operation_type_id, amount, balance, event_date FROM public.transactions
 WHERE  account_id = $1 AND balance < 0
-ORDER BY event_date;
+ORDER BY event_date, serial_id
+FOR UPDATE;
 
 -- name: UpdateTransactionBalances :exec
-UPDATE public.transactions SET balance = $2 WHERE uuid = $1;
\ No newline at end of file
+UPDATE public.transactions SET balance = $2 WHERE uuid = $1;
+
+-- name: GetAccountTransactionVelocity :one
+-- The window is on the creation time, the event date is set by the client. Only the transactions of the operation
+-- types are counted, all of them when operation_type_ids is empty. The reversals are not counted
+SELECT COUNT(*)::BIGINT                       AS txn_count,
+       COALESCE(SUM(ABS(amount)), 0)::FLOAT AS total_amount
+FROM public.transactions
+WHERE account_id = sqlc.arg(account_id)
+  AND created_at >= sqlc.arg(since)
+  AND reversal_of IS NULL
+  AND (COALESCE(CARDINALITY(sqlc.arg(operation_type_ids)::BIGINT[]), 0) = 0 OR operation_type_id = ANY(sqlc.arg(operation_type_ids)::BIGINT[]));
+
+-- name: GetAccountTransactionAmountStats :one
+-- Only the transactions of the operation types are counted, all of them when operation_type_ids is empty.
+-- The reversals are not counted
+SELECT COUNT(*)::BIGINT                               AS txn_count,
+       COALESCE(AVG(ABS(amount)), 0)::FLOAT         AS mean_amount,
+       COALESCE(STDDEV_SAMP(ABS(amount)), 0)::FLOAT AS stddev_amount
+FROM public.transactions
+WHERE account_id = sqlc.arg(account_id)
+  AND reversal_of IS NULL
+  AND (COALESCE(CARDINALITY(sqlc.arg(operation_type_ids)::BIGINT[]), 0) = 0 OR operation_type_id = ANY(sqlc.arg(operation_type_ids)::BIGINT[]));
+
+
+-- name: GetTransactionForUpdate :one
+SELECT uuid, account_id, amount, operation_type_id, balance, reversal_of
+FROM public.transactions
+WHERE uuid = $1
+FOR UPDATE;
+
+-- name: GetReversedAmount :one
+-- The amount of the transaction already reversed. A reversal that was reversed itself, e.g. a clawed back
+-- provisional credit, doesn't count
+SELECT COALESCE(SUM(ABS(r.amount) - COALESCE((SELECT SUM(ABS(rr.amount)) FROM public.transactions rr WHERE rr.reversal_of = r.uuid), 0)), 0)::FLOAT AS reversed_amount
+FROM public.transactions r
+WHERE r.reversal_of = sqlc.arg(transaction_id)::UUID;
+
+-- name: CreateReversalTransaction :one
+INSERT INTO public.transactions (account_id, amount, operation_type_id, balance, event_date, reversal_of)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, reversal_of, updated_at;
+
+-- name: UpdateTransactionAnnotations :one
+-- Only the tags and the notes of a transaction can be updated, the fields that are null are left unchanged
+UPDATE public.transactions
+SET tags  = COALESCE(sqlc.narg('tags')::TEXT[], tags),
+    notes = COALESCE(sqlc.narg('notes'), notes)
+WHERE uuid = @uuid
+RETURNING uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, merchant_id, merchant_country, mcc, reversal_of, metadata, tags, notes, updated_at;
+
+-- name: ListTransactions :many
+-- Keyset pagination on serial_id, pass 0 as after_serial_id to get the first page.
+-- The metadata must contain all the pairs in metadata and all the keys in metadata_keys, and the tags all the tags.
+-- Empty filters match every transaction
+SELECT uuid, serial_id, account_id, amount, operation_type_id, event_date, balance, merchant_id, merchant_country, mcc, reversal_of, metadata, tags, notes, updated_at
+FROM public.transactions
+WHERE (sqlc.narg('account_id')::UUID IS NULL OR account_id = sqlc.narg('account_id')::UUID)
+  AND metadata @> @metadata::JSONB
+  AND metadata ?& @metadata_keys::TEXT[]
+  AND tags @> @tags::TEXT[]
+  AND serial_id > @after_serial_id
+ORDER BY serial_id
+LIMIT @page_size;
@@ -1,2 +1,29 @@
 -- name: UserExists :one
 SELECT EXISTS(SELECT 1 FROM public.users WHERE uuid = $1) AS exists;
+
+
+-- name: CreateUser :one
+INSERT INTO public.users (first_name, middle_name, last_name, phone_number, email)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetUserByUUID :one
+SELECT * FROM public.users WHERE uuid = $1;
+
+-- name: UpdateUser :one
+-- Only the fields that are not null are updated
+UPDATE public.users
+SET first_name   = COALESCE(sqlc.narg('first_name'), first_name),
+    middle_name  = COALESCE(sqlc.narg('middle_name'), middle_name),
+    last_name    = COALESCE(sqlc.narg('last_name'), last_name),
+    phone_number = COALESCE(sqlc.narg('phone_number'), phone_number),
+    email        = COALESCE(sqlc.narg('email'), email)
+WHERE uuid = @uuid
+RETURNING *;
+
+-- name: ListUsers :many
+-- Keyset pagination on serial_id, pass 0 as after_serial_id to get the first page
+SELECT * FROM public.users
+WHERE serial_id > @after_serial_id
+ORDER BY serial_id
+LIMIT @page_size;
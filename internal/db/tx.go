@@ -0,0 +1,35 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+)
+
+// TxBeginner starts database transactions. It is implemented by *pgxpool.Pool
+type TxBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// RunInTx runs fn in a database transaction. The transaction is committed when fn succeeds and rolled back otherwise
+func RunInTx(ctx context.Context, conn TxBeginner, fn func(tx pgx.Tx) error) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("RunInTx: failed to begin DB transaction: %w", err)
+	}
+
+	defer func(tx pgx.Tx, ctx context.Context) {
+		_ = tx.Rollback(ctx)
+	}(tx, ctx)
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("RunInTx: failed to commit DB transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Status is the status of the service or of one of its components
+type Status string
+
+const (
+	StatusUp   Status = "UP"
+	StatusDown Status = "DOWN"
+)
+
+// Checker checks a component the service needs to serve the requests, e.g. the database. The subsystems register
+// their checkers to the registry, the service is ready when all of them pass
+type Checker interface {
+	// Name is the name of the component in the report, e.g. database
+	Name() string
+	// Check returns an error when the component can't be used. It must return when the context is done
+	Check(ctx context.Context) error
+}
+
+// CheckFunc checks a component
+type CheckFunc func(ctx context.Context) error
+
+// funcChecker is the Checker of a CheckFunc
+type funcChecker struct {
+	name  string
+	check CheckFunc
+}
+
+// NewChecker returns a Checker named name that runs check
+func NewChecker(name string, check CheckFunc) Checker {
+	return &funcChecker{name: name, check: check}
+}
+
+func (c *funcChecker) Name() string {
+	return c.name
+}
+
+func (c *funcChecker) Check(ctx context.Context) error {
+	return c.check(ctx)
+}
+
+// ComponentReport is the result of the check of a component
+type ComponentReport struct {
+	Status Status `json:"status"`
+	// Error is why the component is down, it is empty when it is up
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latency_ms"`
+}
+
+// Report is the result of the checks of all the components, the service is down when a component is down
+type Report struct {
+	Status     Status                      `json:"status"`
+	Components map[string]*ComponentReport `json:"components"`
+}
+
+// Registry runs the checks of the components
+type Registry struct {
+	mu       sync.RWMutex
+	checkers []Checker
+	// timeout bounds every check, a check that takes longer fails
+	timeout time.Duration
+}
+
+// NewRegistry returns a Registry whose checks fail when they take longer than timeout
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{timeout: timeout}
+}
+
+// Register adds the checkers to the checks of the readiness
+func (r *Registry) Register(checkers ...Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkers = append(r.checkers, checkers...)
+}
+
+// Check runs all the checks concurrently and reports the status of every component. A check that panics fails
+func (r *Registry) Check(ctx context.Context) *Report {
+	r.mu.RLock()
+	checkers := append([]Checker(nil), r.checkers...)
+	r.mu.RUnlock()
+
+	report := &Report{Status: StatusUp, Components: make(map[string]*ComponentReport, len(checkers))}
+	results := make([]*ComponentReport, len(checkers))
+
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = r.check(ctx, checker)
+		}()
+	}
+	wg.Wait()
+
+	for i, checker := range checkers {
+		report.Components[checker.Name()] = results[i]
+		if results[i].Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+func (r *Registry) check(ctx context.Context, checker Checker) *ComponentReport {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+
+	// The check runs apart, so that a check that doesn't watch the context still fails at the timeout
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if p := recover(); p != nil {
+				done <- fmt.Errorf("the check panicked: %v", p)
+			}
+		}()
+		done <- checker.Check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return down(start, err)
+		}
+	case <-ctx.Done():
+		return down(start, fmt.Errorf("the check timed out after %s", r.timeout))
+	}
+
+	return &ComponentReport{Status: StatusUp, LatencyMs: latencyMs(start)}
+}
+
+func down(start time.Time, err error) *ComponentReport {
+	return &ComponentReport{Status: StatusDown, Error: err.Error(), LatencyMs: latencyMs(start)}
+}
+
+func latencyMs(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
@@ -0,0 +1,133 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_Check(t *testing.T) {
+	t.Run("all the components are up", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register(
+			NewChecker("database", func(context.Context) error { return nil }),
+			NewChecker("scheduler", func(context.Context) error { return nil }),
+		)
+
+		report := registry.Check(context.Background())
+
+		assert.Equal(t, StatusUp, report.Status)
+		assert.Len(t, report.Components, 2)
+		assert.Equal(t, StatusUp, report.Components["database"].Status)
+		assert.Empty(t, report.Components["database"].Error)
+		assert.Equal(t, StatusUp, report.Components["scheduler"].Status)
+	})
+
+	t.Run("a component is down", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register(
+			NewChecker("database", func(context.Context) error { return errors.New("connection refused") }),
+			NewChecker("scheduler", func(context.Context) error { return nil }),
+		)
+
+		report := registry.Check(context.Background())
+
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, StatusDown, report.Components["database"].Status)
+		assert.Equal(t, "connection refused", report.Components["database"].Error)
+		assert.Equal(t, StatusUp, report.Components["scheduler"].Status)
+	})
+
+	t.Run("a check times out", func(t *testing.T) {
+		block := make(chan struct{})
+		defer close(block)
+
+		registry := NewRegistry(10 * time.Millisecond)
+		registry.Register(NewChecker("database", func(context.Context) error {
+			// The check ignores the context, it still fails at the timeout
+			<-block
+			return nil
+		}))
+
+		report := registry.Check(context.Background())
+
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, "the check timed out after 10ms", report.Components["database"].Error)
+	})
+
+	t.Run("a check panics", func(t *testing.T) {
+		registry := NewRegistry(time.Second)
+		registry.Register(NewChecker("database", func(context.Context) error { panic("nil pool") }))
+
+		report := registry.Check(context.Background())
+
+		assert.Equal(t, StatusDown, report.Status)
+		assert.Equal(t, "the check panicked: nil pool", report.Components["database"].Error)
+	})
+
+	t.Run("no components", func(t *testing.T) {
+		report := NewRegistry(time.Second).Check(context.Background())
+
+		assert.Equal(t, StatusUp, report.Status)
+		assert.Empty(t, report.Components)
+	})
+}
+
+// testClock is a Clock the test moves forward
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func TestHeartbeat_Check(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("the worker runs in time", func(t *testing.T) {
+		c := &testClock{now: now}
+		heartbeat := NewHeartbeat("scheduler", 3*time.Minute, c)
+
+		c.now = now.Add(2 * time.Minute)
+		heartbeat.Beat(nil)
+		c.now = now.Add(4 * time.Minute)
+
+		assert.Equal(t, "scheduler", heartbeat.Name())
+		assert.Nil(t, heartbeat.Check(context.Background()))
+	})
+
+	t.Run("the worker has not run yet", func(t *testing.T) {
+		c := &testClock{now: now}
+		heartbeat := NewHeartbeat("scheduler", 3*time.Minute, c)
+
+		assert.Nil(t, heartbeat.Check(context.Background()))
+	})
+
+	t.Run("the worker is stuck", func(t *testing.T) {
+		c := &testClock{now: now}
+		heartbeat := NewHeartbeat("scheduler", 3*time.Minute, c)
+
+		c.now = now.Add(4 * time.Minute)
+
+		err := heartbeat.Check(context.Background())
+		assert.EqualError(t, err, "the last run was 4m0s ago, more than 3m0s")
+	})
+
+	t.Run("the last run failed", func(t *testing.T) {
+		c := &testClock{now: now}
+		heartbeat := NewHeartbeat("scheduler", 3*time.Minute, c)
+
+		heartbeat.Beat(errors.New("connection refused"))
+
+		err := heartbeat.Check(context.Background())
+		assert.EqualError(t, err, "the last run failed: connection refused")
+
+		// The worker recovers on the next run
+		heartbeat.Beat(nil)
+		assert.Nil(t, heartbeat.Check(context.Background()))
+	})
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/clock"
+)
+
+// Heartbeat is the Checker of a background worker. The worker beats after every run, the worker is down when its
+// last run failed or when it hasn't run for longer than the max age, e.g. because it is stuck
+type Heartbeat struct {
+	name   string
+	maxAge time.Duration
+	clock  clock.Clock
+
+	mu      sync.Mutex
+	last    time.Time
+	lastErr error
+}
+
+// NewHeartbeat returns the heartbeat of a worker. The worker has until the max age to run the first time
+func NewHeartbeat(name string, maxAge time.Duration, clock clock.Clock) *Heartbeat {
+	return &Heartbeat{name: name, maxAge: maxAge, clock: clock, last: clock.Now()}
+}
+
+// Beat records a run of the worker and its error
+func (h *Heartbeat) Beat(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.last = h.clock.Now()
+	h.lastErr = err
+}
+
+func (h *Heartbeat) Name() string {
+	return h.name
+}
+
+func (h *Heartbeat) Check(_ context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.lastErr != nil {
+		return fmt.Errorf("the last run failed: %w", h.lastErr)
+	}
+
+	if age := h.clock.Now().Sub(h.last); age > h.maxAge {
+		return fmt.Errorf("the last run was %s ago, more than %s", age.Truncate(time.Second), h.maxAge)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package lifecycle
+
+import (
+	"errors"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+)
+
+// ErrInvalidTransition is returned when an account can't move from its current status to the requested one
+var ErrInvalidTransition = errors.New("INVALID_ACCOUNT_STATUS_TRANSITION")
+
+// transitions is the account state machine. It maps a status to all the statuses an account can move to from it.
+// CLOSED is a terminal status.
+var transitions = map[models.AccountStatus][]models.AccountStatus{
+	models.AccountStatusACTIVE:    {models.AccountStatusBLOCKED, models.AccountStatusSUSPENDED, models.AccountStatusCLOSED},
+	models.AccountStatusBLOCKED:   {models.AccountStatusACTIVE, models.AccountStatusCLOSED},
+	models.AccountStatusSUSPENDED: {models.AccountStatusACTIVE, models.AccountStatusBLOCKED, models.AccountStatusCLOSED},
+	models.AccountStatusCLOSED:    {},
+}
+
+// CanTransition checks if an account can move from one status to the other
+func CanTransition(from, to models.AccountStatus) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AllowsDebit checks if an account in the given status can be debited. Only active accounts can be debited.
+func AllowsDebit(status models.AccountStatus) bool {
+	return status == models.AccountStatusACTIVE
+}
+
+// AllowsCredit checks if an account in the given status can be credited.
+// Blocked and suspended accounts can still receive credits, e.g. refunds. Closed accounts can't.
+func AllowsCredit(status models.AccountStatus) bool {
+	return status != models.AccountStatusCLOSED
+}
+
+// AllowsTransaction checks if an account in the given status accepts a transaction with the given amount behavior
+func AllowsTransaction(status models.AccountStatus, amountBehavior models.AmountBehavior) bool {
+	if amountBehavior == models.AmountBehaviorPOSITIVE {
+		return AllowsCredit(status)
+	}
+
+	return AllowsDebit(status)
+}
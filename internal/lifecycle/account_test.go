@@ -0,0 +1,50 @@
+package lifecycle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to models.AccountStatus
+		allowed  bool
+	}{
+		{models.AccountStatusACTIVE, models.AccountStatusBLOCKED, true},
+		{models.AccountStatusACTIVE, models.AccountStatusCLOSED, true},
+		{models.AccountStatusBLOCKED, models.AccountStatusACTIVE, true},
+		{models.AccountStatusSUSPENDED, models.AccountStatusACTIVE, true},
+		{models.AccountStatusACTIVE, models.AccountStatusACTIVE, false},
+		{models.AccountStatusBLOCKED, models.AccountStatusSUSPENDED, false},
+		{models.AccountStatusCLOSED, models.AccountStatusACTIVE, false},
+		{models.AccountStatusCLOSED, models.AccountStatusBLOCKED, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s to %s", tt.from, tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.allowed, CanTransition(tt.from, tt.to))
+		})
+	}
+}
+
+func TestAllowsTransaction(t *testing.T) {
+	tests := []struct {
+		status        models.AccountStatus
+		debit, credit bool
+	}{
+		{models.AccountStatusACTIVE, true, true},
+		{models.AccountStatusBLOCKED, false, true},
+		{models.AccountStatusSUSPENDED, false, true},
+		{models.AccountStatusCLOSED, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			assert.Equal(t, tt.debit, AllowsTransaction(tt.status, models.AmountBehaviorNEGATIVE))
+			assert.Equal(t, tt.credit, AllowsTransaction(tt.status, models.AmountBehaviorPOSITIVE))
+		})
+	}
+}
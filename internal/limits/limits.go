@@ -0,0 +1,137 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/jackc/pgx/v4"
+)
+
+// ErrLimitExceeded is returned when a transaction would breach a spending limit
+var ErrLimitExceeded = errors.New("SPENDING_LIMIT_EXCEEDED")
+
+// ExceededError has the details of the limit that would be breached
+type ExceededError struct {
+	OperationTypeID int64              `json:"operation_type_id"`
+	Period          models.LimitPeriod `json:"period"`
+	MaxAmount       float64            `json:"max_amount"`
+}
+
+func (e *ExceededError) Error() string {
+	return fmt.Sprintf("%s: %s limit of %.2f for operation type %d", ErrLimitExceeded, e.Period, e.MaxAmount, e.OperationTypeID)
+}
+
+func (e *ExceededError) Is(target error) bool {
+	return target == ErrLimitExceeded
+}
+
+// Periods is the list of all the supported limit periods
+var Periods = []models.LimitPeriod{models.LimitPeriodDAILY, models.LimitPeriodWEEKLY, models.LimitPeriodMONTHLY}
+
+// PeriodStart returns the start of the period containing t. Periods are in UTC and weeks start on Monday.
+func PeriodStart(period models.LimitPeriod, t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch period {
+	case models.LimitPeriodWEEKLY:
+		// time.Weekday starts on Sunday(0), shift it so that Monday is 0
+		daysSinceMonday := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -daysSinceMonday)
+	case models.LimitPeriodMONTHLY:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// PeriodEnd returns the end of the period containing t, i.e. the time the usage of the period resets
+func PeriodEnd(period models.LimitPeriod, t time.Time) time.Time {
+	start := PeriodStart(period, t)
+
+	switch period {
+	case models.LimitPeriodWEEKLY:
+		return start.AddDate(0, 0, 7)
+	case models.LimitPeriodMONTHLY:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Consume adds the amount to the usage counters of all the limits of the account for the operation type.
+// The counters are only incremented when they stay within the limit, so concurrent transactions can never
+// breach a limit. It must be called in the same DB transaction that creates the transaction, so that the counters
+// are rolled back when the transaction fails.
+func Consume(ctx context.Context, querier models.Querier, accountID string, operationTypeID int64, amount float64, at time.Time) error {
+	accountLimits, err := querier.GetAccountLimitsByOperationType(ctx, models.GetAccountLimitsByOperationTypeParams{
+		AccountID:       accountID,
+		OperationTypeID: operationTypeID,
+	})
+	if err != nil {
+		return fmt.Errorf("limits.Consume: error fetching limits: %w", err)
+	}
+
+	for _, limit := range accountLimits {
+		exceeded := &ExceededError{
+			OperationTypeID: operationTypeID,
+			Period:          limit.Period,
+			MaxAmount:       limit.MaxAmount,
+		}
+
+		// The counter is only checked on update, an amount larger than the limit must be rejected before the insert
+		if amount > limit.MaxAmount {
+			return exceeded
+		}
+
+		_, err := querier.IncrementAccountLimitUsage(ctx, models.IncrementAccountLimitUsageParams{
+			AccountID:       accountID,
+			OperationTypeID: operationTypeID,
+			Period:          limit.Period,
+			PeriodStart:     PeriodStart(limit.Period, at),
+			Amount:          amount,
+			MaxAmount:       limit.MaxAmount,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return exceeded
+		}
+
+		if err != nil {
+			return fmt.Errorf("limits.Consume: error incrementing usage: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Check returns the limits of the account for the operation type that the amount would breach, without touching the
+// usage counters. It is used to quote a transaction, Consume is the only reliable check when creating one.
+func Check(ctx context.Context, querier models.Querier, accountID string, operationTypeID int64, amount float64, at time.Time) ([]*ExceededError, error) {
+	accountLimits, err := querier.GetAccountLimitsWithUsage(ctx, models.GetAccountLimitsWithUsageParams{
+		AccountID:    accountID,
+		DailyStart:   PeriodStart(models.LimitPeriodDAILY, at),
+		WeeklyStart:  PeriodStart(models.LimitPeriodWEEKLY, at),
+		MonthlyStart: PeriodStart(models.LimitPeriodMONTHLY, at),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("limits.Check: error fetching limits: %w", err)
+	}
+
+	var exceeded []*ExceededError
+	for _, limit := range accountLimits {
+		if limit.OperationTypeID != operationTypeID || limit.UsedAmount+amount <= limit.MaxAmount {
+			continue
+		}
+
+		exceeded = append(exceeded, &ExceededError{
+			OperationTypeID: operationTypeID,
+			Period:          limit.Period,
+			MaxAmount:       limit.MaxAmount,
+		})
+	}
+
+	return exceeded, nil
+}
@@ -0,0 +1,131 @@
+package limits
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dummyAccountID     = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+	dummyOperationType = int64(3)
+)
+
+func TestPeriodStartAndEnd(t *testing.T) {
+	// Wednesday
+	at := time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		period models.LimitPeriod
+		start  time.Time
+		end    time.Time
+	}{
+		{models.LimitPeriodDAILY, time.Date(2024, time.July, 17, 0, 0, 0, 0, time.UTC), time.Date(2024, time.July, 18, 0, 0, 0, 0, time.UTC)},
+		{models.LimitPeriodWEEKLY, time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), time.Date(2024, time.July, 22, 0, 0, 0, 0, time.UTC)},
+		{models.LimitPeriodMONTHLY, time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.period), func(t *testing.T) {
+			assert.Equal(t, tt.start, PeriodStart(tt.period, at))
+			assert.Equal(t, tt.end, PeriodEnd(tt.period, at))
+		})
+	}
+
+	t.Run("week starting on a sunday belongs to the previous monday", func(t *testing.T) {
+		sunday := time.Date(2024, time.July, 21, 23, 0, 0, 0, time.UTC)
+		assert.Equal(t, time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC), PeriodStart(models.LimitPeriodWEEKLY, sunday))
+	})
+}
+
+func TestConsume(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+
+	dailyLimit := &models.AccountLimit{
+		AccountID:       dummyAccountID,
+		OperationTypeID: dummyOperationType,
+		Period:          models.LimitPeriodDAILY,
+		MaxAmount:       500,
+	}
+
+	t.Run("should increment the usage within the limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		querier := mock.NewMockQuerier(ctrl)
+
+		querier.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return([]*models.AccountLimit{dailyLimit}, nil)
+		querier.EXPECT().IncrementAccountLimitUsage(gomock.Any(), models.IncrementAccountLimitUsageParams{
+			AccountID:       dummyAccountID,
+			OperationTypeID: dummyOperationType,
+			Period:          models.LimitPeriodDAILY,
+			PeriodStart:     time.Date(2024, time.July, 17, 0, 0, 0, 0, time.UTC),
+			Amount:          100,
+			MaxAmount:       500,
+		}).Return(float64(100), nil)
+
+		assert.Nil(t, Consume(ctx, querier, dummyAccountID, dummyOperationType, 100, at))
+	})
+
+	t.Run("should fail when the counter would exceed the limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		querier := mock.NewMockQuerier(ctrl)
+
+		querier.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return([]*models.AccountLimit{dailyLimit}, nil)
+		querier.EXPECT().IncrementAccountLimitUsage(gomock.Any(), gomock.Any()).Return(float64(0), pgx.ErrNoRows)
+
+		err := Consume(ctx, querier, dummyAccountID, dummyOperationType, 100, at)
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+
+		var exceeded *ExceededError
+		assert.True(t, errors.As(err, &exceeded))
+		assert.Equal(t, models.LimitPeriodDAILY, exceeded.Period)
+	})
+
+	t.Run("should fail without touching the counter when the amount is larger than the limit", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		querier := mock.NewMockQuerier(ctrl)
+
+		querier.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return([]*models.AccountLimit{dailyLimit}, nil)
+
+		err := Consume(ctx, querier, dummyAccountID, dummyOperationType, 501, at)
+		assert.ErrorIs(t, err, ErrLimitExceeded)
+	})
+
+	t.Run("should pass when there are no limits", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		querier := mock.NewMockQuerier(ctrl)
+
+		querier.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return(nil, nil)
+
+		assert.Nil(t, Consume(ctx, querier, dummyAccountID, dummyOperationType, 10_000, at))
+	})
+}
+
+func TestCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	querier := mock.NewMockQuerier(ctrl)
+	at := time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+
+	querier.EXPECT().GetAccountLimitsWithUsage(gomock.Any(), models.GetAccountLimitsWithUsageParams{
+		AccountID:    dummyAccountID,
+		DailyStart:   time.Date(2024, time.July, 17, 0, 0, 0, 0, time.UTC),
+		WeeklyStart:  time.Date(2024, time.July, 15, 0, 0, 0, 0, time.UTC),
+		MonthlyStart: time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+	}).Return([]*models.GetAccountLimitsWithUsageRow{
+		{OperationTypeID: dummyOperationType, Period: models.LimitPeriodDAILY, MaxAmount: 500, UsedAmount: 450},
+		{OperationTypeID: dummyOperationType, Period: models.LimitPeriodMONTHLY, MaxAmount: 5000, UsedAmount: 450},
+		{OperationTypeID: 1, Period: models.LimitPeriodDAILY, MaxAmount: 10, UsedAmount: 10},
+	}, nil)
+
+	// Only the daily limit of the operation type is breached, the counters are not touched
+	exceeded, err := Check(context.Background(), querier, dummyAccountID, dummyOperationType, 100, at)
+	assert.Nil(t, err)
+	assert.Equal(t, []*ExceededError{{OperationTypeID: dummyOperationType, Period: models.LimitPeriodDAILY, MaxAmount: 500}}, exceeded)
+}
@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+type contextKey struct{}
+
+// redactors mask the values of the attributes that identify an account or a person, whatever logs them.
+// The attributes must use these keys, e.g. logger.Info("...", "account_id", accountID)
+var redactors = map[string]func(string) string{
+	"account_id":      maskKeepLast(4),
+	"document_number": maskKeepLast(2),
+}
+
+// New returns a logger that writes JSON lines with the level or above to the writer
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	}))
+}
+
+// redact masks the attributes of the redactors
+func redact(_ []string, a slog.Attr) slog.Attr {
+	mask, ok := redactors[a.Key]
+	if !ok {
+		return a
+	}
+
+	if a.Value.Kind() == slog.KindString {
+		return slog.String(a.Key, mask(a.Value.String()))
+	}
+
+	// The values that are not strings can't be masked, they are dropped
+	return slog.String(a.Key, "[REDACTED]")
+}
+
+// maskKeepLast masks all but the last n characters of the value, so that the logs can still be told apart
+func maskKeepLast(n int) func(string) string {
+	return func(value string) string {
+		if len(value) <= n {
+			return strings.Repeat("*", len(value))
+		}
+
+		return strings.Repeat("*", len(value)-n) + value[len(value)-n:]
+	}
+}
+
+// NewContext returns a copy of the context with the logger
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger of the context, e.g. the logger of a request with its request ID.
+// It returns the default logger when the context has none
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
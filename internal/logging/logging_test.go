@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const dummyAccountID = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+
+// readLines parses the JSON lines written by the logger
+func readLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var lines []map[string]any
+	for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+		entry := map[string]any{}
+		assert.Nil(t, json.Unmarshal(line, &entry))
+		lines = append(lines, entry)
+	}
+
+	return lines
+}
+
+func TestNew_Redacts(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := New(buf, slog.LevelInfo)
+
+	logger.Info("createAndRespondAccount: document number is already used by another account",
+		"account_id", dummyAccountID, "document_number", "52998224725", "user_id", "88e0e837")
+	logger.With("account_id", 42).Info("not a string")
+	logger.Debug("below the level")
+
+	lines := readLines(t, buf)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "********************************d611", lines[0]["account_id"])
+	assert.Equal(t, "*********25", lines[0]["document_number"])
+	assert.Equal(t, "88e0e837", lines[0]["user_id"])
+	assert.Equal(t, "[REDACTED]", lines[1]["account_id"])
+}
+
+func TestMaskKeepLast(t *testing.T) {
+	assert.Equal(t, "***", maskKeepLast(4)("abc"))
+	assert.Equal(t, "**cdef", maskKeepLast(4)("abcdef"))
+	assert.Equal(t, "", maskKeepLast(2)(""))
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, slog.Default(), FromContext(context.Background()))
+
+	logger := New(&bytes.Buffer{}, slog.LevelInfo)
+	assert.Equal(t, logger, FromContext(NewContext(context.Background(), logger)))
+}
@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/tracing"
+)
+
+// RequestIDHeader carries the ID of the request from the caller and back in the response
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID is the request ID accepted from the callers, other IDs are replaced so that they can't inject
+// anything in the logs
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// responseWriter records the status and the size of the response for the access log. It tells the request ID
+// and the trace ID to the JSONWriter, which adds them to the errors, and the logger of the request, which it logs with
+type responseWriter struct {
+	http.ResponseWriter
+	requestID   string
+	traceID     string
+	logger      *slog.Logger
+	status      int
+	bytes       int
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the features of the underlying writer, e.g. flushing
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) RequestID() string {
+	return w.requestID
+}
+
+func (w *responseWriter) TraceID() string {
+	return w.traceID
+}
+
+func (w *responseWriter) Logger() *slog.Logger {
+	return w.logger
+}
+
+// NewMiddleware returns a middleware that assigns an ID to every request, or keeps the X-Request-ID of the caller,
+// adds a logger with the ID to the context and writes an access log line once the request is served. The trace ID of
+// the request is logged too when the request is traced
+func NewMiddleware(logger *slog.Logger) func(h http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := RequestID(r.Header.Get(RequestIDHeader))
+
+			w.Header().Set(RequestIDHeader, requestID)
+
+			requestLogger := logger.With("request_id", requestID)
+
+			traceID := tracing.TraceID(r.Context())
+			if traceID != "" {
+				requestLogger = requestLogger.With("trace_id", traceID)
+			}
+
+			rw := &responseWriter{ResponseWriter: w, requestID: requestID, traceID: traceID, logger: requestLogger, status: http.StatusOK}
+
+			next.ServeHTTP(rw, r.WithContext(NewContext(r.Context(), requestLogger)))
+
+			level := slog.LevelInfo
+			if rw.status >= http.StatusInternalServerError {
+				level = slog.LevelError
+			}
+
+			// The route template is logged instead of the path, so that the IDs of the accounts aren't logged
+			requestLogger.LogAttrs(r.Context(), level, "request",
+				slog.String("method", r.Method),
+				slog.String("route", routeTemplate(r)),
+				slog.Int("status", rw.status),
+				slog.Int("bytes", rw.bytes),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.String("remote_addr", r.RemoteAddr),
+				slog.String("user_agent", r.UserAgent()),
+			)
+		})
+	}
+}
+
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+
+	return ""
+}
+
+// RequestID returns the request ID of the caller when it is valid, and a new ID otherwise
+func RequestID(callerID string) string {
+	if !validRequestID.MatchString(callerID) {
+		return newRequestID()
+	}
+
+	return callerID
+}
+
+// newRequestID returns a random 128-bit ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
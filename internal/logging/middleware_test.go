@@ -0,0 +1,125 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/tracing"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// newTestRouter mounts a route that logs and fails, behind the middleware
+func newTestRouter(logger *slog.Logger) *mux.Router {
+	writer := response.NewJSONWriter()
+
+	r := mux.NewRouter()
+	api := r.PathPrefix("/api/").Subrouter()
+	api.Use(NewMiddleware(logger))
+	api.HandleFunc("/v1/accounts/{accountID}", func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Error("fetchAndRespondAccountDetails: failed to fetch account details",
+			"account_id", mux.Vars(r)["accountID"])
+		writer.DefaultError(w)
+	})
+
+	return r
+}
+
+func TestMiddleware_AssignsRequestID(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts/"+dummyAccountID, nil)
+	newTestRouter(New(buf, slog.LevelInfo)).ServeHTTP(rr, req)
+
+	requestID := rr.Header().Get(RequestIDHeader)
+	assert.Len(t, requestID, 32)
+
+	// The error carries the ID of the request
+	res := &struct {
+		Error *response.APIError `json:"error"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Equal(t, requestID, res.Error.RequestID)
+
+	// The log of the handler and the access log carry the ID too
+	lines := readLines(t, buf)
+	assert.Len(t, lines, 2)
+
+	assert.Equal(t, requestID, lines[0]["request_id"])
+	assert.Equal(t, "********************************d611", lines[0]["account_id"])
+
+	access := lines[1]
+	assert.Equal(t, requestID, access["request_id"])
+	assert.Equal(t, "ERROR", access["level"])
+	assert.Equal(t, "request", access["msg"])
+	assert.Equal(t, http.MethodGet, access["method"])
+	assert.Equal(t, "/api/v1/accounts/{accountID}", access["route"])
+	assert.Equal(t, float64(http.StatusInternalServerError), access["status"])
+	assert.Contains(t, access, "latency_ms")
+	assert.Greater(t, access["bytes"], float64(0))
+	assert.NotContains(t, buf.String(), dummyAccountID)
+}
+
+func TestMiddleware_PropagatesRequestID(t *testing.T) {
+	tests := map[string]struct {
+		requestID string
+		kept      bool
+	}{
+		"valid ID":     {"7f6c0c1e-2f57-4b8a-9d55-5b1a3b2c9e11", true},
+		"injection":    {"id\n{\"level\":\"ERROR\"}", false},
+		"too long ID":  {string(bytes.Repeat([]byte("a"), 129)), false},
+		"no ID at all": {"", false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts/"+dummyAccountID, nil)
+			req.Header.Set(RequestIDHeader, tt.requestID)
+
+			newTestRouter(New(&bytes.Buffer{}, slog.LevelInfo)).ServeHTTP(rr, req)
+
+			if tt.kept {
+				assert.Equal(t, tt.requestID, rr.Header().Get(RequestIDHeader))
+				return
+			}
+
+			assert.NotEqual(t, tt.requestID, rr.Header().Get(RequestIDHeader))
+			assert.Regexp(t, "^[0-9a-f]{32}$", rr.Header().Get(RequestIDHeader))
+		})
+	}
+}
+
+func TestMiddleware_LogsTraceID(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	buf := &bytes.Buffer{}
+
+	r := newTestRouter(New(buf, slog.LevelInfo))
+	// The requests are traced before they are logged, as in api.Routes. No spans are exported, the trace of the
+	// caller is still followed
+	traced := mux.NewRouter()
+	traced.PathPrefix("/api/").Handler(tracing.Middleware(r))
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts/"+dummyAccountID, nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	traced.ServeHTTP(rr, req)
+
+	res := &struct {
+		Error *response.APIError `json:"error"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", res.Error.TraceID)
+
+	for _, line := range readLines(t, buf) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", line["trace_id"])
+	}
+}
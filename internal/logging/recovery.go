@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"net/http"
+	"runtime/debug"
+
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+// NewRecoveryMiddleware returns a middleware that recovers the handlers that panic. The panic is logged with its
+// stack trace and the caller gets the default error, instead of a dropped connection. It runs after the logging
+// middleware, so that the panic is logged with the ID of the request and the error carries it
+func NewRecoveryMiddleware(jsonWriter *response.JSONWriter) func(h http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				p := recover()
+				if p == nil {
+					return
+				}
+
+				// The handlers abort a response already sent with http.ErrAbortHandler, the server handles it
+				if p == http.ErrAbortHandler {
+					panic(p)
+				}
+
+				FromContext(r.Context()).Error("recovery: the handler panicked",
+					"method", r.Method,
+					"route", routeTemplate(r),
+					"panic", p,
+					"stack", string(debug.Stack()),
+				)
+
+				jsonWriter.DefaultError(w)
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
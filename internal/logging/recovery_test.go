@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	buf := &bytes.Buffer{}
+	writer := response.NewJSONWriter()
+
+	r := mux.NewRouter()
+	r.Use(NewMiddleware(New(buf, slog.LevelInfo)))
+	r.Use(NewRecoveryMiddleware(writer))
+	r.HandleFunc("/v1/accounts", func(w http.ResponseWriter, r *http.Request) {
+		// The handler panics, e.g. like the validator on data that isn't a struct
+		panic("validator: (nil string)")
+	}).Methods(http.MethodPost)
+
+	// Call the server
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/v1/accounts", nil))
+
+	// Check the results
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+
+	res := &struct {
+		Error *response.APIError `json:"error"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Equal(t, response.DefaultErr.Code, res.Error.Code)
+	assert.Equal(t, rr.Header().Get(RequestIDHeader), res.Error.RequestID)
+
+	// The panic is logged with its stack, then the request is logged as failed
+	lines := readLines(t, buf)
+	assert.Len(t, lines, 2)
+
+	assert.Equal(t, "recovery: the handler panicked", lines[0]["msg"])
+	assert.Equal(t, "/v1/accounts", lines[0]["route"])
+	assert.Contains(t, lines[0]["stack"], "TestRecoveryMiddleware")
+	assert.Equal(t, rr.Header().Get(RequestIDHeader), lines[0]["request_id"])
+
+	assert.Equal(t, "request", lines[1]["msg"])
+	assert.Equal(t, float64(http.StatusInternalServerError), lines[1]["status"])
+}
+
+func TestRecoveryMiddleware_AbortHandler(t *testing.T) {
+	r := mux.NewRouter()
+	r.Use(NewRecoveryMiddleware(response.NewJSONWriter()))
+	r.HandleFunc("/v1/accounts", func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})
+
+	// The abort is left to the server, which closes the connection
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/accounts", nil))
+	})
+}
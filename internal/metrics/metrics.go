@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "pismo"
+
+// Registry has all the metrics of the service, it is served by Handler
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "The HTTP requests served, by method, route template and status.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "The latency of the HTTP requests, by method, route template and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	transactionsCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_created_total",
+		Help:      "The transactions created, by operation type and whether they reverse another transaction.",
+	}, []string{"operation_type_id", "reversal"})
+
+	dischargedAmount = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "discharged_amount",
+		Help:      "The amount of debt paid by each credit or debit reversal that discharged debts, by source.",
+		Buckets:   []float64{1, 5, 10, 50, 100, 500, 1000, 5000, 10000, 50000},
+	}, []string{"source"})
+)
+
+// The sources of the discharges
+const (
+	DischargeSourceCredit   = "credit"
+	DischargeSourceReversal = "reversal"
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpRequestDuration,
+		transactionsCreated,
+		dischargedAmount,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// TransactionCreated counts a transaction once it is committed
+func TransactionCreated(operationTypeID int64, reversal bool) {
+	transactionsCreated.WithLabelValues(strconv.FormatInt(operationTypeID, 10), strconv.FormatBool(reversal)).Inc()
+}
+
+// Discharged records the amount of debt paid by a credit or a debit reversal once it is committed.
+// Nothing is recorded when no debt was paid
+func Discharged(source string, amount float64) {
+	if amount <= 0 {
+		return
+	}
+
+	dischargedAmount.WithLabelValues(source).Observe(amount)
+}
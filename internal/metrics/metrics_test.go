@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+)
+
+// histogramCount reads the number of observations of a histogram
+func histogramCount(t *testing.T, h prometheus.Observer) uint64 {
+	t.Helper()
+
+	m := &dto.Metric{}
+	assert.Nil(t, h.(prometheus.Metric).Write(m))
+
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestMiddleware(t *testing.T) {
+	r := mux.NewRouter()
+	api := r.PathPrefix("/api/").Subrouter()
+	api.Use(Middleware)
+	api.HandleFunc("/v1/accounts/{accountID}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	labels := []string{http.MethodGet, "/api/v1/accounts/{accountID}", "404"}
+	requests := testutil.ToFloat64(httpRequests.WithLabelValues(labels...))
+	observations := histogramCount(t, httpRequestDuration.WithLabelValues(labels...))
+
+	for _, id := range []string{"115be6d7-6d9a-4391-b3ee-1d753ac7d611", "88e0e837-3c4f-4a5e-9f43-0d2b8a8f4c21"} {
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/v1/accounts/"+id, nil))
+		assert.Equal(t, http.StatusNotFound, rr.Code)
+	}
+
+	// Both requests are counted under the route template, not under their paths
+	assert.Equal(t, requests+2, testutil.ToFloat64(httpRequests.WithLabelValues(labels...)))
+	assert.Equal(t, observations+2, histogramCount(t, httpRequestDuration.WithLabelValues(labels...)))
+}
+
+func TestTransactionCreated(t *testing.T) {
+	purchases := testutil.ToFloat64(transactionsCreated.WithLabelValues("1", "false"))
+	reversals := testutil.ToFloat64(transactionsCreated.WithLabelValues("1", "true"))
+
+	TransactionCreated(1, false)
+	TransactionCreated(1, true)
+	TransactionCreated(1, true)
+
+	assert.Equal(t, purchases+1, testutil.ToFloat64(transactionsCreated.WithLabelValues("1", "false")))
+	assert.Equal(t, reversals+2, testutil.ToFloat64(transactionsCreated.WithLabelValues("1", "true")))
+}
+
+func TestDischarged(t *testing.T) {
+	credits := histogramCount(t, dischargedAmount.WithLabelValues(DischargeSourceCredit))
+
+	Discharged(DischargeSourceCredit, 60)
+	// A credit that paid no debt isn't a discharge
+	Discharged(DischargeSourceCredit, 0)
+
+	assert.Equal(t, credits+1, histogramCount(t, dischargedAmount.WithLabelValues(DischargeSourceCredit)))
+}
+
+func TestHandler(t *testing.T) {
+	TransactionCreated(4, false)
+
+	rr := httptest.NewRecorder()
+	Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Contains(t, rr.Body.String(), `pismo_transactions_created_total{operation_type_id="4",reversal="false"}`)
+	assert.Contains(t, rr.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,214 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+)
+
+// maxPeekedBody is the largest request body read to find the account of a request. The bodies are limited to the
+// limit of their route before, see request.LimitBody
+const maxPeekedBody = 1 << 20
+
+var ErrRateLimitExceeded = errors.New("RATE_LIMIT_EXCEEDED")
+
+// Limiter limits the requests to the routes with the first rule that matches each of them
+type Limiter struct {
+	rules *Rules
+	store Store
+}
+
+// NewLimiter reads the rules of the rules file, no request is limited when the file is empty
+func NewLimiter(rulesFile string, store Store) (*Limiter, error) {
+	rules := &Rules{}
+
+	if rulesFile != "" {
+		var err error
+		if rules, err = loadRules(rulesFile); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Limiter{rules: rules, store: store}, nil
+}
+
+// Take counts the request against the rule of its route that counts the requests by credentials or account,
+// once the caller is authenticated. It returns nil when no rule applies
+func (l *Limiter) Take(r *http.Request) (*Result, error) {
+	return l.take(r, false)
+}
+
+// TakeByIP counts the request against the rule of its route that counts the requests by IP, before the caller is
+// authenticated, so that the requests with bad credentials are limited too. It returns nil when no rule applies
+func (l *Limiter) TakeByIP(r *http.Request) (*Result, error) {
+	return l.take(r, true)
+}
+
+func (l *Limiter) take(r *http.Request, byIP bool) (*Result, error) {
+	rule := l.rules.match(r.Method, routeTemplate(r), byIP)
+	if rule == nil {
+		return nil, nil
+	}
+
+	key, err := requestKey(r, rule.KeyBy)
+	if err != nil {
+		return nil, err
+	}
+
+	// The name of the rule is part of the key, so every rule has its own counters
+	return l.store.Take(r.Context(), rule.Name+":"+key, rule)
+}
+
+// NewMiddleware returns a middleware that limits the requests of the authenticated callers with the rules that
+// count them by credentials or account, see newMiddleware. It runs after the authentication
+func NewMiddleware(limiter *Limiter, jsonWriter *response.JSONWriter) func(h http.Handler) http.Handler {
+	return newMiddleware(limiter.Take, jsonWriter)
+}
+
+// NewIPMiddleware returns a middleware that limits the requests with the rules that count them by IP, see
+// newMiddleware. It runs before the authentication, so that the requests with bad credentials are counted
+func NewIPMiddleware(limiter *Limiter, jsonWriter *response.JSONWriter) func(h http.Handler) http.Handler {
+	return newMiddleware(limiter.TakeByIP, jsonWriter)
+}
+
+// newMiddleware returns a middleware that responds with a 429 to the requests over the limit of their route,
+// and adds the X-RateLimit-* headers to the others. The requests are allowed when the store fails
+func newMiddleware(take func(r *http.Request) (*Result, error), jsonWriter *response.JSONWriter) func(h http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			result, err := take(r)
+			if err != nil {
+				logging.FromContext(r.Context()).Error("rateLimitMiddleware: failed to count request, allowing it", "error", err)
+			}
+
+			if result == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			w.Header().Set("X-RateLimit-Reset", ceilSeconds(result.Reset))
+
+			if !result.Allowed {
+				w.Header().Set("Retry-After", ceilSeconds(result.RetryAfter))
+				jsonWriter.TooManyRequest(w, response.NewError(response.RateLimitExceeded, ErrRateLimitExceeded.Error(),
+					fmt.Sprintf("Retry after %s seconds", ceilSeconds(result.RetryAfter)), nil))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// routeTemplate returns the template of the matched route, e.g. /api/v1/accounts/{accountID}, so that the rules
+// apply to every resource of the route
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if template, err := route.GetPathTemplate(); err == nil {
+			return template
+		}
+	}
+
+	return r.URL.Path
+}
+
+// requestKey returns what the request is counted by. The requests on no account are counted by their credentials,
+// and the requests without credentials, on the routes that don't authenticate, by IP
+func requestKey(r *http.Request, keyBy KeyBy) (string, error) {
+	switch keyBy {
+	case KeyByAccount:
+		// The account isn't authorized yet, the requests of a caller on an account are counted apart from the ones of
+		// the other callers, so that nobody uses up the requests of an account they don't own
+		caller, err := requestKey(r, KeyByAPIKey)
+		if err != nil {
+			return "", err
+		}
+
+		accountID, err := accountID(r)
+		if err != nil {
+			return "", err
+		}
+
+		if accountID != "" {
+			return caller + ":account:" + accountID, nil
+		}
+
+		return caller, nil
+	case KeyByAPIKey:
+		if identity, ok := auth.FromContext(r.Context()); ok {
+			return string(identity.Method) + ":" + identity.Subject, nil
+		}
+
+		return requestKey(r, KeyByIP)
+	default:
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+
+		return "ip:" + host, nil
+	}
+}
+
+// accountID finds the account of the request in the path, the query or the JSON body, in that order
+func accountID(r *http.Request) (string, error) {
+	if accountID := mux.Vars(r)["accountID"]; accountID != "" {
+		return accountID, nil
+	}
+
+	if accountID := r.URL.Query().Get("account_id"); accountID != "" {
+		return accountID, nil
+	}
+
+	if r.Body == nil || r.Body == http.NoBody {
+		return "", nil
+	}
+
+	// The body is read and put back for the handler, the part that is read is put in front of the rest
+	peeked, err := io.ReadAll(io.LimitReader(r.Body, maxPeekedBody))
+
+	var maxBytesErr *http.MaxBytesError
+	if err != nil && !errors.As(err, &maxBytesErr) {
+		return "", fmt.Errorf("ratelimit.accountID: error reading body: %w", err)
+	}
+
+	r.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(peeked), r.Body), Closer: r.Body}
+
+	// A body over the limit of the route has no account, the handler fails to read it again and rejects it
+	if maxBytesErr != nil {
+		return "", nil
+	}
+
+	body := struct {
+		AccountID string `json:"account_id"`
+	}{}
+
+	// A body that isn't JSON has no account, the handler rejects it
+	_ = json.Unmarshal(peeked, &body)
+
+	return body.AccountID, nil
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// ceilSeconds formats the duration in whole seconds, rounded up so that the caller doesn't retry too early
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,249 @@
+package ratelimit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/pkg/http/request"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dummyAccountID      = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+	dummyOtherAccountID = "9f0f9b5e-2c1d-4e0b-8b1a-3f4c5d6e7f80"
+)
+
+const testRules = `
+rules:
+  - name: create-transaction
+    method: POST
+    path: /api/v1/transactions
+    key_by: account
+    requests: 1
+    period: 1m
+  - name: default
+    key_by: api_key
+    requests: 2
+    period: 1m
+`
+
+// failingStore is a store that is down
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, *Rule) (*Result, error) {
+	return nil, errors.New("connection refused")
+}
+
+func writeRules(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rate_limits.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	return path
+}
+
+// newTestRouter mounts the routes like the API, behind the middleware
+func newTestRouter(t *testing.T, limiter *Limiter) *mux.Router {
+	t.Helper()
+
+	echoBody := func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}
+
+	r := mux.NewRouter()
+	v1Router := r.PathPrefix("/api/").Subrouter().PathPrefix("/v1/").Subrouter()
+	v1Router.Use(NewMiddleware(limiter, response.NewJSONWriter()))
+
+	transactions := v1Router.PathPrefix("/transactions").Subrouter()
+	transactions.HandleFunc("", echoBody).Methods(http.MethodPost)
+	transactions.HandleFunc("", echoBody).Methods(http.MethodGet)
+
+	return r
+}
+
+func newRequest(method, target string, body []byte, identity *auth.Identity) *http.Request {
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	if identity != nil {
+		req = req.WithContext(auth.NewContext(req.Context(), identity))
+	}
+
+	return req
+}
+
+func TestMiddleware_LimitsPerRoute(t *testing.T) {
+	limiter, err := NewLimiter(writeRules(t, testRules), NewMemoryStore(&testClock{now: dummyNow}))
+	assert.Nil(t, err)
+
+	router := newTestRouter(t, limiter)
+	partner := &auth.Identity{Subject: "partner", Method: auth.MethodAPIKey}
+
+	body, _ := json.Marshal(map[string]any{"account_id": dummyAccountID, "amount": 10})
+
+	// The first transaction of the account is allowed, and the handler still reads the body
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest(http.MethodPost, "/api/v1/transactions", body, partner))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, string(body), rr.Body.String())
+	assert.Equal(t, "1", rr.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "0", rr.Header().Get("X-RateLimit-Remaining"))
+	assert.Equal(t, "60", rr.Header().Get("X-RateLimit-Reset"))
+
+	// The second one is over the limit of the route
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest(http.MethodPost, "/api/v1/transactions", body, partner))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+	assert.Equal(t, "60", rr.Header().Get("Retry-After"))
+
+	res := &struct {
+		Error *response.APIError `json:"error"`
+	}{}
+	assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+	assert.Equal(t, response.RateLimitExceeded, res.Error.Code)
+
+	// Another caller on the same account isn't limited, it can't use up the requests of the partner
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest(http.MethodPost, "/api/v1/transactions", body, &auth.Identity{Subject: "other", Method: auth.MethodAPIKey}))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// Another account isn't limited
+	otherBody, _ := json.Marshal(map[string]any{"account_id": dummyOtherAccountID, "amount": 10})
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest(http.MethodPost, "/api/v1/transactions", otherBody, partner))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	// The other routes use the default rule
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, newRequest(http.MethodGet, "/api/v1/transactions", nil, partner))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "2", rr.Header().Get("X-RateLimit-Limit"))
+	assert.Equal(t, "1", rr.Header().Get("X-RateLimit-Remaining"))
+}
+
+func TestMiddleware_WithBodyLimits(t *testing.T) {
+	limiter, err := NewLimiter(writeRules(t, testRules), NewMemoryStore(&testClock{now: dummyNow}))
+	assert.Nil(t, err)
+
+	readBody := func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}
+	}
+
+	// The routes are mounted like the API, the body limits of the router and of the routes apply before the rate limits
+	r := mux.NewRouter()
+	apiRouter := r.PathPrefix("/api/").Subrouter()
+	apiRouter.Use(request.LimitBody(64))
+	v1Router := apiRouter.PathPrefix("/v1/").Subrouter()
+	v1Router.Use(NewMiddleware(limiter, response.NewJSONWriter()))
+	v1Router.Handle("/transactions", request.RouteLimit(1024)(http.HandlerFunc(readBody))).Methods(http.MethodPost)
+
+	partner := &auth.Identity{Subject: "partner", Method: auth.MethodAPIKey}
+	body, _ := json.Marshal(map[string]any{"account_id": dummyAccountID, "notes": strings.Repeat("a", 100)})
+
+	// The route raises the limit of the router, the account of the body is counted
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, newRequest(http.MethodPost, "/api/v1/transactions", body, partner))
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("X-RateLimit-Remaining"))
+
+	// A body over the limit of the route is still counted, by the caller, and rejected by the handler
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, newRequest(http.MethodPost, "/api/v1/transactions", bytes.Repeat([]byte("a"), 2048), partner))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	assert.Equal(t, "0", rr.Header().Get("X-RateLimit-Remaining"))
+
+	rr = httptest.NewRecorder()
+	r.ServeHTTP(rr, newRequest(http.MethodPost, "/api/v1/transactions", bytes.Repeat([]byte("a"), 2048), partner))
+	assert.Equal(t, http.StatusTooManyRequests, rr.Code)
+}
+
+func TestMiddleware_WithoutRules(t *testing.T) {
+	limiter, err := NewLimiter("", failingStore{})
+	assert.Nil(t, err)
+
+	rr := httptest.NewRecorder()
+	newTestRouter(t, limiter).ServeHTTP(rr, newRequest(http.MethodGet, "/api/v1/transactions", nil, nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assert.Empty(t, rr.Header().Get("X-RateLimit-Limit"))
+}
+
+func TestMiddleware_AllowsWhenTheStoreFails(t *testing.T) {
+	limiter, err := NewLimiter(writeRules(t, testRules), failingStore{})
+	assert.Nil(t, err)
+
+	rr := httptest.NewRecorder()
+	newTestRouter(t, limiter).ServeHTTP(rr, newRequest(http.MethodGet, "/api/v1/transactions", nil, nil))
+
+	assert.Equal(t, http.StatusOK, rr.Code)
+}
+
+func TestRequestKey(t *testing.T) {
+	user := &auth.Identity{Subject: "user-1", Method: auth.MethodJWT, UserID: "user-1"}
+
+	tests := []struct {
+		name     string
+		keyBy    KeyBy
+		request  *http.Request
+		expected string
+	}{
+		{"api key", KeyByAPIKey, newRequest(http.MethodGet, "/", nil, user), "JWT:user-1"},
+		{"api key without credentials", KeyByAPIKey, newRequest(http.MethodGet, "/", nil, nil), "ip:192.0.2.1"},
+		{"ip", KeyByIP, newRequest(http.MethodGet, "/", nil, user), "ip:192.0.2.1"},
+		{"account in the path", KeyByAccount, mux.SetURLVars(newRequest(http.MethodGet, "/", nil, user), map[string]string{"accountID": dummyAccountID}), "JWT:user-1:account:" + dummyAccountID},
+		{"account in the query", KeyByAccount, newRequest(http.MethodGet, "/?account_id="+dummyAccountID, nil, user), "JWT:user-1:account:" + dummyAccountID},
+		{"account in the body", KeyByAccount, newRequest(http.MethodPost, "/", []byte(`{"account_id": "`+dummyAccountID+`"}`), user), "JWT:user-1:account:" + dummyAccountID},
+		{"no account", KeyByAccount, newRequest(http.MethodPost, "/", []byte(`not json`), user), "JWT:user-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := requestKey(tt.request, tt.keyBy)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, key)
+		})
+	}
+}
+
+func TestLoadRules_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"no name":        "rules: [{key_by: ip, requests: 1, period: 1s}]",
+		"duplicate name": "rules: [{name: a, key_by: ip, requests: 1, period: 1s}, {name: a, key_by: ip, requests: 1, period: 1s}]",
+		"unknown method": "rules: [{name: a, method: TRACE, key_by: ip, requests: 1, period: 1s}]",
+		"unknown key_by": "rules: [{name: a, key_by: user, requests: 1, period: 1s}]",
+		"no requests":    "rules: [{name: a, key_by: ip, requests: 0, period: 1s}]",
+		"no period":      "rules: [{name: a, key_by: ip, requests: 1}]",
+		"invalid period": "rules: [{name: a, key_by: ip, requests: 1, period: soon}]",
+	}
+
+	for name, contents := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewLimiter(writeRules(t, contents), NewMemoryStore(&testClock{now: dummyNow}))
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestLoadRules_DefaultFile(t *testing.T) {
+	rules, err := loadRules("../../config/rate_limits.yaml")
+	assert.Nil(t, err)
+	assert.Equal(t, "create-transaction", rules.match(http.MethodPost, "/api/v1/transactions", false).Name)
+	assert.Equal(t, "default", rules.match(http.MethodGet, "/api/v1/transactions", false).Name)
+	assert.Equal(t, time.Minute, rules.match(http.MethodGet, "/api/v1/users", false).Period)
+	assert.Equal(t, "per-ip", rules.match(http.MethodPost, "/api/v1/transactions", true).Name)
+}
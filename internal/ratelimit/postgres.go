@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/logging"
+)
+
+// PostgresStore counts the requests of every key in fixed windows of the period in Postgres, so the limits are
+// shared by all the instances of the service. A key can make up to twice the requests of the limit across the
+// boundary of two windows, which is the price of a single upsert per request
+type PostgresStore struct {
+	querier models.Querier
+	clock   clock.Clock
+
+	mu      sync.Mutex
+	sweptAt time.Time
+}
+
+func NewPostgresStore(querier models.Querier, clock clock.Clock) *PostgresStore {
+	return &PostgresStore{querier: querier, clock: clock, sweptAt: clock.Now()}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, rule *Rule) (*Result, error) {
+	now := s.clock.Now()
+	s.sweep(ctx, now)
+
+	windowStart := now.Truncate(rule.Period)
+	windowEnd := windowStart.Add(rule.Period)
+
+	count, err := s.querier.IncrementRateLimitCounter(ctx, models.IncrementRateLimitCounterParams{
+		Key:         key,
+		WindowStart: windowStart,
+		WindowEnd:   windowEnd,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ratelimit.Take: error incrementing counter: %w", err)
+	}
+
+	result := &Result{
+		Allowed:   int(count) <= rule.Requests,
+		Limit:     rule.Requests,
+		Remaining: max(rule.Requests-int(count), 0),
+		Reset:     windowEnd.Sub(now),
+	}
+
+	if !result.Allowed {
+		result.RetryAfter = result.Reset
+	}
+
+	return result, nil
+}
+
+// sweep deletes the counters of the windows that ended, at most once per sweepInterval across the requests
+func (s *PostgresStore) sweep(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(s.sweptAt) < sweepInterval {
+		s.mu.Unlock()
+		return
+	}
+	s.sweptAt = now
+	s.mu.Unlock()
+
+	if _, err := s.querier.DeleteExpiredRateLimitCounters(ctx, now); err != nil {
+		logging.FromContext(ctx).Warn("sweep: failed to delete the expired rate limit counters", "error", err)
+	}
+}
@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// KeyBy is what the requests are counted by
+type KeyBy string
+
+const (
+	// KeyByAPIKey counts the requests of each API key or bearer token subject
+	KeyByAPIKey KeyBy = "api_key"
+	// KeyByIP counts the requests of each source IP, before the caller is authenticated
+	KeyByIP KeyBy = "ip"
+	// KeyByAccount counts the requests of each caller on each account, like KeyByAPIKey when there is no account
+	KeyByAccount KeyBy = "account"
+)
+
+type (
+	// Rules is the set of rate limits read from the YAML rules file
+	Rules struct {
+		Rules []Rule `yaml:"rules"`
+	}
+
+	// Rule limits the number of requests to the matching routes per key
+	Rule struct {
+		Name string `yaml:"name"`
+		// Method restricts the rule to an HTTP method. The rule applies to all when empty
+		Method string `yaml:"method"`
+		// Path restricts the rule to a route template, e.g. /api/v1/accounts/{accountID}. The rule applies to all when empty
+		Path string `yaml:"path"`
+		// KeyBy is what the requests are counted by
+		KeyBy KeyBy `yaml:"key_by"`
+		// Requests is the number of requests allowed per Period
+		Requests int `yaml:"requests"`
+		// Period is the time it takes to allow Requests requests again after they are all used
+		Period time.Duration `yaml:"period"`
+	}
+)
+
+// loadRules reads and validates the rules in the given YAML file
+func loadRules(path string) (*Rules, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loadRules: failed to read rules file: %w", err)
+	}
+
+	rules := &Rules{}
+	if err = yaml.Unmarshal(contents, rules); err != nil {
+		return nil, fmt.Errorf("loadRules: failed to parse rules file: %w", err)
+	}
+
+	if err = rules.validate(); err != nil {
+		return nil, fmt.Errorf("loadRules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// validate checks that every rule is usable, a bad rules file is rejected as a whole
+func (r *Rules) validate() error {
+	names := make(map[string]bool, len(r.Rules))
+
+	for _, rule := range r.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("rate limit rule without a name")
+		}
+
+		// The name is part of the key of the counters, two rules can't share their counters
+		if names[rule.Name] {
+			return fmt.Errorf("rate limit rule %q: the name is used by another rule", rule.Name)
+		}
+		names[rule.Name] = true
+
+		switch rule.Method {
+		case "", http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			return fmt.Errorf("rate limit rule %q: unsupported method %q", rule.Name, rule.Method)
+		}
+
+		if rule.KeyBy != KeyByAPIKey && rule.KeyBy != KeyByIP && rule.KeyBy != KeyByAccount {
+			return fmt.Errorf("rate limit rule %q: key_by must be api_key, ip or account", rule.Name)
+		}
+
+		if rule.Requests <= 0 {
+			return fmt.Errorf("rate limit rule %q: requests must be positive", rule.Name)
+		}
+
+		if rule.Period <= 0 {
+			return fmt.Errorf("rate limit rule %q: period must be positive", rule.Name)
+		}
+	}
+
+	return nil
+}
+
+// matches checks if the rule applies to the route
+func (r *Rule) matches(method, path string) bool {
+	return (r.Method == "" || r.Method == method) && (r.Path == "" || r.Path == path)
+}
+
+// match returns the first rule that applies to the route among the rules that count the requests by IP, or among
+// the others, nil when none does. The rules by IP are matched before the authentication, the others after it
+func (r *Rules) match(method, path string, byIP bool) *Rule {
+	for i := range r.Rules {
+		if (r.Rules[i].KeyBy == KeyByIP) == byIP && r.Rules[i].matches(method, path) {
+			return &r.Rules[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/clock"
+)
+
+// sweepInterval is how often the memory store forgets the buckets that are full again
+const sweepInterval = time.Minute
+
+// Result is the state of the limit of a key after a request
+type Result struct {
+	Allowed bool
+	// Limit is the number of requests allowed per period
+	Limit int
+	// Remaining is the number of requests still allowed right now
+	Remaining int
+	// Reset is the time until all the requests are allowed again
+	Reset time.Duration
+	// RetryAfter is the time until the next request is allowed, it is zero when the request is allowed
+	RetryAfter time.Duration
+}
+
+// Store counts the requests of every key
+type Store interface {
+	// Take counts a request of the key against the rule
+	Take(ctx context.Context, key string, rule *Rule) (*Result, error)
+}
+
+// bucket is a token bucket that holds up to Requests tokens and refills them over the Period
+type bucket struct {
+	tokens   float64
+	capacity float64
+	// rate is the number of tokens refilled per second
+	rate      float64
+	updatedAt time.Time
+}
+
+// refill adds the tokens refilled since the last update
+func (b *bucket) refill(now time.Time) {
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.updatedAt).Seconds()*b.rate)
+	b.updatedAt = now
+}
+
+// MemoryStore is a token bucket per key in the memory of the instance. Each instance of the service has its own
+// buckets, so the limits are per instance
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	clock   clock.Clock
+	sweptAt time.Time
+}
+
+func NewMemoryStore(clock clock.Clock) *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]*bucket), clock: clock, sweptAt: clock.Now()}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, rule *Rule) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{
+			tokens:    float64(rule.Requests),
+			capacity:  float64(rule.Requests),
+			rate:      float64(rule.Requests) / rule.Period.Seconds(),
+			updatedAt: now,
+		}
+		s.buckets[key] = b
+	}
+
+	b.refill(now)
+
+	result := &Result{Limit: rule.Requests}
+
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / b.rate)
+	}
+
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((b.capacity - b.tokens) / b.rate)
+
+	return result, nil
+}
+
+// sweep forgets the buckets that are full again, a full bucket is the same as no bucket
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.sweptAt) < sweepInterval {
+		return
+	}
+
+	for key, b := range s.buckets {
+		b.refill(now)
+		if b.tokens >= b.capacity {
+			delete(s.buckets, key)
+		}
+	}
+
+	s.sweptAt = now
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
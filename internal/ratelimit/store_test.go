@@ -0,0 +1,119 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+var dummyNow = time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+
+// testClock is a clock that the tests move forward
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func TestMemoryStore_Take(t *testing.T) {
+	clk := &testClock{now: dummyNow}
+	store := NewMemoryStore(clk)
+	rule := &Rule{Name: "default", KeyBy: KeyByAPIKey, Requests: 3, Period: 3 * time.Second}
+
+	// The burst of the limit is allowed at once
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(context.Background(), "key", rule)
+		assert.Nil(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Take(context.Background(), "key", rule)
+	assert.Nil(t, err)
+	assert.Equal(t, &Result{Allowed: false, Limit: 3, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second}, result)
+
+	// The other keys have their own bucket
+	result, _ = store.Take(context.Background(), "other-key", rule)
+	assert.True(t, result.Allowed)
+
+	// A token is refilled every second
+	clk.advance(time.Second)
+	result, _ = store.Take(context.Background(), "key", rule)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	result, _ = store.Take(context.Background(), "key", rule)
+	assert.False(t, result.Allowed)
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	clk := &testClock{now: dummyNow}
+	store := NewMemoryStore(clk)
+
+	_, _ = store.Take(context.Background(), "slow", &Rule{Requests: 1, Period: time.Hour})
+	_, _ = store.Take(context.Background(), "fast", &Rule{Requests: 1, Period: time.Second})
+
+	// The full buckets are forgotten, the others are kept
+	clk.advance(sweepInterval)
+	_, _ = store.Take(context.Background(), "other", &Rule{Requests: 1, Period: time.Second})
+
+	assert.Contains(t, store.buckets, "slow")
+	assert.NotContains(t, store.buckets, "fast")
+}
+
+func TestPostgresStore_Take(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	querier := mock.NewMockQuerier(ctrl)
+	store := NewPostgresStore(querier, &testClock{now: dummyNow})
+	rule := &Rule{Name: "default", KeyBy: KeyByAPIKey, Requests: 2, Period: time.Minute}
+
+	params := models.IncrementRateLimitCounterParams{
+		Key:         "key",
+		WindowStart: time.Date(2024, time.July, 17, 15, 4, 0, 0, time.UTC),
+		WindowEnd:   time.Date(2024, time.July, 17, 15, 5, 0, 0, time.UTC),
+	}
+
+	// Prepare mock responses
+	gomock.InOrder(
+		querier.EXPECT().IncrementRateLimitCounter(gomock.Any(), params).Return(int32(2), nil),
+		querier.EXPECT().IncrementRateLimitCounter(gomock.Any(), params).Return(int32(3), nil),
+	)
+
+	// Check the results
+	result, err := store.Take(context.Background(), "key", rule)
+	assert.Nil(t, err)
+	assert.Equal(t, &Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 55 * time.Second}, result)
+
+	result, err = store.Take(context.Background(), "key", rule)
+	assert.Nil(t, err)
+	assert.Equal(t, &Result{Allowed: false, Limit: 2, Remaining: 0, Reset: 55 * time.Second, RetryAfter: 55 * time.Second}, result)
+}
+
+func TestPostgresStore_Sweep(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	querier := mock.NewMockQuerier(ctrl)
+	clk := &testClock{now: dummyNow}
+	store := NewPostgresStore(querier, clk)
+	rule := &Rule{Name: "default", KeyBy: KeyByAPIKey, Requests: 2, Period: time.Minute}
+
+	// Prepare mock responses, the expired counters are deleted once per interval even when it fails
+	querier.EXPECT().IncrementRateLimitCounter(gomock.Any(), gomock.Any()).Return(int32(1), nil).Times(3)
+	querier.EXPECT().DeleteExpiredRateLimitCounters(gomock.Any(), dummyNow.Add(sweepInterval)).Return(int64(0), errors.New("timeout"))
+
+	_, _ = store.Take(context.Background(), "key", rule)
+	clk.advance(sweepInterval)
+	_, _ = store.Take(context.Background(), "key", rule)
+	_, _ = store.Take(context.Background(), "key", rule)
+}
@@ -0,0 +1,115 @@
+package rewards
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+)
+
+// Transaction is the debit the rewards are accrued for
+type Transaction struct {
+	ID              string
+	AccountID       string
+	OperationTypeID int64
+	MCC             string
+	// Amount is the absolute value of the transaction amount
+	Amount    float64
+	EventDate time.Time
+}
+
+// Engine accrues the rewards of the transactions with the rules read from a YAML file.
+// For each kind of reward, the first matching rule applies, so promotions must be listed before the rules they override.
+type Engine struct {
+	rules *Rules
+}
+
+// NewEngine creates a new Engine with the rules of the given file.
+// When path is empty the engine has no rules and doesn't accrue any reward.
+func NewEngine(path string) (*Engine, error) {
+	if path == "" {
+		return &Engine{rules: &Rules{}}, nil
+	}
+
+	rules, err := loadRules(path)
+	if err != nil {
+		return nil, fmt.Errorf("rewards.NewEngine: %w", err)
+	}
+
+	return &Engine{rules: rules}, nil
+}
+
+// Accrue records the rewards of the transaction. It must be called in the DB transaction that creates the
+// transaction, so that no reward is accrued for a transaction that is rolled back
+func (e *Engine) Accrue(ctx context.Context, querier models.Querier, txn *Transaction) error {
+	applied := map[models.RewardKind]bool{}
+
+	for i := range e.rules.Rules {
+		rule := &e.rules.Rules[i]
+		if applied[rule.Kind] || !rule.matches(txn) {
+			continue
+		}
+
+		applied[rule.Kind] = true
+
+		amount := txn.Amount * rule.Rate
+		if rule.MaxAmount > 0 {
+			amount = math.Min(amount, rule.MaxAmount)
+		}
+
+		if err := createAccrual(ctx, querier, txn.AccountID, txn.ID, rule.Name, rule.Kind, amount); err != nil {
+			return fmt.Errorf("engine.Accrue: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Reverse takes back the share of the rewards of the original transaction that is reversed.
+// The negative accruals belong to the reversal, so reversing the reversal accrues the rewards again
+func Reverse(ctx context.Context, querier models.Querier, accountID, originalID, reversalID string, share float64) error {
+	totals, err := querier.GetRewardAccrualTotalsByTransactionID(ctx, originalID)
+	if err != nil {
+		return fmt.Errorf("rewards.Reverse: failed to fetch accruals: %w", err)
+	}
+
+	for _, total := range totals {
+		if err = createAccrual(ctx, querier, accountID, reversalID, total.RuleName, total.Kind, -total.Amount*share); err != nil {
+			return fmt.Errorf("rewards.Reverse: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// createAccrual rounds the amount to cents for cashback and to whole points, nothing is recorded when it rounds to zero
+func createAccrual(ctx context.Context, querier models.Querier, accountID, transactionID, ruleName string, kind models.RewardKind, amount float64) error {
+	amount = Round(kind, amount)
+	if amount == 0 {
+		return nil
+	}
+
+	err := querier.CreateRewardAccrual(ctx, models.CreateRewardAccrualParams{
+		AccountID:     accountID,
+		TransactionID: transactionID,
+		RuleName:      ruleName,
+		Kind:          kind,
+		Amount:        amount,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create %s accrual of rule %s: %w", kind, ruleName, err)
+	}
+
+	return nil
+}
+
+// Round rounds cashback to cents and points to whole points
+func Round(kind models.RewardKind, amount float64) float64 {
+	if kind == models.RewardKindPOINTS {
+		return math.Round(amount)
+	}
+
+	return math.Round(amount*100) / 100
+}
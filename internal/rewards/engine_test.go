@@ -0,0 +1,158 @@
+package rewards
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dummyAccountID     = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+	dummyTransactionID = "98a0f8e7-6e28-4d4f-872b-4d28b3d5ee66"
+	dummyReversalID    = "6a7b8c9d-1e2f-4a3b-8c4d-5e6f7a8b9c0d"
+)
+
+const testRules = `
+rules:
+  - name: groceries-promo
+    kind: CASHBACK
+    rate: 0.05
+    max_amount: 10
+    operation_types: [1, 2, 3]
+    mccs: ["5411"]
+    starts_at: 2024-07-01T00:00:00Z
+    ends_at: 2024-08-01T00:00:00Z
+  - name: cashback
+    kind: CASHBACK
+    rate: 0.01
+    operation_types: [1, 2, 3]
+  - name: points
+    kind: POINTS
+    rate: 2
+`
+
+func writeRules(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rewards.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	return path
+}
+
+func TestEngine_Accrue(t *testing.T) {
+	ctx := context.Background()
+	inPromo := time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+	afterPromo := time.Date(2024, time.August, 1, 0, 0, 0, 0, time.UTC)
+
+	engine, err := NewEngine(writeRules(t, testRules))
+	assert.Nil(t, err)
+
+	tests := []struct {
+		name     string
+		txn      *Transaction
+		expected map[string]float64
+	}{
+		{
+			name:     "accrues the promo cashback for a matching MCC in the promo period",
+			txn:      &Transaction{OperationTypeID: 1, MCC: "5411", Amount: 100.50, EventDate: inPromo},
+			expected: map[string]float64{"groceries-promo": 5.03, "points": 201},
+		},
+		{
+			name:     "caps the accrual at the max amount of the rule",
+			txn:      &Transaction{OperationTypeID: 1, MCC: "5411", Amount: 1000, EventDate: inPromo},
+			expected: map[string]float64{"groceries-promo": 10, "points": 2000},
+		},
+		{
+			name:     "falls back to the next rule after the promo period",
+			txn:      &Transaction{OperationTypeID: 1, MCC: "5411", Amount: 100, EventDate: afterPromo},
+			expected: map[string]float64{"cashback": 1, "points": 200},
+		},
+		{
+			name:     "falls back to the next rule for another MCC",
+			txn:      &Transaction{OperationTypeID: 3, MCC: "5812", Amount: 100, EventDate: inPromo},
+			expected: map[string]float64{"cashback": 1, "points": 200},
+		},
+		{
+			name:     "only accrues the rules of the operation type",
+			txn:      &Transaction{OperationTypeID: 4, Amount: 100, EventDate: inPromo},
+			expected: map[string]float64{"points": 200},
+		},
+		{
+			name:     "skips the accruals that round to zero",
+			txn:      &Transaction{OperationTypeID: 1, Amount: 0.2, EventDate: inPromo},
+			expected: map[string]float64{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			querier := mock.NewMockQuerier(ctrl)
+
+			tt.txn.ID = dummyTransactionID
+			tt.txn.AccountID = dummyAccountID
+
+			accrued := map[string]float64{}
+			querier.EXPECT().CreateRewardAccrual(gomock.Any(), gomock.Any()).DoAndReturn(
+				func(_ any, arg models.CreateRewardAccrualParams) error {
+					assert.Equal(t, dummyAccountID, arg.AccountID)
+					assert.Equal(t, dummyTransactionID, arg.TransactionID)
+					accrued[arg.RuleName] = arg.Amount
+					return nil
+				}).AnyTimes()
+
+			assert.Nil(t, engine.Accrue(ctx, querier, tt.txn))
+			assert.Equal(t, tt.expected, accrued)
+		})
+	}
+}
+
+func TestReverse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	querier := mock.NewMockQuerier(ctrl)
+
+	querier.EXPECT().GetRewardAccrualTotalsByTransactionID(gomock.Any(), dummyTransactionID).Return([]*models.GetRewardAccrualTotalsByTransactionIDRow{
+		{RuleName: "cashback", Kind: models.RewardKindCASHBACK, Amount: 1.25},
+		{RuleName: "points", Kind: models.RewardKindPOINTS, Amount: 250},
+	}, nil)
+	querier.EXPECT().CreateRewardAccrual(gomock.Any(), models.CreateRewardAccrualParams{
+		AccountID:     dummyAccountID,
+		TransactionID: dummyReversalID,
+		RuleName:      "cashback",
+		Kind:          models.RewardKindCASHBACK,
+		Amount:        -0.5,
+	}).Return(nil)
+	querier.EXPECT().CreateRewardAccrual(gomock.Any(), models.CreateRewardAccrualParams{
+		AccountID:     dummyAccountID,
+		TransactionID: dummyReversalID,
+		RuleName:      "points",
+		Kind:          models.RewardKindPOINTS,
+		Amount:        -100,
+	}).Return(nil)
+
+	assert.Nil(t, Reverse(context.Background(), querier, dummyAccountID, dummyTransactionID, dummyReversalID, 0.4))
+}
+
+func TestNewEngine_InvalidRules(t *testing.T) {
+	tests := map[string]string{
+		"unknown kind":       "rules:\n  - name: miles\n    kind: MILES\n    rate: 1\n",
+		"rate not positive":  "rules:\n  - name: cashback\n    kind: CASHBACK\n    rate: 0\n",
+		"missing name":       "rules:\n  - kind: POINTS\n    rate: 1\n",
+		"ends before starts": "rules:\n  - name: promo\n    kind: POINTS\n    rate: 1\n    starts_at: 2024-08-01T00:00:00Z\n    ends_at: 2024-07-01T00:00:00Z\n",
+	}
+
+	for name, rules := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewEngine(writeRules(t, rules))
+			assert.NotNil(t, err)
+		})
+	}
+}
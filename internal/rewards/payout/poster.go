@@ -0,0 +1,200 @@
+package payout
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/imjenal/transaction-service/api/v1/transactions"
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/health"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/risk"
+	"github.com/jackc/pgx/v4"
+)
+
+// Outcome is the outcome of the payout of an account
+type Outcome string
+
+const (
+	// OutcomePaid is a payout whose credit was created, the accruals are marked as paid by it
+	OutcomePaid Outcome = "PAID"
+	// OutcomeNothingToPay is a payout of no cashback, e.g. when reversals cancel the pending cashback
+	OutcomeNothingToPay Outcome = "NOTHING_TO_PAY"
+	// OutcomeRejected is a payout whose credit was rejected, e.g. by the risk rules. The cashback stays pending
+	OutcomeRejected Outcome = "REJECTED"
+)
+
+// Poster pays out the pending cashback of the accounts. The cashback is posted as a CREDIT_VOUCHER transaction
+// through the transactions service, so it discharges the debts of the account like any other credit.
+type Poster struct {
+	querier models.Querier
+	// conn starts the DB transaction of each account
+	conn               db.TxBeginner
+	riskEngine         *risk.Engine
+	rewardsEngine      *rewards.Engine
+	clock              clock.Clock
+	transactionsConfig *config.Transactions
+	config             *config.Rewards
+	// heartbeat reports the health of the poster, it beats after every payout
+	heartbeat *health.Heartbeat
+	// txQuerier runs the queries of the payout of an account in its DB transaction
+	txQuerier func(tx pgx.Tx) models.Querier
+}
+
+func NewPoster(querier models.Querier, conn db.TxBeginner, riskEngine *risk.Engine, rewardsEngine *rewards.Engine, clock clock.Clock, transactionsConfig *config.Transactions, config *config.Rewards) *Poster {
+	return &Poster{
+		querier:            querier,
+		conn:               conn,
+		riskEngine:         riskEngine,
+		rewardsEngine:      rewardsEngine,
+		clock:              clock,
+		transactionsConfig: transactionsConfig,
+		config:             config,
+		heartbeat:          health.NewHeartbeat("rewards_payout", missedPayouts*config.PayoutInterval, clock),
+		txQuerier:          func(tx pgx.Tx) models.Querier { return models.New(tx) },
+	}
+}
+
+// missedPayouts is the number of payouts the poster may miss before it is reported down
+const missedPayouts = 3
+
+// HealthChecker returns the check of the poster, it is down when the last payout failed or it has missed payouts
+func (p *Poster) HealthChecker() health.Checker {
+	return p.heartbeat
+}
+
+// Run pays out the pending cashback every interval. It blocks until the context is cancelled.
+func (p *Poster) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.PayoutInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			paid, err := p.PostPending(ctx)
+			p.heartbeat.Beat(err)
+			if err != nil {
+				logging.FromContext(ctx).Error("poster.Run: failed to pay out cashback", "error", err)
+			}
+
+			if paid > 0 {
+				logging.FromContext(ctx).Info("poster.Run: paid out the cashback", "accounts", paid)
+			}
+		}
+	}
+}
+
+// PostPending pays out the pending cashback of up to the batch size of accounts, each one in its own DB transaction.
+// An account that fails or whose credit is rejected is skipped, its cashback stays pending until the retry backoff
+// is over. An account with nothing to pay isn't a failure. It returns the number of accounts that were paid.
+func (p *Poster) PostPending(ctx context.Context) (int, error) {
+	operationTypeID, err := p.querier.GetOperationTypeIDByDescription(ctx, models.TransactionTypeCREDITVOUCHER)
+	if err != nil {
+		return 0, fmt.Errorf("poster.PostPending: failed to fetch the credit voucher operation type: %w", err)
+	}
+
+	accountIDs, err := p.querier.GetAccountsWithPendingCashback(ctx, models.GetAccountsWithPendingCashbackParams{
+		RetryBefore: p.clock.Now().Add(-p.config.PayoutRetryBackoff),
+		BatchSize:   int32(p.config.PayoutBatchSize),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("poster.PostPending: failed to fetch accounts: %w", err)
+	}
+
+	paid := 0
+	for _, accountID := range accountIDs {
+		var outcome Outcome
+		err := db.RunInTx(ctx, p.conn, func(tx pgx.Tx) error {
+			querier := p.txQuerier(tx)
+			service := transactions.NewService(transactions.NewRepository(querier, tx), p.riskEngine, p.rewardsEngine, p.clock, p.transactionsConfig)
+
+			var err error
+			if outcome, err = Post(ctx, querier, service, accountID, operationTypeID); err != nil || outcome != OutcomePaid {
+				return err
+			}
+
+			return querier.DeleteRewardPayoutFailure(ctx, accountID)
+		})
+		if err != nil {
+			logging.FromContext(ctx).Error("poster.PostPending: failed to pay out the cashback", "account_id", accountID, "error", err)
+		}
+
+		if err != nil || outcome == OutcomeRejected {
+			p.recordFailure(ctx, accountID)
+			continue
+		}
+
+		if outcome == OutcomePaid {
+			paid++
+		}
+	}
+
+	return paid, nil
+}
+
+// recordFailure makes the account wait for the retry backoff before its cashback is paid out again, so that the
+// accounts that can't be paid out don't take the place of the others in every batch
+func (p *Poster) recordFailure(ctx context.Context, accountID string) {
+	err := p.querier.RecordRewardPayoutFailure(ctx, models.RecordRewardPayoutFailureParams{
+		AccountID: accountID,
+		FailedAt:  p.clock.Now(),
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("poster.recordFailure: failed to record the payout failure", "account_id", accountID, "error", err)
+	}
+}
+
+// Post credits the pending cashback of the account and marks the accruals as paid by the credit.
+// It returns OutcomeNothingToPay when reversals cancel the pending cashback, and OutcomeRejected when the credit is
+// rejected, the cashback stays pending then.
+func Post(ctx context.Context, querier models.Querier, service *transactions.Service, accountID string, operationTypeID int64) (Outcome, error) {
+	accruals, err := querier.GetPendingCashbackAccrualsForUpdate(ctx, accountID)
+	if err != nil {
+		return "", fmt.Errorf("payout.Post: failed to fetch accruals: %w", err)
+	}
+
+	total := 0.0
+	uuids := make([]string, 0, len(accruals))
+	for _, accrual := range accruals {
+		total += accrual.Amount
+		uuids = append(uuids, accrual.Uuid)
+	}
+
+	total = rewards.Round(models.RewardKindCASHBACK, total)
+	if total <= 0 {
+		return OutcomeNothingToPay, nil
+	}
+
+	credit, err := service.Create(ctx, &transactions.CreateTransactionRequestData{
+		AccountId:       accountID,
+		OperationTypeId: operationTypeID,
+		Amount:          total,
+	})
+	if transactions.IsRejected(err) {
+		logging.FromContext(ctx).Warn("payout.Post: cashback credit rejected", "account_id", accountID, "error", err)
+		return OutcomeRejected, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("payout.Post: failed to create credit: %w", err)
+	}
+
+	err = querier.SetRewardAccrualsPayout(ctx, models.SetRewardAccrualsPayoutParams{
+		PayoutTransactionID: sql.NullString{String: credit.Uuid, Valid: true},
+		Uuids:               uuids,
+	})
+	if err != nil {
+		return "", fmt.Errorf("payout.Post: failed to mark accruals as paid: %w", err)
+	}
+
+	return OutcomePaid, nil
+}
@@ -0,0 +1,160 @@
+package payout
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/api/v1/transactions"
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/risk"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dummyAccountID       = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+	dummyOtherAccountID  = "9f0f9b5e-2c1d-4e0b-8b1a-3f4c5d6e7f80"
+	dummyCreditID        = "d4c3b2a1-0f9e-4d8c-b7a6-958473625140"
+	dummyOperationTypeID = int64(4)
+)
+
+var dummyNow = time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+
+func newTestService(t *testing.T, querier models.Querier) *transactions.Service {
+	t.Helper()
+
+	riskEngine, err := risk.NewEngine("", risk.NewStore(querier), clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	rewardsEngine, err := rewards.NewEngine("")
+	assert.Nil(t, err)
+
+	return transactions.NewService(transactions.NewRepository(querier, nil), riskEngine, rewardsEngine, clock.Fixed(dummyNow), &config.Transactions{})
+}
+
+func TestPost(t *testing.T) {
+	ctx := context.Background()
+	accruals := []*models.GetPendingCashbackAccrualsForUpdateRow{
+		{Uuid: "a1", Amount: 1.10},
+		{Uuid: "a2", Amount: 2.25},
+		{Uuid: "a3", Amount: -0.35},
+	}
+
+	t.Run("should credit the pending cashback and mark the accruals as paid", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		querier := mock.NewMockQuerier(ctrl)
+
+		querier.EXPECT().GetPendingCashbackAccrualsForUpdate(gomock.Any(), dummyAccountID).Return(accruals, nil)
+		querier.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountID).Return(models.AccountStatusACTIVE, nil)
+		querier.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountID).Return(models.AccountStatusACTIVE, nil)
+		querier.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationTypeID).Return(models.AmountBehaviorPOSITIVE, nil)
+		querier.EXPECT().CreateRiskDecision(gomock.Any(), gomock.Any()).Return(nil)
+		querier.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return(nil, nil)
+		querier.EXPECT().GetNegativeBalanceTransactionsByAccountID(gomock.Any(), dummyAccountID).Return(nil, nil)
+		querier.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, arg models.CreateTransactionParams) (*models.CreateTransactionRow, error) {
+				assert.Equal(t, 3.0, arg.Amount)
+				assert.Equal(t, dummyOperationTypeID, arg.OperationTypeID)
+				return &models.CreateTransactionRow{Uuid: dummyCreditID, Amount: arg.Amount}, nil
+			})
+		querier.EXPECT().SetRewardAccrualsPayout(gomock.Any(), models.SetRewardAccrualsPayoutParams{
+			PayoutTransactionID: sql.NullString{String: dummyCreditID, Valid: true},
+			Uuids:               []string{"a1", "a2", "a3"},
+		}).Return(nil)
+
+		outcome, err := Post(ctx, querier, newTestService(t, querier), dummyAccountID, dummyOperationTypeID)
+		assert.Nil(t, err)
+		assert.Equal(t, OutcomePaid, outcome)
+	})
+
+	t.Run("should not pay anything when the reversals cancel the pending cashback", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		querier := mock.NewMockQuerier(ctrl)
+
+		querier.EXPECT().GetPendingCashbackAccrualsForUpdate(gomock.Any(), dummyAccountID).Return([]*models.GetPendingCashbackAccrualsForUpdateRow{
+			{Uuid: "a1", Amount: 1.10},
+			{Uuid: "a2", Amount: -1.10},
+		}, nil)
+
+		outcome, err := Post(ctx, querier, newTestService(t, querier), dummyAccountID, dummyOperationTypeID)
+		assert.Nil(t, err)
+		assert.Equal(t, OutcomeNothingToPay, outcome)
+	})
+
+	t.Run("should keep the cashback pending when the credit is rejected", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		querier := mock.NewMockQuerier(ctrl)
+
+		querier.EXPECT().GetPendingCashbackAccrualsForUpdate(gomock.Any(), dummyAccountID).Return(accruals, nil)
+		querier.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountID).Return(models.AccountStatusCLOSED, nil)
+		querier.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationTypeID).Return(models.AmountBehaviorPOSITIVE, nil)
+
+		outcome, err := Post(ctx, querier, newTestService(t, querier), dummyAccountID, dummyOperationTypeID)
+		assert.Nil(t, err)
+		assert.Equal(t, OutcomeRejected, outcome)
+	})
+}
+
+// fakeTx is a DB transaction that commits, its queries are run by the mock querier of the poster
+type fakeTx struct {
+	pgx.Tx
+}
+
+func (fakeTx) Commit(context.Context) error   { return nil }
+func (fakeTx) Rollback(context.Context) error { return nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Begin(context.Context) (pgx.Tx, error) { return fakeTx{}, nil }
+
+func TestPoster_PostPending(t *testing.T) {
+	ctx := context.Background()
+
+	// Prepare mock responses
+	ctrl := gomock.NewController(t)
+	querier := mock.NewMockQuerier(ctrl)
+
+	poster := NewPoster(querier, fakeConn{}, nil, nil, clock.Fixed(dummyNow), &config.Transactions{}, &config.Rewards{
+		PayoutInterval:     time.Hour,
+		PayoutBatchSize:    10,
+		PayoutRetryBackoff: 72 * time.Hour,
+	})
+	poster.txQuerier = func(pgx.Tx) models.Querier { return querier }
+
+	querier.EXPECT().GetOperationTypeIDByDescription(gomock.Any(), models.TransactionTypeCREDITVOUCHER).Return(dummyOperationTypeID, nil)
+	querier.EXPECT().GetAccountsWithPendingCashback(gomock.Any(), models.GetAccountsWithPendingCashbackParams{
+		RetryBefore: dummyNow.Add(-72 * time.Hour),
+		BatchSize:   10,
+	}).Return([]string{dummyAccountID, dummyOtherAccountID}, nil)
+
+	// The pending cashback of the first account nets to zero, the credit of the other one is rejected
+	querier.EXPECT().GetPendingCashbackAccrualsForUpdate(gomock.Any(), dummyAccountID).Return([]*models.GetPendingCashbackAccrualsForUpdateRow{
+		{Uuid: "a1", Amount: 1.10},
+		{Uuid: "a2", Amount: -1.10},
+	}, nil)
+	querier.EXPECT().GetPendingCashbackAccrualsForUpdate(gomock.Any(), dummyOtherAccountID).Return([]*models.GetPendingCashbackAccrualsForUpdateRow{
+		{Uuid: "a3", Amount: 2.00},
+	}, nil)
+	querier.EXPECT().GetAccountStatus(gomock.Any(), dummyOtherAccountID).Return(models.AccountStatusCLOSED, nil)
+	querier.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), dummyOperationTypeID).Return(models.AmountBehaviorPOSITIVE, nil)
+
+	// Only the rejected payout is a failure, nothing to pay isn't retried later
+	querier.EXPECT().RecordRewardPayoutFailure(gomock.Any(), models.RecordRewardPayoutFailureParams{
+		AccountID: dummyOtherAccountID,
+		FailedAt:  dummyNow,
+	}).Return(nil)
+
+	// Call the poster
+	paid, err := poster.PostPending(ctx)
+
+	// Check the results
+	assert.Nil(t, err)
+	assert.Equal(t, 0, paid)
+}
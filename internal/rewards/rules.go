@@ -0,0 +1,124 @@
+package rewards
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Rules is the set of reward rules read from the YAML rules file
+	Rules struct {
+		Rules []Rule `yaml:"rules"`
+	}
+
+	// Rule accrues a share of the amount of the matching transactions as cashback or points
+	Rule struct {
+		Name string            `yaml:"name"`
+		Kind models.RewardKind `yaml:"kind"`
+		// Rate is the share of the amount that is accrued, e.g. 0.01 for 1% cashback or 2 for 2 points per unit
+		Rate float64 `yaml:"rate"`
+		// MaxAmount caps the accrual of a single transaction, there is no cap when it is zero
+		MaxAmount float64 `yaml:"max_amount"`
+		// OperationTypes restricts the rule to the given operation types. The rule applies to all when empty
+		OperationTypes []int64 `yaml:"operation_types"`
+		// MCCs restricts the rule to the given merchant category codes. The rule applies to all when empty
+		MCCs []string `yaml:"mccs"`
+		// StartsAt and EndsAt restrict the rule to a promotional period, both are optional
+		StartsAt *time.Time `yaml:"starts_at"`
+		EndsAt   *time.Time `yaml:"ends_at"`
+	}
+)
+
+// loadRules reads and validates the rules in the given YAML file
+func loadRules(path string) (*Rules, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loadRules: failed to read rules file: %w", err)
+	}
+
+	rules := &Rules{}
+	if err = yaml.Unmarshal(contents, rules); err != nil {
+		return nil, fmt.Errorf("loadRules: failed to parse rules file: %w", err)
+	}
+
+	if err = rules.validate(); err != nil {
+		return nil, fmt.Errorf("loadRules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// validate checks that every rule is usable, a bad rules file is rejected as a whole
+func (r *Rules) validate() error {
+	for _, rule := range r.Rules {
+		if rule.Name == "" {
+			return fmt.Errorf("reward rule without a name")
+		}
+
+		if rule.Kind != models.RewardKindCASHBACK && rule.Kind != models.RewardKindPOINTS {
+			return fmt.Errorf("reward rule %q: kind must be CASHBACK or POINTS", rule.Name)
+		}
+
+		if rule.Rate <= 0 {
+			return fmt.Errorf("reward rule %q: rate must be positive", rule.Name)
+		}
+
+		if rule.MaxAmount < 0 {
+			return fmt.Errorf("reward rule %q: max_amount can't be negative", rule.Name)
+		}
+
+		if rule.StartsAt != nil && rule.EndsAt != nil && !rule.EndsAt.After(*rule.StartsAt) {
+			return fmt.Errorf("reward rule %q: ends_at must be after starts_at", rule.Name)
+		}
+	}
+
+	return nil
+}
+
+// matches checks if the rule applies to the transaction
+func (r *Rule) matches(txn *Transaction) bool {
+	if r.StartsAt != nil && txn.EventDate.Before(*r.StartsAt) {
+		return false
+	}
+
+	if r.EndsAt != nil && !txn.EventDate.Before(*r.EndsAt) {
+		return false
+	}
+
+	return appliesTo(r.OperationTypes, txn.OperationTypeID) && matchesMCC(r.MCCs, txn.MCC)
+}
+
+// appliesTo returns true when the operation type is in the list or when the list is empty
+func appliesTo(operationTypes []int64, operationTypeID int64) bool {
+	if len(operationTypes) == 0 {
+		return true
+	}
+
+	for _, id := range operationTypes {
+		if id == operationTypeID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchesMCC returns true when the MCC is in the list or when the list is empty.
+// A transaction without an MCC only matches the rules without MCCs
+func matchesMCC(mccs []string, mcc string) bool {
+	if len(mccs) == 0 {
+		return true
+	}
+
+	for _, m := range mccs {
+		if m == mcc {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,262 @@
+package risk
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/logging"
+)
+
+// ReasonCode tells the client why a transaction was declined
+type ReasonCode string
+
+const (
+	ReasonVelocityCount   ReasonCode = "VELOCITY_COUNT_EXCEEDED"
+	ReasonVelocityAmount  ReasonCode = "VELOCITY_AMOUNT_EXCEEDED"
+	ReasonAmountAnomaly   ReasonCode = "AMOUNT_ANOMALY"
+	ReasonMerchantBlocked ReasonCode = "MERCHANT_BLOCKED"
+	ReasonCountryBlocked  ReasonCode = "COUNTRY_BLOCKED"
+)
+
+type (
+	// Transaction is the transaction being evaluated by the engine
+	Transaction struct {
+		AccountID       string
+		OperationTypeID int64
+		// Amount is the absolute value of the transaction amount
+		Amount          float64
+		MerchantID      string
+		MerchantCountry string
+	}
+
+	// Decision is the outcome of the evaluation of a transaction
+	Decision struct {
+		Approved   bool       `json:"approved"`
+		ReasonCode ReasonCode `json:"reason_code,omitempty"`
+		Rule       string     `json:"rule,omitempty"`
+	}
+
+	// AmountStats describes the amounts an account has transacted so far
+	AmountStats struct {
+		Count  int64
+		Mean   float64
+		StdDev float64
+	}
+
+	// Store provides the account history to the engine and records every decision it takes
+	Store interface {
+		// Velocity counts and sums the transactions of the operation types created on the account since the given time,
+		// whatever their event date. The reversals are not counted, and all the operation types are when there is none
+		Velocity(ctx context.Context, accountID string, operationTypes []int64, since time.Time) (count int64, total float64, err error)
+		// AmountStats describes the amounts of the transactions of the operation types, like Velocity
+		AmountStats(ctx context.Context, accountID string, operationTypes []int64) (*AmountStats, error)
+		SaveDecision(ctx context.Context, txn *Transaction, decision *Decision) error
+	}
+)
+
+// Engine evaluates transactions against the risk rules before they are persisted.
+// The rules are read from a YAML file and can be reloaded at runtime.
+type Engine struct {
+	path  string
+	store Store
+	clock clock.Clock
+
+	mu    sync.RWMutex
+	rules *Rules
+}
+
+// NewEngine creates a new Engine and loads the rules from the given file.
+// When path is empty the engine has no rules and approves every transaction.
+func NewEngine(path string, store Store, clock clock.Clock) (*Engine, error) {
+	e := &Engine{
+		path:  path,
+		store: store,
+		clock: clock,
+		rules: &Rules{},
+	}
+
+	if err := e.Reload(); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// Reload reads the rules file again. The current rules are kept if the file is invalid.
+func (e *Engine) Reload() error {
+	if e.path == "" {
+		return nil
+	}
+
+	rules, err := loadRules(e.path)
+	if err != nil {
+		return fmt.Errorf("engine.Reload: %w", err)
+	}
+
+	e.mu.Lock()
+	e.rules = rules
+	e.mu.Unlock()
+
+	return nil
+}
+
+// Watch reloads the rules whenever the rules file changes. It blocks until the context is cancelled.
+func (e *Engine) Watch(ctx context.Context) error {
+	if e.path == "" {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("engine.Watch: failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// Watch the directory instead of the file, editors and config management tools
+	// usually replace the file instead of writing to it
+	if err = watcher.Add(filepath.Dir(e.path)); err != nil {
+		return fmt.Errorf("engine.Watch: failed to watch rules directory: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			if filepath.Clean(event.Name) != filepath.Clean(e.path) || !event.Has(fsnotify.Write|fsnotify.Create) {
+				continue
+			}
+
+			if err := e.Reload(); err != nil {
+				logging.FromContext(ctx).Error("engine.Watch: failed to reload risk rules, keeping the previous rules", "error", err)
+				continue
+			}
+
+			logging.FromContext(ctx).Info("engine.Watch: risk rules reloaded", "path", e.path)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			logging.FromContext(ctx).Error("engine.Watch: watcher error", "error", err)
+		}
+	}
+}
+
+// Evaluate runs the rules against the transaction and stores the decision.
+// Rules are evaluated from the cheapest to the most expensive one and the first failing rule declines the transaction.
+func (e *Engine) Evaluate(ctx context.Context, txn *Transaction) (*Decision, error) {
+	decision, err := e.evaluate(ctx, e.currentRules(), txn)
+	if err != nil {
+		return nil, fmt.Errorf("engine.Evaluate: %w", err)
+	}
+
+	if err = e.store.SaveDecision(ctx, txn, decision); err != nil {
+		return nil, fmt.Errorf("engine.Evaluate: failed to save decision: %w", err)
+	}
+
+	return decision, nil
+}
+
+// Simulate runs the rules against the transaction like Evaluate, but doesn't store the decision.
+// It is used to quote a transaction that isn't created.
+func (e *Engine) Simulate(ctx context.Context, txn *Transaction) (*Decision, error) {
+	decision, err := e.evaluate(ctx, e.currentRules(), txn)
+	if err != nil {
+		return nil, fmt.Errorf("engine.Simulate: %w", err)
+	}
+
+	return decision, nil
+}
+
+func (e *Engine) currentRules() *Rules {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.rules
+}
+
+func (e *Engine) evaluate(ctx context.Context, rules *Rules, txn *Transaction) (*Decision, error) {
+	if contains(rules.Blocklist.Merchants, txn.MerchantID) {
+		return decline(ReasonMerchantBlocked, "blocklist"), nil
+	}
+
+	if contains(rules.Blocklist.Countries, txn.MerchantCountry) {
+		return decline(ReasonCountryBlocked, "blocklist"), nil
+	}
+
+	for _, rule := range rules.Velocity {
+		if !appliesTo(rule.OperationTypes, txn.OperationTypeID) {
+			continue
+		}
+
+		count, total, err := e.store.Velocity(ctx, txn.AccountID, rule.OperationTypes, e.clock.Now().Add(-rule.Window))
+		if err != nil {
+			return nil, err
+		}
+
+		// The transaction being evaluated is counted as part of the window
+		if rule.MaxCount > 0 && count+1 > rule.MaxCount {
+			return decline(ReasonVelocityCount, rule.Name), nil
+		}
+
+		if rule.MaxAmount > 0 && total+txn.Amount > rule.MaxAmount {
+			return decline(ReasonVelocityAmount, rule.Name), nil
+		}
+	}
+
+	for _, rule := range rules.Anomaly {
+		if !appliesTo(rule.OperationTypes, txn.OperationTypeID) {
+			continue
+		}
+
+		// The baseline of each rule is the history of its operation types
+		stats, err := e.store.AmountStats(ctx, txn.AccountID, rule.OperationTypes)
+		if err != nil {
+			return nil, err
+		}
+
+		if isAnomaly(rule, stats, txn.Amount) {
+			return decline(ReasonAmountAnomaly, rule.Name), nil
+		}
+	}
+
+	return &Decision{Approved: true}, nil
+}
+
+// isAnomaly checks if the amount is an outlier for the account
+func isAnomaly(rule AnomalyRule, stats *AmountStats, amount float64) bool {
+	if stats.Count < max(rule.MinHistory, 1) {
+		return false
+	}
+
+	if rule.MaxMultiplier > 0 && amount > stats.Mean*rule.MaxMultiplier {
+		return true
+	}
+
+	// A standard deviation of zero means every past amount is the same, a z-score is meaningless then
+	if rule.MaxStdDev > 0 && stats.StdDev > 0 {
+		return (amount-stats.Mean)/stats.StdDev > rule.MaxStdDev
+	}
+
+	return false
+}
+
+func decline(reason ReasonCode, rule string) *Decision {
+	return &Decision{
+		Approved:   false,
+		ReasonCode: reason,
+		Rule:       rule,
+	}
+}
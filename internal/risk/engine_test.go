@@ -0,0 +1,251 @@
+package risk
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+const dummyAccountID = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+
+var dummyNow = time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+
+type fakeStore struct {
+	count     int64
+	total     float64
+	stats     *AmountStats
+	decisions []*Decision
+	// since is the start of the last velocity window
+	since time.Time
+}
+
+func (f *fakeStore) Velocity(_ context.Context, _ string, _ []int64, since time.Time) (int64, float64, error) {
+	f.since = since
+	return f.count, f.total, nil
+}
+
+func (f *fakeStore) AmountStats(_ context.Context, _ string, _ []int64) (*AmountStats, error) {
+	return f.stats, nil
+}
+
+// historyStore is a store with the transactions of an account, it filters them like the DB queries
+type historyStore struct {
+	fakeStore
+	transactions []*Transaction
+}
+
+func (h *historyStore) Velocity(_ context.Context, _ string, operationTypes []int64, _ time.Time) (int64, float64, error) {
+	count, total := int64(0), 0.0
+	for _, txn := range h.transactions {
+		if appliesTo(operationTypes, txn.OperationTypeID) {
+			count++
+			total += txn.Amount
+		}
+	}
+
+	return count, total, nil
+}
+
+func (f *fakeStore) SaveDecision(_ context.Context, _ *Transaction, decision *Decision) error {
+	f.decisions = append(f.decisions, decision)
+	return nil
+}
+
+const testRules = `
+velocity:
+  - name: ten-per-hour
+    window: 1h
+    max_count: 10
+    max_amount: 1000
+    operation_types: [1, 2, 3]
+anomaly:
+  - name: large-amount
+    min_history: 5
+    max_stddev: 3
+    max_multiplier: 10
+blocklist:
+  merchants: ["bad-merchant"]
+  countries: ["KP"]
+`
+
+func writeRules(t *testing.T, contents string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(contents), 0o600))
+
+	return path
+}
+
+func TestEngine_Evaluate(t *testing.T) {
+	path := writeRules(t, testRules)
+
+	tests := []struct {
+		name   string
+		store  *fakeStore
+		txn    *Transaction
+		reason ReasonCode
+	}{
+		{
+			name:  "approves a normal transaction",
+			store: &fakeStore{count: 1, total: 10, stats: &AmountStats{Count: 10, Mean: 50, StdDev: 10}},
+			txn:   &Transaction{AccountID: dummyAccountID, OperationTypeID: 1, Amount: 60},
+		},
+		{
+			name:   "declines a blocked merchant",
+			store:  &fakeStore{},
+			txn:    &Transaction{AccountID: dummyAccountID, OperationTypeID: 1, Amount: 60, MerchantID: "BAD-MERCHANT"},
+			reason: ReasonMerchantBlocked,
+		},
+		{
+			name:   "declines a blocked country",
+			store:  &fakeStore{},
+			txn:    &Transaction{AccountID: dummyAccountID, OperationTypeID: 1, Amount: 60, MerchantCountry: "kp"},
+			reason: ReasonCountryBlocked,
+		},
+		{
+			name:   "declines when the count in the window is exceeded",
+			store:  &fakeStore{count: 10},
+			txn:    &Transaction{AccountID: dummyAccountID, OperationTypeID: 1, Amount: 60},
+			reason: ReasonVelocityCount,
+		},
+		{
+			name:   "declines when the amount in the window is exceeded",
+			store:  &fakeStore{count: 2, total: 950},
+			txn:    &Transaction{AccountID: dummyAccountID, OperationTypeID: 1, Amount: 60},
+			reason: ReasonVelocityAmount,
+		},
+		{
+			name:  "skips velocity rules for other operation types",
+			store: &fakeStore{count: 10, stats: &AmountStats{}},
+			txn:   &Transaction{AccountID: dummyAccountID, OperationTypeID: 4, Amount: 60},
+		},
+		{
+			name:   "declines an outlier amount",
+			store:  &fakeStore{stats: &AmountStats{Count: 10, Mean: 50, StdDev: 10}},
+			txn:    &Transaction{AccountID: dummyAccountID, OperationTypeID: 1, Amount: 90},
+			reason: ReasonAmountAnomaly,
+		},
+		{
+			name:  "approves an outlier amount without enough history",
+			store: &fakeStore{stats: &AmountStats{Count: 2, Mean: 50, StdDev: 10}},
+			txn:   &Transaction{AccountID: dummyAccountID, OperationTypeID: 1, Amount: 900},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := NewEngine(path, tt.store, clock.Fixed(dummyNow))
+			assert.Nil(t, err)
+
+			decision, err := engine.Evaluate(context.Background(), tt.txn)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.reason == "", decision.Approved)
+			assert.Equal(t, tt.reason, decision.ReasonCode)
+
+			// Every decision is stored
+			assert.Len(t, tt.store.decisions, 1)
+		})
+	}
+}
+
+func TestEngine_VelocityOperationTypes(t *testing.T) {
+	rules := `
+velocity:
+  - name: max-1000-withdrawn-per-day
+    window: 24h
+    max_amount: 1000
+    operation_types: [3]
+`
+
+	// The account withdrew 600 and received a credit of 5000 in the window
+	store := &historyStore{transactions: []*Transaction{
+		{AccountID: dummyAccountID, OperationTypeID: 3, Amount: 600},
+		{AccountID: dummyAccountID, OperationTypeID: 4, Amount: 5000},
+	}}
+
+	engine, err := NewEngine(writeRules(t, rules), store, clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	// The credit doesn't count toward the withdrawals
+	decision, err := engine.Evaluate(context.Background(), &Transaction{AccountID: dummyAccountID, OperationTypeID: 3, Amount: 300})
+	assert.Nil(t, err)
+	assert.True(t, decision.Approved)
+
+	decision, err = engine.Evaluate(context.Background(), &Transaction{AccountID: dummyAccountID, OperationTypeID: 3, Amount: 500})
+	assert.Nil(t, err)
+	assert.Equal(t, ReasonVelocityAmount, decision.ReasonCode)
+}
+
+func TestEngine_VelocityWindow(t *testing.T) {
+	store := &fakeStore{stats: &AmountStats{}}
+
+	engine, err := NewEngine(writeRules(t, testRules), store, clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	_, err = engine.Evaluate(context.Background(), &Transaction{AccountID: dummyAccountID, OperationTypeID: 1, Amount: 60})
+	assert.Nil(t, err)
+
+	// The window ends at the current time of the clock, the transactions are counted by creation time in it
+	assert.Equal(t, dummyNow.Add(-time.Hour), store.since)
+}
+
+func TestEngine_Reload(t *testing.T) {
+	path := writeRules(t, testRules)
+	store := &fakeStore{stats: &AmountStats{}}
+
+	engine, err := NewEngine(path, store, clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	txn := &Transaction{AccountID: dummyAccountID, OperationTypeID: 4, Amount: 60, MerchantID: "new-bad-merchant"}
+
+	decision, err := engine.Evaluate(context.Background(), txn)
+	assert.Nil(t, err)
+	assert.True(t, decision.Approved)
+
+	t.Run("should pick up the new rules", func(t *testing.T) {
+		assert.Nil(t, os.WriteFile(path, []byte("blocklist:\n  merchants: [new-bad-merchant]\n"), 0o600))
+		assert.Nil(t, engine.Reload())
+
+		decision, err := engine.Evaluate(context.Background(), txn)
+		assert.Nil(t, err)
+		assert.Equal(t, ReasonMerchantBlocked, decision.ReasonCode)
+	})
+
+	t.Run("should keep the current rules when the file is invalid", func(t *testing.T) {
+		assert.Nil(t, os.WriteFile(path, []byte("velocity:\n  - name: no-window\n    max_count: 1\n"), 0o600))
+		assert.NotNil(t, engine.Reload())
+
+		decision, err := engine.Evaluate(context.Background(), txn)
+		assert.Nil(t, err)
+		assert.Equal(t, ReasonMerchantBlocked, decision.ReasonCode)
+	})
+}
+
+func TestNewEngine_WithoutRulesFile(t *testing.T) {
+	engine, err := NewEngine("", &fakeStore{}, clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	decision, err := engine.Evaluate(context.Background(), &Transaction{AccountID: dummyAccountID, Amount: 1_000_000})
+	assert.Nil(t, err)
+	assert.True(t, decision.Approved)
+}
+
+func TestEngine_Simulate(t *testing.T) {
+	store := &fakeStore{count: 10}
+
+	engine, err := NewEngine(writeRules(t, testRules), store, clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	decision, err := engine.Simulate(context.Background(), &Transaction{AccountID: dummyAccountID, OperationTypeID: 1, Amount: 60})
+	assert.Nil(t, err)
+	assert.Equal(t, ReasonVelocityCount, decision.ReasonCode)
+
+	// The decision of a simulation is not stored
+	assert.Empty(t, store.decisions)
+}
@@ -0,0 +1,126 @@
+package risk
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type (
+	// Rules is the set of risk rules read from the YAML rules file
+	Rules struct {
+		Velocity  []VelocityRule `yaml:"velocity"`
+		Anomaly   []AnomalyRule  `yaml:"anomaly"`
+		Blocklist Blocklist      `yaml:"blocklist"`
+	}
+
+	// VelocityRule limits the number of transactions or the total amount an account can transact within a window
+	VelocityRule struct {
+		Name      string        `yaml:"name"`
+		Window    time.Duration `yaml:"window"`
+		MaxCount  int64         `yaml:"max_count"`
+		MaxAmount float64       `yaml:"max_amount"`
+		// OperationTypes restricts the rule to the given operation types. The rule applies to all when empty
+		OperationTypes []int64 `yaml:"operation_types"`
+	}
+
+	// AnomalyRule declines amounts that are unusually large when compared to the account's history
+	AnomalyRule struct {
+		Name string `yaml:"name"`
+		// MinHistory is the number of transactions an account needs before the rule is applied
+		MinHistory int64 `yaml:"min_history"`
+		// MaxStdDev declines amounts more than MaxStdDev standard deviations above the mean
+		MaxStdDev float64 `yaml:"max_stddev"`
+		// MaxMultiplier declines amounts more than MaxMultiplier times the mean
+		MaxMultiplier  float64 `yaml:"max_multiplier"`
+		OperationTypes []int64 `yaml:"operation_types"`
+	}
+
+	// Blocklist contains the merchants and countries that are not allowed to transact
+	Blocklist struct {
+		Merchants []string `yaml:"merchants"`
+		Countries []string `yaml:"countries"`
+	}
+)
+
+// loadRules reads and validates the rules in the given YAML file
+func loadRules(path string) (*Rules, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("loadRules: failed to read rules file: %w", err)
+	}
+
+	rules := &Rules{}
+	if err = yaml.Unmarshal(contents, rules); err != nil {
+		return nil, fmt.Errorf("loadRules: failed to parse rules file: %w", err)
+	}
+
+	if err = rules.validate(); err != nil {
+		return nil, fmt.Errorf("loadRules: %w", err)
+	}
+
+	return rules, nil
+}
+
+// validate checks that every rule is usable. A bad rules file is rejected as a whole so that a typo
+// can never silently disable a rule
+func (r *Rules) validate() error {
+	for _, rule := range r.Velocity {
+		if rule.Name == "" {
+			return fmt.Errorf("velocity rule without a name")
+		}
+
+		if rule.Window <= 0 {
+			return fmt.Errorf("velocity rule %q: window must be positive", rule.Name)
+		}
+
+		if rule.MaxCount <= 0 && rule.MaxAmount <= 0 {
+			return fmt.Errorf("velocity rule %q: max_count or max_amount is required", rule.Name)
+		}
+	}
+
+	for _, rule := range r.Anomaly {
+		if rule.Name == "" {
+			return fmt.Errorf("anomaly rule without a name")
+		}
+
+		if rule.MaxStdDev <= 0 && rule.MaxMultiplier <= 0 {
+			return fmt.Errorf("anomaly rule %q: max_stddev or max_multiplier is required", rule.Name)
+		}
+	}
+
+	return nil
+}
+
+// appliesTo returns true when the operation type is in the list or when the list is empty
+func appliesTo(operationTypes []int64, operationTypeID int64) bool {
+	if len(operationTypes) == 0 {
+		return true
+	}
+
+	for _, id := range operationTypes {
+		if id == operationTypeID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contains does a case-insensitive search for the value in the list
+func contains(list []string, value string) bool {
+	if value == "" {
+		return false
+	}
+
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,76 @@
+package risk
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+)
+
+// querierStore is the Store backed by the database
+type querierStore struct {
+	querier models.Querier
+}
+
+// NewStore returns a Store that reads the account history from and saves the decisions to the database
+func NewStore(querier models.Querier) Store {
+	return &querierStore{querier: querier}
+}
+
+func (s *querierStore) Velocity(ctx context.Context, accountID string, operationTypes []int64, since time.Time) (int64, float64, error) {
+	velocity, err := s.querier.GetAccountTransactionVelocity(ctx, models.GetAccountTransactionVelocityParams{
+		AccountID:        accountID,
+		Since:            since,
+		OperationTypeIds: operationTypes,
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("store.Velocity: error fetching velocity: %w", err)
+	}
+
+	return velocity.TxnCount, velocity.TotalAmount, nil
+}
+
+func (s *querierStore) AmountStats(ctx context.Context, accountID string, operationTypes []int64) (*AmountStats, error) {
+	stats, err := s.querier.GetAccountTransactionAmountStats(ctx, models.GetAccountTransactionAmountStatsParams{
+		AccountID:        accountID,
+		OperationTypeIds: operationTypes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("store.AmountStats: error fetching amount stats: %w", err)
+	}
+
+	return &AmountStats{
+		Count:  stats.TxnCount,
+		Mean:   stats.MeanAmount,
+		StdDev: stats.StddevAmount,
+	}, nil
+}
+
+func (s *querierStore) SaveDecision(ctx context.Context, txn *Transaction, decision *Decision) error {
+	outcome := models.RiskOutcomeAPPROVED
+	if !decision.Approved {
+		outcome = models.RiskOutcomeDECLINED
+	}
+
+	err := s.querier.CreateRiskDecision(ctx, models.CreateRiskDecisionParams{
+		AccountID:       txn.AccountID,
+		OperationTypeID: txn.OperationTypeID,
+		Amount:          txn.Amount,
+		MerchantID:      nullString(txn.MerchantID),
+		MerchantCountry: nullString(txn.MerchantCountry),
+		Outcome:         outcome,
+		ReasonCode:      nullString(string(decision.ReasonCode)),
+		RuleName:        nullString(decision.Rule),
+	})
+	if err != nil {
+		return fmt.Errorf("store.SaveDecision: error saving decision: %w", err)
+	}
+
+	return nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
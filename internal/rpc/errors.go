@@ -0,0 +1,119 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	pismov1 "github.com/imjenal/transaction-service/api/proto/pismo/v1"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/tracing"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Error converts the API error to the error of a gRPC call with the code. The API error is in the details of the
+// status, as an ErrorDetails with the same error code as the HTTP API and the IDs of the call and of its trace
+func Error(ctx context.Context, code codes.Code, apiErr *response.APIError) error {
+	details := &pismov1.ErrorDetails{
+		Code:      int32(apiErr.Code),
+		Message:   apiErr.Message,
+		HowToFix:  apiErr.HowToFix,
+		RequestId: RequestIDFromContext(ctx),
+		TraceId:   tracing.TraceID(ctx),
+	}
+
+	if apiErr.Data != nil {
+		data, err := toValue(apiErr.Data)
+		if err != nil {
+			logging.FromContext(ctx).Warn("rpc.Error: failed to convert the data of the error", "code", apiErr.Code, "error", err)
+		}
+		details.Data = data
+	}
+
+	st, err := status.New(code, apiErr.Message).WithDetails(details)
+	if err != nil {
+		return status.Error(code, apiErr.Message)
+	}
+
+	return st.Err()
+}
+
+// InvalidArgument returns the error of a call with invalid arguments, e.g. a request that fails the validation
+func InvalidArgument(ctx context.Context, apiErr *response.APIError) error {
+	return Error(ctx, codes.InvalidArgument, apiErr)
+}
+
+// NotFound returns the error of a call on a resource that doesn't exist, the 404 of the HTTP API
+func NotFound(ctx context.Context, apiErr *response.APIError) error {
+	return Error(ctx, codes.NotFound, apiErr)
+}
+
+// AlreadyExists returns the error of a call that conflicts with an existing resource, the 409 of the HTTP API
+func AlreadyExists(ctx context.Context, apiErr *response.APIError) error {
+	return Error(ctx, codes.AlreadyExists, apiErr)
+}
+
+// FailedPrecondition returns the error of a call that breaks a rule of the domain, the 422 of the HTTP API
+// other than the validation errors, e.g. a declined transaction
+func FailedPrecondition(ctx context.Context, apiErr *response.APIError) error {
+	return Error(ctx, codes.FailedPrecondition, apiErr)
+}
+
+// Internal returns the error of a call that failed on the server
+func Internal(ctx context.Context, apiErr *response.APIError) error {
+	return Error(ctx, codes.Internal, apiErr)
+}
+
+// DefaultError returns the unknown error of a call
+func DefaultError(ctx context.Context) error {
+	return Internal(ctx, response.DefaultErr)
+}
+
+// AuthorizationError converts the error of the policy, it is a PermissionDenied when the caller isn't authorized
+// and an Internal error when the owner of the resource can't be found. The attributes of the resource are logged
+// with the error, e.g. "account_id", accountID
+func AuthorizationError(ctx context.Context, scope auth.Scope, err error, attrs ...any) error {
+	if errors.Is(err, auth.ErrForbidden) {
+		logging.FromContext(ctx).Info("authorize: forbidden", append([]any{"scope", scope, "error", err}, attrs...)...)
+		return Error(ctx, codes.PermissionDenied, response.NewError(response.Forbidden, auth.ErrForbidden.Error(),
+			auth.ForbiddenFix(scope), nil))
+	}
+
+	logging.FromContext(ctx).Error("authorize: failed to fetch the owner of the resource", append([]any{"error", err}, attrs...)...)
+
+	return Internal(ctx, &response.APIError{
+		Code:    response.DefaultErrorCode,
+		Message: "Failed to authorize the request.",
+	})
+}
+
+// DetailsFromError returns the ErrorDetails in the status of the error of a call, it is nil when there is none
+func DetailsFromError(err error) *pismov1.ErrorDetails {
+	for _, detail := range status.Convert(err).Details() {
+		if details, ok := detail.(*pismov1.ErrorDetails); ok {
+			return details
+		}
+	}
+
+	return nil
+}
+
+// toValue converts the data of an API error to a protobuf value, through its JSON encoding so that it has the same
+// fields as in the HTTP API
+func toValue(data any) (*structpb.Value, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, err
+	}
+
+	return structpb.NewValue(decoded)
+}
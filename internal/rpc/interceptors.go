@@ -0,0 +1,217 @@
+package rpc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/tracing"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// The metadata keys of the credentials and of the request ID, the same as the headers of the HTTP API
+const (
+	apiKeyKey        = "x-api-key"
+	authorizationKey = "authorization"
+	requestIDKey     = "x-request-id"
+)
+
+type requestIDContextKey struct{}
+
+// RequestIDFromContext returns the ID of the call set by the logging interceptor
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// NewServer returns a gRPC server whose calls are traced, logged with an ID, recovered from panics and
+// authenticated with an API key or a bearer token in the metadata
+func NewServer(logger *slog.Logger, authenticator *auth.Authenticator, opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.StatsHandler(tracing.GRPCStatsHandler()),
+		grpc.ChainUnaryInterceptor(
+			LoggingUnaryInterceptor(logger),
+			RecoveryUnaryInterceptor(),
+			AuthUnaryInterceptor(authenticator),
+		),
+		grpc.ChainStreamInterceptor(
+			LoggingStreamInterceptor(logger),
+			RecoveryStreamInterceptor(),
+			AuthStreamInterceptor(authenticator),
+		),
+	)
+
+	return grpc.NewServer(opts...)
+}
+
+// LoggingUnaryInterceptor assigns an ID to every call, or keeps the x-request-id of the caller, adds a logger with
+// the ID to the context and logs the call once it is served
+func LoggingUnaryInterceptor(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, done := startCall(ctx, logger, info.FullMethod)
+
+		res, err := handler(ctx, req)
+		done(err)
+
+		return res, err
+	}
+}
+
+// LoggingStreamInterceptor is the LoggingUnaryInterceptor of the streams
+func LoggingStreamInterceptor(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, done := startCall(ss.Context(), logger, info.FullMethod)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		done(err)
+
+		return err
+	}
+}
+
+// startCall adds the request ID and the logger of the call to the context, done logs the call with its status
+func startCall(ctx context.Context, logger *slog.Logger, method string) (context.Context, func(err error)) {
+	start := time.Now()
+
+	requestID := logging.RequestID(metadataValue(ctx, requestIDKey))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDKey, requestID))
+
+	callLogger := logger.With("request_id", requestID)
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		callLogger = callLogger.With("trace_id", traceID)
+	}
+
+	ctx = context.WithValue(ctx, requestIDContextKey{}, requestID)
+	ctx = logging.NewContext(ctx, callLogger)
+
+	return ctx, func(err error) {
+		code := status.Code(err)
+
+		level := slog.LevelInfo
+		if code == codes.Internal || code == codes.Unknown {
+			level = slog.LevelError
+		}
+
+		callLogger.LogAttrs(ctx, level, "call",
+			slog.String("method", method),
+			slog.String("code", code.String()),
+			slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+		)
+	}
+}
+
+// RecoveryUnaryInterceptor responds with the default error when the handler panics, the panic is logged
+func RecoveryUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res any, err error) {
+		defer recoverCall(ctx, info.FullMethod, &err)
+
+		return handler(ctx, req)
+	}
+}
+
+// RecoveryStreamInterceptor is the RecoveryUnaryInterceptor of the streams
+func RecoveryStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer recoverCall(ss.Context(), info.FullMethod, &err)
+
+		return handler(srv, ss)
+	}
+}
+
+func recoverCall(ctx context.Context, method string, err *error) {
+	if p := recover(); p != nil {
+		logging.FromContext(ctx).Error("recoverCall: the handler panicked", "method", method, "panic", p, "stack", string(debug.Stack()))
+		*err = DefaultError(ctx)
+	}
+}
+
+// AuthUnaryInterceptor rejects the unauthenticated calls with Unauthenticated and adds the identity of the caller
+// to the context of the others. The credentials are in the x-api-key or the authorization metadata
+func AuthUnaryInterceptor(authenticator *auth.Authenticator) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, authenticator)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor is the AuthUnaryInterceptor of the streams
+func AuthStreamInterceptor(authenticator *auth.Authenticator) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), authenticator)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+func authenticate(ctx context.Context, authenticator *auth.Authenticator) (context.Context, error) {
+	identity, err := authenticator.AuthenticateConnection(ctx, metadataValue(ctx, apiKeyKey), metadataValue(ctx, authorizationKey), tlsState(ctx))
+
+	switch {
+	case err == nil:
+		return auth.NewContext(ctx, identity), nil
+	case errors.Is(err, auth.ErrMissingCredentials):
+		return nil, Error(ctx, codes.Unauthenticated, response.NewError(response.MissingCredentials, auth.ErrMissingCredentials.Error(),
+			"Send an API key in the x-api-key metadata or a token in the authorization metadata with the Bearer scheme", nil))
+	case errors.Is(err, auth.ErrExpiredCredentials):
+		return nil, Error(ctx, codes.Unauthenticated, response.NewError(response.ExpiredCredentials, auth.ErrExpiredCredentials.Error(),
+			"Request a new token or API key", nil))
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		logging.FromContext(ctx).Info("authenticate: invalid credentials", "error", err)
+		return nil, Error(ctx, codes.Unauthenticated, response.NewError(response.InvalidCredentials, auth.ErrInvalidCredentials.Error(), "", nil))
+	default:
+		logging.FromContext(ctx).Error("authenticate: failed to authenticate call", "error", err)
+		return nil, DefaultError(ctx)
+	}
+}
+
+// tlsState returns the state of the TLS connection of the call, it is nil when the call isn't over TLS
+func tlsState(ctx context.Context) *tls.ConnectionState {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+
+	return &info.State
+}
+
+// metadataValue returns the first value of the key in the incoming metadata
+func metadataValue(ctx context.Context, key string) string {
+	if values := metadata.ValueFromIncomingContext(ctx, key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// serverStream replaces the context of a stream, e.g. with the identity of the caller
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	pismov1 "github.com/imjenal/transaction-service/api/proto/pismo/v1"
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const testAPIKey = "pk_test"
+
+// testAccountService answers GetAccount with the subject of the caller as the account ID, and panics on CreateAccount
+type testAccountService struct {
+	pismov1.UnimplementedAccountServiceServer
+}
+
+func (testAccountService) GetAccount(ctx context.Context, _ *pismov1.GetAccountRequest) (*pismov1.GetAccountResponse, error) {
+	identity, _ := auth.FromContext(ctx)
+	return &pismov1.GetAccountResponse{Account: &pismov1.Account{Id: identity.Subject, UserId: RequestIDFromContext(ctx)}}, nil
+}
+
+func (testAccountService) CreateAccount(context.Context, *pismov1.CreateAccountRequest) (*pismov1.CreateAccountResponse, error) {
+	panic("unexpected")
+}
+
+// newTestClient serves the test service on an in-memory listener with the interceptors of NewServer
+func newTestClient(t *testing.T, querier models.Querier) pismov1.AccountServiceClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	s := NewServer(slog.New(slog.NewTextHandler(io.Discard, nil)), auth.NewAuthenticator(querier, nil, nil, clock.System{}))
+	pismov1.RegisterAccountServiceServer(s, testAccountService{})
+
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return pismov1.NewAccountServiceClient(conn)
+}
+
+func TestServer_Unauthenticated(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	client := newTestClient(t, mock.NewMockQuerier(ctrl))
+
+	// Call the server without credentials
+	_, err := client.GetAccount(context.Background(), &pismov1.GetAccountRequest{})
+
+	// Check the results, the error has the code of the HTTP API and the ID of the call
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	details := DetailsFromError(err)
+	assert.Equal(t, int32(response.MissingCredentials), details.GetCode())
+	assert.NotEmpty(t, details.GetRequestId())
+}
+
+func TestServer_InvalidAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	client := newTestClient(t, mockRepo)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), auth.HashAPIKey(testAPIKey)).Return(nil, pgx.ErrNoRows)
+
+	// Call the server
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", testAPIKey)
+	_, err := client.GetAccount(ctx, &pismov1.GetAccountRequest{})
+
+	// Check the results
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	assert.Equal(t, int32(response.InvalidCredentials), DetailsFromError(err).GetCode())
+}
+
+func TestServer_AuthenticatesAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	client := newTestClient(t, mockRepo)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), auth.HashAPIKey(testAPIKey)).Return(&models.GetAPIKeyByHashRow{Uuid: "key-id"}, nil)
+	mockRepo.EXPECT().TouchAPIKey(gomock.Any(), "key-id").Return(nil)
+
+	// Call the server, the request ID of the caller is kept
+	var header metadata.MD
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", testAPIKey, "x-request-id", "req-123")
+	res, err := client.GetAccount(ctx, &pismov1.GetAccountRequest{}, grpc.Header(&header))
+
+	// Check the results
+	assert.Nil(t, err)
+	assert.Equal(t, "key-id", res.GetAccount().GetId())
+	assert.Equal(t, "req-123", res.GetAccount().GetUserId())
+	assert.Equal(t, []string{"req-123"}, header.Get("x-request-id"))
+}
+
+func TestServer_RecoversPanics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mock.NewMockQuerier(ctrl)
+	client := newTestClient(t, mockRepo)
+
+	// Prepare mock responses
+	mockRepo.EXPECT().GetAPIKeyByHash(gomock.Any(), gomock.Any()).Return(&models.GetAPIKeyByHashRow{Uuid: "key-id"}, nil)
+	mockRepo.EXPECT().TouchAPIKey(gomock.Any(), "key-id").Return(nil)
+
+	// Call the server
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-api-key", testAPIKey)
+	_, err := client.CreateAccount(ctx, &pismov1.CreateAccountRequest{})
+
+	// Check the results
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, int32(response.DefaultErrorCode), DetailsFromError(err).GetCode())
+}
+
+func TestError(t *testing.T) {
+	err := NotFound(context.Background(), response.NewError(response.ErrAccountNotFound, "ACCOUNT_NOT_FOUND", "", map[string]any{"account_id": "acc"}))
+
+	// The details are the error of the HTTP API, with the same data
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Equal(t, "ACCOUNT_NOT_FOUND", status.Convert(err).Message())
+
+	details := DetailsFromError(err)
+	assert.Equal(t, int32(response.ErrAccountNotFound), details.GetCode())
+	assert.Equal(t, map[string]any{"account_id": "acc"}, details.GetData().AsInterface())
+}
+
+func TestAuthorizationError(t *testing.T) {
+	// A caller that isn't authorized is denied
+	err := AuthorizationError(context.Background(), auth.ScopeAccountsRead, auth.ErrForbidden)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	assert.Equal(t, int32(response.Forbidden), DetailsFromError(err).GetCode())
+
+	// A failure to find the owner is an internal error
+	err = AuthorizationError(context.Background(), auth.ScopeAccountsRead, errors.New("database error"))
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestDetailsFromError(t *testing.T) {
+	assert.Nil(t, DetailsFromError(status.Error(codes.Internal, "no details")))
+	assert.Nil(t, DetailsFromError(nil))
+}
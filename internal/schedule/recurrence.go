@@ -0,0 +1,96 @@
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/robfig/cron/v3"
+	"github.com/teambition/rrule-go"
+)
+
+// ErrInvalidRecurrence is returned when the recurrence of a schedule can't be parsed
+var ErrInvalidRecurrence = errors.New("INVALID_RECURRENCE")
+
+// Recurrence tells when the occurrences of a schedule happen. Occurrences are in UTC.
+type Recurrence interface {
+	// Next returns the first occurrence strictly after t, or the zero time when there are no more occurrences
+	Next(t time.Time) time.Time
+}
+
+// Parse parses a cron expression in the standard 5 fields format, e.g. "0 9 1 * *", or an RFC 5545 RRULE,
+// e.g. "FREQ=MONTHLY;BYMONTHDAY=1". The RRULE starts at startsAt, so it must not have a DTSTART.
+func Parse(recurrenceType models.RecurrenceType, expr string, startsAt time.Time) (Recurrence, error) {
+	switch recurrenceType {
+	case models.RecurrenceTypeCRON:
+		s, err := cron.ParseStandard(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+
+		return cronRecurrence{schedule: s}, nil
+
+	case models.RecurrenceTypeRRULE:
+		option, err := rrule.StrToROption(strings.TrimPrefix(expr, "RRULE:"))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+
+		option.Dtstart = startsAt.UTC()
+
+		r, err := rrule.NewRRule(*option)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidRecurrence, err)
+		}
+
+		return rruleRecurrence{rule: r}, nil
+
+	default:
+		return nil, fmt.Errorf("%w: unknown recurrence type %q", ErrInvalidRecurrence, recurrenceType)
+	}
+}
+
+type cronRecurrence struct {
+	schedule cron.Schedule
+}
+
+func (c cronRecurrence) Next(t time.Time) time.Time {
+	return c.schedule.Next(t.UTC())
+}
+
+type rruleRecurrence struct {
+	rule *rrule.RRule
+}
+
+func (r rruleRecurrence) Next(t time.Time) time.Time {
+	return r.rule.After(t.UTC(), false)
+}
+
+// First returns the first occurrence at or after startsAt that is not after endsAt, it returns the zero time when there is none
+func First(recurrence Recurrence, startsAt time.Time, endsAt *time.Time) time.Time {
+	return NextBefore(recurrence, startsAt.Add(-time.Nanosecond), endsAt)
+}
+
+// NextBefore returns the first occurrence after t that is not after endsAt, it returns the zero time when there is none.
+// endsAt is optional.
+func NextBefore(recurrence Recurrence, t time.Time, endsAt *time.Time) time.Time {
+	next := recurrence.Next(t)
+	if next.IsZero() || (endsAt != nil && next.After(*endsAt)) {
+		return time.Time{}
+	}
+
+	return next
+}
+
+// Upcoming returns up to n occurrences starting from the given occurrence
+func Upcoming(recurrence Recurrence, from time.Time, endsAt *time.Time, n int) []time.Time {
+	occurrences := make([]time.Time, 0, n)
+
+	for next := from; !next.IsZero() && len(occurrences) < n; next = NextBefore(recurrence, next, endsAt) {
+		occurrences = append(occurrences, next)
+	}
+
+	return occurrences
+}
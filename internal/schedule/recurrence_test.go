@@ -0,0 +1,99 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	startsAt := time.Date(2024, time.July, 17, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		recurrenceType models.RecurrenceType
+		expr           string
+		first          time.Time
+		second         time.Time
+	}{
+		{
+			name:           "cron on the first day of the month",
+			recurrenceType: models.RecurrenceTypeCRON,
+			expr:           "0 9 1 * *",
+			first:          time.Date(2024, time.August, 1, 9, 0, 0, 0, time.UTC),
+			second:         time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "cron descriptor",
+			recurrenceType: models.RecurrenceTypeCRON,
+			expr:           "@daily",
+			first:          time.Date(2024, time.July, 18, 0, 0, 0, 0, time.UTC),
+			second:         time.Date(2024, time.July, 19, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:           "weekly rrule starting at starts_at",
+			recurrenceType: models.RecurrenceTypeRRULE,
+			expr:           "FREQ=WEEKLY",
+			first:          startsAt,
+			second:         startsAt.AddDate(0, 0, 7),
+		},
+		{
+			name:           "rrule with prefix",
+			recurrenceType: models.RecurrenceTypeRRULE,
+			expr:           "RRULE:FREQ=MONTHLY;BYMONTHDAY=1;BYHOUR=9;BYMINUTE=0;BYSECOND=0",
+			first:          time.Date(2024, time.August, 1, 9, 0, 0, 0, time.UTC),
+			second:         time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurrence, err := Parse(tt.recurrenceType, tt.expr, startsAt)
+			assert.Nil(t, err)
+
+			first := First(recurrence, startsAt, nil)
+			assert.Equal(t, tt.first, first)
+			assert.Equal(t, tt.second, NextBefore(recurrence, first, nil))
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, tt := range []struct {
+		recurrenceType models.RecurrenceType
+		expr           string
+	}{
+		{models.RecurrenceTypeCRON, "every day"},
+		{models.RecurrenceTypeCRON, "0 9 32 * *"},
+		{models.RecurrenceTypeRRULE, "FREQ=SOMETIMES"},
+		{"HOURLY", "0 * * * *"},
+	} {
+		_, err := Parse(tt.recurrenceType, tt.expr, time.Now())
+		assert.ErrorIs(t, err, ErrInvalidRecurrence, tt.expr)
+	}
+}
+
+func TestUpcoming(t *testing.T) {
+	startsAt := time.Date(2024, time.July, 17, 0, 0, 0, 0, time.UTC)
+	endsAt := time.Date(2024, time.July, 20, 0, 0, 0, 0, time.UTC)
+
+	recurrence, err := Parse(models.RecurrenceTypeRRULE, "FREQ=DAILY", startsAt)
+	assert.Nil(t, err)
+
+	t.Run("should stop at the limit", func(t *testing.T) {
+		assert.Len(t, Upcoming(recurrence, startsAt, nil, 2), 2)
+	})
+
+	t.Run("should stop at the end of the schedule", func(t *testing.T) {
+		// The end is inclusive
+		assert.Equal(t, []time.Time{startsAt, startsAt.AddDate(0, 0, 1), startsAt.AddDate(0, 0, 2), endsAt}, Upcoming(recurrence, startsAt, &endsAt, 10))
+	})
+
+	t.Run("should stop when the rrule has no more occurrences", func(t *testing.T) {
+		recurrence, err := Parse(models.RecurrenceTypeRRULE, "FREQ=DAILY;COUNT=2", startsAt)
+		assert.Nil(t, err)
+		assert.Len(t, Upcoming(recurrence, startsAt, nil, 10), 2)
+	})
+}
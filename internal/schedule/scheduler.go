@@ -0,0 +1,206 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/imjenal/transaction-service/api/v1/transactions"
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/health"
+	"github.com/imjenal/transaction-service/internal/logging"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/risk"
+	"github.com/jackc/pgx/v4"
+)
+
+// Scheduler posts the due occurrences of the scheduled transactions. Occurrences go through the transactions service,
+// so they are checked like any other transaction. An occurrence is posted at most once, even with several schedulers.
+type Scheduler struct {
+	conn               db.TxBeginner
+	riskEngine         *risk.Engine
+	rewardsEngine      *rewards.Engine
+	clock              clock.Clock
+	transactionsConfig *config.Transactions
+	config             *config.Scheduler
+	// heartbeat reports the health of the scheduler, it beats after every run
+	heartbeat *health.Heartbeat
+}
+
+func NewScheduler(conn db.TxBeginner, riskEngine *risk.Engine, rewardsEngine *rewards.Engine, clock clock.Clock, transactionsConfig *config.Transactions, config *config.Scheduler) *Scheduler {
+	return &Scheduler{
+		conn:               conn,
+		riskEngine:         riskEngine,
+		rewardsEngine:      rewardsEngine,
+		clock:              clock,
+		transactionsConfig: transactionsConfig,
+		config:             config,
+		heartbeat:          health.NewHeartbeat("scheduler", missedRuns*config.Interval, clock),
+	}
+}
+
+// missedRuns is the number of runs the scheduler may miss before it is reported down
+const missedRuns = 3
+
+// HealthChecker returns the check of the scheduler, it is down when the last run failed or it has missed runs
+func (s *Scheduler) HealthChecker() health.Checker {
+	return s.heartbeat
+}
+
+// Run posts the due occurrences every interval. It blocks until the context is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			posted, err := s.PostDue(ctx)
+			s.heartbeat.Beat(err)
+			if err != nil {
+				logging.FromContext(ctx).Error("scheduler.Run: failed to post due occurrences", "error", err)
+			}
+
+			if posted > 0 {
+				logging.FromContext(ctx).Info("scheduler.Run: processed occurrences", "occurrences", posted)
+			}
+		}
+	}
+}
+
+// PostDue processes up to the batch size of due occurrences, each one in its own DB transaction.
+// It returns the number of processed occurrences.
+func (s *Scheduler) PostDue(ctx context.Context) (int, error) {
+	for processed := 0; processed < s.config.BatchSize; processed++ {
+		found, err := s.postNext(ctx)
+		if err != nil || !found {
+			return processed, err
+		}
+	}
+
+	return s.config.BatchSize, nil
+}
+
+// postNext locks the next due schedule and posts its occurrence. It returns false when nothing is due.
+func (s *Scheduler) postNext(ctx context.Context) (bool, error) {
+	found := false
+
+	err := db.RunInTx(ctx, s.conn, func(tx pgx.Tx) error {
+		querier := models.New(tx)
+
+		schedule, err := querier.GetNextDueScheduledTransaction(ctx, sql.NullTime{Time: s.clock.Now(), Valid: true})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("scheduler.postNext: failed to fetch due schedule: %w", err)
+		}
+
+		found = true
+
+		// The transaction runs in a savepoint of the DB transaction, so a rejected transaction is rolled back
+		// while its failed run is still recorded
+		service := transactions.NewService(transactions.NewRepository(querier, tx), s.riskEngine, s.rewardsEngine, s.clock, s.transactionsConfig)
+
+		return Post(ctx, querier, service, schedule)
+	})
+
+	return found, err
+}
+
+// Post posts the next occurrence of the schedule and moves the schedule to the following occurrence.
+// An occurrence rejected by the transaction checks is recorded as failed and isn't retried.
+// Any other error must roll back the DB transaction, so that the occurrence is retried.
+func Post(ctx context.Context, querier models.Querier, service *transactions.Service, schedule *models.ScheduledTransaction) error {
+	occurrence := schedule.NextRunAt.Time
+
+	posted, err := querier.ScheduledTransactionRunExists(ctx, models.ScheduledTransactionRunExistsParams{
+		ScheduleID:   schedule.Uuid,
+		ScheduledFor: occurrence,
+	})
+	if err != nil {
+		return fmt.Errorf("schedule.Post: failed to check run: %w", err)
+	}
+
+	if !posted {
+		if err = postOccurrence(ctx, querier, service, schedule, occurrence); err != nil {
+			return err
+		}
+	}
+
+	recurrence, err := Parse(schedule.RecurrenceType, schedule.Recurrence, schedule.StartsAt)
+	if err != nil {
+		return fmt.Errorf("schedule.Post: schedule %s: %w", schedule.Uuid, err)
+	}
+
+	status := models.ScheduleStatusACTIVE
+	next := NextBefore(recurrence, occurrence, EndsAt(schedule))
+	if next.IsZero() {
+		status = models.ScheduleStatusCOMPLETED
+	}
+
+	_, err = querier.UpdateScheduledTransactionStatus(ctx, models.UpdateScheduledTransactionStatusParams{
+		Uuid:      schedule.Uuid,
+		Status:    status,
+		NextRunAt: sql.NullTime{Time: next, Valid: !next.IsZero()},
+	})
+	if err != nil {
+		return fmt.Errorf("schedule.Post: failed to move schedule to the next occurrence: %w", err)
+	}
+
+	return nil
+}
+
+// postOccurrence creates the transaction of the occurrence and records the run
+func postOccurrence(ctx context.Context, querier models.Querier, service *transactions.Service, schedule *models.ScheduledTransaction, occurrence time.Time) error {
+	run := models.CreateScheduledTransactionRunParams{
+		ScheduleID:   schedule.Uuid,
+		ScheduledFor: occurrence,
+		Status:       models.ScheduleRunStatusPOSTED,
+	}
+
+	// The event date is the occurrence, so an occurrence posted late is still dated when it was due
+	txn, err := service.Create(ctx, &transactions.CreateTransactionRequestData{
+		AccountId:       schedule.AccountID,
+		OperationTypeId: schedule.OperationTypeID,
+		Amount:          schedule.Amount,
+		EventDate:       &occurrence,
+	})
+
+	switch {
+	case err == nil:
+		run.TransactionID = sql.NullString{String: txn.Uuid, Valid: true}
+
+	case transactions.IsRejected(err):
+		logging.FromContext(ctx).Warn("schedule.postOccurrence: occurrence rejected", "occurrence", occurrence, "schedule_id", schedule.Uuid, "error", err)
+		run.Status = models.ScheduleRunStatusFAILED
+		run.Error = sql.NullString{String: err.Error(), Valid: true}
+
+	default:
+		return fmt.Errorf("schedule.postOccurrence: failed to create transaction: %w", err)
+	}
+
+	if _, err = querier.CreateScheduledTransactionRun(ctx, run); err != nil {
+		return fmt.Errorf("schedule.postOccurrence: failed to record run: %w", err)
+	}
+
+	return nil
+}
+
+// EndsAt returns the end of the schedule, it is nil when the schedule never ends
+func EndsAt(schedule *models.ScheduledTransaction) *time.Time {
+	if !schedule.EndsAt.Valid {
+		return nil
+	}
+
+	return &schedule.EndsAt.Time
+}
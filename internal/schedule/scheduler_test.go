@@ -0,0 +1,125 @@
+package schedule
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/imjenal/transaction-service/api/v1/transactions"
+	"github.com/imjenal/transaction-service/config"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/internal/db/models"
+	"github.com/imjenal/transaction-service/internal/db/models/mock"
+	"github.com/imjenal/transaction-service/internal/rewards"
+	"github.com/imjenal/transaction-service/internal/risk"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	dummyAccountID     = "115be6d7-6d9a-4391-b3ee-1d753ac7d611"
+	dummyScheduleID    = "2f0e8e44-3d57-4a4e-8d0e-4c1f4a6f3a11"
+	dummyTransactionID = "98a0f8e7-6e28-4d4f-872b-4d28b3d5ee66"
+)
+
+var dummyNow = time.Date(2024, time.August, 1, 9, 0, 30, 0, time.UTC)
+
+func newTestService(t *testing.T, querier models.Querier) *transactions.Service {
+	t.Helper()
+
+	engine, err := risk.NewEngine("", risk.NewStore(querier), clock.Fixed(dummyNow))
+	assert.Nil(t, err)
+
+	rewardsEngine, err := rewards.NewEngine("")
+	assert.Nil(t, err)
+
+	return transactions.NewService(transactions.NewRepository(querier, nil), engine, rewardsEngine, clock.Fixed(dummyNow), &config.Transactions{
+		MaxEventDateBackdate: 24 * time.Hour,
+	})
+}
+
+func newTestSchedule(endsAt sql.NullTime) *models.ScheduledTransaction {
+	return &models.ScheduledTransaction{
+		Uuid:            dummyScheduleID,
+		AccountID:       dummyAccountID,
+		OperationTypeID: 1,
+		Amount:          50,
+		RecurrenceType:  models.RecurrenceTypeCRON,
+		Recurrence:      "0 9 1 * *",
+		StartsAt:        time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC),
+		EndsAt:          endsAt,
+		NextRunAt:       sql.NullTime{Time: time.Date(2024, time.August, 1, 9, 0, 0, 0, time.UTC), Valid: true},
+		Status:          models.ScheduleStatusACTIVE,
+	}
+}
+
+func TestPost(t *testing.T) {
+	ctx := context.Background()
+	occurrence := time.Date(2024, time.August, 1, 9, 0, 0, 0, time.UTC)
+	nextOccurrence := time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should post the occurrence and move to the next one", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		querier := mock.NewMockQuerier(ctrl)
+
+		querier.EXPECT().ScheduledTransactionRunExists(gomock.Any(), models.ScheduledTransactionRunExistsParams{ScheduleID: dummyScheduleID, ScheduledFor: occurrence}).Return(false, nil)
+		querier.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountID).Return(models.AccountStatusACTIVE, nil)
+		querier.EXPECT().GetAccountStatusForUpdate(gomock.Any(), dummyAccountID).Return(models.AccountStatusACTIVE, nil)
+		querier.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), int64(1)).Return(models.AmountBehaviorNEGATIVE, nil)
+		querier.EXPECT().CreateRiskDecision(gomock.Any(), gomock.Any()).Return(nil)
+		querier.EXPECT().GetAccountLimitsByOperationType(gomock.Any(), gomock.Any()).Return(nil, nil)
+		querier.EXPECT().CreateTransaction(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, arg models.CreateTransactionParams) (*models.CreateTransactionRow, error) {
+				// The transaction is dated when the occurrence was due
+				assert.Equal(t, occurrence, arg.EventDate)
+				assert.Equal(t, float64(-50), arg.Amount)
+				return &models.CreateTransactionRow{Uuid: dummyTransactionID}, nil
+			})
+		querier.EXPECT().CreateScheduledTransactionRun(gomock.Any(), models.CreateScheduledTransactionRunParams{
+			ScheduleID:    dummyScheduleID,
+			ScheduledFor:  occurrence,
+			Status:        models.ScheduleRunStatusPOSTED,
+			TransactionID: sql.NullString{String: dummyTransactionID, Valid: true},
+		}).Return(&models.ScheduledTransactionRun{}, nil)
+		querier.EXPECT().UpdateScheduledTransactionStatus(gomock.Any(), models.UpdateScheduledTransactionStatusParams{
+			Uuid:      dummyScheduleID,
+			Status:    models.ScheduleStatusACTIVE,
+			NextRunAt: sql.NullTime{Time: nextOccurrence, Valid: true},
+		}).Return(&models.ScheduledTransaction{}, nil)
+
+		assert.Nil(t, Post(ctx, querier, newTestService(t, querier), newTestSchedule(sql.NullTime{})))
+	})
+
+	t.Run("should record a rejected occurrence as failed and complete the schedule after the last occurrence", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		querier := mock.NewMockQuerier(ctrl)
+
+		querier.EXPECT().ScheduledTransactionRunExists(gomock.Any(), gomock.Any()).Return(false, nil)
+		querier.EXPECT().GetAccountStatus(gomock.Any(), dummyAccountID).Return(models.AccountStatusBLOCKED, nil)
+		querier.EXPECT().GetOperationTypeAmountBehavior(gomock.Any(), int64(1)).Return(models.AmountBehaviorNEGATIVE, nil)
+		querier.EXPECT().CreateScheduledTransactionRun(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, arg models.CreateScheduledTransactionRunParams) (*models.ScheduledTransactionRun, error) {
+				assert.Equal(t, models.ScheduleRunStatusFAILED, arg.Status)
+				assert.Contains(t, arg.Error.String, "ACCOUNT_INACTIVE")
+				return &models.ScheduledTransactionRun{}, nil
+			})
+		querier.EXPECT().UpdateScheduledTransactionStatus(gomock.Any(), models.UpdateScheduledTransactionStatusParams{
+			Uuid:   dummyScheduleID,
+			Status: models.ScheduleStatusCOMPLETED,
+		}).Return(&models.ScheduledTransaction{}, nil)
+
+		endsAt := sql.NullTime{Time: time.Date(2024, time.August, 15, 0, 0, 0, 0, time.UTC), Valid: true}
+		assert.Nil(t, Post(ctx, querier, newTestService(t, querier), newTestSchedule(endsAt)))
+	})
+
+	t.Run("should not post an occurrence twice", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		querier := mock.NewMockQuerier(ctrl)
+
+		querier.EXPECT().ScheduledTransactionRunExists(gomock.Any(), gomock.Any()).Return(true, nil)
+		querier.EXPECT().UpdateScheduledTransactionStatus(gomock.Any(), gomock.Any()).Return(&models.ScheduledTransaction{}, nil)
+
+		assert.Nil(t, Post(ctx, querier, newTestService(t, querier), newTestSchedule(sql.NullTime{})))
+	})
+}
@@ -2,9 +2,10 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
-	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,17 +14,39 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/imjenal/transaction-service/api"
+	"github.com/imjenal/transaction-service/internal/metrics"
+	"github.com/imjenal/transaction-service/internal/rpc"
+	"github.com/imjenal/transaction-service/internal/tlsconfig"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 type Config struct {
 	Port int
 	Host string
+	// AdminPort and AdminHost are the address of the admin listener, which serves the metrics.
+	// The admin listener is not started when the port is 0
+	AdminPort int
+	AdminHost string
+	// GRPCPort is the port of the gRPC API, on the host of the HTTP API. The gRPC server is not started when it is 0
+	GRPCPort int
+	// ReadTimeout, WriteTimeout and IdleTimeout bound the requests and the idle connections of the HTTP listeners
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	IdleTimeout  time.Duration
+	// ShutdownTimeout is how long the pending requests and calls have to finish once a signal stops the server
+	ShutdownTimeout time.Duration
+	// TLS serves the certificates of the HTTP and the gRPC listeners. They serve plaintext when it is nil, the admin
+	// listener always does
+	TLS *tlsconfig.Reloader
 }
 
 // Server houses the http.Server and other variables for our HTTP server
 type Server struct {
 	server    http.Server
 	router    *mux.Router
+	admin     *http.Server  //admin serves the metrics apart from the API. It is nil when the admin listener is disabled
+	grpc      *grpc.Server  //grpc serves the gRPC API on its own port. It is nil when the gRPC listener is disabled
 	connClose chan struct{} //connClose channel is closed when the http.Server is shutdown. It can be used to listen when the server closes
 	apiParams *api.Params
 	cfg       *Config
@@ -33,14 +56,42 @@ type Server struct {
 func New(config *Config, params *api.Params) *Server {
 	r := mux.NewRouter().StrictSlash(true)
 
+	var admin *http.Server
+	if config.AdminPort != 0 {
+		admin = &http.Server{
+			Addr:         fmt.Sprintf("%s:%d", config.AdminHost, config.AdminPort),
+			ReadTimeout:  config.ReadTimeout,
+			WriteTimeout: config.WriteTimeout,
+			IdleTimeout:  config.IdleTimeout,
+		}
+	}
+
+	var grpcServer *grpc.Server
+	if config.GRPCPort != 0 {
+		var opts []grpc.ServerOption
+		if config.TLS != nil {
+			opts = append(opts, grpc.Creds(credentials.NewTLS(config.TLS.TLSConfig())))
+		}
+
+		grpcServer = rpc.NewServer(params.Logger, params.Authenticator, opts...)
+	}
+
+	var tlsConfig *tls.Config
+	if config.TLS != nil {
+		tlsConfig = config.TLS.TLSConfig()
+	}
+
 	return &Server{
+		admin:     admin,
+		grpc:      grpcServer,
 		router:    r,
 		connClose: make(chan struct{}, 1),
 		server: http.Server{
 			Addr:         fmt.Sprintf("%s:%d", config.Host, config.Port),
-			ReadTimeout:  2 * time.Second,
-			WriteTimeout: 20 * time.Second,
-			IdleTimeout:  65 * time.Second,
+			TLSConfig:    tlsConfig,
+			ReadTimeout:  config.ReadTimeout,
+			WriteTimeout: config.WriteTimeout,
+			IdleTimeout:  config.IdleTimeout,
 		},
 		apiParams: params,
 		cfg:       config,
@@ -49,9 +100,48 @@ func New(config *Config, params *api.Params) *Server {
 
 func (s *Server) Listen() {
 	s.setup()
-	log.Printf("Starting server... %s \n\n\n", s.server.Addr)
-	if err := s.server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
-		log.Printf("Failed to start HTTP server: %v", err)
+
+	if s.admin != nil {
+		go func() {
+			s.apiParams.Logger.Info("Starting admin server", "addr", s.admin.Addr)
+			if err := s.admin.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				s.apiParams.Logger.Error("Failed to start admin HTTP server", "error", err)
+			}
+		}()
+	}
+
+	if s.grpc != nil {
+		go s.listenGRPC()
+	}
+
+	s.apiParams.Logger.Info("Starting server", "addr", s.server.Addr, "tls", s.server.TLSConfig != nil)
+	if err := s.listenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		s.apiParams.Logger.Error("Failed to start HTTP server", "error", err)
+	}
+}
+
+// listenAndServe serves the API over TLS when it is configured, the certificates are in the TLS config
+func (s *Server) listenAndServe() error {
+	if s.server.TLSConfig != nil {
+		return s.server.ListenAndServeTLS("", "")
+	}
+
+	return s.server.ListenAndServe()
+}
+
+// listenGRPC serves the gRPC API until the server is stopped
+func (s *Server) listenGRPC() {
+	addr := fmt.Sprintf("%s:%d", s.cfg.Host, s.cfg.GRPCPort)
+
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		s.apiParams.Logger.Error("Failed to listen for gRPC", "addr", addr, "error", err)
+		return
+	}
+
+	s.apiParams.Logger.Info("Starting gRPC server", "addr", addr)
+	if err := s.grpc.Serve(lis); err != nil {
+		s.apiParams.Logger.Error("Failed to start gRPC server", "error", err)
 	}
 }
 
@@ -67,6 +157,14 @@ func (s *Server) setup() {
 	defer s.graceFullShutdown()
 	s.routes()
 	s.server.Handler = s.router
+
+	if s.admin != nil {
+		s.admin.Handler = s.adminRoutes()
+	}
+
+	if s.grpc != nil {
+		api.RegisterGRPC(s.grpc, s.apiParams)
+	}
 }
 
 func (s *Server) graceFullShutdown() {
@@ -81,22 +179,56 @@ func (s *Server) graceFullShutdown() {
 
 		// Once a signal is received, log it and shutdown the server
 
-		log.Printf("OS terminate signal received : %v \n", sig)
-		log.Printf("Shutting down server\n")
+		s.apiParams.Logger.Info("OS terminate signal received, shutting down server", "signal", sig.String())
 
-		// Create a context with timeout of 5 seconds
-		// The shutdown will wait for 5 seconds before timing out and abruptly closing the connections
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		// Create a context with the shutdown timeout
+		// The shutdown will wait for the timeout before abruptly closing the connections
+		ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
 		defer cancel()
 
 		if err := s.server.Shutdown(ctx); err != nil {
-			log.Printf("Error shutting down server: %v", err)
+			s.apiParams.Logger.Error("Error shutting down server", "error", err)
+		}
+
+		if s.admin != nil {
+			if err := s.admin.Shutdown(ctx); err != nil {
+				s.apiParams.Logger.Error("Error shutting down admin server", "error", err)
+			}
+		}
+
+		if s.grpc != nil {
+			s.stopGRPC(ctx)
 		}
 
 		close(s.connClose) // Close the channel to notify the shutdown
 	}()
 }
 
+// stopGRPC waits for the pending calls, e.g. the streams of transactions, until the context is done and then closes
+// the connections
+func (s *Server) stopGRPC(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.apiParams.Logger.Error("Error shutting down gRPC server", "error", ctx.Err())
+		s.grpc.Stop()
+	}
+}
+
 func (s *Server) routes() {
 	api.Routes(s.router.PathPrefix("/api/").Subrouter(), s.apiParams)
 }
+
+// adminRoutes are the routes of the admin listener
+func (s *Server) adminRoutes() http.Handler {
+	r := mux.NewRouter()
+	r.Handle("/metrics", metrics.Handler()).Methods(http.MethodGet)
+
+	return r
+}
@@ -0,0 +1,213 @@
+package tlsconfig
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/imjenal/transaction-service/internal/logging"
+)
+
+// ClientAuth is whether the TLS listeners require a client certificate
+type ClientAuth string
+
+const (
+	// ClientAuthOptional verifies the client certificates that are sent, the other callers authenticate with their
+	// credentials
+	ClientAuthOptional ClientAuth = "OPTIONAL"
+	// ClientAuthRequired rejects the connections without a client certificate signed by the client CA
+	ClientAuthRequired ClientAuth = "REQUIRED"
+)
+
+// Config has the files of the certificate of the listeners and of the CA of the client certificates
+type Config struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the PEM bundle of the CAs that sign the client certificates. The client certificates are not
+	// requested when it is empty
+	ClientCAFile string
+	ClientAuth   ClientAuth
+}
+
+// Reloader serves the certificate and the client CAs of the config to the TLS handshakes. The files are read again
+// when they change, so that the certificates can be renewed without a restart
+type Reloader struct {
+	cfg *Config
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	// checksum is the checksum of the contents of the files that are loaded
+	checksum []byte
+}
+
+// NewReloader returns a Reloader with the certificate and the client CAs of the config
+func NewReloader(cfg *Config) (*Reloader, error) {
+	r := &Reloader{cfg: cfg}
+
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Reload reads the files again. The current certificate and client CAs are kept when a file is invalid, e.g. while
+// a new certificate is written before its key
+func (r *Reloader) Reload() error {
+	_, err := r.reload()
+	return err
+}
+
+// reload reads the files and loads them when their contents changed. It tells whether they were loaded
+func (r *Reloader) reload() (bool, error) {
+	certPEM, err := os.ReadFile(r.cfg.CertFile)
+	if err != nil {
+		return false, fmt.Errorf("tlsconfig.Reload: failed to load the certificate: %w", err)
+	}
+
+	keyPEM, err := os.ReadFile(r.cfg.KeyFile)
+	if err != nil {
+		return false, fmt.Errorf("tlsconfig.Reload: failed to load the certificate: %w", err)
+	}
+
+	var caPEM []byte
+	if r.cfg.ClientCAFile != "" {
+		if caPEM, err = os.ReadFile(r.cfg.ClientCAFile); err != nil {
+			return false, fmt.Errorf("tlsconfig.Reload: failed to read the client CA: %w", err)
+		}
+	}
+
+	checksum := sha256.New()
+	for _, contents := range [][]byte{certPEM, keyPEM, caPEM} {
+		checksum.Write(contents)
+		checksum.Write([]byte{0})
+	}
+	sum := checksum.Sum(nil)
+
+	r.mu.RLock()
+	unchanged := bytes.Equal(sum, r.checksum)
+	r.mu.RUnlock()
+
+	if unchanged {
+		return false, nil
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return false, fmt.Errorf("tlsconfig.Reload: failed to load the certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.cfg.ClientCAFile != "" {
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caPEM) {
+			return false, errors.New("tlsconfig.Reload: the client CA file has no PEM certificate")
+		}
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.checksum = sum
+	r.mu.Unlock()
+
+	return true, nil
+}
+
+// TLSConfig returns the config of a TLS listener. Every handshake gets the current certificate and client CAs, and
+// HTTP/2 is negotiated for the HTTP and the gRPC clients
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// The certificate of the config of the handshakes is served, it is set here so that net/http and gRPC see
+		// a certificate in the config of the listener
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return r.cert, nil
+		},
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				NextProtos:   []string{"h2", "http/1.1"},
+			}
+
+			if r.clientCAs != nil {
+				cfg.ClientCAs = r.clientCAs
+				cfg.ClientAuth = tls.VerifyClientCertIfGiven
+				if r.cfg.ClientAuth == ClientAuthRequired {
+					cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				}
+			}
+
+			return cfg, nil
+		},
+	}
+}
+
+// Watch reloads the files whenever they change. It blocks until the context is cancelled.
+func (r *Reloader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("tlsconfig.Watch: failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	for _, file := range []string{r.cfg.CertFile, r.cfg.KeyFile, r.cfg.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+
+		// Watch the directories instead of the files, the certificates are usually renewed by replacing the files
+		// instead of writing to them
+		if err = watcher.Add(filepath.Dir(file)); err != nil {
+			return fmt.Errorf("tlsconfig.Watch: failed to watch certificates directory: %w", err)
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+
+			// The files are read again on any change in their directories, whatever the name of the event. The files
+			// may be symlinks that are not changed themselves, e.g. the Kubernetes secrets swap their ..data symlink.
+			// The certificates are only swapped when their contents changed
+			reloaded, err := r.reload()
+			if err != nil {
+				logging.FromContext(ctx).Error("tlsconfig.Watch: failed to reload the certificates, keeping the previous ones", "error", err)
+				continue
+			}
+
+			if reloaded {
+				logging.FromContext(ctx).Info("tlsconfig.Watch: certificates reloaded", "path", event.Name)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+
+			logging.FromContext(ctx).Error("tlsconfig.Watch: watcher error", "error", err)
+		}
+	}
+}
@@ -0,0 +1,373 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/imjenal/transaction-service/internal/auth"
+	"github.com/imjenal/transaction-service/internal/clock"
+	"github.com/imjenal/transaction-service/pkg/http/response"
+	"github.com/stretchr/testify/assert"
+)
+
+// testCert is a certificate generated for the tests and its key
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// newCA returns a self-signed CA
+func newCA(t *testing.T, name string) *testCert {
+	return newCert(t, nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	})
+}
+
+// newCert returns a certificate of the template signed by the CA, it is self-signed when the CA is nil
+func newCert(t *testing.T, ca *testCert, template *x509.Certificate) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	assert.Nil(t, err)
+
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	parent, signer := template, key
+	if ca != nil {
+		parent, signer = ca.cert, ca.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	assert.Nil(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	assert.Nil(t, err)
+
+	return &testCert{cert: cert, key: key}
+}
+
+// newServerCert returns a certificate of the server on 127.0.0.1 signed by the CA
+func newServerCert(t *testing.T, ca *testCert) *testCert {
+	return newCert(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "pismo"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+}
+
+// newClientCert returns a client certificate of the subject signed by the CA
+func newClientCert(t *testing.T, ca *testCert, subject pkix.Name) *testCert {
+	return newCert(t, ca, &x509.Certificate{
+		Subject:     subject,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+}
+
+// writeFiles writes the certificate and the key as PEM files
+func (c *testCert) writeFiles(t *testing.T, certFile, keyFile string) {
+	keyDER, err := x509.MarshalECPrivateKey(c.key)
+	assert.Nil(t, err)
+
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0o600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+}
+
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key, Leaf: c.cert}
+}
+
+// newTestServer serves the handler over TLS with the config of the reloader, on a random port
+func newTestServer(t *testing.T, reloader *Reloader, handler http.Handler) string {
+	lis, err := tls.Listen("tcp", "127.0.0.1:0", reloader.TLSConfig())
+	assert.Nil(t, err)
+
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: time.Second}
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	return "https://" + lis.Addr().String()
+}
+
+// newTestClient returns a client that trusts the CA and sends the client certificate when it is set
+func newTestClient(ca *testCert, clientCert *testCert) *http.Client {
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+
+	cfg := &tls.Config{RootCAs: roots, MinVersion: tls.VersionTLS12}
+	if clientCert != nil {
+		// The certificate is sent even when the server doesn't list its CA, like a misconfigured client would
+		cfg.GetClientCertificate = func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			cert := clientCert.tlsCertificate()
+			return &cert, nil
+		}
+	}
+
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}, Timeout: 5 * time.Second}
+}
+
+// subjectHandler responds with the subject of the verified client certificate
+var subjectHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	if len(r.TLS.VerifiedChains) == 0 {
+		_, _ = io.WriteString(w, "anonymous")
+		return
+	}
+
+	_, _ = io.WriteString(w, r.TLS.VerifiedChains[0][0].Subject.String())
+})
+
+// testFiles writes the certificate of the server and the client CA to a temporary directory
+func testFiles(t *testing.T, serverCert, clientCA *testCert) *Config {
+	dir := t.TempDir()
+	cfg := &Config{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "client-ca.crt"),
+		ClientAuth:   ClientAuthOptional,
+	}
+
+	serverCert.writeFiles(t, cfg.CertFile, cfg.KeyFile)
+	clientCA.writeFiles(t, cfg.ClientCAFile, filepath.Join(dir, "client-ca.key"))
+
+	return cfg
+}
+
+func get(t *testing.T, client *http.Client, url string) (string, error) {
+	res, err := client.Get(url)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	assert.Nil(t, err)
+
+	return string(body), nil
+}
+
+func TestReloader_ClientAuth(t *testing.T) {
+	serverCA := newCA(t, "server-ca")
+	clientCA := newCA(t, "client-ca")
+	otherCA := newCA(t, "other-ca")
+
+	partner := newClientCert(t, clientCA, pkix.Name{CommonName: "partner-x", Organization: []string{"Partner X"}})
+	impostor := newClientCert(t, otherCA, pkix.Name{CommonName: "partner-x", Organization: []string{"Partner X"}})
+
+	t.Run("optional", func(t *testing.T) {
+		reloader, err := NewReloader(testFiles(t, newServerCert(t, serverCA), clientCA))
+		assert.Nil(t, err)
+
+		url := newTestServer(t, reloader, subjectHandler)
+
+		// The client certificate is verified and its subject is known to the handlers
+		body, err := get(t, newTestClient(serverCA, partner), url)
+		assert.Nil(t, err)
+		assert.Equal(t, "CN=partner-x,O=Partner X", body)
+
+		// The callers without a certificate connect, they authenticate with their credentials
+		body, err = get(t, newTestClient(serverCA, nil), url)
+		assert.Nil(t, err)
+		assert.Equal(t, "anonymous", body)
+
+		// A certificate of another CA is rejected
+		_, err = get(t, newTestClient(serverCA, impostor), url)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("required", func(t *testing.T) {
+		cfg := testFiles(t, newServerCert(t, serverCA), clientCA)
+		cfg.ClientAuth = ClientAuthRequired
+
+		reloader, err := NewReloader(cfg)
+		assert.Nil(t, err)
+
+		url := newTestServer(t, reloader, subjectHandler)
+
+		body, err := get(t, newTestClient(serverCA, partner), url)
+		assert.Nil(t, err)
+		assert.Equal(t, "CN=partner-x,O=Partner X", body)
+
+		_, err = get(t, newTestClient(serverCA, nil), url)
+		assert.NotNil(t, err)
+	})
+}
+
+func TestReloader_Watch(t *testing.T) {
+	serverCA := newCA(t, "server-ca")
+	clientCA := newCA(t, "client-ca")
+	first := newServerCert(t, serverCA)
+
+	cfg := testFiles(t, first, clientCA)
+	reloader, err := NewReloader(cfg)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watching := make(chan error, 1)
+	go func() { watching <- reloader.Watch(ctx) }()
+
+	url := newTestServer(t, reloader, subjectHandler)
+
+	assert.Equal(t, first.cert.SerialNumber, servedSerial(t, serverCA, url))
+
+	// The renewed certificate is served to the new connections, without a restart. The watcher may have started
+	// after the files were written, so they are written until the new certificate is served
+	renewed := newServerCert(t, serverCA)
+	assert.Eventually(t, func() bool {
+		renewed.writeFiles(t, cfg.CertFile, cfg.KeyFile)
+		return renewed.cert.SerialNumber.Cmp(servedSerial(t, serverCA, url)) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// An invalid certificate is not loaded, the renewed one is still served
+	assert.Nil(t, os.WriteFile(cfg.CertFile, []byte("not a certificate"), 0o600))
+	assert.NotNil(t, reloader.Reload())
+	assert.Equal(t, renewed.cert.SerialNumber, servedSerial(t, serverCA, url))
+
+	cancel()
+	assert.Nil(t, <-watching)
+}
+
+// TestReloader_WatchSymlinkSwap renews the certificate like Kubernetes updates a mounted secret. The files are
+// symlinks to the ..data symlink, which is swapped to a new directory, the files themselves never change. The
+// previous directory is removed once ..data points to the new one
+func TestReloader_WatchSymlinkSwap(t *testing.T) {
+	serverCA := newCA(t, "server-ca")
+	dir := t.TempDir()
+
+	// swap writes the certificate to a new directory, points ..data to it and removes the previous one
+	version := 0
+	swap := func(cert *testCert) {
+		version++
+		data := fmt.Sprintf("..%d", version)
+		assert.Nil(t, os.Mkdir(filepath.Join(dir, data), 0o700))
+		cert.writeFiles(t, filepath.Join(dir, data, "tls.crt"), filepath.Join(dir, data, "tls.key"))
+
+		assert.Nil(t, os.Symlink(data, filepath.Join(dir, "..data_tmp")))
+		assert.Nil(t, os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")))
+		assert.Nil(t, os.RemoveAll(filepath.Join(dir, fmt.Sprintf("..%d", version-1))))
+	}
+
+	first := newServerCert(t, serverCA)
+	swap(first)
+
+	cfg := &Config{CertFile: filepath.Join(dir, "tls.crt"), KeyFile: filepath.Join(dir, "tls.key")}
+	assert.Nil(t, os.Symlink(filepath.Join("..data", "tls.crt"), cfg.CertFile))
+	assert.Nil(t, os.Symlink(filepath.Join("..data", "tls.key"), cfg.KeyFile))
+
+	reloader, err := NewReloader(cfg)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watching := make(chan error, 1)
+	go func() { watching <- reloader.Watch(ctx) }()
+
+	url := newTestServer(t, reloader, subjectHandler)
+	assert.Equal(t, first.cert.SerialNumber, servedSerial(t, serverCA, url))
+
+	// The watcher may have started after the swap, so the secret is swapped until the new certificate is served
+	renewed := newServerCert(t, serverCA)
+	assert.Eventually(t, func() bool {
+		swap(renewed)
+		return renewed.cert.SerialNumber.Cmp(servedSerial(t, serverCA, url)) == 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	// The removal of the previous directory is a change too, the renewed certificate is still served
+	assert.Never(t, func() bool {
+		return renewed.cert.SerialNumber.Cmp(servedSerial(t, serverCA, url)) != 0
+	}, 200*time.Millisecond, 50*time.Millisecond)
+
+	cancel()
+	assert.Nil(t, <-watching)
+}
+
+// servedSerial returns the serial of the certificate the server presents to a new connection
+func servedSerial(t *testing.T, ca *testCert, url string) *big.Int {
+	client := newTestClient(ca, nil)
+	client.Transport.(*http.Transport).DisableKeepAlives = true
+
+	res, err := client.Get(url)
+	if !assert.Nil(t, err) {
+		return nil
+	}
+	defer res.Body.Close()
+
+	return res.TLS.PeerCertificates[0].SerialNumber
+}
+
+func TestNewReloader_InvalidFiles(t *testing.T) {
+	serverCA := newCA(t, "server-ca")
+	cfg := testFiles(t, newServerCert(t, serverCA), newCA(t, "client-ca"))
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := NewReloader(&Config{CertFile: cfg.CertFile, KeyFile: filepath.Join(t.TempDir(), "missing.key")})
+		assert.ErrorContains(t, err, "failed to load the certificate")
+	})
+
+	t.Run("client CA without certificates", func(t *testing.T) {
+		empty := filepath.Join(t.TempDir(), "empty.crt")
+		assert.Nil(t, os.WriteFile(empty, []byte("no certificates here"), 0o600))
+
+		_, err := NewReloader(&Config{CertFile: cfg.CertFile, KeyFile: cfg.KeyFile, ClientCAFile: empty})
+		assert.ErrorContains(t, err, "the client CA file has no PEM certificate")
+	})
+}
+
+// TestReloader_ClientCertIdentity checks that a partner connected with mTLS is authenticated by its certificate
+func TestReloader_ClientCertIdentity(t *testing.T) {
+	serverCA := newCA(t, "server-ca")
+	clientCA := newCA(t, "client-ca")
+
+	identities := filepath.Join(t.TempDir(), "client_identities.yaml")
+	assert.Nil(t, os.WriteFile(identities, []byte(strings.Join([]string{
+		"clients:",
+		"  - subject: CN=partner-x,O=Partner X",
+		"    scopes: [accounts:read]",
+	}, "\n")), 0o600))
+
+	certs, err := auth.LoadClientCerts(identities)
+	assert.Nil(t, err)
+
+	reloader, err := NewReloader(testFiles(t, newServerCert(t, serverCA), clientCA))
+	assert.Nil(t, err)
+
+	authenticator := auth.NewAuthenticator(nil, nil, certs, clock.System{})
+	url := newTestServer(t, reloader, auth.NewMiddleware(authenticator, response.NewJSONWriter())(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			identity, _ := auth.FromContext(r.Context())
+			_, _ = io.WriteString(w, string(identity.Method)+" "+identity.Subject)
+		})))
+
+	body, err := get(t, newTestClient(serverCA, newClientCert(t, clientCA, pkix.Name{CommonName: "partner-x", Organization: []string{"Partner X"}})), url)
+	assert.Nil(t, err)
+	assert.Equal(t, "CLIENT_CERT CN=partner-x,O=Partner X", body)
+
+	// A verified certificate of an unknown subject doesn't authenticate the caller
+	res, err := newTestClient(serverCA, newClientCert(t, clientCA, pkix.Name{CommonName: "partner-y"})).Get(url)
+	assert.Nil(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
@@ -0,0 +1,12 @@
+package tracing
+
+import (
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc/stats"
+)
+
+// GRPCStatsHandler returns the stats handler of the gRPC server, which starts a span for every call in the trace of
+// the traceparent metadata of the caller
+func GRPCStatsHandler() stats.Handler {
+	return otelgrpc.NewServerHandler()
+}
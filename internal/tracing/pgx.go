@@ -0,0 +1,70 @@
+package tracing
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// queryName is the name sqlc gives to the queries, e.g. "-- name: AccountExists :one"
+var queryName = regexp.MustCompile(`^-- name: (\w+)`)
+
+// QueryTracer records a span for every query run by pgx. pgx v4 reports the queries to its logger once they are done,
+// with their duration, so the spans are started back in time. It must be set as the Logger of the pgx config with
+// LogLevel pgx.LogLevelInfo
+type QueryTracer struct{}
+
+// NewQueryTracer creates a new instance of QueryTracer
+func NewQueryTracer() *QueryTracer {
+	return &QueryTracer{}
+}
+
+func (t *QueryTracer) Log(ctx context.Context, _ pgx.LogLevel, msg string, data map[string]interface{}) {
+	// Only the queries are traced, not the other events of the connections
+	if msg != "Query" && msg != "Exec" && msg != "SendBatch" {
+		return
+	}
+
+	end := time.Now()
+	duration, _ := data["time"].(time.Duration)
+	statement, _ := data["sql"].(string)
+
+	// The arguments aren't recorded, they have the personal data of the users
+	_, span := Start(ctx, spanName(msg, statement),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(end.Add(-duration)),
+		trace.WithAttributes(
+			semconv.DBSystemPostgreSQL,
+			semconv.DBQueryText(statement),
+		),
+	)
+
+	if rowCount, ok := data["rowCount"].(int); ok {
+		span.SetAttributes(attribute.Int("db.response.returned_rows", rowCount))
+	}
+
+	if err, ok := data["err"].(error); ok {
+		RecordError(span, err)
+	}
+
+	span.End(trace.WithTimestamp(end))
+}
+
+// spanName names the span of a query by its sqlc name, or by its SQL command for the queries written by hand
+func spanName(msg, statement string) string {
+	if m := queryName.FindStringSubmatch(statement); m != nil {
+		return "db." + m[1]
+	}
+
+	if fields := strings.Fields(statement); len(fields) > 0 {
+		return "db." + strings.ToUpper(fields[0])
+	}
+
+	return "db." + msg
+}
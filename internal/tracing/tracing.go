@@ -0,0 +1,126 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName names the tracer of the service
+const instrumentationName = "github.com/imjenal/transaction-service"
+
+// The exporters of the spans
+const (
+	ExporterNone   = "NONE"
+	ExporterStdout = "STDOUT"
+	ExporterOTLP   = "OTLP"
+)
+
+var errUnknownExporter = errors.New("UNKNOWN_EXPORTER")
+
+// Config is the configuration of the tracing
+type Config struct {
+	// Exporter is where the spans are sent: NONE, STDOUT or OTLP
+	Exporter string
+	// Endpoint is the host:port of the OTLP/HTTP collector. When empty the OTEL_EXPORTER_OTLP_* variables apply
+	Endpoint string
+	// Insecure sends the spans to the collector over plain HTTP
+	Insecure bool
+	// SampleRatio is the share of the traces started by the service that are sampled. The traces started by the
+	// callers follow the sampling decision of their traceparent
+	SampleRatio float64
+	ServiceName string
+	// Stdout is where the STDOUT exporter writes the spans
+	Stdout io.Writer
+}
+
+// Setup installs the global tracer provider and the W3C trace context propagator. The spans are exported as
+// configured. The returned function flushes the spans and stops the exporter
+func Setup(ctx context.Context, cfg *Config) (func(ctx context.Context) error, error) {
+	// The traceparent of the callers is propagated even when the spans of the service aren't exported
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+
+	switch cfg.Exporter {
+	case ExporterNone:
+		// The spans are still started, so that the trace IDs of the callers are logged, but they aren't recorded
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(cfg.Stdout))
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	default:
+		err = errUnknownExporter
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("tracing.Setup: failed to create the %s exporter: %w", cfg.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return nil, fmt.Errorf("tracing.Setup: failed to create the resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the service, from the global tracer provider
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts a span that is a child of the span in ctx, e.g.
+//
+//	ctx, span := tracing.Start(ctx, "transactions.Repository.createTransaction")
+//	defer span.End()
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, opts...)
+}
+
+// RecordError marks the span as failed with err. Nothing is recorded when err is nil
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// TraceID returns the ID of the trace of the span in ctx, or "" when there is none
+func TraceID(ctx context.Context) string {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+
+	return sc.TraceID().String()
+}
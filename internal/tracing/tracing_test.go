@@ -0,0 +1,143 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const (
+	traceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	dummyTrace  = "4bf92f3577b34da6a3ce929d0e0e4736"
+)
+
+// recordSpans installs a tracer provider that records the ended spans, for the duration of the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	return recorder
+}
+
+// attributes maps the attributes of a span by key
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+
+	return attrs
+}
+
+func TestMiddleware(t *testing.T) {
+	recorder := recordSpans(t)
+
+	var traceID string
+	r := mux.NewRouter()
+	api := r.PathPrefix("/api/").Subrouter()
+	api.Use(Middleware)
+	api.HandleFunc("/v1/accounts/{accountID}", func(w http.ResponseWriter, r *http.Request) {
+		traceID = TraceID(r.Context())
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/accounts/115be6d7-6d9a-4391-b3ee-1d753ac7d611", nil)
+	req.Header.Set("traceparent", traceParent)
+	r.ServeHTTP(rr, req)
+
+	// The span continues the trace of the caller, and the handlers know the trace
+	assert.Equal(t, dummyTrace, traceID)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "GET /api/v1/accounts/{accountID}", spans[0].Name())
+	assert.Equal(t, dummyTrace, spans[0].SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+	assert.Equal(t, int64(http.StatusInternalServerError), attributes(spans[0])["http.response.status_code"].AsInt64())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestQueryTracer(t *testing.T) {
+	recorder := recordSpans(t)
+	tracer := NewQueryTracer()
+
+	ctx, parent := Start(context.Background(), "accounts.Repository.accountExists")
+	tracer.Log(ctx, pgx.LogLevelInfo, "Query", map[string]interface{}{
+		"sql":      "-- name: AccountExists :one\nSELECT EXISTS(SELECT 1 FROM public.accounts WHERE uuid = $1) AS exists\n",
+		"args":     []interface{}{"115be6d7-6d9a-4391-b3ee-1d753ac7d611"},
+		"time":     20 * time.Millisecond,
+		"rowCount": 1,
+	})
+	tracer.Log(ctx, pgx.LogLevelError, "Exec", map[string]interface{}{
+		"sql":  "commit",
+		"err":  errors.New("connection reset"),
+		"time": time.Millisecond,
+	})
+	// The other events of the connections aren't traced
+	tracer.Log(ctx, pgx.LogLevelInfo, "closed connection", nil)
+	parent.End()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+
+	query := spans[0]
+	assert.Equal(t, "db.AccountExists", query.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+	assert.Equal(t, 20*time.Millisecond, query.EndTime().Sub(query.StartTime()))
+	assert.Equal(t, int64(1), attributes(query)["db.response.returned_rows"].AsInt64())
+	// The arguments aren't recorded
+	for _, kv := range query.Attributes() {
+		assert.NotContains(t, kv.Value.Emit(), "115be6d7")
+	}
+
+	commit := spans[1]
+	assert.Equal(t, "db.COMMIT", commit.Name())
+	assert.Equal(t, codes.Error, commit.Status().Code)
+}
+
+func TestSetup(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	_, err := Setup(context.Background(), &Config{Exporter: "ZIPKIN"})
+	assert.ErrorIs(t, err, errUnknownExporter)
+
+	shutdown, err := Setup(context.Background(), &Config{Exporter: ExporterNone})
+	assert.Nil(t, err)
+	assert.Nil(t, shutdown(context.Background()))
+
+	buf := &bytes.Buffer{}
+	shutdown, err = Setup(context.Background(), &Config{Exporter: ExporterStdout, SampleRatio: 1, ServiceName: "pismo", Stdout: buf})
+	assert.Nil(t, err)
+
+	_, span := Start(context.Background(), "transactions.Service.Create")
+	span.End()
+
+	// The spans are flushed on shutdown
+	assert.Nil(t, shutdown(context.Background()))
+	assert.Contains(t, buf.String(), `"Name":"transactions.Service.Create"`)
+}
+
+func TestTraceID(t *testing.T) {
+	assert.Empty(t, TraceID(context.Background()))
+}
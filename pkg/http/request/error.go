@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 
 	"github.com/imjenal/transaction-service/pkg/http/response"
@@ -29,6 +30,7 @@ func (read *Reader) HandleParseError(err error) *ParseError {
 	var (
 		syntaxError        *json.SyntaxError
 		unmarshalTypeError *json.UnmarshalTypeError
+		maxBytesError      *http.MaxBytesError
 	)
 
 	switch {
@@ -36,15 +38,21 @@ func (read *Reader) HandleParseError(err error) *ParseError {
 	// which interpolates the location of the problem to make it easier for the client to fix.
 	// In some circumstances Decode() may also return an io.ErrUnexpectedEOF error for syntax errors in the JSON.
 	case errors.As(err, &syntaxError), errors.Is(err, io.ErrUnexpectedEOF):
-		return &ParseError{
+		parseErr := &ParseError{
 			Msg:     "Request body contains badly-formed JSON",
 			Fix:     "Make sure the syntax of the JSON payload passed in the request body is valid.",
 			ErrCode: response.InvalidJSON,
-			ErrData: map[string]interface{}{
+		}
+
+		// A truncated payload has no syntax error, and so no offset
+		if syntaxError != nil {
+			parseErr.ErrData = map[string]interface{}{
 				"offset": syntaxError.Offset,
-			},
+			}
 		}
 
+		return parseErr
+
 	// Catch any type errors, like trying to assign a string in the JSON request body to an int field in our Person struct.
 	// We can interpolate the relevant field name and position into the error
 	// Message to make it easier for the client to fix.
@@ -60,8 +68,9 @@ func (read *Reader) HandleParseError(err error) *ParseError {
 
 	// Catch the error caused by extra unexpected fields in the request body.
 	// We extract the field name from the error Message and interpolate it in our custom error Message.
-	case strings.HasPrefix(err.Error(), "json: unknown field "):
-		fieldName := strings.TrimPrefix(err.Error(), "json: unknown field ")
+	// The error is wrapped by ReadJSONRequest, so the field name is after the prefix.
+	case strings.Contains(err.Error(), "json: unknown field "):
+		_, fieldName, _ := strings.Cut(err.Error(), "json: unknown field ")
 
 		return &ParseError{
 			Msg:     fmt.Sprintf("Request body contains unknown field %s", fieldName),
@@ -82,12 +91,15 @@ func (read *Reader) HandleParseError(err error) *ParseError {
 		}
 
 	// Catch the error caused by the request body being too large.
-	case err.Error() == "http: request body too large":
+	// The limit is the one of the route, see LimitBody.
+	case errors.As(err, &maxBytesError):
 		return &ParseError{
-			Msg:     "Request body must not be larger than 1MB",
-			Fix:     "Please make sure that the size of the request body payload is less than 1MB",
+			Msg:     fmt.Sprintf("Request body must not be larger than %d bytes", maxBytesError.Limit),
+			Fix:     fmt.Sprintf("Please make sure that the size of the request body payload is at most %d bytes", maxBytesError.Limit),
 			ErrCode: response.RequestSizeExceeds,
-			ErrData: nil,
+			ErrData: map[string]interface{}{
+				"limit": maxBytesError.Limit,
+			},
 		}
 	}
 
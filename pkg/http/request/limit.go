@@ -0,0 +1,45 @@
+package request
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// routeLimit is the handler of a route that takes larger or smaller bodies than the default limit, see RouteLimit
+type routeLimit struct {
+	http.Handler
+	limit int64
+}
+
+// RouteLimit sets the body limit of a route, which replaces the limit of the LimitBody middleware of its router.
+// The body is limited by LimitBody before any other middleware reads it, RouteLimit doesn't limit it by itself
+func RouteLimit(limit int64) func(h http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return &routeLimit{Handler: next, limit: limit}
+	}
+}
+
+// LimitBody returns a middleware that limits the request bodies to limit bytes, or to the limit of the matched route
+// when it is set with RouteLimit. Reading more fails with an *http.MaxBytesError, which HandleParseError reports as
+// RequestSizeExceeds, and the connection is closed once the response is sent
+func LimitBody(limit int64) func(h http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.Body = http.MaxBytesReader(w, r.Body, bodyLimit(r, limit))
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// bodyLimit returns the limit of the route of the request, or the default limit when the route doesn't set one
+func bodyLimit(r *http.Request, limit int64) int64 {
+	if route := mux.CurrentRoute(r); route != nil {
+		if handler, ok := route.GetHandler().(*routeLimit); ok {
+			return handler.limit
+		}
+	}
+
+	return limit
+}
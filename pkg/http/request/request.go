@@ -34,12 +34,17 @@ func NewReader(jw *response.JSONWriter, validator *validator.Validator) *Reader
 func (read *Reader) ReadJSONAndValidate(w http.ResponseWriter, r *http.Request, v interface{}) bool {
 	if err := read.ReadJSONRequest(r, v); err != nil {
 		parseErr := read.HandleParseError(err)
+		if parseErr.ErrCode == response.RequestSizeExceeds {
+			read.jw.RequestEntityTooLarge(w, parseErr.APIError())
+			return false
+		}
+
 		read.jw.BadRequest(w, parseErr.APIError())
 
 		return false
 	}
 
-	ve := read.validate(r.Context(), v)
+	ve := read.Validate(r.Context(), v)
 	if ve != nil {
 		read.jw.UnprocessableEntity(w, ve)
 		return false
@@ -58,7 +63,7 @@ func (read *Reader) ReadQueryParamsAndValidate(w http.ResponseWriter, r *http.Re
 		return false
 	}
 
-	ve := read.validate(r.Context(), v)
+	ve := read.Validate(r.Context(), v)
 	if ve != nil {
 		read.jw.UnprocessableEntity(w, ve)
 		return false
@@ -78,12 +83,12 @@ func (read *Reader) ReadJSONRequest(r *http.Request, v interface{}) error {
 	d.DisallowUnknownFields()
 
 	if err := d.Decode(v); err != nil {
-		return fmt.Errorf("error decoding request body: %v", err)
+		return fmt.Errorf("error decoding request body: %w", err)
 	}
 
 	bts, err := io.ReadAll(&buf)
 	if err != nil {
-		return fmt.Errorf("error reading request body: %v", err)
+		return fmt.Errorf("error reading request body: %w", err)
 	}
 
 	rawJSON := removeAllWhitespace(string(bts))
@@ -101,8 +106,9 @@ func (read *Reader) ReadQueryParams(r *http.Request, v interface{}) error {
 	return read.schemaDecoder.Decode(v, r.URL.Query())
 }
 
-// validate functions uses the validator to test issues with the given data
-func (read *Reader) validate(ctx context.Context, v interface{}) *response.APIError {
+// Validate uses the validator to test issues with the given data, it returns the error to respond with
+// when the data is invalid
+func (read *Reader) Validate(ctx context.Context, v interface{}) *response.APIError {
 	result, err := read.validator.IsValidStruct(ctx, v)
 	if err != nil {
 		panic(err)
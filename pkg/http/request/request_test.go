@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gorilla/mux"
 	"github.com/imjenal/transaction-service/pkg/http/response"
 	"github.com/imjenal/transaction-service/pkg/validator"
 	"github.com/stretchr/testify/assert"
@@ -183,7 +184,7 @@ func TestReader_validate(t *testing.T) {
 			Field2: "a@dlclec",
 		}
 
-		err := reader.validate(ctx, v)
+		err := reader.Validate(ctx, v)
 		assert.NotNil(t, err)
 		assert.Len(t, err.Data, 2)
 		assert.Equal(t, err.Code, response.ValidationFailed)
@@ -196,7 +197,89 @@ func TestReader_validate(t *testing.T) {
 			Field2: "test@mail.com",
 		}
 
-		err := reader.validate(ctx, v)
+		err := reader.Validate(ctx, v)
 		assert.Nil(t, err)
 	})
 }
+
+func TestLimitBody(t *testing.T) {
+	jw := response.NewJSONWriter()
+	reader := NewReader(jw, validator.New())
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		output := &testRequest{}
+		if reader.ReadJSONAndValidate(w, r, output) {
+			jw.Ok(w, output)
+		}
+	}
+
+	body := `{"field_1":"` + strings.Repeat("a", 100) + `","field_2":"test@mail.com"}`
+
+	t.Run("test that a body within the limit is read", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/some/endpoint", strings.NewReader(body))
+
+		LimitBody(1024)(http.HandlerFunc(handler)).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+	})
+
+	t.Run("test that a body over the limit is rejected", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/some/endpoint", strings.NewReader(body))
+
+		LimitBody(64)(http.HandlerFunc(handler)).ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+
+		res := &struct {
+			Error *response.APIError `json:"error"`
+		}{}
+		assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), res))
+		assert.Equal(t, response.RequestSizeExceeds, res.Error.Code)
+		assert.Equal(t, "Request body must not be larger than 64 bytes", res.Error.Message)
+		assert.Equal(t, map[string]any{"limit": float64(64)}, res.Error.Data)
+	})
+
+	t.Run("test that the limit of the route replaces the limit of the router", func(t *testing.T) {
+		r := mux.NewRouter()
+		r.Use(LimitBody(64))
+		r.Handle("/larger", RouteLimit(1024)(http.HandlerFunc(handler)))
+		r.Handle("/smaller", RouteLimit(16)(http.HandlerFunc(handler)))
+
+		// The route allows more than the router
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/larger", strings.NewReader(body)))
+
+		assert.Equal(t, http.StatusOK, rr.Code)
+
+		// The route allows less than the router
+		rr = httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest(http.MethodPost, "/smaller", strings.NewReader(`{"field_1":"abcdefghij"}`)))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+	})
+}
+
+func TestReader_HandleParseError(t *testing.T) {
+	reader := NewReader(response.NewJSONWriter(), validator.New())
+
+	t.Run("test that a truncated body is badly-formed JSON", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/some/endpoint", strings.NewReader(`{"field_1":`))
+
+		err := reader.ReadJSONRequest(req, &testRequest{})
+
+		parseErr := reader.HandleParseError(err)
+		assert.Equal(t, response.InvalidJSON, parseErr.ErrCode)
+	})
+
+	t.Run("test that an unknown field is named", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/some/endpoint", strings.NewReader(`{"field_3":"a"}`))
+
+		err := reader.ReadJSONRequest(req, &testRequest{})
+
+		parseErr := reader.HandleParseError(err)
+		assert.Equal(t, response.UnKnownJSONField, parseErr.ErrCode)
+		assert.Equal(t, map[string]interface{}{"field": `"field_3"`}, parseErr.ErrData)
+	})
+}
@@ -23,16 +23,65 @@ const (
 	InvalidPathParam ErrorCode = 1007
 	//InvalidUUID - when the uuid is invalid
 	InvalidUUID ErrorCode = 1008
+	//MissingCredentials - when the request has neither an API key nor a bearer token
+	MissingCredentials ErrorCode = 1009
+	//InvalidCredentials - when the API key or the bearer token is unknown, revoked or can't be verified
+	InvalidCredentials ErrorCode = 1010
+	//ExpiredCredentials - when the API key or the bearer token has expired
+	ExpiredCredentials ErrorCode = 1011
+	//Forbidden - when the caller is missing the scope or doesn't own the resource
+	Forbidden ErrorCode = 1012
+	//RateLimitExceeded - when the caller, its IP or the account has made too many requests to the route
+	RateLimitExceeded ErrorCode = 1013
+	//NotReady - when a dependency of the service, e.g. the database, is down
+	NotReady ErrorCode = 1014
 
 	//ErrAccountNotFound - when account isn't found
 	ErrAccountNotFound ErrorCode = 2001
+	//ErrInvalidAccountStatusTransition - when the account can't move from its current status to the requested one
+	ErrInvalidAccountStatusTransition ErrorCode = 2002
+	//ErrAccountInactive - when the status of the account doesn't allow the transaction
+	ErrAccountInactive ErrorCode = 2003
+	//ErrAccountAlreadyExists - when the document number is already used by another account
+	ErrAccountAlreadyExists ErrorCode = 2004
 
 	//ErrTransactionNotFound - when transaction isn't found
 	ErrTransactionNotFound ErrorCode = 3001
+	//ErrTransactionDeclined - when the transaction is declined by the risk rules
+	ErrTransactionDeclined ErrorCode = 3002
+	//ErrSpendingLimitExceeded - when the transaction would breach a spending limit of the account
+	ErrSpendingLimitExceeded ErrorCode = 3003
+	//ErrInvalidEventDate - when the event date of the transaction is too far in the past or in the future
+	ErrInvalidEventDate ErrorCode = 3004
 
 	//ErrUserNotFound - when user isn't found
 	ErrUserNotFound ErrorCode = 4001
+	//ErrPhoneNumberAlreadyExists - when the phone number belongs to another user
+	ErrPhoneNumberAlreadyExists ErrorCode = 4002
+	//ErrEmailAlreadyExists - when the email belongs to another user
+	ErrEmailAlreadyExists ErrorCode = 4003
 
 	//ErrOperationTypeNotFound - when operation type isn't found
 	ErrOperationTypeNotFound ErrorCode = 5001
+
+	//ErrScheduledTransactionNotFound - when the scheduled transaction isn't found
+	ErrScheduledTransactionNotFound ErrorCode = 6001
+	//ErrInvalidRecurrence - when the recurrence of the scheduled transaction can't be parsed or has no occurrence
+	ErrInvalidRecurrence ErrorCode = 6002
+	//ErrInvalidScheduledTransactionStatusTransition - when the scheduled transaction can't move to the requested status
+	ErrInvalidScheduledTransactionStatusTransition ErrorCode = 6003
+
+	//ErrDisputeNotFound - when the dispute isn't found
+	ErrDisputeNotFound ErrorCode = 7001
+	//ErrInvalidDisputeStatusTransition - when the dispute can't move to the requested status
+	ErrInvalidDisputeStatusTransition ErrorCode = 7002
+	//ErrDisputeAlreadyOpen - when the transaction already has a dispute in progress
+	ErrDisputeAlreadyOpen ErrorCode = 7003
+	//ErrTransactionNotDisputable - when the transaction isn't a debit that can be disputed
+	ErrTransactionNotDisputable ErrorCode = 7004
+	//ErrDisputeAmountExceeded - when the disputed amount is more than what is left to reverse of the transaction
+	ErrDisputeAmountExceeded ErrorCode = 7005
+
+	//ErrInvalidDateRange - when the start of the date range of the analytics is after its end
+	ErrInvalidDateRange ErrorCode = 8001
 )
@@ -3,13 +3,29 @@ package response
 import (
 	"encoding/json"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 )
 
 type JSONWriter struct{}
 
+// requestIDWriter is a ResponseWriter that knows the ID of the request it responds to
+type requestIDWriter interface {
+	RequestID() string
+}
+
+// traceIDWriter is a ResponseWriter that knows the ID of the trace of the request it responds to
+type traceIDWriter interface {
+	TraceID() string
+}
+
+// loggerWriter is a ResponseWriter that knows the logger of the request it responds to, the logger of the context
+// of the request, which logs its request ID
+type loggerWriter interface {
+	Logger() *slog.Logger
+}
+
 // NewJSONWriter creates a new instance of JSONWriter
 func NewJSONWriter() *JSONWriter {
 	return &JSONWriter{}
@@ -21,8 +37,21 @@ func (j *JSONWriter) Ok(w http.ResponseWriter, data interface{}) {
 	j.jsonWrite(w, res, http.StatusOK)
 }
 
-// Error sends error to client with the given http status
+// Error sends error to client with the given http status. The error carries the ID of the request and the ID of its
+// trace when the writer knows them
 func (j *JSONWriter) Error(w http.ResponseWriter, apiError *APIError, httpStatus int) {
+	if apiError != nil {
+		// The error is copied, the errors like DefaultErr are shared by all the requests
+		withIDs := *apiError
+		if rw, ok := w.(requestIDWriter); ok {
+			withIDs.RequestID = rw.RequestID()
+		}
+		if rw, ok := w.(traceIDWriter); ok {
+			withIDs.TraceID = rw.TraceID()
+		}
+		apiError = &withIDs
+	}
+
 	res := j.buildResponse(nil, apiError)
 	j.jsonWrite(w, res, httpStatus)
 }
@@ -62,6 +91,16 @@ func (j *JSONWriter) TooManyRequest(w http.ResponseWriter, apiError *APIError) {
 	j.Error(w, apiError, http.StatusTooManyRequests)
 }
 
+// RequestEntityTooLarge sends error to client with http status 413
+func (j *JSONWriter) RequestEntityTooLarge(w http.ResponseWriter, apiError *APIError) {
+	j.Error(w, apiError, http.StatusRequestEntityTooLarge)
+}
+
+// ServiceUnavailable sends error to client with http status 503
+func (j *JSONWriter) ServiceUnavailable(w http.ResponseWriter, apiError *APIError) {
+	j.Error(w, apiError, http.StatusServiceUnavailable)
+}
+
 func (j *JSONWriter) Conflict(w http.ResponseWriter, ae *APIError) {
 	j.Error(w, ae, http.StatusConflict)
 }
@@ -87,11 +126,21 @@ func (j *JSONWriter) jsonWrite(w http.ResponseWriter, data interface{}, statusCo
 	err := json.NewEncoder(w).Encode(data)
 
 	if err != nil && errors.Is(err, os.ErrDeadlineExceeded) {
-		log.Printf("jsonWrite: failed to write response due to io timeout: %v", err)
+		writerLogger(w).Warn("jsonWrite: failed to write response due to io timeout", "error", err)
 		return
 	}
 
 	if err != nil {
-		log.Printf("jsonWrite: failed to write response: %v\n", err)
+		writerLogger(w).Error("jsonWrite: failed to write response", "error", err)
 	}
 }
+
+// writerLogger returns the logger of the request the writer responds to, or the default logger when the writer
+// doesn't know it
+func writerLogger(w http.ResponseWriter) *slog.Logger {
+	if rw, ok := w.(loggerWriter); ok {
+		return rw.Logger()
+	}
+
+	return slog.Default()
+}
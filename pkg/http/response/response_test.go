@@ -1,6 +1,10 @@
 package response
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -43,6 +47,47 @@ func TestJSONWriter_DefaultError(t *testing.T) {
 	assert.Equal(t, http.StatusInternalServerError, rr.Code)
 }
 
+// requestIDRecorder is a recorder that knows the ID of its request
+type requestIDRecorder struct {
+	*httptest.ResponseRecorder
+}
+
+func (requestIDRecorder) RequestID() string {
+	return "req-1"
+}
+
+func TestJSONWriter_ErrorWithRequestID(t *testing.T) {
+	jw := NewJSONWriter()
+	rr := requestIDRecorder{httptest.NewRecorder()}
+
+	jw.DefaultError(rr)
+
+	assert.Equal(t, http.StatusInternalServerError, rr.Code)
+	assert.Contains(t, rr.Body.String(), `"request_id":"req-1"`)
+	// The shared errors are left untouched
+	assert.Empty(t, DefaultErr.RequestID)
+}
+
+// tracedRecorder is a recorder that knows the ID of its request and of its trace
+type tracedRecorder struct {
+	requestIDRecorder
+}
+
+func (tracedRecorder) TraceID() string {
+	return "4bf92f3577b34da6a3ce929d0e0e4736"
+}
+
+func TestJSONWriter_ErrorWithTraceID(t *testing.T) {
+	jw := NewJSONWriter()
+	rr := tracedRecorder{requestIDRecorder{httptest.NewRecorder()}}
+
+	jw.DefaultError(rr)
+
+	assert.Contains(t, rr.Body.String(), `"request_id":"req-1"`)
+	assert.Contains(t, rr.Body.String(), `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+	assert.Empty(t, DefaultErr.TraceID)
+}
+
 func TestJSONWriter_Forbidden(t *testing.T) {
 	jw := NewJSONWriter()
 	rr, r := getResponseRequest()
@@ -118,6 +163,34 @@ func TestJSONWriter_jsonWrite(t *testing.T) {
 	assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
 }
 
+// loggedRecorder is a recorder that knows the logger of its request, and fails to write the body
+type loggedRecorder struct {
+	*httptest.ResponseRecorder
+	logger *slog.Logger
+}
+
+func (r loggedRecorder) Logger() *slog.Logger {
+	return r.logger
+}
+
+func (loggedRecorder) Write([]byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestJSONWriter_jsonWrite_LogsWithTheRequestLogger(t *testing.T) {
+	jw := NewJSONWriter()
+	buf := &bytes.Buffer{}
+	rr := loggedRecorder{ResponseRecorder: httptest.NewRecorder(), logger: slog.New(slog.NewJSONHandler(buf, nil)).With("request_id", "req-1")}
+
+	jw.jsonWrite(rr, map[string]string{"hello": "world"}, http.StatusOK)
+
+	line := map[string]any{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "jsonWrite: failed to write response", line["msg"])
+	assert.Equal(t, "broken pipe", line["error"])
+	assert.Equal(t, "req-1", line["request_id"])
+}
+
 func getResponseRequest() (*httptest.ResponseRecorder, *http.Request) {
 	w := httptest.NewRecorder()
 	return w, getRequest()
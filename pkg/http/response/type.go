@@ -20,6 +20,12 @@ type (
 		//Data can be added when there's any additional data on why error occurred,
 		// or can contain the field names that failed validation
 		Data interface{} `json:"data"`
+
+		//RequestID is the ID of the request, to be quoted when reporting the error. This field is not sent when empty
+		RequestID string `json:"request_id,omitempty"`
+
+		//TraceID is the ID of the trace of the request. This field is not sent when the request isn't traced
+		TraceID string `json:"trace_id,omitempty"`
 	}
 
 	response struct {
@@ -0,0 +1,146 @@
+package validator
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// DocumentValidator validates the number of a type of document, e.g. the brazilian CPF.
+// Validators are added with AddDocumentValidator and are used by the document tag.
+type DocumentValidator interface {
+	// Type is the name of the document type, it is also the tag to validate only this type in lowercase, e.g. cpf
+	Type() string
+	// IsValid checks a document number that is already normalized with NormalizeDocument
+	IsValid(number string) bool
+}
+
+// NormalizeDocument strips the punctuation and whitespace from a document number and upper cases it,
+// e.g. 529.982.247-25 becomes 52998224725
+func NormalizeDocument(number string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) || unicode.IsSpace(r) {
+			return -1
+		}
+
+		return unicode.ToUpper(r)
+	}, number)
+}
+
+// AddDocumentValidator adds a document type. The number of a field with the document tag is valid when it is valid
+// for any of the added document types, the tag with the lowercase type name only accepts that type.
+// Both tags normalize the field with NormalizeDocument.
+func (v *Validator) AddDocumentValidator(dv DocumentValidator) error {
+	v.documentValidators = append(v.documentValidators, dv)
+
+	return v.AddCustomValidator(strings.ToLower(dv.Type()), func(fl validator.FieldLevel) bool {
+		return dv.IsValid(normalizeDocumentField(fl))
+	})
+}
+
+// isValidDocument is the document tag, it checks the field against all the document validators
+func (v *Validator) isValidDocument(fl validator.FieldLevel) bool {
+	number := normalizeDocumentField(fl)
+
+	for _, dv := range v.documentValidators {
+		if dv.IsValid(number) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// normalizeDocumentField normalizes the field in place and returns the normalized number
+func normalizeDocumentField(fl validator.FieldLevel) string {
+	number := NormalizeDocument(fl.Field().String())
+	if fl.Field().CanSet() {
+		fl.Field().SetString(number)
+	}
+
+	return number
+}
+
+// CPF is the brazilian individual taxpayer registry number, 9 digits followed by 2 check digits
+type CPF struct{}
+
+func (CPF) Type() string {
+	return "CPF"
+}
+
+func (CPF) IsValid(number string) bool {
+	if len(number) != 11 || !isDigits(number) || isRepeated(number) {
+		return false
+	}
+
+	return checkDigit(number[:9], cpfWeights(10)) == number[9] && checkDigit(number[:10], cpfWeights(11)) == number[10]
+}
+
+// cpfWeights returns the CPF weights, they go down from first to 2
+func cpfWeights(first int) []int {
+	weights := make([]int, 0, first-1)
+	for w := first; w >= 2; w-- {
+		weights = append(weights, w)
+	}
+
+	return weights
+}
+
+// CNPJ is the brazilian company registry number, 12 characters followed by 2 check digits.
+// The 12 first characters can be letters since the alphanumeric CNPJ introduced in July 2026.
+type CNPJ struct{}
+
+var (
+	cnpjFirstWeights  = []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	cnpjSecondWeights = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+)
+
+func (CNPJ) Type() string {
+	return "CNPJ"
+}
+
+func (CNPJ) IsValid(number string) bool {
+	if len(number) != 14 || !isDigits(number[12:]) || isRepeated(number) {
+		return false
+	}
+
+	for _, r := range number[:12] {
+		if !(r >= '0' && r <= '9') && !(r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+
+	return checkDigit(number[:12], cnpjFirstWeights) == number[12] && checkDigit(number[:13], cnpjSecondWeights) == number[13]
+}
+
+// checkDigit calculates the modulo 11 check digit used by CPF and CNPJ.
+// The value of a character is its ASCII code minus 48, so digits keep their value and letters are 17 and above.
+func checkDigit(number string, weights []int) byte {
+	sum := 0
+	for i := range number {
+		sum += int(number[i]-'0') * weights[i]
+	}
+
+	rest := sum % 11
+	if rest < 2 {
+		return '0'
+	}
+
+	return byte('0' + 11 - rest)
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isRepeated checks if all the characters are the same, e.g. 11111111111 has valid check digits but isn't a valid number
+func isRepeated(s string) bool {
+	return strings.Count(s, s[:1]) == len(s)
+}
@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDocument(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "52998224725", NormalizeDocument(" 529.982.247-25 "))
+	assert.Equal(t, "11222333000181", NormalizeDocument("11.222.333/0001-81"))
+	assert.Equal(t, "12ABC34501DE35", NormalizeDocument("12.abc.345/01de-35"))
+}
+
+func TestDocumentValidation(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		number string
+		tag    string
+		valid  bool
+	}{
+		{"529.982.247-25", "cpf", true},
+		{"52998224725", "document", true},
+		{"111.444.777-35", "cpf", true},
+		{"529.982.247-24", "cpf", false}, // wrong check digit
+		{"111.111.111-11", "cpf", false}, // repeated digits
+		{"5299822472", "cpf", false},     // too short
+		{"11.222.333/0001-81", "cnpj", true},
+		{"11222333000181", "document", true},
+		{"12.ABC.345/01DE-35", "cnpj", true}, // alphanumeric CNPJ
+		{"11.222.333/0001-82", "cnpj", false},
+		{"00.000.000/0000-00", "cnpj", false},
+		{"11.222.333/0001-81", "cpf", false}, // a CNPJ isn't a CPF
+		{"DOC123456", "document", false},
+		{"", "document", false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(fmt.Sprintf("%s %s", tt.tag, tt.number), func(t *testing.T) {
+			t.Parallel()
+
+			res, err := testValidator.IsValidString(ctx, tt.number, tt.tag)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.valid, res.Valid)
+		})
+	}
+}
+
+func TestDocumentValidation_NormalizesTheField(t *testing.T) {
+	t.Parallel()
+
+	data := &struct {
+		DocumentNumber string `json:"document_number" validate:"required,document"`
+	}{DocumentNumber: "529.982.247-25"}
+
+	res, err := testValidator.IsValidStruct(ctx, data)
+	assert.Nil(t, err)
+	assert.True(t, res.Valid)
+	assert.Equal(t, "52998224725", data.DocumentNumber)
+}
+
+type fakeDocument struct{}
+
+func (fakeDocument) Type() string { return "FAKE" }
+
+func (fakeDocument) IsValid(number string) bool { return number == "FAKE1" }
+
+func TestAddDocumentValidator(t *testing.T) {
+	t.Parallel()
+
+	v := New()
+	assert.Nil(t, v.AddDocumentValidator(fakeDocument{}))
+
+	for _, tag := range []string{"fake", "document"} {
+		res, err := v.IsValidString(ctx, "fake-1", tag)
+		assert.Nil(t, err)
+		assert.True(t, res.Valid, tag)
+	}
+}
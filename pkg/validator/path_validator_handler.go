@@ -1,10 +1,10 @@
 package validator
 
 import (
-	"fmt"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"github.com/imjenal/transaction-service/internal/logging"
 	"github.com/imjenal/transaction-service/pkg/http/response"
 )
 
@@ -43,7 +43,7 @@ func (p PathValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		result, err := p.v.IsValidString(r.Context(), value, tags)
 		if err != nil {
-			fmt.Println("PathValidator.ServeHTTP: failed to validate string")
+			logging.FromContext(r.Context()).Error("PathValidator.ServeHTTP: failed to validate string", "error", err)
 			p.jsonWriter.DefaultError(w)
 
 			return
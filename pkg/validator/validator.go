@@ -2,9 +2,11 @@ package validator
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -12,7 +14,8 @@ import (
 
 // Validator has functions for validating struct and variables
 type Validator struct {
-	validate *validator.Validate
+	validate           *validator.Validate
+	documentValidators []DocumentValidator
 }
 
 // New returns a new Validator
@@ -151,4 +154,27 @@ func (v *Validator) registerCustomTags() {
 		return fl.Field().Bool()
 	})
 
+	// document: Normalize the document number and validate it against all the document types.
+	// cpf and cnpj: Normalize the document number and validate it as the given document type
+	_ = v.AddCustomValidator("document", v.isValidDocument)
+	_ = v.AddDocumentValidator(CPF{})
+	_ = v.AddDocumentValidator(CNPJ{})
+
+	// jsonsize: Validate that the field encoded as JSON is at most the given number of bytes, e.g. jsonsize=4096
+	_ = v.AddCustomValidator("jsonsize", isValidJSONSize)
+}
+
+// isValidJSONSize is the jsonsize tag, a field that can't be encoded as JSON is invalid
+func isValidJSONSize(fl validator.FieldLevel) bool {
+	maxSize, err := strconv.Atoi(fl.Param())
+	if err != nil {
+		panic(fmt.Sprintf("jsonsize: invalid size %q", fl.Param()))
+	}
+
+	encoded, err := json.Marshal(fl.Field().Interface())
+	if err != nil {
+		return false
+	}
+
+	return len(encoded) <= maxSize
 }
@@ -62,3 +62,32 @@ func TestNameValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONSizeValidation(t *testing.T) {
+	t.Parallel()
+
+	type metadata struct {
+		Metadata map[string]string `json:"metadata" validate:"jsonsize=20"`
+	}
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		valid    bool
+	}{
+		{"should pass for an empty map", map[string]string{}, true},
+		{"should pass for exactly the max size", map[string]string{"order": "12345678"}, true}, // {"order":"12345678"}
+		{"should fail above the max size", map[string]string{"order": "123456789"}, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			res, err := testValidator.IsValidStruct(ctx, &metadata{Metadata: tt.metadata})
+			assert.Nil(t, err)
+			assert.Equal(t, tt.valid, res.Valid)
+		})
+	}
+}